@@ -51,6 +51,7 @@ func Main(version string, cliCtx *cli.Context) error {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel() // Stop pprof and metrics only after main loop returns
+	defer batchSubmitter.TxManager.Close()
 	defer batchSubmitter.StopIfRunning(context.Background())
 
 	pprofConfig := cfg.PprofConfig
@@ -5,6 +5,7 @@ import (
 	"math"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -20,10 +21,15 @@ type channel struct {
 
 	// pending channel builder
 	channelBuilder *channelBuilder
-	// Set of unconfirmed txID -> frame data. For tx resubmission
+	// Set of unconfirmed txID -> tx data. For tx resubmission.
+	// The tx data may also hold frames of other channels.
 	pendingTransactions map[txID]txData
 	// Set of confirmed txID -> inclusion block. For determining if the channel is timed out
 	confirmedTransactions map[txID]eth.BlockID
+
+	// whether the channel was flushed by the operator, its frames are submitted
+	// right away, regardless of the L1 fees
+	flushed bool
 }
 
 func newChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig) (*channel, error) {
@@ -41,21 +47,20 @@ func newChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig) (*chan
 	}, nil
 }
 
-// TxFailed records a transaction as failed. It will attempt to resubmit the data
-// in the failed transaction.
+// TxFailed records a transaction as failed. It will attempt to resubmit the
+// frames of this channel in the failed transaction.
 func (s *channel) TxFailed(id txID) {
 	if data, ok := s.pendingTransactions[id]; ok {
 		s.log.Trace("marked transaction as failed", "id", id)
-		// Note: when the batcher is changed to send multiple frames per tx,
-		// this needs to be changed to iterate over all frames of the tx data
-		// and re-queue them.
-		s.channelBuilder.PushFrame(data.Frame())
+		for _, frame := range data.Frames() {
+			if frame.id.chID == s.ID() {
+				s.channelBuilder.PushFrame(frame)
+			}
+		}
 		delete(s.pendingTransactions, id)
 	} else {
 		s.log.Warn("unknown transaction marked as failed", "id", id)
 	}
-
-	s.metr.RecordBatchTxFailed()
 }
 
 // TxConfirmed marks a transaction as confirmed on L1. Unfortunately even if all frames in
@@ -63,7 +68,6 @@ func (s *channel) TxFailed(id txID) {
 // resubmitted.
 // This function may reset the pending channel if the pending channel has timed out.
 func (s *channel) TxConfirmed(id txID, inclusionBlock eth.BlockID) (bool, []*types.Block) {
-	s.metr.RecordBatchTxSubmitted()
 	s.log.Debug("marked transaction as confirmed", "id", id, "block", inclusionBlock)
 	if _, ok := s.pendingTransactions[id]; !ok {
 		s.log.Warn("unknown transaction marked as confirmed", "id", id, "block", inclusionBlock)
@@ -122,20 +126,64 @@ func (s *channel) NoneSubmitted() bool {
 	return len(s.confirmedTransactions) == 0 && len(s.pendingTransactions) == 0
 }
 
+// Status returns the submission state of the channel.
+func (s *channel) Status() rpc.ChannelStatus {
+	status := rpc.ChannelStatus{
+		ID:            s.ID(),
+		Open:          !s.IsFull(),
+		Blocks:        len(s.channelBuilder.Blocks()),
+		InputBytes:    s.InputBytes(),
+		OutputBytes:   s.OutputBytes(),
+		FramesTotal:   s.TotalFrames(),
+		FramesPending: s.PendingFrames(),
+	}
+	if err := s.FullErr(); err != nil {
+		status.FullReason = err.Error()
+	}
+	if status.InputBytes > 0 {
+		status.ComprRatio = float64(status.OutputBytes) / float64(status.InputBytes)
+	}
+	for _, tx := range s.pendingTransactions {
+		for _, frame := range tx.Frames() {
+			if frame.id.chID == s.ID() {
+				status.FramesInFlight++
+			}
+		}
+	}
+	for id := range s.confirmedTransactions {
+		for _, frame := range id.Frames() {
+			if frame.chID == s.ID() {
+				status.FramesConfirmed++
+			}
+		}
+	}
+	return status
+}
+
 func (s *channel) ID() derive.ChannelID {
 	return s.channelBuilder.ID()
 }
 
-func (s *channel) NextTxData() txData {
-	frame := s.channelBuilder.NextFrame()
+// NextFrame returns the next pending frame of the channel.
+// HasFrame must be called prior to check if there's a next frame available.
+// The frame has to be registered with TxSent as part of the tx data it is sent in.
+func (s *channel) NextFrame() frameData {
+	return s.channelBuilder.NextFrame()
+}
 
-	txdata := txData{frame}
-	id := txdata.ID()
+// NextFrameLen returns the size of the next pending frame, or 0 if there's none.
+func (s *channel) NextFrameLen() int {
+	if !s.HasFrame() {
+		return 0
+	}
+	return len(s.channelBuilder.frames[0].data)
+}
 
+// TxSent registers the tx data, holding frames of this channel, as pending.
+func (s *channel) TxSent(txdata txData) {
+	id := txdata.ID()
 	s.log.Trace("returning next tx data", "id", id)
 	s.pendingTransactions[id] = txdata
-
-	return txdata
 }
 
 func (s *channel) HasFrame() bool {
@@ -55,6 +55,13 @@ type ChannelConfig struct {
 	SubSafetyMargin uint64
 	// The maximum byte-size a frame can have.
 	MaxFrameSize uint64
+	// TargetTxSize is the target byte-size of a batcher transaction's data.
+	// Frames, possibly of different channels, are packed into a single
+	// transaction as long as its data doesn't exceed this size. A transaction
+	// always holds at least one frame.
+	//
+	// If 0, every transaction holds a single frame.
+	TargetTxSize uint64
 
 	// CompressorConfig contains the configuration for creating new compressors.
 	CompressorConfig compressor.Config
@@ -83,6 +90,12 @@ func (cc *ChannelConfig) Check() error {
 		return fmt.Errorf("max frame size %d is less than the minimum 23", cc.MaxFrameSize)
 	}
 
+	// The [TargetTxSize] must not exceed the size of a transaction holding a
+	// single frame of [MaxFrameSize], which is the max L1 tx data size.
+	if cc.TargetTxSize > cc.MaxFrameSize+1 {
+		return fmt.Errorf("target tx size %d exceeds the max tx size %d", cc.TargetTxSize, cc.MaxFrameSize+1)
+	}
+
 	return nil
 }
 
@@ -101,6 +114,10 @@ type frameData struct {
 type channelBuilder struct {
 	cfg ChannelConfig
 
+	// id of the current channel, which is also the channel out's ID,
+	// unless the channel was restored from the channel journal.
+	id derive.ChannelID
+
 	// L1 block number timeout of combined
 	// - channel duration timeout,
 	// - consensus channel timeout,
@@ -109,6 +126,12 @@ type channelBuilder struct {
 	timeout uint64
 	// reason for currently set timeout
 	timeoutReason error
+	// L1 block number deadline of combined
+	// - consensus channel timeout,
+	// - sequencing window timeout,
+	// by which the channel must be submitted. Unlike the timeout, it does not
+	// include the channel duration timeout. 0 if no deadline set yet.
+	deadline uint64
 
 	// Reason for the channel being full. Set by setFullErr so it's always
 	// guaranteed to be a ChannelFullError wrapping the specific reason.
@@ -123,6 +146,8 @@ type channelBuilder struct {
 	numFrames int
 	// total amount of output data of all frames created yet
 	outputBytes int
+	// whether the channel out got closed and all frames got created
+	closed bool
 }
 
 // newChannelBuilder creates a new channel builder or returns an error if the
@@ -139,12 +164,38 @@ func newChannelBuilder(cfg ChannelConfig) (*channelBuilder, error) {
 
 	return &channelBuilder{
 		cfg: cfg,
+		id:  co.ID(),
 		co:  co,
 	}, nil
 }
 
+// restoreChannelBuilder creates a closed channel builder for a channel that was
+// restored from the channel journal, with the given blocks. Its remaining frames
+// must be pushed with PushFrame.
+func restoreChannelBuilder(cfg ChannelConfig, id derive.ChannelID, blocks []*types.Block, totalFrames int) (*channelBuilder, error) {
+	if len(blocks) == 0 {
+		return nil, errors.New("cannot restore channel without blocks")
+	}
+	c, err := newChannelBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// The sequencing window timeout is set by the first block, it has the earliest epoch.
+	batch, _, err := derive.BlockToBatch(blocks[0])
+	if err != nil {
+		return nil, fmt.Errorf("converting block to batch: %w", err)
+	}
+	c.updateSwTimeout(batch)
+	c.id = id
+	c.blocks = blocks
+	c.numFrames = totalFrames
+	c.closed = true
+	c.setFullErr(ErrTerminated)
+	return c, nil
+}
+
 func (c *channelBuilder) ID() derive.ChannelID {
-	return c.co.ID()
+	return c.id
 }
 
 // InputBytes returns the total amount of input bytes added to the channel.
@@ -174,8 +225,14 @@ func (c *channelBuilder) Reset() error {
 	c.blocks = c.blocks[:0]
 	c.frames = c.frames[:0]
 	c.timeout = 0
+	c.deadline = 0
 	c.fullErr = nil
-	return c.co.Reset()
+	c.closed = false
+	if err := c.co.Reset(); err != nil {
+		return err
+	}
+	c.id = c.co.ID()
+	return nil
 }
 
 // AddBlock adds a block to the channel compression pipeline. IsFull should be
@@ -233,6 +290,7 @@ func (c *channelBuilder) RegisterL1Block(l1BlockNum uint64) {
 func (c *channelBuilder) FramePublished(l1BlockNum uint64) {
 	timeout := l1BlockNum + c.cfg.ChannelTimeout - c.cfg.SubSafetyMargin
 	c.updateTimeout(timeout, ErrChannelTimeoutClose)
+	c.updateDeadline(timeout)
 }
 
 // updateDurationTimeout updates the block timeout with the channel duration
@@ -255,6 +313,22 @@ func (c *channelBuilder) updateDurationTimeout(l1BlockNum uint64) {
 func (c *channelBuilder) updateSwTimeout(batch *derive.BatchData) {
 	timeout := uint64(batch.EpochNum) + c.cfg.SeqWindowSize - c.cfg.SubSafetyMargin
 	c.updateTimeout(timeout, ErrSeqWindowClose)
+	c.updateDeadline(timeout)
+}
+
+// updateDeadline moves the submission deadline to the given block number if
+// it is earlier than the current deadline, or if it is still unset.
+func (c *channelBuilder) updateDeadline(blockNum uint64) {
+	if c.deadline == 0 || c.deadline > blockNum {
+		c.deadline = blockNum
+	}
+}
+
+// PastDeadline returns whether the passed block number is at or after the
+// submission deadline, i.e. the channel has to be submitted right away to be
+// included in time. If no deadline is set yet, it returns false.
+func (c *channelBuilder) PastDeadline(blockNum uint64) bool {
+	return c.deadline != 0 && blockNum >= c.deadline
 }
 
 // updateTimeout updates the timeout block to the given block number if it is
@@ -319,7 +393,11 @@ func (c *channelBuilder) setFullErr(err error) {
 // pull readily available frames from the compression output.
 // If it is full, the channel is closed and all remaining
 // frames will be created, possibly with a small leftover frame.
+// Once all frames are created, it does nothing.
 func (c *channelBuilder) OutputFrames() error {
+	if c.closed {
+		return nil
+	}
 	if c.IsFull() {
 		return c.closeAndOutputAllFrames()
 	}
@@ -351,6 +429,7 @@ func (c *channelBuilder) closeAndOutputAllFrames() error {
 
 	for {
 		if err := c.outputFrame(); err == io.EOF {
+			c.closed = true
 			return nil
 		} else if err != nil {
 			return err
@@ -389,6 +468,12 @@ func (c *channelBuilder) outputFrame() error {
 	return err // possibly io.EOF (last frame)
 }
 
+// IsClosed returns whether the channel out got closed and all frames got created.
+// The frames may not all be submitted yet.
+func (c *channelBuilder) IsClosed() bool {
+	return c.closed
+}
+
 // Close immediately marks the channel as full with an ErrTerminated
 // if the channel is not already full.
 func (c *channelBuilder) Close() {
@@ -47,6 +47,8 @@ func TestChannelConfig_Check(t *testing.T) {
 	timeoutChannelConfig := defaultTestChannelConfig
 	timeoutChannelConfig.ChannelTimeout = 0
 	timeoutChannelConfig.SubSafetyMargin = 1
+	targetTxSizeChannelConfig := defaultTestChannelConfig
+	targetTxSizeChannelConfig.TargetTxSize = defaultTestChannelConfig.MaxFrameSize + 2
 	tests := []test{
 		{
 			input: defaultTestChannelConfig,
@@ -66,6 +68,12 @@ func TestChannelConfig_Check(t *testing.T) {
 				require.EqualError(t, output, "max frame size cannot be zero")
 			},
 		},
+		{
+			input: targetTxSizeChannelConfig,
+			assertion: func(output error) {
+				require.ErrorContains(t, output, "target tx size")
+			},
+		},
 	}
 	for i := 1; i < derive.FrameV0OverHeadSize; i++ {
 		smallChannelConfig := defaultTestChannelConfig
@@ -383,7 +391,7 @@ func TestChannelBuilder_NextFrame(t *testing.T) {
 	require.NoError(t, err)
 
 	// Push one frame into to the channel builder
-	expectedTx := txID{chID: co.ID(), frameNumber: fn}
+	expectedTx := frameID{chID: co.ID(), frameNumber: fn}
 	expectedBytes := buf.Bytes()
 	frameData := frameData{
 		id: frameID{
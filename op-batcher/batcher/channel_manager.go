@@ -7,9 +7,11 @@ import (
 	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -38,8 +40,15 @@ type channelManager struct {
 	currentChannel *channel
 	// channels to read frame data from, for writing batches onchain
 	channelQueue []*channel
-	// used to lookup channels by tx ID upon tx success / failure
-	txChannels map[txID]*channel
+	// used to lookup the channels of the frames of a tx by tx ID upon tx success / failure
+	txChannels map[txID][]*channel
+	// L1 head at the time each pending tx was sent, for the channel journal
+	txSentAt map[txID]uint64
+	// L1 head of the last request for new tx data
+	l1Head eth.BlockID
+
+	// journals the channels for restoring them after a restart, nil if disabled
+	journal *channelJournal
 
 	// if set to true, prevents production of any new channel frames
 	closed bool
@@ -50,7 +59,8 @@ func NewChannelManager(log log.Logger, metr metrics.Metricer, cfg ChannelConfig)
 		log:        log,
 		metr:       metr,
 		cfg:        cfg,
-		txChannels: make(map[txID]*channel),
+		txChannels: make(map[txID][]*channel),
+		txSentAt:   make(map[txID]uint64),
 	}
 }
 
@@ -60,12 +70,55 @@ func (s *channelManager) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.log.Trace("clearing channel manager state")
+	for _, ch := range s.channelQueue {
+		s.journalRemove(ch)
+	}
 	s.blocks = s.blocks[:0]
 	s.tip = common.Hash{}
 	s.closed = false
 	s.currentChannel = nil
 	s.channelQueue = nil
-	s.txChannels = make(map[txID]*channel)
+	s.txChannels = make(map[txID][]*channel)
+	s.txSentAt = make(map[txID]uint64)
+}
+
+// restoreChannels restores the channels of the channel journal into the cleared state.
+// The tip is the last block of the restored channels, new blocks have to extend it.
+func (s *channelManager) restoreChannels(channels []*channel, tip common.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channelQueue = channels
+	s.tip = tip
+	if s.journal == nil {
+		return
+	}
+	journaled := make([]journalChannel, 0, len(channels))
+	for _, ch := range channels {
+		journaled = append(journaled, ch.journalChannel(s.txSentAt))
+	}
+	if err := s.journal.Save(journaled); err != nil {
+		s.log.Error("Failed to save channel journal", "err", err)
+	}
+}
+
+// journalAppend appends the records to the channel journal, if enabled.
+func (s *channelManager) journalAppend(records ...journalRecord) {
+	if s.journal == nil {
+		return
+	}
+	if err := s.journal.Append(records...); err != nil {
+		s.log.Error("Failed to append to channel journal", "err", err)
+	}
+}
+
+// journalRemove records the removal of the channel in the channel journal, if enabled.
+func (s *channelManager) journalRemove(ch *channel) {
+	if s.journal == nil {
+		return
+	}
+	if err := s.journal.Remove(ch.ID()); err != nil {
+		s.log.Error("Failed to remove channel from channel journal", "id", ch.ID(), "err", err)
+	}
 }
 
 // TxFailed records a transaction as failed. It will attempt to resubmit the data
@@ -73,16 +126,21 @@ func (s *channelManager) Clear() {
 func (s *channelManager) TxFailed(id txID) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if channel, ok := s.txChannels[id]; ok {
+	if channels, ok := s.txChannels[id]; ok {
 		delete(s.txChannels, id)
-		channel.TxFailed(id)
-		if s.closed && channel.NoneSubmitted() {
-			s.log.Info("Channel has no submitted transactions, clearing for shutdown", "chID", channel.ID())
-			s.removePendingChannel(channel)
+		delete(s.txSentAt, id)
+		s.journalAppend(journalRecord{Op: journalOpFailed, Tx: hexutil.Bytes(id)})
+		for _, channel := range channels {
+			channel.TxFailed(id)
+			if s.closed && channel.NoneSubmitted() {
+				s.log.Info("Channel has no submitted transactions, clearing for shutdown", "chID", channel.ID())
+				s.removePendingChannel(channel)
+			}
 		}
 	} else {
 		s.log.Warn("transaction from unknown channel marked as failed", "id", id)
 	}
+	s.metr.RecordBatchTxFailed()
 }
 
 // TxConfirmed marks a transaction as confirmed on L1. Unfortunately even if all frames in
@@ -92,13 +150,20 @@ func (s *channelManager) TxFailed(id txID) {
 func (s *channelManager) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if channel, ok := s.txChannels[id]; ok {
+	if channels, ok := s.txChannels[id]; ok {
 		delete(s.txChannels, id)
-		done, blocks := channel.TxConfirmed(id, inclusionBlock)
-		s.blocks = append(blocks, s.blocks...)
-		if done {
-			s.removePendingChannel(channel)
+		delete(s.txSentAt, id)
+		inclusion := inclusionBlock
+		s.journalAppend(journalRecord{Op: journalOpConfirmed, Tx: hexutil.Bytes(id), Inclusion: &inclusion})
+		var timedOut []*types.Block
+		for _, channel := range channels {
+			done, blocks := channel.TxConfirmed(id, inclusionBlock)
+			timedOut = append(timedOut, blocks...)
+			if done {
+				s.removePendingChannel(channel)
+			}
 		}
+		s.blocks = append(timedOut, s.blocks...)
 	} else {
 		s.log.Warn("transaction from unknown channel marked as confirmed", "id", id)
 	}
@@ -123,27 +188,61 @@ func (s *channelManager) removePendingChannel(channel *channel) {
 		return
 	}
 	s.channelQueue = append(s.channelQueue[:index], s.channelQueue[index+1:]...)
+	s.journalRemove(channel)
 }
 
-// nextTxData pops off s.datas & handles updating the internal state
-func (s *channelManager) nextTxData(channel *channel) (txData, error) {
-	if channel == nil || !channel.HasFrame() {
+// nextTxData pops off the next frames, starting with the first channel, & handles
+// updating the internal state.
+//
+// If a target tx size is configured, frames are packed into the tx data until the
+// next frame doesn't fit anymore. Frames of the first channel are taken first, then
+// frames of the other queued channels, in queue order.
+func (s *channelManager) nextTxData(first *channel) (txData, error) {
+	if first == nil || !first.HasFrame() {
 		s.log.Trace("no next tx data")
 		return txData{}, io.EOF // TODO: not enough data error instead
 	}
-	tx := channel.NextTxData()
-	s.txChannels[tx.ID()] = channel
+
+	var (
+		tx       txData
+		channels []*channel
+	)
+	takeFrames := func(ch *channel) {
+		taken := false
+		for ch.HasFrame() && (len(tx.frames) == 0 || uint64(tx.Len()+ch.NextFrameLen()) <= s.cfg.TargetTxSize) {
+			tx.frames = append(tx.frames, ch.NextFrame())
+			taken = true
+		}
+		if taken {
+			channels = append(channels, ch)
+		}
+	}
+	takeFrames(first)
+	for _, ch := range s.channelQueue {
+		if ch != first {
+			takeFrames(ch)
+		}
+	}
+
+	for _, ch := range channels {
+		ch.TxSent(tx)
+	}
+	id := tx.ID()
+	s.txChannels[id] = channels
+	s.txSentAt[id] = s.l1Head.Number
+	s.journalAppend(journalRecord{Op: journalOpSent, Tx: hexutil.Bytes(id), SentAt: s.l1Head.Number})
 	return tx, nil
 }
 
 // TxData returns the next tx data that should be submitted to L1.
 //
-// It currently only uses one frame per transaction. If the pending channel is
-// full, it only returns the remaining frames of this channel until it got
+// If the pending channel is full, it only returns the remaining frames of this
+// channel, possibly packed together with frames of other channels, until it got
 // successfully fully sent to L1. It returns io.EOF if there's no pending frame.
 func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.l1Head = l1Head
 	var firstWithFrame *channel
 	for _, ch := range s.channelQueue {
 		if ch.HasFrame() {
@@ -201,6 +300,8 @@ func (s *channelManager) ensureChannelWithSpace(l1Head eth.BlockID) error {
 	}
 	s.currentChannel = pc
 	s.channelQueue = append(s.channelQueue, pc)
+	id := pc.ID()
+	s.journalAppend(journalRecord{Op: journalOpOpen, Channel: &id})
 	s.log.Info("Created channel",
 		"id", pc.ID(),
 		"l1Head", l1Head,
@@ -237,6 +338,10 @@ func (s *channelManager) processBlocks() error {
 			return fmt.Errorf("adding block[%d] to channel builder: %w", i, err)
 		}
 		s.log.Debug("Added block to channel", "channel", s.currentChannel.ID(), "block", block)
+		if s.journal != nil {
+			id, blockID := s.currentChannel.ID(), eth.ToBlockID(block)
+			s.journalAppend(journalRecord{Op: journalOpBlock, Channel: &id, Block: &blockID})
+		}
 
 		blocksAdded += 1
 		latestL2ref = l2BlockRefFromBlockAndL1Info(block, l1info)
@@ -271,9 +376,23 @@ func (s *channelManager) processBlocks() error {
 }
 
 func (s *channelManager) outputFrames() error {
+	cb := s.currentChannel.channelBuilder
+	numFrames, wasClosed := cb.TotalFrames(), cb.IsClosed()
 	if err := s.currentChannel.OutputFrames(); err != nil {
 		return fmt.Errorf("creating frames with channel builder: %w", err)
 	}
+	if s.journal != nil {
+		id := s.currentChannel.ID()
+		var records []journalRecord
+		// new frames are appended to the frames queue of the channel builder
+		for _, frame := range cb.frames[len(cb.frames)-(cb.TotalFrames()-numFrames):] {
+			records = append(records, journalRecord{Op: journalOpFrame, Channel: &id, Frame: frame.id.frameNumber, Data: frame.data})
+		}
+		if !wasClosed && cb.IsClosed() {
+			records = append(records, journalRecord{Op: journalOpClose, Channel: &id, TotalFrames: cb.TotalFrames()})
+		}
+		s.journalAppend(records...)
+	}
 	if !s.currentChannel.IsFull() {
 		return nil
 	}
@@ -321,6 +440,151 @@ func (s *channelManager) AddL2Block(block *types.Block) error {
 	return nil
 }
 
+// HasUrgentData returns whether there is data that has to be submitted right
+// away, regardless of the L1 fees. This is the case if the channel manager is
+// closed, if a channel is flushed or partially submitted, or if a channel or the first
+// pending block reached its submission deadline, which is derived from the
+// channel timeout and sequencing window, minus the SubSafetyMargin.
+func (s *channelManager) HasUrgentData(l1Head eth.BlockID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return true
+	}
+	for _, ch := range s.channelQueue {
+		if ch.HasFrame() && (ch.flushed || !ch.NoneSubmitted()) {
+			return true
+		}
+		if (ch.HasFrame() || !ch.IsFull()) && ch.channelBuilder.PastDeadline(l1Head.Number) {
+			return true
+		}
+	}
+	if len(s.blocks) == 0 {
+		return false
+	}
+	block := s.blocks[0]
+	if len(block.Transactions()) == 0 {
+		return true
+	}
+	l1Info, err := derive.L1InfoDepositTxData(block.Transactions()[0].Data())
+	if err != nil {
+		// let the channel builder surface the invalid block
+		return true
+	}
+	return l1Head.Number >= l1Info.Number+s.cfg.SeqWindowSize-s.cfg.SubSafetyMargin
+}
+
+// Backlog returns the approximate size in bytes of the L2 data that is not
+// submitted yet: the transactions of the pending blocks and the input of the
+// open channel, uncompressed, and the pending frames of all channels.
+func (s *channelManager) Backlog() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var size uint64
+	for _, block := range s.blocks {
+		for _, tx := range block.Transactions() {
+			if !tx.IsDepositTx() {
+				size += tx.Size()
+			}
+		}
+	}
+	for _, ch := range s.channelQueue {
+		if !ch.IsFull() {
+			size += uint64(ch.InputBytes())
+		}
+		for _, frame := range ch.channelBuilder.frames {
+			size += uint64(len(frame.data))
+		}
+	}
+	return size
+}
+
+// Status returns the submission state of all channels, and the range of L2 blocks
+// that are loaded, but not yet fully confirmed on L1. The range is nil if there
+// are no such blocks.
+func (s *channelManager) Status() ([]rpc.ChannelStatus, *rpc.L2Range) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channels := make([]rpc.ChannelStatus, 0, len(s.channelQueue))
+	var first, last *types.Block
+	addBlocks := func(blocks []*types.Block) {
+		if len(blocks) == 0 {
+			return
+		}
+		if first == nil {
+			first = blocks[0]
+		}
+		last = blocks[len(blocks)-1]
+	}
+	for _, ch := range s.channelQueue {
+		channels = append(channels, ch.Status())
+		addBlocks(ch.channelBuilder.Blocks())
+	}
+	addBlocks(s.blocks)
+	if first == nil {
+		return channels, nil
+	}
+	return channels, &rpc.L2Range{Start: eth.ToBlockID(first), End: eth.ToBlockID(last)}
+}
+
+// FlushChannel closes the current channel, after adding the pending blocks to it,
+// and outputs all of its frames. The frames of the flushed channel are urgent data,
+// so they are submitted right away, regardless of the L1 fees.
+// If there's no current channel with space, but there are pending blocks, a new
+// channel is opened and flushed, if the L1 head is known. It returns the ID of the flushed channel.
+func (s *channelManager) FlushChannel() (derive.ChannelID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return derive.ChannelID{}, errors.New("channel manager is closed")
+	}
+	hasOpenChannel := s.currentChannel != nil && !s.currentChannel.IsFull() && s.currentChannel.InputBytes() > 0
+	if !hasOpenChannel && len(s.blocks) == 0 {
+		return derive.ChannelID{}, errors.New("nothing to flush, no open channel or pending blocks")
+	}
+	// A new channel times out relative to the L1 head, which is only known once tx data was requested.
+	if !hasOpenChannel && s.l1Head == (eth.BlockID{}) {
+		return derive.ChannelID{}, errors.New("cannot open a channel to flush, L1 head is not known yet")
+	}
+
+	if len(s.blocks) > 0 {
+		if err := s.ensureChannelWithSpace(s.l1Head); err != nil {
+			return derive.ChannelID{}, err
+		}
+		if err := s.processBlocks(); err != nil {
+			return derive.ChannelID{}, err
+		}
+	}
+	ch := s.currentChannel
+	ch.Close()
+	if err := s.outputFrames(); err != nil {
+		return derive.ChannelID{}, err
+	}
+	ch.flushed = true
+	s.log.Info("Flushed channel", "id", ch.ID(), "num_frames", ch.TotalFrames(), "blocks_pending", len(s.blocks))
+	return ch.ID(), nil
+}
+
+// SetTargetFrameSize changes the target frame size of the compressor of new channels.
+// It must not exceed the max frame size.
+func (s *channelManager) SetTargetFrameSize(size uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if size == 0 || size > s.cfg.MaxFrameSize {
+		return fmt.Errorf("target frame size %d must be in range (0, %d]", size, s.cfg.MaxFrameSize)
+	}
+	s.log.Info("Changed target frame size", "old", s.cfg.CompressorConfig.TargetFrameSize, "new", size)
+	s.cfg.CompressorConfig.TargetFrameSize = size
+	return nil
+}
+
+// TargetFrameSize returns the target frame size of new channels.
+func (s *channelManager) TargetFrameSize() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.CompressorConfig.TargetFrameSize
+}
+
 func l2BlockRefFromBlockAndL1Info(block *types.Block, l1info derive.L1BlockInfo) eth.L2BlockRef {
 	return eth.L2BlockRef{
 		Hash:           block.Hash(),
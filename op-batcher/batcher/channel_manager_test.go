@@ -1,6 +1,7 @@
 package batcher
 
 import (
+	"bytes"
 	"io"
 	"math/big"
 	"math/rand"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	derivetest "github.com/ethereum-optimism/optimism/op-node/rollup/derive/test"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
@@ -345,3 +347,186 @@ func TestChannelManagerCloseAllTxsFailed(t *testing.T) {
 	_, err = m.TxData(eth.BlockID{})
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to produce no more tx data")
 }
+
+// TestChannelManagerHasUrgentData ensures that pending data becomes urgent at
+// the sequencing window deadline, and that partially submitted channels are urgent.
+func TestChannelManagerHasUrgentData(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics,
+		ChannelConfig{
+			SeqWindowSize:   10,
+			SubSafetyMargin: 2,
+			MaxFrameSize:    1000,
+			ChannelTimeout:  1000,
+			CompressorConfig: compressor.Config{
+				TargetNumFrames:  100,
+				TargetFrameSize:  1000,
+				ApproxComprRatio: 1.0,
+			},
+		})
+	require.False(m.HasUrgentData(eth.BlockID{Number: 200}), "no data")
+	require.Zero(m.Backlog())
+
+	// the L1 origin of mini blocks is block 100
+	a := newMiniL2Block(50_000)
+	require.NoError(m.AddL2Block(a))
+	require.False(m.HasUrgentData(eth.BlockID{Number: 107}))
+	require.True(m.HasUrgentData(eth.BlockID{Number: 108}), "sequencing window deadline reached")
+	require.NotZero(m.Backlog())
+
+	_, err := m.TxData(eth.BlockID{Number: 100})
+	require.NoError(err)
+	require.True(m.currentChannel.HasFrame())
+	require.True(m.HasUrgentData(eth.BlockID{Number: 100}), "partially submitted channel")
+	require.Equal(uint64(len(m.currentChannel.channelBuilder.frames[0].data)), m.Backlog())
+}
+
+// TestChannelManagerMultiFrameTx ensures that the channel manager packs frames of
+// multiple channels into a single tx up to the target tx size, and that tx
+// failure and confirmation is handled for all frames of the tx.
+func TestChannelManagerMultiFrameTx(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := ChannelConfig{
+		MaxFrameSize:   1000,
+		ChannelTimeout: 100,
+		// three frames of 33 bytes each, plus the version byte
+		TargetTxSize: 100,
+	}
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg)
+
+	pushFrames := func(ch *channel, n int) []frameData {
+		var frames []frameData
+		for i := 0; i < n; i++ {
+			var buf bytes.Buffer
+			f := derive.Frame{ID: ch.ID(), FrameNumber: uint16(i), Data: make([]byte, 10)}
+			require.NoError(f.MarshalBinary(&buf))
+			frame := frameData{id: frameID{chID: ch.ID(), frameNumber: uint16(i)}, data: buf.Bytes()}
+			ch.channelBuilder.PushFrame(frame)
+			frames = append(frames, frame)
+		}
+		return frames
+	}
+	a, err := newChannel(log, metrics.NoopMetrics, cfg)
+	require.NoError(err)
+	b, err := newChannel(log, metrics.NoopMetrics, cfg)
+	require.NoError(err)
+	m.channelQueue = []*channel{a, b}
+	aFrames, bFrames := pushFrames(a, 2), pushFrames(b, 2)
+
+	tx0, err := m.TxData(eth.BlockID{})
+	require.NoError(err)
+	require.Equal([]frameData{aFrames[0], aFrames[1], bFrames[0]}, tx0.Frames())
+	require.Equal(100, tx0.Len())
+	require.Equal([]frameID{aFrames[0].id, aFrames[1].id, bFrames[0].id}, tx0.ID().Frames())
+	require.Equal([]*channel{a, b}, m.txChannels[tx0.ID()])
+	fs, err := derive.ParseFrames(tx0.Bytes())
+	require.NoError(err)
+	require.Len(fs, 3)
+
+	tx1, err := m.TxData(eth.BlockID{})
+	require.NoError(err)
+	require.Equal([]frameData{bFrames[1]}, tx1.Frames(), "next frame doesn't fit anymore")
+	require.Equal([]*channel{b}, m.txChannels[tx1.ID()])
+
+	m.TxFailed(tx0.ID())
+	require.Equal(2, a.PendingFrames())
+	require.Equal(1, b.PendingFrames())
+	require.Empty(a.pendingTransactions)
+	require.Len(b.pendingTransactions, 1)
+
+	tx2, err := m.TxData(eth.BlockID{})
+	require.NoError(err)
+	require.Equal(tx0.ID(), tx2.ID(), "failed frames are resubmitted")
+
+	inclusion := eth.BlockID{Number: 1}
+	m.TxConfirmed(tx2.ID(), inclusion)
+	m.TxConfirmed(tx1.ID(), inclusion)
+	require.Empty(m.txChannels)
+	require.Empty(a.pendingTransactions)
+	require.Empty(b.pendingTransactions)
+	require.Equal(map[txID]eth.BlockID{tx2.ID(): inclusion}, a.confirmedTransactions)
+	require.Equal(map[txID]eth.BlockID{tx2.ID(): inclusion, tx1.ID(): inclusion}, b.confirmedTransactions)
+
+	jc := b.journalChannel(nil)
+	require.Len(jc.Frames, 2, "only frames of the channel itself are journaled")
+	require.Equal(frameConfirmed, jc.Frames[0].Status)
+	require.Equal(frameConfirmed, jc.Frames[1].Status)
+}
+
+// TestChannelManagerFlushChannel ensures that the operator can flush the current
+// channel, and that the channel state is reported by the status.
+func TestChannelManagerFlushChannel(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		SeqWindowSize:  1000,
+		MaxFrameSize:   1000,
+		ChannelTimeout: 1000,
+		CompressorConfig: compressor.Config{
+			TargetNumFrames:  100,
+			TargetFrameSize:  1000,
+			ApproxComprRatio: 1.0,
+		},
+	})
+	l1Head := eth.BlockID{Number: 101}
+
+	_, err := m.FlushChannel()
+	require.ErrorContains(err, "nothing to flush")
+	channels, unsubmitted := m.Status()
+	require.Empty(channels)
+	require.Nil(unsubmitted)
+
+	a := newMiniL2Block(1)
+	require.NoError(m.AddL2Block(a))
+	_, err = m.FlushChannel()
+	require.ErrorContains(err, "L1 head is not known", "no channel is opened before the L1 head is known")
+	require.Nil(m.currentChannel)
+	_, err = m.TxData(l1Head)
+	require.ErrorIs(err, io.EOF)
+	b := newMiniL2BlockWithNumberParent(1, big.NewInt(1), a.Hash())
+	require.NoError(m.AddL2Block(b))
+
+	channels, unsubmitted = m.Status()
+	require.Len(channels, 1)
+	require.True(channels[0].Open)
+	require.Equal(1, channels[0].Blocks)
+	require.Equal(&rpc.L2Range{Start: eth.ToBlockID(a), End: eth.ToBlockID(b)}, unsubmitted)
+	require.False(m.HasUrgentData(l1Head))
+
+	id, err := m.FlushChannel()
+	require.NoError(err)
+	require.Equal(m.currentChannel.ID(), id)
+	require.Empty(m.blocks, "pending blocks are added to the flushed channel")
+	require.True(m.HasUrgentData(l1Head), "flushed channel is submitted regardless of L1 fees")
+
+	_, err = m.TxData(l1Head)
+	require.NoError(err)
+	channels, unsubmitted = m.Status()
+	require.Len(channels, 1)
+	status := channels[0]
+	require.Equal(id, status.ID)
+	require.False(status.Open)
+	require.Contains(status.FullReason, ErrTerminated.Error())
+	require.Equal(2, status.Blocks)
+	require.Positive(status.ComprRatio)
+	require.Equal(status.FramesTotal, status.FramesPending+status.FramesInFlight)
+	require.Equal(1, status.FramesInFlight)
+	require.Zero(status.FramesConfirmed)
+	require.Equal(&rpc.L2Range{Start: eth.ToBlockID(a), End: eth.ToBlockID(b)}, unsubmitted)
+
+	_, err = m.FlushChannel()
+	require.ErrorContains(err, "nothing to flush", "flushed channel is full")
+}
+
+func TestChannelManagerSetTargetFrameSize(t *testing.T) {
+	m := NewChannelManager(testlog.Logger(t, log.LvlCrit), metrics.NoopMetrics, ChannelConfig{
+		MaxFrameSize:     1000,
+		CompressorConfig: compressor.Config{TargetFrameSize: 1000},
+	})
+	require.Error(t, m.SetTargetFrameSize(0))
+	require.Error(t, m.SetTargetFrameSize(1001), "exceeds max frame size")
+	require.NoError(t, m.SetTargetFrameSize(500))
+	require.Equal(t, uint64(500), m.TargetFrameSize())
+}
@@ -37,8 +37,8 @@ func TestChannelTimeout(t *testing.T) {
 
 	// Manually set a confirmed transactions
 	// To avoid other methods clearing state
-	channel.confirmedTransactions[frameID{frameNumber: 0}] = eth.BlockID{Number: 0}
-	channel.confirmedTransactions[frameID{frameNumber: 1}] = eth.BlockID{Number: 99}
+	channel.confirmedTransactions[newTxID(frameID{frameNumber: 0})] = eth.BlockID{Number: 0}
+	channel.confirmedTransactions[newTxID(frameID{frameNumber: 1})] = eth.BlockID{Number: 99}
 
 	// Since the ChannelTimeout is 100, the
 	// pending channel should not be timed out
@@ -47,9 +47,9 @@ func TestChannelTimeout(t *testing.T) {
 
 	// Add a confirmed transaction with a higher number
 	// than the ChannelTimeout
-	channel.confirmedTransactions[frameID{
+	channel.confirmedTransactions[newTxID(frameID{
 		frameNumber: 2,
-	}] = eth.BlockID{
+	})] = eth.BlockID{
 		Number: 101,
 	}
 
@@ -92,7 +92,7 @@ func TestChannelNextTxData(t *testing.T) {
 
 	// Now the nextTxData function should return the frame
 	returnedTxData, err = m.nextTxData(channel)
-	expectedTxData := txData{frame}
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
@@ -125,7 +125,7 @@ func TestChannelTxConfirmed(t *testing.T) {
 	m.currentChannel.channelBuilder.PushFrame(frame)
 	require.Equal(t, 1, m.currentChannel.PendingFrames())
 	returnedTxData, err := m.nextTxData(m.currentChannel)
-	expectedTxData := txData{frame}
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
@@ -138,7 +138,7 @@ func TestChannelTxConfirmed(t *testing.T) {
 	actualChannelID := m.currentChannel.ID()
 	unknownChannelID := derive.ChannelID([derive.ChannelIDLength]byte{0x69})
 	require.NotEqual(t, actualChannelID, unknownChannelID)
-	unknownTxID := frameID{chID: unknownChannelID, frameNumber: 0}
+	unknownTxID := newTxID(frameID{chID: unknownChannelID, frameNumber: 0})
 	blockID := eth.BlockID{Number: 0, Hash: common.Hash{0x69}}
 	m.TxConfirmed(unknownTxID, blockID)
 	require.Empty(t, m.currentChannel.confirmedTransactions)
@@ -173,7 +173,7 @@ func TestChannelTxFailed(t *testing.T) {
 	m.currentChannel.channelBuilder.PushFrame(frame)
 	require.Equal(t, 1, m.currentChannel.PendingFrames())
 	returnedTxData, err := m.nextTxData(m.currentChannel)
-	expectedTxData := txData{frame}
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
@@ -183,7 +183,7 @@ func TestChannelTxFailed(t *testing.T) {
 
 	// Trying to mark an unknown pending transaction as failed
 	// shouldn't modify state
-	m.TxFailed(frameID{})
+	m.TxFailed(newTxID(frameID{}))
 	require.Equal(t, 0, m.currentChannel.PendingFrames())
 	require.Equal(t, expectedTxData, m.currentChannel.pendingTransactions[expectedChannelID])
 
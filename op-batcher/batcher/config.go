@@ -1,6 +1,7 @@
 package batcher
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -31,11 +32,17 @@ type Config struct {
 	PollInterval           time.Duration
 	MaxPendingTransactions uint64
 
+	// ChannelJournal is the path of the channel journal file, empty if disabled.
+	ChannelJournal string
+
 	// RollupConfig is queried at startup
 	Rollup *rollup.Config
 
 	// Channel builder parameters
 	Channel ChannelConfig
+
+	// L1 fee-aware submission throttling parameters
+	Throttle ThrottleConfig
 }
 
 // Check ensures that the [Config] is valid.
@@ -85,8 +92,28 @@ type CLIConfig struct {
 	// MaxL1TxSize is the maximum size of a batch tx submitted to L1.
 	MaxL1TxSize uint64
 
+	// TargetL1TxSize is the target size of a batch tx submitted to L1, up to
+	// which multiple frames are packed into a single tx (0 == single frame per tx).
+	TargetL1TxSize uint64
+
 	Stopped bool
 
+	// ChannelJournal is the path of the file that the state of pending
+	// channels is persisted to. If empty, pending channels are lost on restart.
+	ChannelJournal string
+
+	// ThrottleMaxL1BaseFee is the L1 base fee ceiling in gwei, above which
+	// non-urgent batch submission is held back (0 == disabled).
+	ThrottleMaxL1BaseFee uint64
+
+	// ThrottleBacklogThreshold is the size in bytes of unsubmitted L2 data at
+	// which the sequencer is asked to throttle block building (0 == disabled).
+	ThrottleBacklogThreshold uint64
+
+	// ThrottleSequencerInterval is the interval of blocks in which the
+	// throttled sequencer includes tx-pool transactions.
+	ThrottleSequencerInterval uint64
+
 	TxMgrConfig      txmgr.CLIConfig
 	RPCConfig        rpc.CLIConfig
 	LogConfig        oplog.CLIConfig
@@ -96,6 +123,9 @@ type CLIConfig struct {
 }
 
 func (c CLIConfig) Check() error {
+	if c.ThrottleBacklogThreshold > 0 && c.ThrottleSequencerInterval < 2 {
+		return errors.New("throttle sequencer interval must be at least 2")
+	}
 	if err := c.RPCConfig.Check(); err != nil {
 		return err
 	}
@@ -125,15 +155,20 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 		PollInterval:    ctx.Duration(flags.PollIntervalFlag.Name),
 
 		/* Optional Flags */
-		MaxPendingTransactions: ctx.Uint64(flags.MaxPendingTransactionsFlag.Name),
-		MaxChannelDuration:     ctx.Uint64(flags.MaxChannelDurationFlag.Name),
-		MaxL1TxSize:            ctx.Uint64(flags.MaxL1TxSizeBytesFlag.Name),
-		Stopped:                ctx.Bool(flags.StoppedFlag.Name),
-		TxMgrConfig:            txmgr.ReadCLIConfig(ctx),
-		RPCConfig:              rpc.ReadCLIConfig(ctx),
-		LogConfig:              oplog.ReadCLIConfig(ctx),
-		MetricsConfig:          opmetrics.ReadCLIConfig(ctx),
-		PprofConfig:            oppprof.ReadCLIConfig(ctx),
-		CompressorConfig:       compressor.ReadCLIConfig(ctx),
+		MaxPendingTransactions:    ctx.Uint64(flags.MaxPendingTransactionsFlag.Name),
+		MaxChannelDuration:        ctx.Uint64(flags.MaxChannelDurationFlag.Name),
+		MaxL1TxSize:               ctx.Uint64(flags.MaxL1TxSizeBytesFlag.Name),
+		TargetL1TxSize:            ctx.Uint64(flags.TargetL1TxSizeBytesFlag.Name),
+		Stopped:                   ctx.Bool(flags.StoppedFlag.Name),
+		ChannelJournal:            ctx.String(flags.ChannelJournalFlag.Name),
+		ThrottleMaxL1BaseFee:      ctx.Uint64(flags.ThrottleMaxL1BaseFeeFlag.Name),
+		ThrottleBacklogThreshold:  ctx.Uint64(flags.ThrottleBacklogThresholdFlag.Name),
+		ThrottleSequencerInterval: ctx.Uint64(flags.ThrottleSequencerIntervalFlag.Name),
+		TxMgrConfig:               txmgr.ReadCLIConfig(ctx),
+		RPCConfig:                 rpc.ReadCLIConfig(ctx),
+		LogConfig:                 oplog.ReadCLIConfig(ctx),
+		MetricsConfig:             opmetrics.ReadCLIConfig(ctx),
+		PprofConfig:               oppprof.ReadCLIConfig(ctx),
+		CompressorConfig:          compressor.ReadCLIConfig(ctx),
 	}
 }
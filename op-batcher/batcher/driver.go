@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opclient "github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -18,6 +19,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
@@ -35,12 +37,15 @@ type BatchSubmitter struct {
 
 	mutex   sync.Mutex
 	running bool
+	// tx send queue of the running loop, nil before the first start
+	queue *txmgr.Queue[txData]
 
 	// lastStoredBlock is the last block loaded into `state`. If it is empty it should be set to the l2 safe head.
 	lastStoredBlock eth.BlockID
 	lastL1Tip       eth.L1BlockRef
 
-	state *channelManager
+	state    *channelManager
+	throttle *submissionThrottle
 }
 
 // NewBatchSubmitterFromCLIConfig initializes the BatchSubmitter, gathering any resources
@@ -81,6 +86,7 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 		RollupNode:             rollupClient,
 		PollInterval:           cfg.PollInterval,
 		MaxPendingTransactions: cfg.MaxPendingTransactions,
+		ChannelJournal:         cfg.ChannelJournal,
 		NetworkTimeout:         cfg.TxMgrConfig.NetworkTimeout,
 		TxManager:              txManager,
 		Rollup:                 rcfg,
@@ -90,8 +96,14 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 			MaxChannelDuration: cfg.MaxChannelDuration,
 			SubSafetyMargin:    cfg.SubSafetyMargin,
 			MaxFrameSize:       cfg.MaxL1TxSize - 1, // subtract 1 byte for version
+			TargetTxSize:       cfg.TargetL1TxSize,
 			CompressorConfig:   cfg.CompressorConfig.Config(),
 		},
+		Throttle: ThrottleConfig{
+			MaxL1BaseFee:      new(big.Int).Mul(new(big.Int).SetUint64(cfg.ThrottleMaxL1BaseFee), big.NewInt(params.GWei)),
+			BacklogThreshold:  cfg.ThrottleBacklogThreshold,
+			SequencerInterval: cfg.ThrottleSequencerInterval,
+		},
 	}
 
 	// Validate the batcher config
@@ -115,10 +127,16 @@ func NewBatchSubmitter(ctx context.Context, cfg Config, l log.Logger, m metrics.
 
 	cfg.metr = m
 
+	state := NewChannelManager(l, m, cfg.Channel)
+	if cfg.ChannelJournal != "" {
+		state.journal = newChannelJournal(cfg.ChannelJournal)
+	}
+
 	return &BatchSubmitter{
-		Config: cfg,
-		txMgr:  cfg.TxManager,
-		state:  NewChannelManager(l, m, cfg.Channel),
+		Config:   cfg,
+		txMgr:    cfg.TxManager,
+		state:    state,
+		throttle: newSubmissionThrottle(l, m, cfg.Throttle),
 	}, nil
 
 }
@@ -138,6 +156,7 @@ func (l *BatchSubmitter) Start() error {
 	l.killCtx, l.cancelKillCtx = context.WithCancel(context.Background())
 	l.state.Clear()
 	l.lastStoredBlock = eth.BlockID{}
+	l.queue = txmgr.NewQueue[txData](l.killCtx, l.txMgr, l.MaxPendingTransactions)
 
 	l.wg.Add(1)
 	go l.loop()
@@ -180,6 +199,51 @@ func (l *BatchSubmitter) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Status returns the state of the channels and of the L2 blocks that are not yet submitted.
+func (l *BatchSubmitter) Status() *rpc.BatcherStatus {
+	l.mutex.Lock()
+	running, maxPending := l.running, l.MaxPendingTransactions
+	l.mutex.Unlock()
+
+	channels, unsubmitted := l.state.Status()
+	return &rpc.BatcherStatus{
+		Running:                running,
+		Channels:               channels,
+		Unsubmitted:            unsubmitted,
+		MaxPendingTransactions: maxPending,
+		TargetFrameSize:        l.state.TargetFrameSize(),
+	}
+}
+
+// FlushChannel closes the current channel and submits all of its frames right away.
+// It returns the ID of the flushed channel.
+func (l *BatchSubmitter) FlushChannel() (derive.ChannelID, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.running {
+		return derive.ChannelID{}, errors.New("batcher is not running")
+	}
+	return l.state.FlushChannel()
+}
+
+// SetMaxPendingTransactions changes the max number of concurrently pending transactions
+// (0 == no limit). If the batcher is running, the new limit applies right away, pending
+// transactions beyond a lowered limit are not canceled.
+func (l *BatchSubmitter) SetMaxPendingTransactions(maxPending uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.log.Info("Changed max pending transactions", "old", l.MaxPendingTransactions, "new", maxPending)
+	l.MaxPendingTransactions = maxPending
+	if l.queue != nil {
+		l.queue.SetMaxPending(maxPending)
+	}
+}
+
+// SetTargetFrameSize changes the target frame size of new channels.
+func (l *BatchSubmitter) SetTargetFrameSize(size uint64) error {
+	return l.state.SetTargetFrameSize(size)
+}
+
 // loadBlocksIntoState loads all blocks since the previous stored block
 // It does the following:
 // 1. Fetch the sync status of the sequencer
@@ -290,7 +354,13 @@ func (l *BatchSubmitter) loop() {
 	defer ticker.Stop()
 
 	receiptsCh := make(chan txmgr.TxReceipt[txData])
-	queue := txmgr.NewQueue[txData](l.killCtx, l.txMgr, l.MaxPendingTransactions)
+	queue := l.queue
+
+	if l.state.journal != nil {
+		if err := l.restoreState(l.shutdownCtx); err != nil {
+			l.log.Error("Failed to restore channels from the channel journal", "err", err)
+		}
+	}
 
 	for {
 		select {
@@ -304,6 +374,7 @@ func (l *BatchSubmitter) loop() {
 				l.state.Clear()
 				continue
 			}
+			l.updateSequencerThrottle(l.shutdownCtx)
 			l.publishStateToL1(queue, receiptsCh, false)
 		case r := <-receiptsCh:
 			l.handleReceipt(r)
@@ -313,6 +384,10 @@ func (l *BatchSubmitter) loop() {
 				l.log.Error("error closing the channel manager", "err", err)
 			}
 			l.publishStateToL1(queue, receiptsCh, true)
+			if l.throttle.sequencerInterval != 0 {
+				// the sequencer must not stay throttled while the batcher is stopped
+				l.setSequencerThrottle(l.killCtx, 0, l.state.Backlog())
+			}
 			return
 		}
 	}
@@ -355,17 +430,24 @@ func (l *BatchSubmitter) publishStateToL1(queue *txmgr.Queue[txData], receiptsCh
 // publishTxToL1 submits a single state tx to the L1
 func (l *BatchSubmitter) publishTxToL1(ctx context.Context, queue *txmgr.Queue[txData], receiptsCh chan txmgr.TxReceipt[txData]) error {
 	// send all available transactions
-	l1tip, err := l.l1Tip(ctx)
+	l1Head, err := l.l1Head(ctx)
 	if err != nil {
 		l.log.Error("Failed to query L1 tip", "error", err)
 		return err
 	}
+	l1tip := eth.InfoToL1BlockRef(eth.HeaderBlockInfo(l1Head))
 	l.recordL1Tip(l1tip)
 
+	if l.throttle.Hold(l1Head.BaseFee, l.state.HasUrgentData(l1tip.ID())) {
+		l.log.Trace("holding back transaction data")
+		return io.EOF
+	}
+
 	// Collect next transaction data
 	txdata, err := l.state.TxData(l1tip.ID())
 	if err == io.EOF {
 		l.log.Trace("no transaction data available")
+		l.throttle.Drained()
 		return err
 	} else if err != nil {
 		l.log.Error("unable to get tx data", "err", err)
@@ -394,6 +476,29 @@ func (l *BatchSubmitter) sendTransaction(txdata txData, queue *txmgr.Queue[txDat
 		GasLimit: intrinsicGas,
 	}
 	queue.Send(txdata, candidate, receiptsCh)
+	l.throttle.TxSent(intrinsicGas)
+}
+
+// updateSequencerThrottle records the size of the backlog of unsubmitted data,
+// and asks the rollup node to throttle or unthrottle the sequencer if the backlog
+// crossed the configured threshold.
+func (l *BatchSubmitter) updateSequencerThrottle(ctx context.Context) {
+	backlog := l.state.Backlog()
+	l.metr.RecordBacklog(backlog)
+	if interval, changed := l.throttle.SequencerThrottle(backlog); changed {
+		l.setSequencerThrottle(ctx, interval, backlog)
+	}
+}
+
+func (l *BatchSubmitter) setSequencerThrottle(ctx context.Context, interval uint64, backlog uint64) {
+	ctx, cancel := context.WithTimeout(ctx, l.NetworkTimeout)
+	defer cancel()
+	if err := l.RollupNode.SetSequencerThrottle(ctx, interval); err != nil {
+		l.log.Warn("Failed to set sequencer throttle", "interval", interval, "backlog", backlog, "err", err)
+		return
+	}
+	l.log.Info("Set sequencer throttle", "interval", interval, "backlog", backlog)
+	l.throttle.SequencerThrottleSet(interval)
 }
 
 func (l *BatchSubmitter) handleReceipt(r txmgr.TxReceipt[txData]) {
@@ -429,11 +534,20 @@ func (l *BatchSubmitter) recordConfirmedTx(id txID, receipt *types.Receipt) {
 // l1Tip gets the current L1 tip as a L1BlockRef. The passed context is assumed
 // to be a lifetime context, so it is internally wrapped with a network timeout.
 func (l *BatchSubmitter) l1Tip(ctx context.Context) (eth.L1BlockRef, error) {
+	head, err := l.l1Head(ctx)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return eth.InfoToL1BlockRef(eth.HeaderBlockInfo(head)), nil
+}
+
+// l1Head gets the header of the current L1 tip, with the same context handling as l1Tip.
+func (l *BatchSubmitter) l1Head(ctx context.Context) (*types.Header, error) {
 	tctx, cancel := context.WithTimeout(ctx, l.NetworkTimeout)
 	defer cancel()
 	head, err := l.L1Client.HeaderByNumber(tctx, nil)
 	if err != nil {
-		return eth.L1BlockRef{}, fmt.Errorf("getting latest L1 block: %w", err)
+		return nil, fmt.Errorf("getting latest L1 block: %w", err)
 	}
-	return eth.InfoToL1BlockRef(eth.HeaderBlockInfo(head)), nil
+	return head, nil
 }
@@ -0,0 +1,699 @@
+package batcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+type frameStatus string
+
+const (
+	// framePending frames were not handed to the tx manager yet.
+	framePending frameStatus = "pending"
+	// frameInFlight frames were handed to the tx manager, but no receipt was received yet.
+	frameInFlight frameStatus = "inflight"
+	// frameConfirmed frames are included in L1.
+	frameConfirmed frameStatus = "confirmed"
+)
+
+type journalFrame struct {
+	Number uint16
+	Status frameStatus
+	// Data is the frame data. It is kept for confirmed frames too, the frames of open channels are read again on restore.
+	Data []byte
+	// SentAt is the L1 head at the time the frame was handed to the tx manager.
+	SentAt uint64
+	// Inclusion is the L1 block that included the frame, if confirmed.
+	Inclusion *eth.BlockID
+}
+
+// journalChannel is the journaled state of a channel that is not fully submitted yet.
+type journalChannel struct {
+	ID     derive.ChannelID
+	Blocks []eth.BlockID
+	// Closed is true once all frames of the channel were output, TotalFrames is only set then.
+	Closed      bool
+	TotalFrames int
+	Frames      []journalFrame
+}
+
+// frame returns the journaled frame with the given number, or nil if it was not output yet.
+func (jc *journalChannel) frame(number uint16) *journalFrame {
+	for i := range jc.Frames {
+		if jc.Frames[i].Number == number {
+			return &jc.Frames[i]
+		}
+	}
+	return nil
+}
+
+// hasConfirmedFrame returns whether any frame of the channel is included in L1.
+func (jc *journalChannel) hasConfirmedFrame() bool {
+	for _, f := range jc.Frames {
+		if f.Status == frameConfirmed {
+			return true
+		}
+	}
+	return false
+}
+
+// records returns the journal records that restore the channel when replayed.
+func (jc *journalChannel) records() []journalRecord {
+	id := jc.ID
+	records := []journalRecord{{Op: journalOpOpen, Channel: &id}}
+	for i := range jc.Blocks {
+		records = append(records, journalRecord{Op: journalOpBlock, Channel: &id, Block: &jc.Blocks[i]})
+	}
+	for _, f := range jc.Frames {
+		records = append(records, journalRecord{Op: journalOpFrame, Channel: &id, Frame: f.Number, Data: f.Data})
+	}
+	if jc.Closed {
+		records = append(records, journalRecord{Op: journalOpClose, Channel: &id, TotalFrames: jc.TotalFrames})
+	}
+	for _, f := range jc.Frames {
+		tx := hexutil.Bytes(newTxID(frameID{chID: id, frameNumber: f.Number}))
+		switch f.Status {
+		case frameInFlight:
+			records = append(records, journalRecord{Op: journalOpSent, Tx: tx, SentAt: f.SentAt})
+		case frameConfirmed:
+			records = append(records, journalRecord{Op: journalOpConfirmed, Tx: tx, Inclusion: f.Inclusion})
+		}
+	}
+	return records
+}
+
+type journalOp string
+
+const (
+	// journalOpOpen records a new channel.
+	journalOpOpen journalOp = "open"
+	// journalOpBlock records a block that was added to a channel.
+	journalOpBlock journalOp = "block"
+	// journalOpFrame records a frame that was output by a channel.
+	journalOpFrame journalOp = "frame"
+	// journalOpClose records that all frames of a channel were output.
+	journalOpClose journalOp = "close"
+	// journalOpSent records the frames of a tx that was handed to the tx manager.
+	journalOpSent journalOp = "sent"
+	// journalOpConfirmed records the frames of a tx that was included in L1.
+	journalOpConfirmed journalOp = "confirmed"
+	// journalOpFailed records the frames of a tx that failed, they are submitted again.
+	journalOpFailed journalOp = "failed"
+	// journalOpRemove records that a channel was removed from the channel manager.
+	journalOpRemove journalOp = "remove"
+)
+
+// journalRecord is a single entry of the channel journal.
+// Channel records are keyed by the channel ID, tx records by the tx ID, which holds the IDs of the frames of the tx.
+type journalRecord struct {
+	Op          journalOp         `json:"op"`
+	Channel     *derive.ChannelID `json:"channel,omitempty"`
+	Block       *eth.BlockID      `json:"block,omitempty"`
+	Frame       uint16            `json:"frame,omitempty"`
+	Data        hexutil.Bytes     `json:"data,omitempty"`
+	TotalFrames int               `json:"totalFrames,omitempty"`
+	Tx          hexutil.Bytes     `json:"tx,omitempty"`
+	SentAt      uint64            `json:"sentAt,omitempty"`
+	Inclusion   *eth.BlockID      `json:"inclusion,omitempty"`
+}
+
+// replayJournal applies the journal records in order, and returns the channels that were not removed.
+func replayJournal(records []journalRecord) ([]journalChannel, error) {
+	var (
+		order    []*journalChannel
+		channels = make(map[derive.ChannelID]*journalChannel)
+	)
+	for i, r := range records {
+		switch r.Op {
+		case journalOpOpen, journalOpBlock, journalOpFrame, journalOpClose, journalOpRemove:
+			if r.Channel == nil {
+				return nil, fmt.Errorf("record %d: %s record without channel", i, r.Op)
+			}
+			if r.Op == journalOpOpen {
+				jc := &journalChannel{ID: *r.Channel}
+				channels[jc.ID] = jc
+				order = append(order, jc)
+				continue
+			}
+			jc, ok := channels[*r.Channel]
+			if !ok {
+				return nil, fmt.Errorf("record %d: %s record of unknown channel %s", i, r.Op, r.Channel)
+			}
+			switch r.Op {
+			case journalOpBlock:
+				if r.Block == nil {
+					return nil, fmt.Errorf("record %d: block record without block", i)
+				}
+				jc.Blocks = append(jc.Blocks, *r.Block)
+			case journalOpFrame:
+				jc.Frames = append(jc.Frames, journalFrame{Number: r.Frame, Status: framePending, Data: r.Data})
+			case journalOpClose:
+				jc.Closed = true
+				jc.TotalFrames = r.TotalFrames
+			case journalOpRemove:
+				delete(channels, jc.ID)
+			}
+		case journalOpSent, journalOpConfirmed, journalOpFailed:
+			if r.Op == journalOpConfirmed && r.Inclusion == nil {
+				return nil, fmt.Errorf("record %d: confirmed record without inclusion block", i)
+			}
+			for _, id := range txID(r.Tx).Frames() {
+				jc, ok := channels[id.chID]
+				if !ok {
+					// frames of removed channels may still be packed into a tx with other frames
+					continue
+				}
+				f := jc.frame(id.frameNumber)
+				if f == nil {
+					return nil, fmt.Errorf("record %d: %s record of unknown frame %v", i, r.Op, id)
+				}
+				switch r.Op {
+				case journalOpSent:
+					f.Status = frameInFlight
+					f.SentAt = r.SentAt
+				case journalOpConfirmed:
+					inclusion := *r.Inclusion
+					f.Status = frameConfirmed
+					f.Inclusion = &inclusion
+				case journalOpFailed:
+					f.Status = framePending
+					f.SentAt = 0
+				}
+			}
+		default:
+			return nil, fmt.Errorf("record %d: unknown op %q", i, r.Op)
+		}
+	}
+	result := make([]journalChannel, 0, len(channels))
+	for _, jc := range order {
+		if channels[jc.ID] == jc {
+			result = append(result, *jc)
+		}
+	}
+	return result, nil
+}
+
+// journalCompactionThreshold is the number of removed channels after which the journal is compacted.
+const journalCompactionThreshold = 16
+
+// channelJournal persists the channels of the channel manager to an append-only log of JSON records,
+// so that the submission of channels can be resumed after a restart. The records of removed channels
+// are dropped when the journal is compacted, which happens on restore and after every
+// journalCompactionThreshold removed channels.
+//
+// Appended records are not synced to disk. Losing the last records on a machine crash only causes
+// frames or blocks to be submitted again, which the derivation pipeline ignores.
+//
+// The tx manager does not expose the hashes of in-flight transactions, and they change when fees are bumped.
+// Instead of transaction hashes, the journal records the L1 head at the time a frame was sent,
+// and in-flight frames are reconciled by scanning L1 for batcher transactions from that block on.
+type channelJournal struct {
+	path string
+	// file is the journal opened for appending, nil until the first record is appended after a compaction
+	file *os.File
+	// removed is the number of removed channels since the last compaction
+	removed int
+}
+
+func newChannelJournal(path string) *channelJournal {
+	return &channelJournal{path: path}
+}
+
+// Load replays the journal and returns the channels that were not removed.
+// It returns no channels if the journal does not exist yet.
+// An incomplete last record, from a crash while it was written, is ignored.
+func (j *channelJournal) Load() ([]journalChannel, error) {
+	file, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("opening channel journal %s: %w", j.path, err)
+	}
+	defer file.Close()
+	var records []journalRecord
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading channel journal %s: %w", j.path, err)
+		}
+		var record journalRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("decoding channel journal %s record %d: %w", j.path, len(records), err)
+		}
+		records = append(records, record)
+	}
+	channels, err := replayJournal(records)
+	if err != nil {
+		return nil, fmt.Errorf("replaying channel journal %s: %w", j.path, err)
+	}
+	return channels, nil
+}
+
+// Append appends the records to the journal.
+func (j *channelJournal) Append(records ...journalRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("encoding channel journal record: %w", err)
+		}
+	}
+	if j.file == nil {
+		file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("opening channel journal %s: %w", j.path, err)
+		}
+		j.file = file
+	}
+	// a single write per call, so that a crash can only leave the last record incomplete
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing channel journal %s: %w", j.path, err)
+	}
+	return nil
+}
+
+// Remove records that the channel was removed, and compacts the journal
+// once enough channels were removed.
+func (j *channelJournal) Remove(id derive.ChannelID) error {
+	if err := j.Append(journalRecord{Op: journalOpRemove, Channel: &id}); err != nil {
+		return err
+	}
+	j.removed++
+	if j.removed < journalCompactionThreshold {
+		return nil
+	}
+	channels, err := j.Load()
+	if err != nil {
+		return err
+	}
+	return j.Save(channels)
+}
+
+// Save atomically replaces the journal with the records of the given channels.
+func (j *channelJournal) Save(channels []journalChannel) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range channels {
+		for _, r := range channels[i].records() {
+			if err := enc.Encode(r); err != nil {
+				return fmt.Errorf("encoding channel journal record: %w", err)
+			}
+		}
+	}
+	tmpFile := j.path + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening channel journal temp file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing channel journal temp file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("syncing channel journal temp file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing channel journal temp file: %w", err)
+	}
+	if err := j.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFile, j.path); err != nil {
+		return fmt.Errorf("replacing channel journal: %w", err)
+	}
+	j.removed = 0
+	return nil
+}
+
+// Close closes the journal file. It is reopened when the next record is appended.
+func (j *channelJournal) Close() error {
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	if err != nil {
+		return fmt.Errorf("closing channel journal %s: %w", j.path, err)
+	}
+	return nil
+}
+
+// journalChannel returns the journal entry of the channel.
+// The sent map holds the L1 head at the time each in-flight frame was sent.
+func (s *channel) journalChannel(sent map[txID]uint64) journalChannel {
+	cb := s.channelBuilder
+	jc := journalChannel{
+		ID:     s.ID(),
+		Blocks: make([]eth.BlockID, 0, len(cb.blocks)),
+		Closed: cb.IsClosed(),
+	}
+	if jc.Closed {
+		jc.TotalFrames = cb.TotalFrames()
+	}
+	for _, block := range cb.blocks {
+		jc.Blocks = append(jc.Blocks, eth.ToBlockID(block))
+	}
+	for _, frame := range cb.frames {
+		jc.Frames = append(jc.Frames, journalFrame{
+			Number: frame.id.frameNumber,
+			Status: framePending,
+			Data:   frame.data,
+		})
+	}
+	for id, tx := range s.pendingTransactions {
+		for _, frame := range tx.Frames() {
+			if frame.id.chID != s.ID() {
+				continue
+			}
+			jc.Frames = append(jc.Frames, journalFrame{
+				Number: frame.id.frameNumber,
+				Status: frameInFlight,
+				Data:   frame.data,
+				SentAt: sent[id],
+			})
+		}
+	}
+	for id, inclusion := range s.confirmedTransactions {
+		for _, frame := range id.Frames() {
+			if frame.chID != s.ID() {
+				continue
+			}
+			inclusion := inclusion
+			jc.Frames = append(jc.Frames, journalFrame{
+				Number:    frame.frameNumber,
+				Status:    frameConfirmed,
+				Inclusion: &inclusion,
+			})
+		}
+	}
+	sort.Slice(jc.Frames, func(i, j int) bool {
+		return jc.Frames[i].Number < jc.Frames[j].Number
+	})
+	return jc
+}
+
+// restoreChannel restores a journaled channel with the given blocks.
+// Frames that are not confirmed are queued for submission again.
+func restoreChannel(log log.Logger, metr metrics.Metricer, cfg ChannelConfig, jc *journalChannel, blocks []*types.Block) (*channel, error) {
+	cb, err := restoreChannelBuilder(cfg, jc.ID, blocks, jc.TotalFrames)
+	if err != nil {
+		return nil, fmt.Errorf("restoring channel %s: %w", jc.ID, err)
+	}
+	ch := &channel{
+		log:                   log,
+		metr:                  metr,
+		cfg:                   cfg,
+		channelBuilder:        cb,
+		pendingTransactions:   make(map[txID]txData),
+		confirmedTransactions: make(map[txID]eth.BlockID),
+	}
+	for _, f := range jc.Frames {
+		id := frameID{chID: jc.ID, frameNumber: f.Number}
+		if f.Status == frameConfirmed {
+			if f.Inclusion == nil {
+				return nil, fmt.Errorf("confirmed frame %v without inclusion block", id)
+			}
+			ch.confirmedTransactions[newTxID(id)] = *f.Inclusion
+			cb.FramePublished(f.Inclusion.Number)
+			continue
+		}
+		if len(f.Data) == 0 {
+			return nil, fmt.Errorf("frame %v without data", id)
+		}
+		cb.PushFrame(frameData{id: id, data: f.Data})
+		cb.outputBytes += len(f.Data)
+	}
+	return ch, nil
+}
+
+// l1BlockSource is the part of the L1 client that is needed to reconcile the journal with L1.
+type l1BlockSource interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// reconcileInFlightFrames marks in-flight frames of the journaled channels as confirmed,
+// if a successful batcher transaction containing the frame is found on L1, up to the given L1 head.
+// All other in-flight frames are marked as pending, to be submitted again.
+//
+// L1 is scanned from the earliest block an in-flight frame was sent at, but at most lookback blocks back from the
+// L1 head, which should be the channel timeout. Channels with frames that were included before that are timed out
+// by the time they would be resubmitted, so their data is submitted again in new channels.
+func reconcileInFlightFrames(ctx context.Context, l1 l1BlockSource, signer types.Signer, from common.Address, inbox common.Address,
+	channels []journalChannel, l1Head uint64, lookback uint64) error {
+	inFlight := make(map[frameID]*journalFrame)
+	start := uint64(math.MaxUint64)
+	for i := range channels {
+		for j := range channels[i].Frames {
+			f := &channels[i].Frames[j]
+			if f.Status != frameInFlight {
+				continue
+			}
+			inFlight[frameID{chID: channels[i].ID, frameNumber: f.Number}] = f
+			if f.SentAt < start {
+				start = f.SentAt
+			}
+		}
+	}
+
+	if l1Head > lookback && start < l1Head-lookback {
+		start = l1Head - lookback
+	}
+	for num := start; num <= l1Head && len(inFlight) > 0; num++ {
+		block, err := l1.BlockByNumber(ctx, new(big.Int).SetUint64(num))
+		if err != nil {
+			return fmt.Errorf("fetching L1 block %d: %w", num, err)
+		}
+		for _, tx := range block.Transactions() {
+			if to := tx.To(); to == nil || *to != inbox {
+				continue
+			}
+			if sender, err := types.Sender(signer, tx); err != nil || sender != from {
+				continue
+			}
+			frames, err := derive.ParseFrames(tx.Data())
+			if err != nil {
+				continue
+			}
+			var matched []frameID
+			for _, frame := range frames {
+				id := frameID{chID: frame.ID, frameNumber: frame.FrameNumber}
+				if _, ok := inFlight[id]; ok {
+					matched = append(matched, id)
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			receipt, err := l1.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return fmt.Errorf("fetching receipt of batcher tx %s: %w", tx.Hash(), err)
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				continue
+			}
+			inclusion := eth.BlockID{Hash: block.Hash(), Number: block.NumberU64()}
+			for _, id := range matched {
+				f := inFlight[id]
+				f.Status = frameConfirmed
+				f.Inclusion = &inclusion
+				delete(inFlight, id)
+			}
+		}
+	}
+
+	// anything that was not found on L1 has to be resubmitted
+	for _, f := range inFlight {
+		f.Status = framePending
+		f.SentAt = 0
+	}
+	return nil
+}
+
+// terminateOpenChannel closes a journaled channel that was still open, so that its submitted frames are not lost.
+// The compressed data of the channel ends after its last journaled frame, and the derivation pipeline reads the
+// batches of a channel until it fails to decode the next one. So the channel is closed with an additional empty
+// last frame, and only keeps the blocks whose batches can be read from its frames. It returns these blocks,
+// the other blocks of the channel have to be submitted again in new channels.
+func terminateOpenChannel(jc *journalChannel, blocks []*types.Block) ([]*types.Block, error) {
+	var data bytes.Buffer
+	for i, f := range jc.Frames {
+		if int(f.Number) != i {
+			return nil, fmt.Errorf("missing frame %d", i)
+		}
+		var frame derive.Frame
+		if err := frame.UnmarshalBinary(bytes.NewReader(f.Data)); err != nil {
+			return nil, fmt.Errorf("decoding frame %d: %w", i, err)
+		}
+		data.Write(frame.Data)
+	}
+	if data.Len() == 0 {
+		return nil, errors.New("no frame data")
+	}
+	readBatch, err := derive.BatchReader(&data, eth.L1BlockRef{})
+	if err != nil {
+		return nil, fmt.Errorf("reading frame data: %w", err)
+	}
+	n := 0
+	for ; n < len(blocks); n++ {
+		batch, err := readBatch()
+		if err != nil {
+			break
+		}
+		if batch.Batch.ParentHash != blocks[n].ParentHash() || batch.Batch.Timestamp != blocks[n].Time() {
+			return nil, fmt.Errorf("batch %d does not match block %s", n, blocks[n].Hash())
+		}
+	}
+	if n == 0 {
+		return nil, errors.New("no complete batch in frames")
+	}
+
+	last := derive.Frame{ID: jc.ID, FrameNumber: uint16(len(jc.Frames)), IsLast: true}
+	var buf bytes.Buffer
+	if err := last.MarshalBinary(&buf); err != nil {
+		return nil, fmt.Errorf("encoding last frame: %w", err)
+	}
+	jc.Frames = append(jc.Frames, journalFrame{Number: last.FrameNumber, Status: framePending, Data: buf.Bytes()})
+	jc.Blocks = jc.Blocks[:n]
+	jc.Closed = true
+	jc.TotalFrames = len(jc.Frames)
+	return blocks[:n], nil
+}
+
+// restoreState resumes the submission of the channels in the channel journal.
+// Channels are only restored if they continue the L2 safe head without gaps, and if they did not time out.
+// All L2 blocks of the restored channels count as loaded into the state.
+// The journal is replaced with the restored channels, also if restoring fails.
+func (l *BatchSubmitter) restoreState(ctx context.Context) error {
+	restored, last, err := l.loadJournaledChannels(ctx)
+	var tip common.Hash
+	if last != nil {
+		tip = last.Hash()
+		l.lastStoredBlock = eth.ToBlockID(last)
+	}
+	l.state.restoreChannels(restored, tip)
+	return err
+}
+
+// loadJournaledChannels restores the channels of the channel journal, and returns them with their last L2 block.
+func (l *BatchSubmitter) loadJournaledChannels(ctx context.Context) ([]*channel, *types.Block, error) {
+	channels, err := l.state.journal.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(channels) == 0 {
+		return nil, nil, nil
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, l.NetworkTimeout)
+	syncStatus, err := l.RollupNode.SyncStatus(cctx)
+	cancel()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get sync status: %w", err)
+	}
+	l1Head, err := l.l1Tip(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get L1 tip: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(l.Rollup.L1ChainID)
+	if err := reconcileInFlightFrames(ctx, l.L1Client, signer, l.TxManager.From(), l.Rollup.BatchInboxAddress,
+		channels, l1Head.Number, l.Channel.ChannelTimeout); err != nil {
+		return nil, nil, fmt.Errorf("reconciling channel journal with L1: %w", err)
+	}
+
+	var (
+		restored []*channel
+		last     *types.Block
+		next     = syncStatus.SafeL2.Number + 1
+	)
+	for i := range channels {
+		jc := &channels[i]
+		if len(jc.Blocks) == 0 {
+			continue
+		}
+		if jc.Blocks[len(jc.Blocks)-1].Number < next {
+			l.log.Info("Dropping journaled channel below safe head", "id", jc.ID, "safe", syncStatus.SafeL2)
+			continue
+		}
+		if jc.Blocks[0].Number != next {
+			l.log.Warn("Journaled channel does not continue the L2 chain, dropping remaining channels", "id", jc.ID, "first", jc.Blocks[0], "expected", next)
+			break
+		}
+		if !jc.Closed && !jc.hasConfirmedFrame() {
+			l.log.Info("Dropping open journaled channel without confirmed frames", "id", jc.ID)
+			break
+		}
+		blocks, err := l.loadJournaledBlocks(ctx, jc.Blocks)
+		if err != nil {
+			l.log.Warn("Failed to load blocks of journaled channel, dropping remaining channels", "id", jc.ID, "err", err)
+			break
+		}
+		if !jc.Closed {
+			numBlocks := len(blocks)
+			if blocks, err = terminateOpenChannel(jc, blocks); err != nil {
+				l.log.Warn("Failed to close open journaled channel, dropping remaining channels", "id", jc.ID, "err", err)
+				break
+			}
+			l.log.Info("Closed open journaled channel", "id", jc.ID, "blocks", len(blocks), "dropped_blocks", numBlocks-len(blocks))
+		}
+		ch, err := restoreChannel(l.log, l.metr, l.Channel, jc, blocks)
+		if err != nil {
+			l.log.Warn("Failed to restore journaled channel, dropping remaining channels", "id", jc.ID, "err", err)
+			break
+		}
+		fullySubmitted := ch.isFullySubmitted()
+		if ch.isTimedOut() || (!fullySubmitted && ch.channelBuilder.TimedOut(l1Head.Number)) {
+			l.log.Warn("Journaled channel timed out, dropping remaining channels", "id", jc.ID, "l1Head", l1Head)
+			break
+		}
+		next = jc.Blocks[len(jc.Blocks)-1].Number + 1
+		last = blocks[len(blocks)-1]
+		if fullySubmitted {
+			l.log.Info("Journaled channel is fully submitted", "id", jc.ID)
+			continue
+		}
+		l.log.Info("Restored journaled channel", "id", jc.ID, "blocks", len(blocks), "pending_frames", ch.PendingFrames(), "total_frames", ch.TotalFrames())
+		restored = append(restored, ch)
+	}
+	return restored, last, nil
+}
+
+// loadJournaledBlocks fetches the L2 blocks of a journaled channel, and checks that they are still canonical.
+func (l *BatchSubmitter) loadJournaledBlocks(ctx context.Context, ids []eth.BlockID) ([]*types.Block, error) {
+	blocks := make([]*types.Block, 0, len(ids))
+	for _, id := range ids {
+		cctx, cancel := context.WithTimeout(ctx, l.NetworkTimeout)
+		block, err := l.L2Client.BlockByNumber(cctx, new(big.Int).SetUint64(id.Number))
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("getting L2 block %d: %w", id.Number, err)
+		}
+		if block.Hash() != id.Hash {
+			return nil, fmt.Errorf("L2 block %d changed from %s to %s", id.Number, id.Hash, block.Hash())
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
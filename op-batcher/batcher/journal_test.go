@@ -0,0 +1,357 @@
+package batcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func TestChannelJournal(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j := newChannelJournal(path)
+	channels, err := j.Load()
+	require.NoError(err)
+	require.Empty(channels, "missing journal has no channels")
+
+	chA, chB := derive.ChannelID{1}, derive.ChannelID{2}
+	blocks := []eth.BlockID{{Hash: common.Hash{1}, Number: 1}, {Hash: common.Hash{2}, Number: 2}}
+	inclusion := eth.BlockID{Hash: common.Hash{0xaa}, Number: 12}
+	tx0 := hexutil.Bytes(newTxID(frameID{chID: chA, frameNumber: 0}))
+	tx12 := hexutil.Bytes(newTxID(frameID{chID: chA, frameNumber: 1}, frameID{chID: chA, frameNumber: 2}))
+	require.NoError(j.Append(
+		journalRecord{Op: journalOpOpen, Channel: &chA},
+		journalRecord{Op: journalOpBlock, Channel: &chA, Block: &blocks[0]},
+		journalRecord{Op: journalOpBlock, Channel: &chA, Block: &blocks[1]},
+		journalRecord{Op: journalOpFrame, Channel: &chA, Frame: 0, Data: []byte{1}},
+		journalRecord{Op: journalOpFrame, Channel: &chA, Frame: 1, Data: []byte{2}},
+		journalRecord{Op: journalOpSent, Tx: tx0, SentAt: 10},
+		journalRecord{Op: journalOpConfirmed, Tx: tx0, Inclusion: &inclusion},
+		journalRecord{Op: journalOpFrame, Channel: &chA, Frame: 2, Data: []byte{3}},
+		journalRecord{Op: journalOpClose, Channel: &chA, TotalFrames: 3},
+		journalRecord{Op: journalOpSent, Tx: tx12, SentAt: 11},
+		journalRecord{Op: journalOpOpen, Channel: &chB},
+	))
+	require.NoError(j.Append(journalRecord{Op: journalOpFailed, Tx: tx12}))
+	require.NoError(j.Append(journalRecord{Op: journalOpSent, Tx: tx12, SentAt: 13}))
+
+	expected := []journalChannel{{
+		ID:          chA,
+		Blocks:      blocks,
+		Closed:      true,
+		TotalFrames: 3,
+		Frames: []journalFrame{
+			{Number: 0, Status: frameConfirmed, Data: []byte{1}, SentAt: 10, Inclusion: &inclusion},
+			{Number: 1, Status: frameInFlight, Data: []byte{2}, SentAt: 13},
+			{Number: 2, Status: frameInFlight, Data: []byte{3}, SentAt: 13},
+		},
+	}, {
+		ID: chB,
+	}}
+	channels, err = j.Load()
+	require.NoError(err)
+	require.Equal(expected, channels)
+
+	// an incomplete last record is ignored
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(err)
+	_, err = file.WriteString(`{"op":"remove","chan`)
+	require.NoError(err)
+	require.NoError(file.Close())
+	channels, err = j.Load()
+	require.NoError(err)
+	require.Equal(expected, channels)
+
+	// compaction drops the incomplete record, and keeps the state of the channels
+	require.NoError(j.Save(channels))
+	require.NoError(j.Remove(chB))
+	channels, err = j.Load()
+	require.NoError(err)
+	expected[0].Frames[0].SentAt = 0 // not recorded for confirmed frames
+	require.Equal(expected[:1], channels)
+
+	for i := 1; i < journalCompactionThreshold; i++ {
+		id := derive.ChannelID{0xff, byte(i)}
+		require.NoError(j.Append(journalRecord{Op: journalOpOpen, Channel: &id}))
+		require.NoError(j.Remove(id))
+	}
+	require.Zero(j.removed, "compacted after enough removed channels")
+	data, err := os.ReadFile(path)
+	require.NoError(err)
+	require.Equal(len(channels[0].records()), bytes.Count(data, []byte("\n")), "only records of channel A are left")
+
+	require.NoError(j.Save(nil))
+	channels, err = j.Load()
+	require.NoError(err)
+	require.Empty(channels)
+	require.NoError(j.Close())
+}
+
+// TestChannelManagerJournal ensures that the channel manager journals its channels,
+// and that the unconfirmed frames of a journaled channel are resubmitted after restoring it.
+func TestChannelManagerJournal(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := ChannelConfig{
+		MaxFrameSize:   1000,
+		ChannelTimeout: 1000,
+		CompressorConfig: compressor.Config{
+			TargetNumFrames:  100,
+			TargetFrameSize:  1000,
+			ApproxComprRatio: 1.0,
+		},
+	}
+	journal := newChannelJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg)
+	m.journal = journal
+
+	a := newMiniL2Block(50_000)
+	require.NoError(m.AddL2Block(a))
+
+	tx0, err := m.TxData(eth.BlockID{Number: 10})
+	require.NoError(err)
+	require.True(m.currentChannel.channelBuilder.IsClosed())
+	tx1, err := m.TxData(eth.BlockID{Number: 11})
+	require.NoError(err)
+	m.TxConfirmed(tx0.ID(), eth.BlockID{Number: 12})
+
+	channels, err := journal.Load()
+	require.NoError(err)
+	require.Len(channels, 1)
+	jc := channels[0]
+	require.Equal(tx0.Frames()[0].id.chID, jc.ID)
+	require.Equal([]eth.BlockID{eth.ToBlockID(a)}, jc.Blocks)
+	require.True(jc.Closed)
+	require.Equal(2, jc.TotalFrames)
+	require.Len(jc.Frames, 2)
+	require.Equal(frameConfirmed, jc.Frames[0].Status)
+	require.Equal(&eth.BlockID{Number: 12}, jc.Frames[0].Inclusion)
+	require.Equal(tx0.Frames()[0].data, jc.Frames[0].Data)
+	require.Equal(frameInFlight, jc.Frames[1].Status)
+	require.Equal(uint64(11), jc.Frames[1].SentAt)
+	require.Equal(tx1.Frames()[0].data, jc.Frames[1].Data)
+
+	ch, err := restoreChannel(log, metrics.NoopMetrics, cfg, &jc, []*types.Block{a})
+	require.NoError(err)
+	require.Equal(jc.ID, ch.ID())
+	require.True(ch.IsFull())
+	require.Equal(jc.TotalFrames, ch.TotalFrames())
+	require.Equal(1, ch.PendingFrames())
+	require.Len(ch.confirmedTransactions, 1)
+	require.False(ch.isFullySubmitted())
+
+	restored := NewChannelManager(log, metrics.NoopMetrics, cfg)
+	restored.journal = journal
+	restored.restoreChannels([]*channel{ch}, a.Hash())
+	txdata, err := restored.TxData(eth.BlockID{Number: 13})
+	require.NoError(err)
+	require.Equal(tx1.ID(), txdata.ID(), "in-flight frame is resubmitted first")
+	require.Equal(tx1.Bytes(), txdata.Bytes())
+	restored.TxConfirmed(txdata.ID(), eth.BlockID{Number: 14})
+	require.Empty(restored.channelQueue, "restored channel is fully submitted")
+	channels, err = journal.Load()
+	require.NoError(err)
+	require.Empty(channels)
+
+	b := newMiniL2BlockWithNumberParent(0, big.NewInt(1), common.Hash{0xff})
+	require.ErrorIs(restored.AddL2Block(b), ErrReorg, "new blocks must extend the restored channels")
+}
+
+type testL1BlockSource struct {
+	blocks   map[uint64]*types.Block
+	receipts map[common.Hash]*types.Receipt
+}
+
+func (s *testL1BlockSource) BlockByNumber(_ context.Context, number *big.Int) (*types.Block, error) {
+	block, ok := s.blocks[number.Uint64()]
+	if !ok {
+		return types.NewBlock(&types.Header{Number: number}, nil, nil, nil, trie.NewStackTrie(nil)), nil
+	}
+	return block, nil
+}
+
+func (s *testL1BlockSource) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, ok := s.receipts[txHash]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx %s", txHash)
+	}
+	return receipt, nil
+}
+
+func TestReconcileInFlightFrames(t *testing.T) {
+	require := require.New(t)
+	batcherKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	batcher := crypto.PubkeyToAddress(batcherKey.PublicKey)
+	inbox := common.Address{0xff}
+	signer := types.LatestSignerForChainID(big.NewInt(900))
+	chID := derive.ChannelID{1}
+
+	l1 := &testL1BlockSource{blocks: make(map[uint64]*types.Block), receipts: make(map[common.Hash]*types.Receipt)}
+	nonce := uint64(0)
+	batcherTx := func(key *ecdsa.PrivateKey, frameNumber uint16, status uint64) *types.Transaction {
+		var buf bytes.Buffer
+		buf.WriteByte(derive.DerivationVersion0)
+		frame := derive.Frame{ID: chID, FrameNumber: frameNumber, Data: []byte{1}}
+		require.NoError(frame.MarshalBinary(&buf))
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID: big.NewInt(900),
+			Nonce:   nonce,
+			To:      &inbox,
+			Data:    buf.Bytes(),
+		})
+		nonce++
+		l1.receipts[tx.Hash()] = &types.Receipt{TxHash: tx.Hash(), Status: status}
+		return tx
+	}
+	txs := []*types.Transaction{
+		batcherTx(batcherKey, 1, types.ReceiptStatusSuccessful),
+		batcherTx(otherKey, 2, types.ReceiptStatusSuccessful),
+		batcherTx(batcherKey, 3, types.ReceiptStatusFailed),
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(21)}, txs, nil, nil, trie.NewStackTrie(nil))
+	l1.blocks[21] = block
+	l1.blocks[8] = types.NewBlock(&types.Header{Number: big.NewInt(8)}, []*types.Transaction{batcherTx(batcherKey, 5, types.ReceiptStatusSuccessful)}, nil, nil, trie.NewStackTrie(nil))
+
+	channels := []journalChannel{{
+		ID:          chID,
+		TotalFrames: 6,
+		Frames: []journalFrame{
+			{Number: 0, Status: frameConfirmed, Inclusion: &eth.BlockID{Number: 15}},
+			{Number: 1, Status: frameInFlight, Data: []byte{1}, SentAt: 20},
+			{Number: 2, Status: frameInFlight, Data: []byte{2}, SentAt: 20},
+			{Number: 3, Status: frameInFlight, Data: []byte{3}, SentAt: 20},
+			{Number: 4, Status: framePending, Data: []byte{4}},
+			{Number: 5, Status: frameInFlight, Data: []byte{5}, SentAt: 5},
+		},
+	}}
+	require.NoError(reconcileInFlightFrames(context.Background(), l1, signer, batcher, inbox, channels, 25, 10))
+
+	frames := channels[0].Frames
+	require.Equal(frameConfirmed, frames[1].Status, "included batcher tx")
+	require.Equal(&eth.BlockID{Hash: block.Hash(), Number: 21}, frames[1].Inclusion)
+	require.Equal(framePending, frames[2].Status, "tx from other sender is ignored")
+	require.Equal([]byte{2}, frames[2].Data)
+	require.Equal(framePending, frames[3].Status, "failed tx")
+	require.Equal(framePending, frames[4].Status)
+	require.Equal(framePending, frames[5].Status, "included before the lookback")
+}
+
+// newRandomL2Blocks returns a chain of L2 blocks, each with a transaction with the given size of random data,
+// so that the blocks do not compress.
+func newRandomL2Blocks(t *testing.T, rng *rand.Rand, n int, size int) []*types.Block {
+	blocks := make([]*types.Block, 0, n)
+	parent := common.Hash{}
+	for i := 0; i < n; i++ {
+		block := newMiniL2BlockWithNumberParent(0, big.NewInt(int64(i)), parent)
+		data := make([]byte, size)
+		_, err := rng.Read(data)
+		require.NoError(t, err)
+		txs := append(block.Transactions(), types.NewTx(&types.DynamicFeeTx{Data: data}))
+		block = types.NewBlock(block.Header(), txs, nil, nil, trie.NewStackTrie(nil))
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	return blocks
+}
+
+// TestChannelManagerJournalOpenChannel ensures that the frames of open channels are journaled,
+// and that a journaled open channel is closed with the blocks that can be derived from its frames.
+func TestChannelManagerJournalOpenChannel(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := ChannelConfig{
+		MaxFrameSize:   1000,
+		ChannelTimeout: 1000,
+		CompressorConfig: compressor.Config{
+			TargetNumFrames:  1000,
+			TargetFrameSize:  1000,
+			ApproxComprRatio: 1.0,
+		},
+	}
+	journal := newChannelJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg)
+	m.journal = journal
+
+	blocks := newRandomL2Blocks(t, rand.New(rand.NewSource(1)), 10, 10_000)
+	for _, block := range blocks {
+		require.NoError(m.AddL2Block(block))
+	}
+	var sent []txData
+	for {
+		tx, err := m.TxData(eth.BlockID{Number: 10})
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		sent = append(sent, tx)
+	}
+	require.NotEmpty(sent, "open channel outputs frames")
+	require.False(m.currentChannel.IsFull())
+	for _, tx := range sent {
+		m.TxConfirmed(tx.ID(), eth.BlockID{Number: 11})
+	}
+
+	channels, err := journal.Load()
+	require.NoError(err)
+	require.Len(channels, 1)
+	jc := channels[0]
+	require.False(jc.Closed)
+	require.Len(jc.Blocks, len(blocks))
+	require.Len(jc.Frames, len(sent))
+	require.True(jc.hasConfirmedFrame())
+
+	kept, err := terminateOpenChannel(&jc, blocks)
+	require.NoError(err)
+	require.NotEmpty(kept)
+	require.Less(len(kept), len(blocks), "the batches of the last blocks are not output yet")
+	require.Len(jc.Blocks, len(kept))
+	require.True(jc.Closed)
+	require.Equal(len(sent)+1, jc.TotalFrames)
+
+	// the derivation pipeline reads the batches of the kept blocks from the closed channel
+	ch := derive.NewChannel(jc.ID, eth.L1BlockRef{})
+	for _, f := range jc.Frames {
+		var frame derive.Frame
+		require.NoError(frame.UnmarshalBinary(bytes.NewReader(f.Data)))
+		require.NoError(ch.AddFrame(frame, eth.L1BlockRef{}))
+	}
+	require.True(ch.IsReady())
+	readBatch, err := derive.BatchReader(ch.Reader(), eth.L1BlockRef{})
+	require.NoError(err)
+	for _, block := range kept {
+		batch, err := readBatch()
+		require.NoError(err)
+		require.Equal(block.ParentHash(), batch.Batch.ParentHash)
+	}
+	_, err = readBatch()
+	require.Error(err)
+
+	restored, err := restoreChannel(log, metrics.NoopMetrics, cfg, &jc, kept)
+	require.NoError(err)
+	require.Equal(1, restored.PendingFrames(), "only the new last frame is pending")
+	require.Len(restored.confirmedTransactions, len(sent))
+}
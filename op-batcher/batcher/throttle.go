@@ -0,0 +1,116 @@
+package batcher
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+)
+
+type ThrottleConfig struct {
+	// MaxL1BaseFee is the L1 base fee ceiling in wei. While the L1 base fee is
+	// above it, submission of non-urgent batcher transactions is held back.
+	// If nil or zero, submission is never held back.
+	MaxL1BaseFee *big.Int
+
+	// BacklogThreshold is the size of unsubmitted L2 data in bytes, at which the
+	// sequencer is asked to throttle block building. The sequencer throttle is
+	// lifted again once the backlog drops below half of the threshold.
+	// If 0, the sequencer is never throttled.
+	BacklogThreshold uint64
+
+	// SequencerInterval is the interval of blocks in which the throttled
+	// sequencer includes tx-pool transactions. It must be at least 2 if the
+	// backlog threshold is set.
+	SequencerInterval uint64
+}
+
+// submissionThrottle is the L1 fee-aware submission policy of the batcher.
+// It is not safe for concurrent use.
+type submissionThrottle struct {
+	log  log.Logger
+	metr metrics.Metricer
+	cfg  ThrottleConfig
+
+	// L1 base fee of the last submission decision
+	baseFee *big.Int
+	// whether submission is currently held back
+	held bool
+	// L1 base fee at the time submission was first held back, until the backlog is drained.
+	// Nil if the submission was not held back.
+	heldFee *big.Int
+	// sequencer throttle interval that was last set at the rollup node
+	sequencerInterval uint64
+}
+
+func newSubmissionThrottle(log log.Logger, metr metrics.Metricer, cfg ThrottleConfig) *submissionThrottle {
+	return &submissionThrottle{
+		log:  log,
+		metr: metr,
+		cfg:  cfg,
+	}
+}
+
+// Hold returns whether the submission of tx data should be held back at the given L1 base fee.
+// Urgent data, that has to be submitted right away to not miss the channel timeout or
+// sequencing window, is never held back.
+func (t *submissionThrottle) Hold(baseFee *big.Int, urgent bool) bool {
+	t.baseFee = baseFee
+	ceiling := t.cfg.MaxL1BaseFee
+	hold := !urgent && ceiling != nil && ceiling.Sign() > 0 && baseFee != nil && baseFee.Cmp(ceiling) > 0
+	if hold && !t.held {
+		t.log.Info("Holding back batch submission, L1 base fee above ceiling", "base_fee", baseFee, "max_base_fee", ceiling)
+		if t.heldFee == nil {
+			t.heldFee = new(big.Int).Set(baseFee)
+		}
+	} else if !hold && t.held {
+		t.log.Info("Resuming batch submission", "base_fee", baseFee, "urgent", urgent)
+	}
+	t.held = hold
+	t.metr.RecordSubmissionThrottled(hold)
+	return hold
+}
+
+// TxSent records the cost saved by a tx with the given gas usage that was sent after the
+// submission was held back, compared to sending it at the time the submission was first held back.
+func (t *submissionThrottle) TxSent(gas uint64) {
+	if t.heldFee == nil || t.baseFee == nil || t.baseFee.Cmp(t.heldFee) >= 0 {
+		return
+	}
+	saved := new(big.Int).Sub(t.heldFee, t.baseFee)
+	saved.Mul(saved, new(big.Int).SetUint64(gas))
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(saved), big.NewFloat(params.GWei)).Float64()
+	t.metr.RecordThrottleCostSaved(gwei)
+}
+
+// Drained records that all data that was held back has been submitted.
+func (t *submissionThrottle) Drained() {
+	if t.heldFee != nil && !t.held {
+		t.log.Info("Drained held back batch submission backlog")
+		t.heldFee = nil
+	}
+}
+
+// SequencerThrottle returns the throttle interval that the sequencer should use with
+// the given backlog of unsubmitted data, and whether it differs from the last interval
+// that was set at the rollup node with SequencerThrottleSet.
+func (t *submissionThrottle) SequencerThrottle(backlog uint64) (uint64, bool) {
+	if t.cfg.BacklogThreshold == 0 {
+		return 0, false
+	}
+	interval := t.sequencerInterval
+	if backlog >= t.cfg.BacklogThreshold {
+		interval = t.cfg.SequencerInterval
+	} else if backlog < t.cfg.BacklogThreshold/2 {
+		interval = 0
+	}
+	return interval, interval != t.sequencerInterval
+}
+
+// SequencerThrottleSet records the throttle interval that was set at the rollup node.
+func (t *submissionThrottle) SequencerThrottleSet(interval uint64) {
+	t.sequencerInterval = interval
+	t.metr.RecordSequencerThrottle(interval)
+}
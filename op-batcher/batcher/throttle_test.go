@@ -0,0 +1,85 @@
+package batcher
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+type testThrottleMetrics struct {
+	metrics.Metricer
+	throttled bool
+	savedGwei float64
+}
+
+func (m *testThrottleMetrics) RecordSubmissionThrottled(held bool) {
+	m.throttled = held
+}
+
+func (m *testThrottleMetrics) RecordThrottleCostSaved(gwei float64) {
+	m.savedGwei += gwei
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei))
+}
+
+func TestSubmissionThrottleHold(t *testing.T) {
+	m := &testThrottleMetrics{Metricer: metrics.NoopMetrics}
+	th := newSubmissionThrottle(testlog.Logger(t, log.LvlCrit), m, ThrottleConfig{MaxL1BaseFee: gwei(50)})
+
+	require.False(t, th.Hold(gwei(50), false), "at ceiling")
+	require.False(t, m.throttled)
+	require.True(t, th.Hold(gwei(80), false), "above ceiling")
+	require.True(t, m.throttled)
+	require.False(t, th.Hold(gwei(90), true), "urgent data is never held back")
+	require.False(t, m.throttled)
+	th.TxSent(1000)
+	require.Zero(t, m.savedGwei, "no savings at a higher fee")
+
+	require.False(t, th.Hold(gwei(30), false))
+	th.TxSent(1000)
+	require.Equal(t, float64(50*1000), m.savedGwei, "savings relative to the fee when holding back started")
+	th.Drained()
+	th.TxSent(1000)
+	require.Equal(t, float64(50*1000), m.savedGwei, "no savings once the backlog is drained")
+
+	disabled := newSubmissionThrottle(testlog.Logger(t, log.LvlCrit), m, ThrottleConfig{})
+	require.False(t, disabled.Hold(gwei(1000), false))
+}
+
+func TestSubmissionThrottleSequencer(t *testing.T) {
+	th := newSubmissionThrottle(testlog.Logger(t, log.LvlCrit), metrics.NoopMetrics, ThrottleConfig{
+		BacklogThreshold:  1000,
+		SequencerInterval: 4,
+	})
+
+	interval, changed := th.SequencerThrottle(999)
+	require.False(t, changed)
+	require.Zero(t, interval)
+
+	interval, changed = th.SequencerThrottle(1000)
+	require.True(t, changed)
+	require.Equal(t, uint64(4), interval)
+	interval, changed = th.SequencerThrottle(1000)
+	require.True(t, changed, "not changed until set at the rollup node")
+	require.Equal(t, uint64(4), interval)
+	th.SequencerThrottleSet(4)
+
+	interval, changed = th.SequencerThrottle(500)
+	require.False(t, changed, "throttled until the backlog is below half of the threshold")
+	require.Equal(t, uint64(4), interval)
+	interval, changed = th.SequencerThrottle(499)
+	require.True(t, changed)
+	require.Zero(t, interval)
+
+	disabled := newSubmissionThrottle(testlog.Logger(t, log.LvlCrit), metrics.NoopMetrics, ThrottleConfig{})
+	_, changed = disabled.SequencerThrottle(1 << 30)
+	require.False(t, changed)
+}
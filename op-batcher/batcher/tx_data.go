@@ -1,58 +1,108 @@
 package batcher
 
 import (
+	"encoding/binary"
 	"fmt"
+	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 )
 
 // txData represents the data for a single transaction.
 //
-// Note: The batcher currently sends exactly one frame per transaction. This
-// might change in the future to allow for multiple frames from possibly
-// different channels.
+// A transaction holds one or more frames, possibly from different channels.
+// The channel manager packs multiple frames into a transaction only if the
+// target tx size is configured.
 type txData struct {
-	frame frameData
+	frames []frameData
+}
+
+// singleFrameTxData returns the tx data of a transaction with just the given frame.
+func singleFrameTxData(frame frameData) txData {
+	return txData{frames: []frameData{frame}}
 }
 
 // ID returns the id for this transaction data. It can be used as a map key.
 func (td *txData) ID() txID {
-	return td.frame.id
+	ids := make([]frameID, 0, len(td.frames))
+	for _, f := range td.frames {
+		ids = append(ids, f.id)
+	}
+	return newTxID(ids...)
 }
 
 // Bytes returns the transaction data. It's a version byte (0) followed by the
 // concatenated frames for this transaction.
 func (td *txData) Bytes() []byte {
-	return append([]byte{derive.DerivationVersion0}, td.frame.data...)
+	data := make([]byte, 0, td.Len())
+	data = append(data, derive.DerivationVersion0)
+	for _, f := range td.frames {
+		data = append(data, f.data...)
+	}
+	return data
 }
 
 func (td *txData) Len() int {
-	return 1 + len(td.frame.data)
+	l := 1
+	for _, f := range td.frames {
+		l += len(f.data)
+	}
+	return l
 }
 
-// Frame returns the single frame of this tx data.
-//
-// Note: when the batcher is changed to possibly send multiple frames per tx,
-// this should be changed to a func Frames() []frameData.
-func (td *txData) Frame() frameData {
-	return td.frame
+// Frames returns the frames of this tx data, in the order of submission.
+func (td *txData) Frames() []frameData {
+	return td.frames
 }
 
 // txID is an opaque identifier for a transaction.
 // It's internal fields should not be inspected after creation & are subject to change.
 // This ID must be trivially comparable & work as a map key.
 //
-// Note: transactions currently only hold a single frame, so it can be
-// identified by the frame. This needs to be changed once the batcher is changed
-// to send multiple frames per tx.
-type txID = frameID
+// It is made of the IDs of all frames of the transaction, packed into a string.
+type txID string
+
+const frameIDLength = derive.ChannelIDLength + 2
+
+func newTxID(frames ...frameID) txID {
+	var sb strings.Builder
+	sb.Grow(len(frames) * frameIDLength)
+	for _, f := range frames {
+		sb.Write(f.chID[:])
+		var num [2]byte
+		binary.BigEndian.PutUint16(num[:], f.frameNumber)
+		sb.Write(num[:])
+	}
+	return txID(sb.String())
+}
+
+// Frames returns the IDs of the frames of the transaction.
+func (id txID) Frames() []frameID {
+	frames := make([]frameID, 0, len(id)/frameIDLength)
+	for i := 0; i+frameIDLength <= len(id); i += frameIDLength {
+		var f frameID
+		copy(f.chID[:], id[i:i+derive.ChannelIDLength])
+		f.frameNumber = binary.BigEndian.Uint16([]byte(id[i+derive.ChannelIDLength : i+frameIDLength]))
+		frames = append(frames, f)
+	}
+	return frames
+}
 
 func (id txID) String() string {
-	return fmt.Sprintf("%s:%d", id.chID.String(), id.frameNumber)
+	return id.string(func(chID derive.ChannelID) string { return chID.String() })
 }
 
 // TerminalString implements log.TerminalStringer, formatting a string for console
 // output during logging.
 func (id txID) TerminalString() string {
-	return fmt.Sprintf("%s:%d", id.chID.TerminalString(), id.frameNumber)
+	return id.string(func(chID derive.ChannelID) string { return chID.TerminalString() })
+}
+
+func (id txID) string(chIDStringer func(derive.ChannelID) string) string {
+	frames := id.Frames()
+	parts := make([]string, 0, len(frames))
+	for _, f := range frames {
+		parts = append(parts, fmt.Sprintf("%s:%d", chIDStringer(f.chID), f.frameNumber))
+	}
+	return strings.Join(parts, "+")
 }
@@ -72,11 +72,40 @@ var (
 		Value:   120_000,
 		EnvVars: prefixEnvVars("MAX_L1_TX_SIZE_BYTES"),
 	}
+	TargetL1TxSizeBytesFlag = &cli.Uint64Flag{
+		Name:    "target-l1-tx-size-bytes",
+		Usage:   "The target size of a batch tx submitted to L1. Multiple frames are packed into a single tx up to this size. 0 to submit a single frame per tx.",
+		Value:   0,
+		EnvVars: prefixEnvVars("TARGET_L1_TX_SIZE_BYTES"),
+	}
 	StoppedFlag = &cli.BoolFlag{
 		Name:    "stopped",
 		Usage:   "Initialize the batcher in a stopped state. The batcher can be started using the admin_startBatcher RPC",
 		EnvVars: prefixEnvVars("STOPPED"),
 	}
+	ChannelJournalFlag = &cli.StringFlag{
+		Name:    "channel-journal",
+		Usage:   "Path of the file to persist the state of pending channels to, to resume their submission after a restart. Disabled if empty.",
+		EnvVars: prefixEnvVars("CHANNEL_JOURNAL"),
+	}
+	ThrottleMaxL1BaseFeeFlag = &cli.Uint64Flag{
+		Name:    "throttle-max-l1-base-fee",
+		Usage:   "The L1 base fee ceiling in gwei, above which batch submission is held back unless a channel would time out. 0 to disable.",
+		Value:   0,
+		EnvVars: prefixEnvVars("THROTTLE_MAX_L1_BASE_FEE"),
+	}
+	ThrottleBacklogThresholdFlag = &cli.Uint64Flag{
+		Name:    "throttle-backlog-threshold",
+		Usage:   "The size in bytes of unsubmitted L2 data at which the sequencer is asked to throttle block building. 0 to disable.",
+		Value:   0,
+		EnvVars: prefixEnvVars("THROTTLE_BACKLOG_THRESHOLD"),
+	}
+	ThrottleSequencerIntervalFlag = &cli.Uint64Flag{
+		Name:    "throttle-sequencer-interval",
+		Usage:   "The interval of blocks in which the throttled sequencer includes tx-pool transactions.",
+		Value:   4,
+		EnvVars: prefixEnvVars("THROTTLE_SEQUENCER_INTERVAL"),
+	}
 	// Legacy Flags
 	SequencerHDPathFlag = txmgr.SequencerHDPathFlag
 )
@@ -93,7 +122,12 @@ var optionalFlags = []cli.Flag{
 	MaxPendingTransactionsFlag,
 	MaxChannelDurationFlag,
 	MaxL1TxSizeBytesFlag,
+	TargetL1TxSizeBytesFlag,
 	StoppedFlag,
+	ChannelJournalFlag,
+	ThrottleMaxL1BaseFeeFlag,
+	ThrottleBacklogThresholdFlag,
+	ThrottleSequencerIntervalFlag,
 	SequencerHDPathFlag,
 }
 
@@ -41,6 +41,11 @@ type Metricer interface {
 	RecordBatchTxSuccess()
 	RecordBatchTxFailed()
 
+	RecordBacklog(bytes uint64)
+	RecordSubmissionThrottled(held bool)
+	RecordThrottleCostSaved(gwei float64)
+	RecordSequencerThrottle(interval uint64)
+
 	Document() []opmetrics.DocumentedMetric
 }
 
@@ -73,6 +78,11 @@ type Metrics struct {
 	channelOutputBytesTotal prometheus.Counter
 
 	batcherTxEvs opmetrics.EventVec
+
+	backlogBytes           prometheus.Gauge
+	submissionThrottled    prometheus.Gauge
+	throttleCostSavedTotal prometheus.Counter
+	sequencerThrottle      prometheus.Gauge
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -173,6 +183,27 @@ func NewMetrics(procName string) *Metrics {
 		}),
 
 		batcherTxEvs: opmetrics.NewEventVec(factory, ns, "", "batcher_tx", "BatcherTx", []string{"stage"}),
+
+		backlogBytes: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "backlog_bytes",
+			Help:      "Approximate size of the L2 data that is not submitted to L1 yet.",
+		}),
+		submissionThrottled: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "submission_throttled",
+			Help:      "1 if batch submission is held back because of the L1 base fee, 0 otherwise.",
+		}),
+		throttleCostSavedTotal: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "throttle_cost_saved_gwei_total",
+			Help:      "Estimated L1 base fee cost saved in gwei by holding back batch submission.",
+		}),
+		sequencerThrottle: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "sequencer_throttle_interval",
+			Help:      "Block interval in which the throttled sequencer includes tx-pool transactions, 0 if not throttled.",
+		}),
 	}
 }
 
@@ -295,6 +326,26 @@ func (m *Metrics) RecordBatchTxFailed() {
 	m.batcherTxEvs.Record(TxStageFailed)
 }
 
+func (m *Metrics) RecordBacklog(bytes uint64) {
+	m.backlogBytes.Set(float64(bytes))
+}
+
+func (m *Metrics) RecordSubmissionThrottled(held bool) {
+	if held {
+		m.submissionThrottled.Set(1)
+	} else {
+		m.submissionThrottled.Set(0)
+	}
+}
+
+func (m *Metrics) RecordThrottleCostSaved(gwei float64) {
+	m.throttleCostSavedTotal.Add(gwei)
+}
+
+func (m *Metrics) RecordSequencerThrottle(interval uint64) {
+	m.sequencerThrottle.Set(float64(interval))
+}
+
 // estimateBatchSize estimates the size of the batch
 func estimateBatchSize(block *types.Block) uint64 {
 	size := uint64(70) // estimated overhead of batch metadata
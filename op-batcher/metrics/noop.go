@@ -35,3 +35,8 @@ func (*noopMetrics) RecordChannelTimedOut(derive.ChannelID)       {}
 func (*noopMetrics) RecordBatchTxSubmitted() {}
 func (*noopMetrics) RecordBatchTxSuccess()   {}
 func (*noopMetrics) RecordBatchTxFailed()    {}
+
+func (*noopMetrics) RecordBacklog(uint64)            {}
+func (*noopMetrics) RecordSubmissionThrottled(bool)  {}
+func (*noopMetrics) RecordThrottleCostSaved(float64) {}
+func (*noopMetrics) RecordSequencerThrottle(uint64)  {}
@@ -2,11 +2,57 @@ package rpc
 
 import (
 	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
+// ChannelStatus is the submission state of a single channel of the batcher.
+type ChannelStatus struct {
+	ID derive.ChannelID `json:"id"`
+	// Open is true if the channel still accepts new L2 blocks.
+	Open bool `json:"open"`
+	// FullReason is the reason why the channel got closed, if it is not open.
+	FullReason string `json:"fullReason,omitempty"`
+
+	Blocks      int `json:"blocks"`
+	InputBytes  int `json:"inputBytes"`
+	OutputBytes int `json:"outputBytes"`
+	// ComprRatio is the ratio of output to input bytes, 0 if there's no input yet.
+	ComprRatio float64 `json:"comprRatio"`
+
+	FramesTotal     int `json:"framesTotal"`
+	FramesPending   int `json:"framesPending"`
+	FramesInFlight  int `json:"framesInFlight"`
+	FramesConfirmed int `json:"framesConfirmed"`
+}
+
+// L2Range is an inclusive range of L2 blocks.
+type L2Range struct {
+	Start eth.BlockID `json:"start"`
+	End   eth.BlockID `json:"end"`
+}
+
+type BatcherStatus struct {
+	Running  bool            `json:"running"`
+	Channels []ChannelStatus `json:"channels"`
+	// Unsubmitted is the range of L2 blocks that are loaded into the batcher,
+	// but not yet fully confirmed on L1. Nil if there are no such blocks.
+	Unsubmitted *L2Range `json:"unsubmitted"`
+
+	MaxPendingTransactions uint64 `json:"maxPendingTransactions"`
+	TargetFrameSize        uint64 `json:"targetFrameSize"`
+}
+
 type batcherClient interface {
 	Start() error
 	Stop(ctx context.Context) error
+	Status() *BatcherStatus
+	FlushChannel() (derive.ChannelID, error)
+	SetMaxPendingTransactions(maxPending uint64)
+	SetTargetFrameSize(size uint64) error
 }
 
 type adminAPI struct {
@@ -26,3 +72,27 @@ func (a *adminAPI) StartBatcher(_ context.Context) error {
 func (a *adminAPI) StopBatcher(ctx context.Context) error {
 	return a.b.Stop(ctx)
 }
+
+// BatcherStatus returns the state of the batcher's channels and of the L2 blocks
+// that are not yet submitted.
+func (a *adminAPI) BatcherStatus(_ context.Context) (*BatcherStatus, error) {
+	return a.b.Status(), nil
+}
+
+// FlushChannel closes the current channel and submits all of its frames, regardless
+// of the L1 fees. It returns the ID of the flushed channel.
+func (a *adminAPI) FlushChannel(_ context.Context) (derive.ChannelID, error) {
+	return a.b.FlushChannel()
+}
+
+// SetMaxPendingTransactions changes the max number of concurrently pending
+// batcher transactions (0 == no limit).
+func (a *adminAPI) SetMaxPendingTransactions(_ context.Context, maxPending hexutil.Uint64) error {
+	a.b.SetMaxPendingTransactions(uint64(maxPending))
+	return nil
+}
+
+// SetTargetFrameSize changes the target frame size of new channels.
+func (a *adminAPI) SetTargetFrameSize(_ context.Context, size hexutil.Uint64) error {
+	return a.b.SetTargetFrameSize(uint64(size))
+}
@@ -39,6 +39,10 @@ func (l *l2Chain) PayloadByNumber(_ context.Context, _ uint64) (*eth.ExecutionPa
 	return nil, nil
 }
 
+func (l *l2Chain) PayloadByHash(_ context.Context, _ common.Hash) (*eth.ExecutionPayload, error) {
+	return nil, nil
+}
+
 func Main(cliCtx *cli.Context) error {
 	log.Info("Initializing bootnode")
 	logCfg := oplog.ReadCLIConfig(cliCtx)
@@ -58,6 +58,13 @@ func (m *mockTxManager) From() common.Address {
 	return m.from
 }
 
+func (m *mockTxManager) Cancel(ctx context.Context, nonce uint64) (*ethtypes.Receipt, error) {
+	panic("not implemented")
+}
+
+func (m *mockTxManager) Close() {
+}
+
 func newTestCannonUpdater(t *testing.T, sendFails bool) (*cannonUpdater, *mockTxManager) {
 	logger := testlog.Logger(t, log.LvlInfo)
 	txMgr := &mockTxManager{
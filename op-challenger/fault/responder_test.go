@@ -59,6 +59,13 @@ func (m *mockTxManager) From() common.Address {
 	return m.from
 }
 
+func (m *mockTxManager) Cancel(ctx context.Context, nonce uint64) (*ethtypes.Receipt, error) {
+	panic("not implemented")
+}
+
+func (m *mockTxManager) Close() {
+}
+
 func newTestFaultResponder(t *testing.T, sendFails bool) (*faultResponder, *mockTxManager) {
 	log := testlog.Logger(t, log.LvlError)
 	mockTxMgr := &mockTxManager{}
@@ -30,6 +30,7 @@ type service struct {
 	logger  log.Logger
 	metrics metrics.Metricer
 	monitor *gameMonitor
+	txMgr   txmgr.TxManager
 }
 
 // NewService creates a new Service.
@@ -84,6 +85,7 @@ func NewService(ctx context.Context, logger log.Logger, cfg *config.Config) (*se
 		logger:  logger,
 		metrics: m,
 		monitor: monitor,
+		txMgr:   txMgr,
 	}, nil
 }
 
@@ -106,5 +108,6 @@ func ValidateAbsolutePrestate(ctx context.Context, trace types.TraceProvider, lo
 
 // MonitorGame monitors the fault dispute game and attempts to progress it.
 func (s *service) MonitorGame(ctx context.Context) error {
+	defer s.txMgr.Close()
 	return s.monitor.MonitorGames(ctx)
 }
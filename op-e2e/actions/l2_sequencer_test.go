@@ -160,3 +160,36 @@ func TestL2Sequencer_SequencerOnlyReorg(gt *testing.T) {
 	sequencer.ActBuildToL1HeadUnsafe(t)
 	require.Equal(t, newStatus.HeadL1.Hash, sequencer.SyncStatus().UnsafeL2.L1Origin.Hash, "build L2 chain with new correct L1 origins")
 }
+
+// TestL2Sequencer_RewindHead tests the admin API to rewind the unsafe head.
+// The op-geth engine ignores forkchoice updates to an older canonical block as head,
+// which the rewind must detect, instead of continuing with an inconsistent forkchoice state.
+func TestL2Sequencer_RewindHead(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, engine, sequencer := setupSequencerTest(t, sd, log)
+
+	sequencer.ActL2PipelineFull(t)
+	miner.ActEmptyBlock(t)
+	sequencer.ActL1HeadSignal(t)
+	sequencer.ActBuildToL1HeadUnsafe(t)
+
+	status := sequencer.SyncStatus()
+	require.Greater(t, status.UnsafeL2.Number, uint64(3), "built unsafe blocks")
+	require.Zero(t, status.SafeL2.Number, "no safe head progress")
+
+	cl := sequencer.RollupClient()
+	err := cl.RewindHead(t.Ctx(), status.UnsafeL2.Number+1, false)
+	require.ErrorContains(t, err, "after unsafe head")
+
+	err = cl.RewindHead(t.Ctx(), 2, false)
+	require.ErrorContains(t, err, "did not rewind")
+	require.Equal(t, status.UnsafeL2.Hash, engine.l2Chain.CurrentBlock().Hash(), "engine kept its head")
+
+	// derivation is reset, and continues from the unchanged engine state
+	sequencer.ActL2PipelineFull(t)
+	require.Equal(t, status.UnsafeL2, sequencer.SyncStatus().UnsafeL2)
+	require.Equal(t, status.SafeL2, sequencer.SyncStatus().SafeL2)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -30,6 +31,8 @@ type L2Verifier struct {
 	eng interface {
 		derive.Engine
 		L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+		L2BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L2BlockRef, error)
+		L2BlockRefByHash(ctx context.Context, l2Hash common.Hash) (eth.L2BlockRef, error)
 	}
 
 	// L2 rollup
@@ -51,6 +54,8 @@ type L2Verifier struct {
 type L2API interface {
 	derive.Engine
 	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+	L2BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L2BlockRef, error)
+	L2BlockRefByHash(ctx context.Context, l2Hash common.Hash) (eth.L2BlockRef, error)
 	InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error)
 	// GetProof returns a proof of the account, it may return a nil result without error if the address was not found.
 	GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error)
@@ -59,7 +64,7 @@ type L2API interface {
 
 func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cfg *rollup.Config, syncCfg *sync.Config) *L2Verifier {
 	metrics := &testutils.TestDerivationMetrics{}
-	pipeline := derive.NewDerivationPipeline(log, cfg, l1, eng, metrics, syncCfg)
+	pipeline := derive.NewDerivationPipeline(log, cfg, l1, eng, metrics, syncCfg, safedb.Disabled)
 	pipeline.Reset()
 
 	rollupNode := &L2Verifier{
@@ -81,7 +86,7 @@ func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cf
 	apis := []rpc.API{
 		{
 			Namespace:     "optimism",
-			Service:       node.NewNodeAPI(cfg, eng, backend, log, m),
+			Service:       node.NewNodeAPI(cfg, eng, backend, safedb.Disabled, log, m),
 			Public:        true,
 			Authenticated: false,
 		},
@@ -127,6 +132,21 @@ func (s *l2VerifierBackend) SequencerActive(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+func (s *l2VerifierBackend) RewindHead(ctx context.Context, num uint64, rewindSafe bool) error {
+	return s.verifier.RewindHead(ctx, num, rewindSafe)
+}
+
+func (s *l2VerifierBackend) SetSequencerThrottle(ctx context.Context, interval uint64) error {
+	return errors.New("throttling the L2Verifier sequencer is not supported")
+}
+
+// RewindHead rewinds the engine to the L2 block of the given number, and resets the derivation pipeline.
+func (s *L2Verifier) RewindHead(ctx context.Context, num uint64, rewindSafe bool) error {
+	_, _, err := driver.RewindEngine(ctx, s.eng, s.derivation, num, rewindSafe)
+	s.derivation.Reset()
+	return err
+}
+
 func (s *L2Verifier) L2Finalized() eth.L2BlockRef {
 	return s.derivation.Finalized()
 }
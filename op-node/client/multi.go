@@ -0,0 +1,421 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var errEndpointFailover = errors.New("RPC endpoint failover")
+
+// MultiRPCMetrics tracks the health of the endpoints of a MultiRPC, identified by their index.
+type MultiRPCMetrics interface {
+	RecordL1EndpointHealth(endpoint int, healthy bool, head uint64)
+	RecordL1EndpointError(endpoint int)
+	RecordL1ActiveEndpoint(endpoint int)
+	RecordL1QuorumFailure()
+}
+
+// MultiRPCConfig configures the failover and quorum behavior of a MultiRPC.
+type MultiRPCConfig struct {
+	// Quorum is the number of endpoints that must agree on the block hash of a block retrieved by number or label.
+	// Quorum is disabled if 0 or 1.
+	Quorum int
+	// HealthCheckInterval is the interval between checks of the latest block of each endpoint.
+	HealthCheckInterval time.Duration
+	// MaxHeadLag is the number of blocks that the latest block of an endpoint may lag behind
+	// the latest block of the other endpoints, before the endpoint is considered unhealthy.
+	MaxHeadLag uint64
+}
+
+type multiEndpoint struct {
+	rpc     RPC
+	healthy bool
+}
+
+// MultiRPC is a RPC that is backed by multiple RPC endpoints of the same chain.
+// Requests are served by the active endpoint: the first healthy endpoint, in order of preference.
+// The active endpoint fails over to the next healthy endpoint when it returns a non-RPC error,
+// or when its latest block lags too far behind that of the other endpoints.
+//
+// If a quorum is configured, requests for blocks by number are sent to all healthy endpoints,
+// and only accepted if the quorum agrees on the block hash.
+// Requests for blocks by label, like "latest", are first resolved to the highest block number
+// that the quorum of endpoints reached, and are then subject to the same quorum.
+// Requests by hash are not subject to the quorum, as the hash identifies the data.
+type MultiRPC struct {
+	log     log.Logger
+	cfg     MultiRPCConfig
+	metrics MultiRPCMetrics
+
+	mu        sync.RWMutex
+	endpoints []*multiEndpoint
+	active    int
+	// failover is closed and replaced upon every change of the active endpoint,
+	// to end any subscriptions on the previous active endpoint.
+	failover chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ RPC = (*MultiRPC)(nil)
+
+// NewMultiRPC creates a MultiRPC from the given endpoints, in order of preference,
+// and starts checking the health of the endpoints in the background until the MultiRPC is closed.
+func NewMultiRPC(log log.Logger, endpoints []RPC, cfg MultiRPCConfig, metrics MultiRPCMetrics) (*MultiRPC, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no RPC endpoints")
+	}
+	if cfg.Quorum > len(endpoints) {
+		return nil, fmt.Errorf("quorum of %d cannot be met by %d endpoints", cfg.Quorum, len(endpoints))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &MultiRPC{
+		log:      log,
+		cfg:      cfg,
+		metrics:  metrics,
+		failover: make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	for _, e := range endpoints {
+		m.endpoints = append(m.endpoints, &multiEndpoint{rpc: e, healthy: true})
+	}
+	metrics.RecordL1ActiveEndpoint(0)
+	if cfg.HealthCheckInterval > 0 {
+		m.wg.Add(1)
+		go m.healthLoop()
+	}
+	return m, nil
+}
+
+func (m *MultiRPC) Close() {
+	m.cancel()
+	m.wg.Wait()
+	for _, e := range m.endpoints {
+		e.rpc.Close()
+	}
+}
+
+func (m *MultiRPC) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	if m.cfg.Quorum > 1 && method == "eth_getBlockByNumber" && len(args) > 0 {
+		if id, ok := args[0].(string); ok {
+			if !strings.HasPrefix(id, "0x") {
+				// Labels like "latest" legitimately differ between endpoints,
+				// so the label is resolved to a block number that the quorum has reached first.
+				num, found, err := m.quorumLabel(ctx, method, id)
+				if err != nil {
+					return err
+				}
+				if !found {
+					return json.Unmarshal([]byte("null"), result)
+				}
+				args = append([]any{hexutil.EncodeUint64(num)}, args[1:]...)
+			}
+			return m.quorumCall(ctx, result, method, args...)
+		}
+	}
+	return m.failoverCall(ctx, func(e RPC) error {
+		return e.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (m *MultiRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return m.failoverCall(ctx, func(e RPC) error {
+		return e.BatchCallContext(ctx, b)
+	})
+}
+
+// EthSubscribe subscribes on the active endpoint.
+// The subscription fails when the active endpoint changes, so the caller can resubscribe on the new active endpoint.
+func (m *MultiRPC) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	return m.subscribe(ctx, func(e RPC) (ethereum.Subscription, error) {
+		return e.EthSubscribe(ctx, channel, args...)
+	})
+}
+
+// Subscribe subscribes on the active endpoint, and fails upon failover, like EthSubscribe.
+func (m *MultiRPC) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	return m.subscribe(ctx, func(e RPC) (ethereum.Subscription, error) {
+		return e.Subscribe(ctx, namespace, channel, args...)
+	})
+}
+
+func (m *MultiRPC) subscribe(ctx context.Context, fn func(e RPC) (ethereum.Subscription, error)) (ethereum.Subscription, error) {
+	var sub ethereum.Subscription
+	var failover chan struct{}
+	err := m.failoverCall(ctx, func(e RPC) error {
+		m.mu.RLock()
+		failover = m.failover
+		m.mu.RUnlock()
+		var err error
+		sub, err = fn(e)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		select {
+		case err := <-sub.Err():
+			return err
+		case <-failover:
+			return errEndpointFailover
+		case <-quit:
+			return nil
+		}
+	}), nil
+}
+
+// failoverCall runs the call against the active endpoint,
+// and fails over to the next healthy endpoint upon an endpoint error, until all endpoints were tried.
+func (m *MultiRPC) failoverCall(ctx context.Context, fn func(e RPC) error) error {
+	var err error
+	for attempt := 0; attempt < len(m.endpoints); attempt++ {
+		m.mu.RLock()
+		index := m.active
+		e := m.endpoints[index].rpc
+		m.mu.RUnlock()
+		err = fn(e)
+		if !isEndpointError(ctx, err) {
+			return err
+		}
+		m.log.Warn("RPC endpoint failed", "endpoint", index, "err", err)
+		m.markUnhealthy(index)
+	}
+	return err
+}
+
+type quorumResult struct {
+	index int
+	raw   json.RawMessage
+	err   error
+}
+
+// quorumEndpoints returns the indices of the healthy endpoints, if there are enough of them to reach the quorum.
+func (m *MultiRPC) quorumEndpoints() ([]int, error) {
+	m.mu.RLock()
+	var indices []int
+	for i, e := range m.endpoints {
+		if e.healthy {
+			indices = append(indices, i)
+		}
+	}
+	m.mu.RUnlock()
+	if len(indices) < m.cfg.Quorum {
+		m.metrics.RecordL1QuorumFailure()
+		return nil, fmt.Errorf("only %d healthy endpoints, need %d for quorum", len(indices), m.cfg.Quorum)
+	}
+	return indices, nil
+}
+
+// callAll runs the call against the given endpoints concurrently. The returned channel receives one result per endpoint.
+func (m *MultiRPC) callAll(ctx context.Context, indices []int, method string, args ...any) <-chan quorumResult {
+	results := make(chan quorumResult, len(indices))
+	for _, i := range indices {
+		go func(i int) {
+			var raw json.RawMessage
+			err := m.endpoints[i].rpc.CallContext(ctx, &raw, method, args...)
+			results <- quorumResult{index: i, raw: raw, err: err}
+		}(i)
+	}
+	return results
+}
+
+// quorumCall runs the call against all healthy endpoints,
+// and returns the result that the quorum of endpoints agrees on by block hash.
+func (m *MultiRPC) quorumCall(ctx context.Context, result any, method string, args ...any) error {
+	indices, err := m.quorumEndpoints()
+	if err != nil {
+		return err
+	}
+	results := m.callAll(ctx, indices, method, args...)
+
+	votes := make(map[common.Hash]int)
+	var lastErr error
+	for range indices {
+		res := <-results
+		if res.err != nil {
+			if isEndpointError(ctx, res.err) {
+				m.markUnhealthy(res.index)
+			}
+			lastErr = res.err
+			continue
+		}
+		// a null result, i.e. the block is not found, is voted for as the zero hash
+		var block struct {
+			Hash common.Hash `json:"hash"`
+		}
+		if err := json.Unmarshal(res.raw, &block); err != nil {
+			lastErr = fmt.Errorf("invalid block from endpoint %d: %w", res.index, err)
+			continue
+		}
+		votes[block.Hash] += 1
+		if votes[block.Hash] >= m.cfg.Quorum {
+			return json.Unmarshal(res.raw, result)
+		}
+	}
+	m.metrics.RecordL1QuorumFailure()
+	m.log.Warn("RPC endpoints did not reach quorum", "method", method, "id", args[0], "votes", len(votes), "err", lastErr)
+	if lastErr != nil {
+		return fmt.Errorf("no quorum of %d endpoints on %s result: %w", m.cfg.Quorum, method, lastErr)
+	}
+	return fmt.Errorf("no quorum of %d endpoints on %s result", m.cfg.Quorum, method)
+}
+
+// quorumLabel resolves a block label like "latest" to the highest block number that the quorum of endpoints reached,
+// so that a single endpoint cannot serve a block that the other endpoints have not seen.
+// Found is false if the quorum of endpoints does not have a block with the label.
+func (m *MultiRPC) quorumLabel(ctx context.Context, method string, label string) (num uint64, found bool, err error) {
+	indices, err := m.quorumEndpoints()
+	if err != nil {
+		return 0, false, err
+	}
+	results := m.callAll(ctx, indices, method, label, false)
+
+	var nums []uint64
+	var responses int
+	var lastErr error
+	for range indices {
+		res := <-results
+		if res.err != nil {
+			if isEndpointError(ctx, res.err) {
+				m.markUnhealthy(res.index)
+			}
+			lastErr = res.err
+			continue
+		}
+		var block *struct {
+			Number hexutil.Uint64 `json:"number"`
+		}
+		if err := json.Unmarshal(res.raw, &block); err != nil {
+			lastErr = fmt.Errorf("invalid block from endpoint %d: %w", res.index, err)
+			continue
+		}
+		responses += 1
+		if block != nil {
+			nums = append(nums, uint64(block.Number))
+		}
+	}
+	if responses < m.cfg.Quorum {
+		m.metrics.RecordL1QuorumFailure()
+		m.log.Warn("RPC endpoints did not reach quorum", "method", method, "id", label, "responses", responses, "err", lastErr)
+		return 0, false, fmt.Errorf("no quorum of %d endpoints on %s block: %w", m.cfg.Quorum, label, lastErr)
+	}
+	if len(nums) < m.cfg.Quorum {
+		return 0, false, nil
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] > nums[j] })
+	return nums[m.cfg.Quorum-1], true, nil
+}
+
+func (m *MultiRPC) markUnhealthy(index int) {
+	m.metrics.RecordL1EndpointError(index)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpoints[index].healthy = false
+	if index == m.active {
+		// Fail over to the next endpoint, even if it was unhealthy at the last check: it may have recovered.
+		m.setActive((index + 1) % len(m.endpoints))
+	}
+}
+
+// setActive changes the active endpoint. The caller must hold the write lock.
+func (m *MultiRPC) setActive(index int) {
+	if index == m.active {
+		return
+	}
+	m.log.Warn("Switching active RPC endpoint", "prev", m.active, "next", index)
+	m.active = index
+	close(m.failover)
+	m.failover = make(chan struct{})
+	m.metrics.RecordL1ActiveEndpoint(index)
+}
+
+func (m *MultiRPC) healthLoop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.checkHealth()
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// checkHealth retrieves the latest block of every endpoint, and marks the endpoints as healthy if they are
+// no more than MaxHeadLag blocks behind the highest latest block. The most preferred healthy endpoint becomes active.
+func (m *MultiRPC) checkHealth() {
+	heads := make([]uint64, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range m.endpoints {
+		wg.Add(1)
+		go func(i int, e RPC) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(m.ctx, m.cfg.HealthCheckInterval)
+			defer cancel()
+			var header struct {
+				Number hexutil.Uint64 `json:"number"`
+			}
+			errs[i] = e.CallContext(ctx, &header, "eth_getBlockByNumber", "latest", false)
+			heads[i] = uint64(header.Number)
+		}(i, e.rpc)
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for i, head := range heads {
+		if errs[i] == nil && head > maxHead {
+			maxHead = head
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	active := -1
+	for i, e := range m.endpoints {
+		healthy := errs[i] == nil && heads[i]+m.cfg.MaxHeadLag >= maxHead
+		if e.healthy && !healthy {
+			m.log.Warn("RPC endpoint is unhealthy", "endpoint", i, "head", heads[i], "max_head", maxHead, "err", errs[i])
+		} else if !e.healthy && healthy {
+			m.log.Info("RPC endpoint recovered", "endpoint", i, "head", heads[i])
+		}
+		e.healthy = healthy
+		m.metrics.RecordL1EndpointHealth(i, healthy, heads[i])
+		if healthy && active < 0 {
+			active = i
+		}
+	}
+	if active >= 0 {
+		m.setActive(active)
+	}
+}
+
+// isEndpointError returns true if the error is attributed to the endpoint,
+// rather than to the request itself or the context of the caller.
+func isEndpointError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+type fakeBlock struct {
+	Hash   common.Hash    `json:"hash"`
+	Number hexutil.Uint64 `json:"number"`
+}
+
+// fakeEndpoint serves the same block for every block request, or fails with err.
+// If blocks is set, the block is looked up by the requested number or label instead, and null is served if not found.
+type fakeEndpoint struct {
+	block  fakeBlock
+	blocks map[string]fakeBlock
+	err    error
+	calls  int
+	feed   event.Feed
+}
+
+func (f *fakeEndpoint) Close() {}
+
+func (f *fakeEndpoint) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	f.calls += 1
+	if f.err != nil {
+		return f.err
+	}
+	var block any = f.block
+	if f.blocks != nil {
+		block = nil
+		if b, ok := f.blocks[args[0].(string)]; ok {
+			block = b
+		}
+	}
+	data, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (f *fakeEndpoint) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return f.err
+}
+
+func (f *fakeEndpoint) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.feed.Subscribe(channel.(chan fakeBlock)), nil
+}
+
+func (f *fakeEndpoint) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	return f.EthSubscribe(ctx, channel, args...)
+}
+
+type testMultiMetrics struct {
+	active         int
+	errors         map[int]int
+	healthy        map[int]bool
+	quorumFailures int
+}
+
+func newTestMultiMetrics() *testMultiMetrics {
+	return &testMultiMetrics{errors: make(map[int]int), healthy: make(map[int]bool)}
+}
+
+func (m *testMultiMetrics) RecordL1EndpointHealth(endpoint int, healthy bool, head uint64) {
+	m.healthy[endpoint] = healthy
+}
+
+func (m *testMultiMetrics) RecordL1EndpointError(endpoint int) {
+	m.errors[endpoint] += 1
+}
+
+func (m *testMultiMetrics) RecordL1ActiveEndpoint(endpoint int) {
+	m.active = endpoint
+}
+
+func (m *testMultiMetrics) RecordL1QuorumFailure() {
+	m.quorumFailures += 1
+}
+
+func TestMultiRPCFailover(t *testing.T) {
+	a := &fakeEndpoint{err: errors.New("connection refused")}
+	b := &fakeEndpoint{block: fakeBlock{Hash: common.Hash{0xb}}}
+	m := newTestMultiMetrics()
+	multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b}, MultiRPCConfig{}, m)
+	require.NoError(t, err)
+	defer multi.Close()
+
+	var block fakeBlock
+	require.NoError(t, multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "latest", false))
+	require.Equal(t, b.block, block)
+	require.Equal(t, 1, m.active)
+	require.Equal(t, 1, m.errors[0])
+
+	// the failed endpoint is not retried while the next endpoint is active
+	require.NoError(t, multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "latest", false))
+	require.Equal(t, 1, a.calls)
+	require.Equal(t, 2, b.calls)
+}
+
+func TestMultiRPCRequestErrorNoFailover(t *testing.T) {
+	a := &fakeEndpoint{err: ethereum.NotFound}
+	b := &fakeEndpoint{}
+	m := newTestMultiMetrics()
+	multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b}, MultiRPCConfig{}, m)
+	require.NoError(t, err)
+	defer multi.Close()
+
+	var block fakeBlock
+	err = multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x1", false)
+	require.ErrorIs(t, err, ethereum.NotFound)
+	require.Equal(t, 0, m.active)
+	require.Equal(t, 0, b.calls)
+}
+
+func TestMultiRPCQuorum(t *testing.T) {
+	good := fakeBlock{Hash: common.Hash{0xaa}, Number: 1}
+	bad := fakeBlock{Hash: common.Hash{0xbb}, Number: 1}
+
+	t.Run("agree", func(t *testing.T) {
+		a, b, c := &fakeEndpoint{block: good}, &fakeEndpoint{block: bad}, &fakeEndpoint{block: good}
+		m := newTestMultiMetrics()
+		multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b, c}, MultiRPCConfig{Quorum: 2}, m)
+		require.NoError(t, err)
+		defer multi.Close()
+
+		var block fakeBlock
+		require.NoError(t, multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x1", false))
+		require.Equal(t, good, block)
+		require.Equal(t, 0, m.quorumFailures)
+	})
+	t.Run("disagree", func(t *testing.T) {
+		a, b, c := &fakeEndpoint{block: good}, &fakeEndpoint{block: bad}, &fakeEndpoint{err: errors.New("connection refused")}
+		m := newTestMultiMetrics()
+		multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b, c}, MultiRPCConfig{Quorum: 2}, m)
+		require.NoError(t, err)
+		defer multi.Close()
+
+		var block fakeBlock
+		require.ErrorContains(t, multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "0x1", false), "no quorum")
+		require.Equal(t, 1, m.quorumFailures)
+		require.Equal(t, 1, m.errors[2])
+	})
+	t.Run("labels resolve to quorum head", func(t *testing.T) {
+		block1, block2 := fakeBlock{Hash: common.Hash{0x1}, Number: 1}, fakeBlock{Hash: common.Hash{0x2}, Number: 2}
+		a := &fakeEndpoint{blocks: map[string]fakeBlock{"latest": block2, "0x1": block1, "0x2": block2}}
+		b := &fakeEndpoint{blocks: map[string]fakeBlock{"latest": block1, "0x1": block1}}
+		c := &fakeEndpoint{blocks: map[string]fakeBlock{"latest": {Hash: common.Hash{0xff}, Number: 9}, "0x1": block1, "0x2": block2}}
+		m := newTestMultiMetrics()
+		multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b, c}, MultiRPCConfig{Quorum: 2}, m)
+		require.NoError(t, err)
+		defer multi.Close()
+
+		var block fakeBlock
+		require.NoError(t, multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "latest", false))
+		require.Equal(t, block2, block, "highest block that two endpoints reached, agreed on by hash")
+
+		c.blocks["0x2"] = fakeBlock{Hash: common.Hash{0xff}, Number: 2}
+		require.ErrorContains(t, multi.CallContext(context.Background(), &block, "eth_getBlockByNumber", "latest", false), "no quorum")
+		require.Equal(t, 1, m.quorumFailures, "a single endpoint cannot make its latest block accepted")
+
+		var missing *fakeBlock
+		require.NoError(t, multi.CallContext(context.Background(), &missing, "eth_getBlockByNumber", "finalized", false))
+		require.Nil(t, missing, "label not known to the quorum")
+	})
+}
+
+func TestMultiRPCHealthCheck(t *testing.T) {
+	a := &fakeEndpoint{block: fakeBlock{Number: 100}}
+	b := &fakeEndpoint{block: fakeBlock{Number: 110}}
+	m := newTestMultiMetrics()
+	multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b}, MultiRPCConfig{HealthCheckInterval: time.Second, MaxHeadLag: 5}, m)
+	require.NoError(t, err)
+	defer multi.Close()
+
+	multi.checkHealth()
+	require.False(t, m.healthy[0], "lagging endpoint is unhealthy")
+	require.True(t, m.healthy[1])
+	require.Equal(t, 1, m.active)
+
+	a.block.Number = 108
+	multi.checkHealth()
+	require.True(t, m.healthy[0], "recovered endpoint is healthy")
+	require.Equal(t, 0, m.active, "most preferred healthy endpoint becomes active again")
+}
+
+func TestMultiRPCSubscriptionEndsOnFailover(t *testing.T) {
+	a := &fakeEndpoint{}
+	b := &fakeEndpoint{}
+	m := newTestMultiMetrics()
+	multi, err := NewMultiRPC(testlog.Logger(t, log.LvlCrit), []RPC{a, b}, MultiRPCConfig{}, m)
+	require.NoError(t, err)
+	defer multi.Close()
+
+	ch := make(chan fakeBlock, 1)
+	sub, err := multi.EthSubscribe(context.Background(), ch, "newHeads")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	a.feed.Send(fakeBlock{Number: 1})
+	require.Equal(t, fakeBlock{Number: 1}, <-ch)
+
+	multi.markUnhealthy(0)
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, errEndpointFailover)
+	case <-time.After(time.Second):
+		t.Fatal("expected subscription to end on failover")
+	}
+}
@@ -132,6 +132,11 @@ func (w *PollingClient) EthSubscribe(ctx context.Context, channel any, args ...a
 	}), nil
 }
 
+// Subscribe is not supported by the PollingClient, only newHeads subscriptions through EthSubscribe are.
+func (w *PollingClient) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	return nil, errors.New("unsupported subscription namespace")
+}
+
 func (w *PollingClient) pollHeads() {
 	// To prevent polls from stacking up in case HTTP requests
 	// are slow, use a similar model to the driver in which
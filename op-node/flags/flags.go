@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
 
 	"github.com/urfave/cli/v2"
 )
@@ -67,6 +69,29 @@ var (
 		Usage:   "File path used to persist state changes made via the admin API so they persist across restarts. Disabled if not set.",
 		EnvVars: prefixEnvVars("RPC_ADMIN_STATE"),
 	}
+	L1ExtraNodeAddrs = &cli.StringSliceFlag{
+		Name:    "l1.extra-rpcs",
+		Usage:   "Comma-separated addresses of additional L1 User JSON-RPC endpoints, in order of preference, to fail over to if the l1 endpoint is unhealthy",
+		EnvVars: prefixEnvVars("L1_EXTRA_ETH_RPCS"),
+	}
+	L1RPCQuorum = &cli.IntFlag{
+		Name:    "l1.rpc-quorum",
+		Usage:   "Number of L1 endpoints that must agree on the hash of an L1 block retrieved by number or label before it is accepted. Labels like latest resolve to the highest block that this number of endpoints reached. Disabled if 0 or 1.",
+		EnvVars: prefixEnvVars("L1_RPC_QUORUM"),
+		Value:   0,
+	}
+	L1RPCHealthCheckInterval = &cli.DurationFlag{
+		Name:    "l1.rpc-health-check-interval",
+		Usage:   "Interval between checks of the latest block of each L1 endpoint, if there are multiple. Disabled if 0.",
+		EnvVars: prefixEnvVars("L1_RPC_HEALTH_CHECK_INTERVAL"),
+		Value:   time.Second * 12,
+	}
+	L1RPCMaxHeadLag = &cli.Uint64Flag{
+		Name:    "l1.rpc-max-head-lag",
+		Usage:   "Number of blocks an L1 endpoint may lag behind the other L1 endpoints before it is considered unhealthy.",
+		EnvVars: prefixEnvVars("L1_RPC_MAX_HEAD_LAG"),
+		Value:   3,
+	}
 	L1TrustRPC = &cli.BoolFlag{
 		Name:    "l1.trustrpc",
 		Usage:   "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
@@ -100,6 +125,22 @@ var (
 		EnvVars: prefixEnvVars("L1_HTTP_POLL_INTERVAL"),
 		Value:   time.Second * 12,
 	}
+	L1DiskCachePath = &cli.StringFlag{
+		Name:    "l1.disk-cache.path",
+		Usage:   "Directory of the persistent cache of L1 headers, transactions and receipts. Disabled if empty.",
+		EnvVars: prefixEnvVars("L1_DISK_CACHE_PATH"),
+	}
+	L1DiskCacheMaxSize = &cli.Uint64Flag{
+		Name:    "l1.disk-cache.max-size",
+		Usage:   "Max size of the persistent L1 cache in MiB, before the oldest L1 blocks are pruned.",
+		EnvVars: prefixEnvVars("L1_DISK_CACHE_MAX_SIZE"),
+		Value:   1024,
+	}
+	SafeDBPath = &cli.StringFlag{
+		Name:    "safedb.path",
+		Usage:   "Directory of the database that records the L2 safe head derived from each L1 block. Disabled if empty.",
+		EnvVars: prefixEnvVars("SAFEDB_PATH"),
+	}
 	L2EngineJWTSecret = &cli.StringFlag{
 		Name:        "l2.jwt-secret",
 		Usage:       "Path to JWT secret key. Keys are 32 bytes, hex encoded in a file. A new key will be generated if left empty.",
@@ -108,6 +149,16 @@ var (
 		Value:       "",
 		Destination: new(string),
 	}
+	L2ShadowEngineAddr = &cli.StringFlag{
+		Name:    "l2.shadow",
+		Usage:   "Address of a second L2 Engine JSON-RPC endpoint, that receives the same engine API calls as the primary engine, to report any divergence. Disabled if empty.",
+		EnvVars: prefixEnvVars("L2_SHADOW_ENGINE_RPC"),
+	}
+	L2ShadowEngineJWTSecret = &cli.StringFlag{
+		Name:    "l2.shadow.jwt-secret",
+		Usage:   "Path to JWT secret key of the shadow L2 Engine. Uses the JWT secret of the primary L2 Engine if left empty.",
+		EnvVars: prefixEnvVars("L2_SHADOW_ENGINE_AUTH"),
+	}
 	VerifierL1Confs = &cli.Uint64Flag{
 		Name:     "verifier.l1-confs",
 		Usage:    "Number of L1 blocks to keep distance from the L1 head before deriving L2 data from. Reorgs are supported, but may be slow to perform.",
@@ -132,6 +183,49 @@ var (
 		Required: false,
 		Value:    0,
 	}
+	SequencerMaxOriginLagFlag = &cli.Uint64Flag{
+		Name:     "sequencer.max-origin-lag",
+		Usage:    "Maximum number of seconds that a new L2 block may be ahead of its L1 origin before the sequencer builds blocks without tx-pool transactions. Disabled if 0.",
+		EnvVars:  prefixEnvVars("SEQUENCER_MAX_ORIGIN_LAG"),
+		Required: false,
+		Value:    0,
+	}
+	SequencerGasTargetFlag = &cli.Uint64Flag{
+		Name:     "sequencer.gas-target",
+		Usage:    "Combined gas limit of the sequencer-signed (non-deposit) transactions added to a new L2 block by block-building policies, at which the sequencer stops including tx-pool transactions. Deposits are not counted. Disabled if 0.",
+		EnvVars:  prefixEnvVars("SEQUENCER_GAS_TARGET"),
+		Required: false,
+		Value:    0,
+	}
+	SequencerSystemTxRPCFlag = &cli.StringFlag{
+		Name:     "sequencer.system-tx-rpc",
+		Usage:    "RPC endpoint of a service that provides sequencer-signed (non-deposit) transactions to inject into every new L2 block, with the optimism_systemTxs method. Disabled if empty.",
+		EnvVars:  prefixEnvVars("SEQUENCER_SYSTEM_TX_RPC"),
+		Required: false,
+	}
+	SequencerLeadershipLockFlag = &cli.StringFlag{
+		Name: "sequencer.leadership-lock",
+		Usage: "File path of the lock shared by sequencers running in active/standby mode. " +
+			"Only the sequencer holding the lock sequences, others stand by to take over. Disabled if not set.",
+		EnvVars: prefixEnvVars("SEQUENCER_LEADERSHIP_LOCK"),
+	}
+	SequencerOriginSelectionFlag = &cli.GenericFlag{
+		Name: "sequencer.origin-selection",
+		Usage: "Strategy of the sequencer for selecting the L1 origin of new L2 blocks. Valid options: " +
+			openum.EnumString(driver.OriginSelectionKinds),
+		EnvVars: prefixEnvVars("SEQUENCER_ORIGIN_SELECTION"),
+		Value: func() *driver.OriginSelectionKind {
+			out := driver.OriginSelectConfDepth
+			return &out
+		}(),
+	}
+	SequencerTargetDriftFlag = &cli.Uint64Flag{
+		Name:     "sequencer.target-drift",
+		Usage:    "Time drift in seconds between new L2 blocks and their L1 origin, that the adaptive-drift origin selection holds the current L1 origin for, and at which the safe and finalized origin selections fall back to the conf-depth L1 origin. Defaults to half the max sequencer drift if 0.",
+		EnvVars:  prefixEnvVars("SEQUENCER_TARGET_DRIFT"),
+		Required: false,
+		Value:    0,
+	}
 	SequencerL1Confs = &cli.Uint64Flag{
 		Name:     "sequencer.l1-confs",
 		Usage:    "Number of L1 blocks to keep distance from the L1 head as a sequencer for picking an L1 origin.",
@@ -249,16 +343,31 @@ var optionalFlags = []cli.Flag{
 	RPCListenPort,
 	RollupConfig,
 	Network,
+	L1ExtraNodeAddrs,
+	L1RPCQuorum,
+	L1RPCHealthCheckInterval,
+	L1RPCMaxHeadLag,
 	L1TrustRPC,
 	L1RPCProviderKind,
 	L1RPCRateLimit,
 	L1RPCMaxBatchSize,
 	L1HTTPPollInterval,
+	L1DiskCachePath,
+	L1DiskCacheMaxSize,
+	SafeDBPath,
 	L2EngineJWTSecret,
+	L2ShadowEngineAddr,
+	L2ShadowEngineJWTSecret,
 	VerifierL1Confs,
 	SequencerEnabledFlag,
 	SequencerStoppedFlag,
 	SequencerMaxSafeLagFlag,
+	SequencerMaxOriginLagFlag,
+	SequencerGasTargetFlag,
+	SequencerSystemTxRPCFlag,
+	SequencerLeadershipLockFlag,
+	SequencerOriginSelectionFlag,
+	SequencerTargetDriftFlag,
 	SequencerL1Confs,
 	L1EpochPollIntervalFlag,
 	RPCEnableAdmin,
@@ -285,6 +394,7 @@ var Flags []cli.Flag
 
 func init() {
 	optionalFlags = append(optionalFlags, p2pFlags...)
+	optionalFlags = append(optionalFlags, optls.CLIFlagsWithFlagPrefix(EnvVarPrefix+"_P2P_SEQUENCER_SIGNER", SequencerP2PSignerFlagPrefix)...)
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
 	Flags = append(requiredFlags, optionalFlags...)
 }
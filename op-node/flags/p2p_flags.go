@@ -9,6 +9,9 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 )
 
+// SequencerP2PSignerFlagPrefix is the prefix of the TLS flags of the remote sequencer p2p signer
+const SequencerP2PSignerFlagPrefix = "p2p.sequencer.signer"
+
 func p2pEnv(v string) []string {
 	return prefixEnvVars("P2P_" + v)
 }
@@ -252,6 +255,32 @@ var (
 		Value:    "",
 		EnvVars:  p2pEnv("SEQUENCER_KEY"),
 	}
+	SequencerP2PSignerEndpointFlag = &cli.StringFlag{
+		Name:     "p2p.sequencer.signer.endpoint",
+		Usage:    "Endpoint of the remote signer for signing off on p2p application messages as sequencer. Alternative to p2p.sequencer.key.",
+		Required: false,
+		EnvVars:  p2pEnv("SEQUENCER_SIGNER_ENDPOINT"),
+	}
+	SequencerP2PSignerAddressFlag = &cli.StringFlag{
+		Name:     "p2p.sequencer.signer.address",
+		Usage:    "Address of the sequencer key that the remote signer signs p2p application messages with.",
+		Required: false,
+		EnvVars:  p2pEnv("SEQUENCER_SIGNER_ADDRESS"),
+	}
+	SequencerP2PSignerTimeoutFlag = &cli.DurationFlag{
+		Name:     "p2p.sequencer.signer.timeout",
+		Usage:    "Timeout of a single request to the remote signer.",
+		Required: false,
+		Value:    time.Second,
+		EnvVars:  p2pEnv("SEQUENCER_SIGNER_TIMEOUT"),
+	}
+	SequencerP2PSignerMaxAttemptsFlag = &cli.IntFlag{
+		Name:     "p2p.sequencer.signer.max-attempts",
+		Usage:    "Number of requests to the remote signer before giving up on signing a p2p application message.",
+		Required: false,
+		Value:    3,
+		EnvVars:  p2pEnv("SEQUENCER_SIGNER_MAX_ATTEMPTS"),
+	}
 	GossipMeshDFlag = &cli.UintFlag{
 		Name:     "p2p.gossip.mesh.d",
 		Usage:    "Configure GossipSub topic stable mesh target count, a.k.a. desired outbound degree, number of peers to gossip to",
@@ -291,6 +320,25 @@ var (
 		Hidden:   true,
 		EnvVars:  p2pEnv("GOSSIP_FLOOD_PUBLISH"),
 	}
+	AttestationsFlag = &cli.BoolFlag{
+		Name:     "p2p.attestations",
+		Usage:    "Enables the payload attestations gossip topic, to collect the attestations of verifiers that derived the same L2 blocks.",
+		Required: false,
+		EnvVars:  p2pEnv("ATTESTATIONS"),
+	}
+	AttestationsKeyFlag = &cli.StringFlag{
+		Name:     "p2p.attestations.key",
+		Usage:    "Hex-encoded private key for signing off on payload attestations of the L2 blocks processed by this node. Requires p2p.attestations.",
+		Required: false,
+		Value:    "",
+		EnvVars:  p2pEnv("ATTESTATIONS_KEY"),
+	}
+	AttestationsAttestersFlag = &cli.StringSliceFlag{
+		Name:     "p2p.attestations.attesters",
+		Usage:    "Comma-separated addresses of the attesters whose payload attestations are accepted. Required with p2p.attestations.",
+		Required: false,
+		EnvVars:  p2pEnv("ATTESTATIONS_ATTESTERS"),
+	}
 	SyncReqRespFlag = &cli.BoolFlag{
 		Name:     "p2p.sync.req-resp",
 		Usage:    "Enables P2P req-resp alternative sync method, on both server and client side.",
@@ -335,10 +383,17 @@ var p2pFlags = []cli.Flag{
 	PeerstorePath,
 	DiscoveryPath,
 	SequencerP2PKeyFlag,
+	SequencerP2PSignerEndpointFlag,
+	SequencerP2PSignerAddressFlag,
+	SequencerP2PSignerTimeoutFlag,
+	SequencerP2PSignerMaxAttemptsFlag,
 	GossipMeshDFlag,
 	GossipMeshDloFlag,
 	GossipMeshDhiFlag,
 	GossipMeshDlazyFlag,
 	GossipFloodPublishFlag,
 	SyncReqRespFlag,
+	AttestationsFlag,
+	AttestationsKeyFlag,
+	AttestationsAttestersFlag,
 }
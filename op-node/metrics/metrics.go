@@ -56,6 +56,13 @@ type Metricer interface {
 	RecordL1ReorgDepth(d uint64)
 	RecordSequencerInconsistentL1Origin(from eth.BlockID, to eth.BlockID)
 	RecordSequencerReset()
+	RecordSequencerOriginDrift(strategy string, timeDrift uint64, headDistance uint64)
+	RecordL1EndpointHealth(endpoint int, healthy bool, head uint64)
+	RecordL1EndpointError(endpoint int)
+	RecordL1ActiveEndpoint(endpoint int)
+	RecordL1QuorumFailure()
+	RecordRemoteSignerRequest(success bool, attempts int, duration time.Duration)
+	RecordShadowEngineRequest(method string, result string)
 	RecordGossipEvent(evType int32)
 	IncPeerCount()
 	DecPeerCount()
@@ -111,8 +118,23 @@ type Metrics struct {
 	SequencerInconsistentL1Origin *EventMetrics
 	SequencerResets               *EventMetrics
 
+	SequencerOriginTimeDrift    *prometheus.GaugeVec
+	SequencerOriginHeadDistance *prometheus.GaugeVec
+
 	L1RequestDurationSeconds *prometheus.HistogramVec
 
+	L1EndpointHealthy *prometheus.GaugeVec
+	L1EndpointHead    *prometheus.GaugeVec
+	L1EndpointErrors  *prometheus.CounterVec
+	L1ActiveEndpoint  prometheus.Gauge
+	L1QuorumFailures  prometheus.Counter
+
+	RemoteSignerRequests        *prometheus.CounterVec
+	RemoteSignerAttempts        prometheus.Counter
+	RemoteSignerDurationSeconds prometheus.Histogram
+
+	ShadowEngineRequests *prometheus.CounterVec
+
 	SequencerBuildingDiffDurationSeconds prometheus.Histogram
 	SequencerBuildingDiffTotal           prometheus.Counter
 
@@ -247,6 +269,21 @@ func NewMetrics(procName string) *Metrics {
 		SequencerInconsistentL1Origin: NewEventMetrics(factory, ns, "sequencer_inconsistent_l1_origin", "events when the sequencer selects an inconsistent L1 origin"),
 		SequencerResets:               NewEventMetrics(factory, ns, "sequencer_resets", "sequencer resets"),
 
+		SequencerOriginTimeDrift: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "sequencer_origin_time_drift_seconds",
+			Help:      "Time between the latest L2 block that the sequencer selected an L1 origin for, and the time of that L1 origin",
+		}, []string{
+			"strategy",
+		}),
+		SequencerOriginHeadDistance: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "sequencer_origin_l1_head_distance",
+			Help:      "Number of L1 blocks between the L1 head and the latest L1 origin selected by the sequencer",
+		}, []string{
+			"strategy",
+		}),
+
 		UnsafePayloadsBufferLen: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace: ns,
 			Name:      "unsafe_payloads_buffer_len",
@@ -427,6 +464,64 @@ func NewMetrics(procName string) *Metrics {
 			Help: "Histogram of L1 request time",
 		}, []string{"request"}),
 
+		L1EndpointHealthy: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "l1_endpoint_healthy",
+			Help:      "1 if the L1 RPC endpoint is healthy, by index of the endpoint in order of preference",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointHead: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "l1_endpoint_head",
+			Help:      "Latest block number of the L1 RPC endpoint, as seen in the last health check",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointErrors: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "l1_endpoint_errors_total",
+			Help:      "Count of errors attributed to the L1 RPC endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		L1ActiveEndpoint: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "l1_active_endpoint",
+			Help:      "Index of the L1 RPC endpoint that serves requests",
+		}),
+		L1QuorumFailures: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "l1_quorum_failures_total",
+			Help:      "Count of L1 block requests that the L1 RPC endpoints did not reach quorum on",
+		}),
+
+		RemoteSignerRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: "p2p",
+			Name:      "remote_signer_requests_total",
+			Help:      "Count of p2p messages to sign with the remote signer, with label to filter to successfully signed messages",
+		}, []string{"success"}),
+		RemoteSignerAttempts: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: "p2p",
+			Name:      "remote_signer_attempts_total",
+			Help:      "Count of requests to the remote signer, including retries",
+		}),
+		RemoteSignerDurationSeconds: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: "p2p",
+			Name:      "remote_signer_duration_seconds",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+			Help:      "Duration of signing a p2p message with the remote signer, including retries",
+		}),
+
+		ShadowEngineRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "shadow_engine_requests_total",
+			Help:      "Count of engine API calls mirrored to the shadow execution engine, by method and by result: match, syncing, divergence, error or dropped",
+		}, []string{"method", "result"}),
+
 		SequencerBuildingDiffDurationSeconds: factory.NewHistogram(prometheus.HistogramOpts{
 			Namespace: ns,
 			Name:      "sequencer_building_diff_seconds",
@@ -612,6 +707,12 @@ func (m *Metrics) RecordSequencerReset() {
 	m.SequencerResets.RecordEvent()
 }
 
+// RecordSequencerOriginDrift tracks the drift from L1 of the L1 origin selected by the sequencer, in time and in blocks.
+func (m *Metrics) RecordSequencerOriginDrift(strategy string, timeDrift uint64, headDistance uint64) {
+	m.SequencerOriginTimeDrift.WithLabelValues(strategy).Set(float64(timeDrift))
+	m.SequencerOriginHeadDistance.WithLabelValues(strategy).Set(float64(headDistance))
+}
+
 func (m *Metrics) RecordGossipEvent(evType int32) {
 	m.GossipEventsTotal.WithLabelValues(pb.TraceEvent_Type_name[evType]).Inc()
 }
@@ -653,6 +754,39 @@ func (m *Metrics) RecordL1RequestTime(method string, duration time.Duration) {
 	m.L1RequestDurationSeconds.WithLabelValues(method).Observe(float64(duration) / float64(time.Second))
 }
 
+// RecordL1EndpointHealth tracks the health and latest block of an L1 RPC endpoint.
+func (m *Metrics) RecordL1EndpointHealth(endpoint int, healthy bool, head uint64) {
+	label := strconv.Itoa(endpoint)
+	if healthy {
+		m.L1EndpointHealthy.WithLabelValues(label).Set(1)
+	} else {
+		m.L1EndpointHealthy.WithLabelValues(label).Set(0)
+	}
+	m.L1EndpointHead.WithLabelValues(label).Set(float64(head))
+}
+
+func (m *Metrics) RecordL1EndpointError(endpoint int) {
+	m.L1EndpointErrors.WithLabelValues(strconv.Itoa(endpoint)).Inc()
+}
+
+func (m *Metrics) RecordL1ActiveEndpoint(endpoint int) {
+	m.L1ActiveEndpoint.Set(float64(endpoint))
+}
+
+func (m *Metrics) RecordL1QuorumFailure() {
+	m.L1QuorumFailures.Inc()
+}
+
+func (m *Metrics) RecordRemoteSignerRequest(success bool, attempts int, duration time.Duration) {
+	m.RemoteSignerRequests.WithLabelValues(strconv.FormatBool(success)).Inc()
+	m.RemoteSignerAttempts.Add(float64(attempts))
+	m.RemoteSignerDurationSeconds.Observe(float64(duration) / float64(time.Second))
+}
+
+func (m *Metrics) RecordShadowEngineRequest(method string, result string) {
+	m.ShadowEngineRequests.WithLabelValues(method, result).Inc()
+}
+
 // RecordSequencerBuildingDiffTime tracks the amount of time the sequencer was allowed between
 // start to finish, incl. sealing, minus the block time.
 // Ideally this is 0, realistically the sequencer scheduler may be busy with other jobs like syncing sometimes.
@@ -811,6 +945,27 @@ func (n *noopMetricer) RecordSequencerInconsistentL1Origin(from eth.BlockID, to
 func (n *noopMetricer) RecordSequencerReset() {
 }
 
+func (n *noopMetricer) RecordSequencerOriginDrift(strategy string, timeDrift uint64, headDistance uint64) {
+}
+
+func (n *noopMetricer) RecordL1EndpointHealth(endpoint int, healthy bool, head uint64) {
+}
+
+func (n *noopMetricer) RecordL1EndpointError(endpoint int) {
+}
+
+func (n *noopMetricer) RecordL1ActiveEndpoint(endpoint int) {
+}
+
+func (n *noopMetricer) RecordL1QuorumFailure() {
+}
+
+func (n *noopMetricer) RecordRemoteSignerRequest(success bool, attempts int, duration time.Duration) {
+}
+
+func (n *noopMetricer) RecordShadowEngineRequest(method string, result string) {
+}
+
 func (n *noopMetricer) RecordGossipEvent(evType int32) {
 }
 
@@ -2,12 +2,16 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -28,6 +32,17 @@ type driverClient interface {
 	StartSequencer(ctx context.Context, blockHash common.Hash) error
 	StopSequencer(context.Context) (common.Hash, error)
 	SequencerActive(context.Context) (bool, error)
+	RewindHead(ctx context.Context, num uint64, rewindSafe bool) error
+	SetSequencerThrottle(ctx context.Context, interval uint64) error
+}
+
+type syncStatusFeed interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	SubscribeSyncStatus(ch chan<- *eth.SyncStatus) event.Subscription
+}
+
+type SafeDBReader interface {
+	SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1 eth.BlockID, safeHead eth.BlockID, err error)
 }
 
 type rpcMetrics interface {
@@ -71,19 +86,36 @@ func (n *adminAPI) SequencerActive(ctx context.Context) (bool, error) {
 	return n.dr.SequencerActive(ctx)
 }
 
+// RewindHead rewinds the unsafe head, and the safe head if rewindSafe is set, to the L2 block of the given number.
+func (n *adminAPI) RewindHead(ctx context.Context, number hexutil.Uint64, rewindSafe bool) error {
+	recordDur := n.m.RecordRPCServerRequest("admin_rewindHead")
+	defer recordDur()
+	return n.dr.RewindHead(ctx, uint64(number), rewindSafe)
+}
+
+// SetSequencerThrottle makes the sequencer only include tx-pool transactions in every interval-th block,
+// to slow down the growth of unsubmitted L2 data. An interval of 0 or 1 disables throttling.
+func (n *adminAPI) SetSequencerThrottle(ctx context.Context, interval hexutil.Uint64) error {
+	recordDur := n.m.RecordRPCServerRequest("admin_setSequencerThrottle")
+	defer recordDur()
+	return n.dr.SetSequencerThrottle(ctx, uint64(interval))
+}
+
 type nodeAPI struct {
 	config *rollup.Config
 	client l2EthClient
 	dr     driverClient
+	safeDB SafeDBReader
 	log    log.Logger
 	m      rpcMetrics
 }
 
-func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, log log.Logger, m rpcMetrics) *nodeAPI {
+func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, safeDB SafeDBReader, log log.Logger, m rpcMetrics) *nodeAPI {
 	return &nodeAPI{
 		config: config,
 		client: l2Client,
 		dr:     dr,
+		safeDB: safeDB,
 		log:    log,
 		m:      m,
 	}
@@ -112,6 +144,21 @@ func (n *nodeAPI) OutputAtBlock(ctx context.Context, number hexutil.Uint64) (*et
 	}, nil
 }
 
+func (n *nodeAPI) SafeHeadAtL1Block(ctx context.Context, number hexutil.Uint64) (*eth.SafeHeadResponse, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_safeHeadAtL1Block")
+	defer recordDur()
+	l1Block, safeHead, err := n.safeDB.SafeHeadAtL1(ctx, uint64(number))
+	if errors.Is(err, safedb.ErrNotFound) || errors.Is(err, safedb.ErrNotEnabled) {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get safe head at L1 block %d: %w", number, err)
+	}
+	return &eth.SafeHeadResponse{
+		L1Block:  l1Block,
+		SafeHead: safeHead,
+	}, nil
+}
+
 func (n *nodeAPI) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	recordDur := n.m.RecordRPCServerRequest("optimism_syncStatus")
 	defer recordDur()
@@ -129,3 +176,61 @@ func (n *nodeAPI) Version(ctx context.Context) (string, error) {
 	defer recordDur()
 	return version.Version + "-" + version.Meta, nil
 }
+
+// syncStatusSubscriptionAPI serves the sync status subscription. It is registered in the "optimism" namespace,
+// next to the nodeAPI, which serves the SyncStatus method of the same name.
+type syncStatusSubscriptionAPI struct {
+	feed syncStatusFeed
+	log  log.Logger
+	m    rpcMetrics
+}
+
+func NewSyncStatusSubscriptionAPI(feed syncStatusFeed, log log.Logger, m rpcMetrics) *syncStatusSubscriptionAPI {
+	return &syncStatusSubscriptionAPI{
+		feed: feed,
+		log:  log,
+		m:    m,
+	}
+}
+
+// SyncStatus serves the optimism_subscribe("syncStatus") subscription.
+// The current sync status is sent first, followed by the new sync status whenever any of the L1 or L2 heads changes.
+func (api *syncStatusSubscriptionAPI) SyncStatus(ctx context.Context) (*rpc.Subscription, error) {
+	recordDur := api.m.RecordRPCServerRequest("optimism_subscribe_syncStatus")
+	defer recordDur()
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	// subscribe before getting the current status, to not miss any change
+	statusCh := make(chan *eth.SyncStatus, 10)
+	sub := api.feed.SubscribeSyncStatus(statusCh)
+	status, err := api.feed.SyncStatus(ctx)
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, fmt.Errorf("failed to get sync status: %w", err)
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	// notifications are buffered until the subscription is returned to the client
+	if err := notifier.Notify(rpcSub.ID, status); err != nil {
+		sub.Unsubscribe()
+		return nil, fmt.Errorf("failed to send sync status: %w", err)
+	}
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case status := <-statusCh:
+				if err := notifier.Notify(rpcSub.ID, status); err != nil {
+					api.log.Warn("Failed to send sync status to subscriber", "id", rpcSub.ID, "err", err)
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
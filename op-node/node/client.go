@@ -32,7 +32,8 @@ type L1EndpointSetup interface {
 	// Setup a RPC client to a L1 node to pull rollup input-data from.
 	// The results of the RPC client may be trusted for faster processing, or strictly validated.
 	// The kind of the RPC may be non-basic, to optimize RPC usage.
-	Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
+	// The metrics are used to track the health of the RPC endpoints, if there are multiple.
+	Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, metrics client.MultiRPCMetrics) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
 	Check() error
 }
 
@@ -136,6 +137,22 @@ func (cfg *PreparedL2SyncEndpoint) Check() error {
 type L1EndpointConfig struct {
 	L1NodeAddr string // Address of L1 User JSON-RPC endpoint to use (eth namespace required)
 
+	// L1ExtraNodeAddrs are the addresses of additional L1 User JSON-RPC endpoints,
+	// in order of preference, to fail over to if the L1NodeAddr endpoint is unhealthy.
+	L1ExtraNodeAddrs []string
+
+	// L1Quorum is the number of L1 endpoints that must agree on the hash of a block retrieved by number,
+	// before the block is accepted. Disabled if 0 or 1.
+	L1Quorum int
+
+	// L1HealthCheckInterval is the interval between checks of the latest block of each L1 endpoint,
+	// if there are multiple. Disabled if 0.
+	L1HealthCheckInterval time.Duration
+
+	// L1MaxHeadLag is the number of blocks an L1 endpoint may lag behind the other L1 endpoints,
+	// before it is considered unhealthy.
+	L1MaxHeadLag uint64
+
 	// L1TrustRPC: if we trust the L1 RPC we do not have to validate L1 response contents like headers
 	// against block hashes, or cached transaction sender addresses.
 	// Thus we can sync faster at the risk of the source RPC being wrong.
@@ -167,10 +184,13 @@ func (cfg *L1EndpointConfig) Check() error {
 	if cfg.RateLimit < 0 {
 		return fmt.Errorf("rate limit cannot be negative")
 	}
+	if cfg.L1Quorum > 1+len(cfg.L1ExtraNodeAddrs) {
+		return fmt.Errorf("L1 quorum of %d cannot be met by %d L1 endpoints", cfg.L1Quorum, 1+len(cfg.L1ExtraNodeAddrs))
+	}
 	return nil
 }
 
-func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
+func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, metrics client.MultiRPCMetrics) (client.RPC, *sources.L1ClientConfig, error) {
 	opts := []client.RPCOption{
 		client.WithHttpPollInterval(cfg.HttpPollInterval),
 		client.WithDialBackoff(10),
@@ -179,9 +199,38 @@ func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCf
 		opts = append(opts, client.WithRateLimit(cfg.RateLimit, cfg.BatchSize))
 	}
 
-	l1Node, err := client.NewRPC(ctx, log, cfg.L1NodeAddr, opts...)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to dial L1 address (%s): %w", cfg.L1NodeAddr, err)
+	var l1Node client.RPC
+	if len(cfg.L1ExtraNodeAddrs) == 0 {
+		var err error
+		l1Node, err = client.NewRPC(ctx, log, cfg.L1NodeAddr, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to dial L1 address (%s): %w", cfg.L1NodeAddr, err)
+		}
+	} else {
+		var endpoints []client.RPC
+		for i, addr := range append([]string{cfg.L1NodeAddr}, cfg.L1ExtraNodeAddrs...) {
+			endpoint, err := client.NewRPC(ctx, log.New("l1_endpoint", i), addr, opts...)
+			if err != nil {
+				for _, e := range endpoints {
+					e.Close()
+				}
+				return nil, nil, fmt.Errorf("failed to dial L1 address %d: %w", i, err)
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		multiCfg := client.MultiRPCConfig{
+			Quorum:              cfg.L1Quorum,
+			HealthCheckInterval: cfg.L1HealthCheckInterval,
+			MaxHeadLag:          cfg.L1MaxHeadLag,
+		}
+		multi, err := client.NewMultiRPC(log, endpoints, multiCfg, metrics)
+		if err != nil {
+			for _, e := range endpoints {
+				e.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to combine L1 endpoints: %w", err)
+		}
+		l1Node = multi
 	}
 	rpcCfg := sources.L1ClientDefaultConfig(rollupCfg, cfg.L1TrustRPC, cfg.L1RPCKind)
 	rpcCfg.MaxRequestsPerBatch = cfg.BatchSize
@@ -197,7 +246,7 @@ type PreparedL1Endpoint struct {
 
 var _ L1EndpointSetup = (*PreparedL1Endpoint)(nil)
 
-func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
+func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, metrics client.MultiRPCMetrics) (client.RPC, *sources.L1ClientConfig, error) {
 	return p.Client, sources.L1ClientDefaultConfig(rollupCfg, p.TrustRPC, p.RPCProviderKind), nil
 }
 
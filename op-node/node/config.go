@@ -20,6 +20,10 @@ type Config struct {
 	L2     L2EndpointSetup
 	L2Sync L2SyncEndpointSetup
 
+	// L2Shadow is the execution engine that mirrors the engine API calls to the L2 engine, to report divergence.
+	// Optional, may be nil if the shadow engine is disabled.
+	L2Shadow L2EndpointSetup
+
 	Driver driver.Config
 
 	Rollup rollup.Config
@@ -39,8 +43,21 @@ type Config struct {
 	// Used to poll the L1 for new finalized or safe blocks
 	L1EpochPollInterval time.Duration
 
+	L1DiskCache L1DiskCacheConfig
+
+	// SafeDBPath is the directory of the database that records the safe head by L1 block. Disabled if empty.
+	SafeDBPath string
+
 	ConfigPersistence ConfigPersistence
 
+	// SequencerLeadership coordinates active/standby sequencing with other op-nodes.
+	// Optional, may be nil if the sequencer does not run in active/standby mode.
+	SequencerLeadership driver.SequencerLeadership
+
+	// SequencerSystemTxs provides the sequencer-signed transactions to inject into every new L2 block.
+	// Optional, may be nil if the sequencer does not inject any transactions.
+	SequencerSystemTxs driver.SystemTxSource
+
 	// Optional
 	Tracer    Tracer
 	Heartbeat HeartbeatConfig
@@ -76,6 +93,20 @@ func (m MetricsConfig) Check() error {
 	return nil
 }
 
+type L1DiskCacheConfig struct {
+	// Path of the database directory of the L1 disk cache. The disk cache is disabled if empty.
+	Path string
+	// MaxSize is the max number of bytes of L1 data to keep in the disk cache, before pruning the oldest blocks.
+	MaxSize uint64
+}
+
+func (c L1DiskCacheConfig) Check() error {
+	if c.Path != "" && c.MaxSize == 0 {
+		return errors.New("L1 disk cache max size must be non-zero")
+	}
+	return nil
+}
+
 type HeartbeatConfig struct {
 	Enabled bool
 	Moniker string
@@ -108,15 +139,29 @@ func (cfg *Config) Check() error {
 	if err := cfg.L2Sync.Check(); err != nil {
 		return fmt.Errorf("sync config error: %w", err)
 	}
+	if cfg.L2Shadow != nil {
+		if err := cfg.L2Shadow.Check(); err != nil {
+			return fmt.Errorf("l2 shadow endpoint config error: %w", err)
+		}
+	}
 	if err := cfg.Rollup.Check(); err != nil {
 		return fmt.Errorf("rollup config error: %w", err)
 	}
+	if err := cfg.L1DiskCache.Check(); err != nil {
+		return fmt.Errorf("l1 disk cache config error: %w", err)
+	}
 	if err := cfg.Metrics.Check(); err != nil {
 		return fmt.Errorf("metrics config error: %w", err)
 	}
 	if err := cfg.Pprof.Check(); err != nil {
 		return fmt.Errorf("pprof config error: %w", err)
 	}
+	if cfg.SequencerLeadership != nil && !cfg.Driver.SequencerEnabled {
+		return errors.New("sequencer leadership requires the sequencer to be enabled")
+	}
+	if cfg.SequencerSystemTxs != nil && !cfg.Driver.SequencerEnabled {
+		return errors.New("sequencer system txs require the sequencer to be enabled")
+	}
 	if cfg.P2P != nil {
 		if err := cfg.P2P.Check(); err != nil {
 			return fmt.Errorf("p2p config error: %w", err)
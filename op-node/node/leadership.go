@@ -0,0 +1,109 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gofrs/flock"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var _ driver.SequencerLeadership = (*FileLeadership)(nil)
+
+// FileLeadership implements sequencer leadership with a file-lock,
+// shared by all the sequencer op-nodes that run in active/standby mode on the same host or shared file-system.
+// The node that holds the lock is the leader. The lock is released by the OS if the leader process dies.
+// The latest payload committed by the leader is stored next to the lock-file.
+type FileLeadership struct {
+	lock        sync.Mutex
+	fileLock    *flock.Flock
+	payloadFile string
+}
+
+func NewFileLeadership(lockFile string) *FileLeadership {
+	return &FileLeadership{
+		fileLock:    flock.New(lockFile),
+		payloadFile: lockFile + ".payload",
+	}
+}
+
+func (l *FileLeadership) TryAcquire(ctx context.Context) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.fileLock.Locked() {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.fileLock.Path()), 0755); err != nil {
+		return false, fmt.Errorf("create lock dir (%v): %w", l.fileLock.Path(), err)
+	}
+	held, err := l.fileLock.TryLock()
+	if err != nil {
+		return false, fmt.Errorf("lock file (%v): %w", l.fileLock.Path(), err)
+	}
+	return held, nil
+}
+
+func (l *FileLeadership) Release(ctx context.Context) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := l.fileLock.Unlock(); err != nil {
+		return fmt.Errorf("unlock file (%v): %w", l.fileLock.Path(), err)
+	}
+	return nil
+}
+
+// CommitUnsafePayload writes the payload to a temp file first, before renaming it into place,
+// so that a standby never reads a partially written payload.
+func (l *FileLeadership) CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.fileLock.Locked() {
+		return errors.New("not holding sequencer leadership")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	tmpFile := l.payloadFile + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("open file (%v) for writing: %w", tmpFile, err)
+	}
+	defer file.Close() // Ensure file is closed even if write or sync fails
+	if _, err = file.Write(data); err != nil {
+		return fmt.Errorf("write payload to temp file (%v): %w", tmpFile, err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync payload temp file (%v): %w", tmpFile, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close payload temp file (%v): %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, l.payloadFile); err != nil {
+		return fmt.Errorf("rename temp payload file to final destination: %w", err)
+	}
+	return nil
+}
+
+func (l *FileLeadership) LatestUnsafePayload(ctx context.Context) (*eth.ExecutionPayload, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	data, err := os.ReadFile(l.payloadFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read payload file (%v): %w", l.payloadFile, err)
+	}
+	var payload eth.ExecutionPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload file (%v): %w", l.payloadFile, err)
+	}
+	return &payload, nil
+}
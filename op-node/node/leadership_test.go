@@ -0,0 +1,55 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func TestFileLeadership(t *testing.T) {
+	ctx := context.Background()
+	lockFile := t.TempDir() + "/seq/leader.lock"
+	active := NewFileLeadership(lockFile)
+	standby := NewFileLeadership(lockFile)
+
+	payload, err := standby.LatestUnsafePayload(ctx)
+	require.NoError(t, err)
+	require.Nil(t, payload, "no payload committed yet")
+
+	held, err := active.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, held)
+	held, err = active.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, held, "renew leadership")
+
+	held, err = standby.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.False(t, held, "only one leader")
+	require.ErrorContains(t, standby.CommitUnsafePayload(ctx, &eth.ExecutionPayload{}), "not holding")
+
+	committed := &eth.ExecutionPayload{
+		ParentHash:   common.Hash{0xaa},
+		BlockNumber:  123,
+		ExtraData:    eth.BytesMax32{},
+		BlockHash:    common.Hash{0xbb},
+		Transactions: []eth.Data{},
+	}
+	require.NoError(t, active.CommitUnsafePayload(ctx, committed))
+	payload, err = standby.LatestUnsafePayload(ctx)
+	require.NoError(t, err)
+	require.Equal(t, committed, payload)
+
+	require.NoError(t, active.Release(ctx))
+	held, err = standby.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, held, "standby takes over after release")
+	held, err = active.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.False(t, held, "previous leader is now standby")
+	require.NoError(t, standby.Release(ctx))
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	leveldb "github.com/ipfs/go-ds-leveldb"
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/ethereum/go-ethereum"
@@ -15,12 +17,20 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 )
 
+type closableSafeDB interface {
+	derive.SafeHeadListener
+	SafeDBReader
+	io.Closer
+}
+
 type OpNode struct {
 	log        log.Logger
 	appVersion string
@@ -30,15 +40,18 @@ type OpNode struct {
 	l1SafeSub      ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 	l1FinalizedSub ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 
-	l1Source  *sources.L1Client     // L1 Client to fetch data from
-	l2Driver  *driver.Driver        // L2 Engine to Sync
-	l2Source  *sources.EngineClient // L2 Execution Engine RPC bindings
-	rpcSync   *sources.SyncClient   // Alt-sync RPC client, optional (may be nil)
-	server    *rpcServer            // RPC server hosting the rollup-node API
-	p2pNode   *p2p.NodeP2P          // P2P node functionality
-	p2pSigner p2p.Signer            // p2p gogssip application messages will be signed with this signer
-	tracer    Tracer                // tracer to get events for testing/debugging
-	runCfg    *RuntimeConfig        // runtime configurables
+	l1Source  *sources.L1Client           // L1 Client to fetch data from
+	l2Driver  *driver.Driver              // L2 Engine to Sync
+	l2Source  *sources.EngineClient       // L2 Execution Engine RPC bindings
+	l2Shadow  *sources.ShadowEngineClient // L2 Execution Engine RPC bindings, mirrored to a shadow engine, optional (may be nil)
+	rpcSync   *sources.SyncClient         // Alt-sync RPC client, optional (may be nil)
+	safeDB    closableSafeDB              // Records the safe head by L1 block, may be disabled
+	server    *rpcServer                  // RPC server hosting the rollup-node API
+	p2pNode   *p2p.NodeP2P                // P2P node functionality
+	p2pSigner p2p.Signer                  // p2p gogssip application messages will be signed with this signer
+	attestSub event.Subscription          // Subscription to the sync status, to attest to new L2 heads, optional (may be nil)
+	tracer    Tracer                      // tracer to get events for testing/debugging
+	runCfg    *RuntimeConfig              // runtime configurables
 
 	// some resources cannot be stopped directly, like the p2p gossipsub router (not our design),
 	// and depend on this ctx to be closed.
@@ -116,11 +129,23 @@ func (n *OpNode) initTracer(ctx context.Context, cfg *Config) error {
 }
 
 func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
-	l1Node, rpcCfg, err := cfg.L1.Setup(ctx, n.log, &cfg.Rollup)
+	l1Node, rpcCfg, err := cfg.L1.Setup(ctx, n.log, &cfg.Rollup, n.metrics)
 	if err != nil {
 		return fmt.Errorf("failed to get L1 RPC client: %w", err)
 	}
 
+	if cfg.L1DiskCache.Path != "" {
+		store, err := leveldb.NewDatastore(cfg.L1DiskCache.Path, nil) // default leveldb options are fine
+		if err != nil {
+			return fmt.Errorf("failed to open leveldb db for L1 disk cache: %w", err)
+		}
+		rpcCfg.DiskCache, err = sources.NewL1DiskCache(n.log, store, cfg.L1DiskCache.MaxSize, n.metrics.L1SourceCache)
+		if err != nil {
+			store.Close()
+			return fmt.Errorf("failed to load L1 disk cache: %w", err)
+		}
+	}
+
 	n.l1Source, err = sources.NewL1Client(
 		client.NewInstrumentedRPC(l1Node, n.metrics), n.log, n.metrics.L1SourceCache, rpcCfg)
 	if err != nil {
@@ -199,7 +224,36 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 		return err
 	}
 
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, &cfg.Sync)
+	var engine driver.L2Chain = n.l2Source
+	if cfg.L2Shadow != nil {
+		shadowRPC, shadowCfg, err := cfg.L2Shadow.Setup(ctx, n.log, &cfg.Rollup)
+		if err != nil {
+			return fmt.Errorf("failed to setup shadow L2 execution-engine RPC client: %w", err)
+		}
+		shadowLog := n.log.New("engine", "shadow")
+		shadowSource, err := sources.NewEngineClient(shadowRPC, shadowLog, nil, shadowCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create shadow Engine client: %w", err)
+		}
+		n.log.Info("Shadow execution engine enabled")
+		n.l2Shadow = sources.NewShadowEngineClient(n.l2Source, shadowSource, shadowLog, n.metrics)
+		engine = n.l2Shadow
+	}
+
+	if cfg.SafeDBPath != "" {
+		n.log.Info("Safe head database enabled", "path", cfg.SafeDBPath)
+		n.safeDB, err = safedb.NewSafeDB(n.log, cfg.SafeDBPath)
+		if err != nil {
+			return fmt.Errorf("failed to create safe head database at %q: %w", cfg.SafeDBPath, err)
+		}
+	} else {
+		n.safeDB = safedb.Disabled
+	}
+
+	n.l2Driver, err = driver.NewDriver(&cfg.Driver, &cfg.Rollup, engine, n.l1Source, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, cfg.SequencerLeadership, cfg.SequencerSystemTxs, &cfg.Sync, n.safeDB)
+	if err != nil {
+		return fmt.Errorf("failed to create driver: %w", err)
+	}
 
 	return nil
 }
@@ -221,10 +275,11 @@ func (n *OpNode) initRPCSync(ctx context.Context, cfg *Config) error {
 }
 
 func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
-	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.log, n.appVersion, n.metrics)
+	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.safeDB, n.log, n.appVersion, n.metrics)
 	if err != nil {
 		return err
 	}
+	server.EnableSyncStatusSubscription(NewSyncStatusSubscriptionAPI(n.l2Driver, n.log.New("rpc", "sync_status"), n.metrics))
 	if n.p2pNode != nil {
 		server.EnableP2P(p2p.NewP2PAPIBackend(n.p2pNode, n.log, n.metrics))
 	}
@@ -275,7 +330,7 @@ func (n *OpNode) initP2PSigner(ctx context.Context, cfg *Config) error {
 	}
 	// p2pSigner may still be nil, the signer setup may not create any signer, the signer is optional
 	var err error
-	n.p2pSigner, err = cfg.P2PSigner.SetupSigner(ctx)
+	n.p2pSigner, err = cfg.P2PSigner.SetupSigner(ctx, n.log, n.metrics)
 	return err
 }
 
@@ -288,6 +343,15 @@ func (n *OpNode) Start(ctx context.Context) error {
 		return err
 	}
 
+	// If payload attestations are enabled, track the L2 heads to validate received attestations against,
+	// and if the node attests to the L2 blocks it processes, publish attestations whenever the L2 heads change.
+	if n.p2pNode != nil && n.p2pNode.AttestationsEnabled() {
+		statusCh := make(chan *eth.SyncStatus, 10)
+		n.attestSub = n.l2Driver.SubscribeSyncStatus(statusCh)
+		go n.attestHeads(statusCh, n.attestSub.Err())
+		n.log.Info("Started payload attestations", "attesting", n.p2pNode.CanAttest())
+	}
+
 	// If the backup unsafe sync client is enabled, start its event loop
 	if n.rpcSync != nil {
 		if err := n.rpcSync.Start(); err != nil {
@@ -300,9 +364,73 @@ func (n *OpNode) Start(ctx context.Context) error {
 	return nil
 }
 
+// headAttestation is an attestation of an L2 head that is queued to be published.
+type headAttestation struct {
+	ref  eth.L2BlockRef
+	safe bool
+}
+
+// attestHeads updates the unsafe L2 head that received attestations are validated against,
+// and queues an attestation of every new unsafe and safe L2 head if the node attests,
+// until the sync status subscription is closed.
+// Attestations are published asynchronously, so slow publishing does not hold up the processing of sync status updates:
+// if publishing cannot keep up, attestations are dropped.
+func (n *OpNode) attestHeads(statusCh <-chan *eth.SyncStatus, errCh <-chan error) {
+	var pending chan headAttestation
+	if n.p2pNode.CanAttest() {
+		pending = make(chan headAttestation, 16)
+		defer close(pending)
+		go n.publishAttestations(pending)
+	}
+	queue := func(ref eth.L2BlockRef, safe bool) {
+		select {
+		case pending <- headAttestation{ref: ref, safe: safe}:
+		default:
+			n.log.Warn("Dropping payload attestation, publishing does not keep up", "block", ref, "safe", safe)
+		}
+	}
+	var lastUnsafe, lastSafe eth.L2BlockRef
+	for {
+		select {
+		case status := <-statusCh:
+			n.p2pNode.OnUnsafeL2Head(status.UnsafeL2)
+			if pending == nil {
+				continue
+			}
+			if status.UnsafeL2 != lastUnsafe && status.UnsafeL2.Number > 0 {
+				lastUnsafe = status.UnsafeL2
+				queue(status.UnsafeL2, false)
+			}
+			if status.SafeL2 != lastSafe && status.SafeL2.Number > 0 {
+				lastSafe = status.SafeL2
+				queue(status.SafeL2, true)
+			}
+		case <-errCh:
+			return
+		}
+	}
+}
+
+// publishAttestations publishes the queued attestations, until the queue is closed.
+func (n *OpNode) publishAttestations(pending <-chan headAttestation) {
+	for att := range pending {
+		n.publishAttestation(att.ref, att.safe)
+	}
+}
+
+func (n *OpNode) publishAttestation(ref eth.L2BlockRef, safe bool) {
+	ctx, cancel := context.WithTimeout(n.resourcesCtx, time.Second*5)
+	defer cancel()
+	if err := n.p2pNode.PublishAttestation(ctx, ref, safe); err != nil {
+		n.log.Warn("Failed to publish payload attestation", "block", ref, "safe", safe, "err", err)
+	}
+}
+
 func (n *OpNode) OnNewL1Head(ctx context.Context, sig eth.L1BlockRef) {
 	n.tracer.OnNewL1Head(ctx, sig)
 
+	n.l1Source.InvalidateReorged(sig)
+
 	if n.l2Driver == nil {
 		return
 	}
@@ -404,6 +532,9 @@ func (n *OpNode) Close() error {
 	if n.server != nil {
 		n.server.Stop()
 	}
+	if n.attestSub != nil {
+		n.attestSub.Unsubscribe()
+	}
 	if n.p2pNode != nil {
 		if err := n.p2pNode.Close(); err != nil {
 			result = multierror.Append(result, fmt.Errorf("failed to close p2p node: %w", err))
@@ -438,6 +569,18 @@ func (n *OpNode) Close() error {
 		}
 	}
 
+	// close the safe head database, after the driver stopped updating it
+	if n.safeDB != nil {
+		if err := n.safeDB.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close safe head database: %w", err))
+		}
+	}
+
+	// stop mirroring to the shadow engine, and close its RPC client
+	if n.l2Shadow != nil {
+		n.l2Shadow.Close()
+	}
+
 	// close L2 engine RPC client
 	if n.l2Source != nil {
 		n.l2Source.Close()
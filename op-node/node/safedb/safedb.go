@@ -0,0 +1,138 @@
+package safedb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrNotFound   = errors.New("safe head not found")
+	ErrNotEnabled = errors.New("safe head database not enabled")
+)
+
+// safeByL1BlockNumPrefix prefixes the entries that map an L1 block to the L2 safe head derived from it.
+// The L1 block number is stored inverted, so iterating from a given number finds the entries at or below it.
+var safeByL1BlockNumPrefix = []byte("s")
+
+const entrySize = common.HashLength + common.HashLength + 8
+
+// SafeDB persists the L2 safe head after processing each L1 block,
+// to answer which L2 block was safe at a given L1 block, after the derivation pipeline moved on.
+type SafeDB struct {
+	log log.Logger
+	db  ethdb.KeyValueStore
+}
+
+// NewSafeDB opens, or creates, the leveldb database at the given path.
+func NewSafeDB(log log.Logger, path string) (*SafeDB, error) {
+	db, err := leveldb.New(path, 16, 16, "safedb", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safe head database: %w", err)
+	}
+	return newSafeDB(log, db), nil
+}
+
+func newSafeDB(log log.Logger, db ethdb.KeyValueStore) *SafeDB {
+	return &SafeDB{log: log, db: db}
+}
+
+func safeByL1BlockNumKey(l1BlockNum uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, safeByL1BlockNumPrefix...), math.MaxUint64-l1BlockNum)
+}
+
+func encodeEntry(l1 common.Hash, safeHead eth.BlockID) []byte {
+	out := make([]byte, 0, entrySize)
+	out = append(out, l1[:]...)
+	out = append(out, safeHead.Hash[:]...)
+	return binary.BigEndian.AppendUint64(out, safeHead.Number)
+}
+
+func decodeEntry(key []byte, value []byte) (l1 eth.BlockID, safeHead eth.BlockID, err error) {
+	if len(key) != len(safeByL1BlockNumPrefix)+8 || len(value) != entrySize {
+		return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("invalid safe head entry, key length %d, value length %d", len(key), len(value))
+	}
+	l1.Number = math.MaxUint64 - binary.BigEndian.Uint64(key[len(safeByL1BlockNumPrefix):])
+	copy(l1.Hash[:], value[:common.HashLength])
+	copy(safeHead.Hash[:], value[common.HashLength:2*common.HashLength])
+	safeHead.Number = binary.BigEndian.Uint64(value[2*common.HashLength:])
+	return l1, safeHead, nil
+}
+
+// SafeHeadUpdated records the safe head that was derived from the given L1 block.
+func (d *SafeDB) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Block eth.BlockID) error {
+	d.log.Debug("Record safe head", "l2", safeHead.ID(), "l1", l1Block)
+	if err := d.db.Put(safeByL1BlockNumKey(l1Block.Number), encodeEntry(l1Block.Hash, safeHead.ID())); err != nil {
+		return fmt.Errorf("failed to record safe head %s at L1 block %s: %w", safeHead.ID(), l1Block, err)
+	}
+	return nil
+}
+
+// SafeHeadReset removes the entries of safe heads after the given safe head, or conflicting with it.
+// Of the entries that recorded the given safe head itself, only the one of the earliest L1 block is kept:
+// the safe head is derived again from the L1 blocks after it.
+func (d *SafeDB) SafeHeadReset(safeHead eth.L2BlockRef) error {
+	iter := d.db.NewIterator(safeByL1BlockNumPrefix, nil)
+	defer iter.Release()
+	// entries are ordered from the latest L1 block, and the safe head only increases with the L1 block number
+	type entry struct {
+		key    []byte
+		l1, l2 eth.BlockID
+	}
+	var stale []entry
+	for iter.Next() {
+		l1, l2, err := decodeEntry(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+		if l2.Number < safeHead.Number {
+			break
+		}
+		stale = append(stale, entry{key: common.CopyBytes(iter.Key()), l1: l1, l2: l2})
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	if len(stale) > 0 && stale[len(stale)-1].l2 == safeHead.ID() {
+		stale = stale[:len(stale)-1]
+	}
+	batch := d.db.NewBatch()
+	for _, e := range stale {
+		d.log.Debug("Remove reset safe head", "l2", e.l2, "l1", e.l1)
+		if err := batch.Delete(e.key); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to remove safe heads after reset to %s: %w", safeHead.ID(), err)
+	}
+	return nil
+}
+
+// SafeHeadAtL1 returns the last recorded safe head at or before the given L1 block number,
+// and the L1 block it was derived from.
+func (d *SafeDB) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1 eth.BlockID, safeHead eth.BlockID, err error) {
+	key := safeByL1BlockNumKey(l1BlockNum)
+	iter := d.db.NewIterator(safeByL1BlockNumPrefix, key[len(safeByL1BlockNumPrefix):])
+	defer iter.Release()
+	if !iter.Next() {
+		if err := iter.Error(); err != nil {
+			return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to iterate safe heads: %w", err)
+		}
+		return eth.BlockID{}, eth.BlockID{}, ErrNotFound
+	}
+	return decodeEntry(iter.Key(), iter.Value())
+}
+
+func (d *SafeDB) Close() error {
+	return d.db.Close()
+}
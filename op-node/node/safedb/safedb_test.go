@@ -0,0 +1,129 @@
+package safedb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func l2Ref(num uint64, fork byte) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: common.Hash{fork, byte(num)}, Number: num}
+}
+
+func l1ID(num uint64) eth.BlockID {
+	return eth.BlockID{Hash: common.Hash{0xaa, byte(num)}, Number: num}
+}
+
+func TestSafeHeadAtL1(t *testing.T) {
+	db := newSafeDB(testlog.Logger(t, log.LvlCrit), memorydb.New())
+	ctx := context.Background()
+
+	_, _, err := db.SafeHeadAtL1(ctx, 100)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 0), l1ID(10)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(25, 0), l1ID(12)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(26, 0), l1ID(12))) // later safe head derived from the same L1 block
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(30, 0), l1ID(15)))
+
+	_, _, err = db.SafeHeadAtL1(ctx, 9)
+	require.ErrorIs(t, err, ErrNotFound, "nothing recorded before the first L1 block")
+
+	for _, tc := range []struct {
+		l1Num    uint64
+		l1       eth.BlockID
+		safeHead eth.BlockID
+	}{
+		{10, l1ID(10), l2Ref(20, 0).ID()},
+		{11, l1ID(10), l2Ref(20, 0).ID()},
+		{12, l1ID(12), l2Ref(26, 0).ID()},
+		{14, l1ID(12), l2Ref(26, 0).ID()},
+		{15, l1ID(15), l2Ref(30, 0).ID()},
+		{1000, l1ID(15), l2Ref(30, 0).ID()},
+	} {
+		l1, safeHead, err := db.SafeHeadAtL1(ctx, tc.l1Num)
+		require.NoError(t, err)
+		require.Equal(t, tc.l1, l1, "L1 block at %d", tc.l1Num)
+		require.Equal(t, tc.safeHead, safeHead, "safe head at %d", tc.l1Num)
+	}
+}
+
+func TestSafeHeadReset(t *testing.T) {
+	db := newSafeDB(testlog.Logger(t, log.LvlCrit), memorydb.New())
+	ctx := context.Background()
+
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 0), l1ID(10)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(25, 0), l1ID(12)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(30, 0), l1ID(15)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(35, 0), l1ID(18)))
+
+	// reset to the safe head recorded at L1 block 12 keeps that entry
+	require.NoError(t, db.SafeHeadReset(l2Ref(25, 0)))
+	l1, safeHead, err := db.SafeHeadAtL1(ctx, 18)
+	require.NoError(t, err)
+	require.Equal(t, l1ID(12), l1)
+	require.Equal(t, l2Ref(25, 0).ID(), safeHead)
+
+	// reset to a conflicting safe head of the same number removes it
+	require.NoError(t, db.SafeHeadReset(l2Ref(25, 1)))
+	l1, safeHead, err = db.SafeHeadAtL1(ctx, 18)
+	require.NoError(t, err)
+	require.Equal(t, l1ID(10), l1)
+	require.Equal(t, l2Ref(20, 0).ID(), safeHead)
+
+	// re-derived safe heads are recorded again
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(27, 1), l1ID(13)))
+	l1, safeHead, err = db.SafeHeadAtL1(ctx, 18)
+	require.NoError(t, err)
+	require.Equal(t, l1ID(13), l1)
+	require.Equal(t, l2Ref(27, 1).ID(), safeHead)
+
+	// reset before all entries removes everything
+	require.NoError(t, db.SafeHeadReset(l2Ref(5, 0)))
+	_, _, err = db.SafeHeadAtL1(ctx, 18)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSafeHeadResetSharedSafeHead(t *testing.T) {
+	db := newSafeDB(testlog.Logger(t, log.LvlCrit), memorydb.New())
+	ctx := context.Background()
+
+	// the safe head does not change with every L1 block
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 0), l1ID(10)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(25, 0), l1ID(12)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(25, 0), l1ID(13)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(25, 0), l1ID(14)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(30, 0), l1ID(15)))
+
+	require.NoError(t, db.SafeHeadReset(l2Ref(25, 0)))
+	for _, l1Num := range []uint64{12, 13, 14, 15} {
+		l1, safeHead, err := db.SafeHeadAtL1(ctx, l1Num)
+		require.NoError(t, err)
+		require.Equal(t, l1ID(12), l1, "only the earliest entry of the reset safe head is kept")
+		require.Equal(t, l2Ref(25, 0).ID(), safeHead)
+	}
+}
+
+func TestSafeDBPersists(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlCrit)
+	dir := t.TempDir()
+	db, err := NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 0), l1ID(10)))
+	require.NoError(t, db.Close())
+
+	db, err = NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	l1, safeHead, err := db.SafeHeadAtL1(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, l1ID(10), l1)
+	require.Equal(t, l2Ref(20, 0).ID(), safeHead)
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	ophttp "github.com/ethereum-optimism/optimism/op-node/http"
 	"github.com/ethereum/go-ethereum/log"
@@ -21,6 +22,7 @@ import (
 type rpcServer struct {
 	endpoint   string
 	apis       []rpc.API
+	srv        *rpc.Server
 	httpServer *http.Server
 	appVersion string
 	listenAddr net.Addr
@@ -28,8 +30,8 @@ type rpcServer struct {
 	sources.L2Client
 }
 
-func newRPCServer(ctx context.Context, rpcCfg *RPCConfig, rollupCfg *rollup.Config, l2Client l2EthClient, dr driverClient, log log.Logger, appVersion string, m metrics.Metricer) (*rpcServer, error) {
-	api := NewNodeAPI(rollupCfg, l2Client, dr, log.New("rpc", "node"), m)
+func newRPCServer(ctx context.Context, rpcCfg *RPCConfig, rollupCfg *rollup.Config, l2Client l2EthClient, dr driverClient, safeDB SafeDBReader, log log.Logger, appVersion string, m metrics.Metricer) (*rpcServer, error) {
+	api := NewNodeAPI(rollupCfg, l2Client, dr, safeDB, log.New("rpc", "node"), m)
 	// TODO: extend RPC config with options for WS, IPC and HTTP RPC connections
 	endpoint := net.JoinHostPort(rpcCfg.ListenAddr, strconv.Itoa(rpcCfg.ListenPort))
 	r := &rpcServer{
@@ -63,20 +65,38 @@ func (s *rpcServer) EnableP2P(backend *p2p.APIBackend) {
 	})
 }
 
+// EnableSyncStatusSubscription serves the optimism_subscribe("syncStatus") subscription over websocket connections.
+func (s *rpcServer) EnableSyncStatusSubscription(api *syncStatusSubscriptionAPI) {
+	s.apis = append(s.apis, rpc.API{
+		Namespace:     "optimism",
+		Service:       api,
+		Authenticated: false,
+	})
+}
+
 func (s *rpcServer) Start() error {
 	srv := rpc.NewServer()
 	if err := node.RegisterApis(s.apis, nil, srv); err != nil {
 		return err
 	}
+	s.srv = srv
 
 	// The CORS and VHosts arguments below must be set in order for
 	// other services to connect to the opnode. VHosts in particular
 	// defaults to localhost, which will prevent containers from
 	// calling into the opnode without an "invalid host" error.
 	nodeHandler := node.NewHTTPHandlerStack(srv, []string{"*"}, []string{"*"}, nil)
+	wsHandler := node.NewWSHandlerStack(srv.WebsocketHandler([]string{"*"}), nil)
 
 	mux := http.NewServeMux()
-	mux.Handle("/", nodeHandler)
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// subscriptions are only available over websocket connections
+		if isWebsocket(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		nodeHandler.ServeHTTP(w, r)
+	}))
 	mux.HandleFunc("/healthz", healthzHandler(s.appVersion))
 
 	listener, err := net.Listen("tcp", s.endpoint)
@@ -96,12 +116,19 @@ func (s *rpcServer) Start() error {
 
 func (r *rpcServer) Stop() {
 	_ = r.httpServer.Shutdown(context.Background())
+	// the http server does not close the hijacked websocket connections, the rpc server does
+	r.srv.Stop()
 }
 
 func (r *rpcServer) Addr() net.Addr {
 	return r.listenAddr
 }
 
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
 func healthzHandler(appVersion string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte(appVersion))
@@ -3,10 +3,13 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -14,10 +17,13 @@ import (
 	rpcclient "github.com/ethereum-optimism/optimism/op-node/client"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
 	"github.com/ethereum-optimism/optimism/op-node/version"
@@ -102,7 +108,7 @@ func TestOutputAtBlock(t *testing.T) {
 	status := randomSyncStatus(rand.New(rand.NewSource(123)))
 	drClient.ExpectBlockRefWithStatus(0xdcdc89, ref, status, nil)
 
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	require.NoError(t, err)
 	require.NoError(t, server.Start())
 	defer server.Stop()
@@ -134,7 +140,7 @@ func TestVersion(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer server.Stop()
@@ -178,7 +184,7 @@ func TestSyncStatus(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer server.Stop()
@@ -192,6 +198,115 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, status, out)
 }
 
+type testSyncStatusFeed struct {
+	status *eth.SyncStatus
+	feed   event.Feed
+}
+
+func (f *testSyncStatusFeed) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return f.status, nil
+}
+
+func (f *testSyncStatusFeed) SubscribeSyncStatus(ch chan<- *eth.SyncStatus) event.Subscription {
+	return f.feed.Subscribe(ch)
+}
+
+func TestSyncStatusSubscription(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	rng := rand.New(rand.NewSource(1234))
+	feed := &testSyncStatusFeed{status: randomSyncStatus(rng)}
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, &rollup.Config{}, &testutils.MockL2Client{}, &mockDriverClient{}, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	server.EnableSyncStatusSubscription(NewSyncStatusSubscriptionAPI(feed, log, metrics.NoopMetrics))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "ws://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	defer client.Close()
+	rollupClient := sources.NewRollupClient(client)
+
+	// regular calls are still served over the websocket connection
+	_, err = rollupClient.Version(context.Background())
+	require.NoError(t, err)
+
+	statusCh := make(chan *eth.SyncStatus, 10)
+	sub, err := rollupClient.SubscribeSyncStatus(context.Background(), statusCh)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	receive := func() *eth.SyncStatus {
+		select {
+		case status := <-statusCh:
+			return status
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for sync status")
+		}
+		return nil
+	}
+	require.Equal(t, feed.status, receive(), "the current status is sent first")
+
+	// the server subscribed to the feed before the subscription was created
+	next := randomSyncStatus(rng)
+	require.Equal(t, 1, feed.feed.Send(next))
+	require.Equal(t, next, receive(), "changes are sent to the subscriber")
+}
+
+type mockSafeDBReader struct {
+	mock.Mock
+}
+
+func (m *mockSafeDBReader) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (eth.BlockID, eth.BlockID, error) {
+	out := m.Mock.MethodCalled("SafeHeadAtL1", l1BlockNum)
+	return out[0].(eth.BlockID), out[1].(eth.BlockID), *out[2].(*error)
+}
+
+func (m *mockSafeDBReader) ExpectSafeHeadAtL1(l1BlockNum uint64, l1 eth.BlockID, safeHead eth.BlockID, err error) {
+	m.Mock.On("SafeHeadAtL1", l1BlockNum).Once().Return(l1, safeHead, &err)
+}
+
+func TestSafeHeadAtL1Block(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	safeDB := &mockSafeDBReader{}
+	l1 := eth.BlockID{Hash: common.Hash{0xaa}, Number: 123}
+	safeHead := eth.BlockID{Hash: common.Hash{0xbb}, Number: 456}
+	safeDB.ExpectSafeHeadAtL1(125, l1, safeHead, nil)
+	safeDB.ExpectSafeHeadAtL1(100, eth.BlockID{}, eth.BlockID{}, safedb.ErrNotFound)
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		// ignore other rollup config info in this test
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, safeDB, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+
+	var out *eth.SafeHeadResponse
+	err = client.CallContext(context.Background(), &out, "optimism_safeHeadAtL1Block", hexutil.Uint64(125))
+	require.NoError(t, err)
+	require.Equal(t, &eth.SafeHeadResponse{L1Block: l1, SafeHead: safeHead}, out)
+
+	err = client.CallContext(context.Background(), &out, "optimism_safeHeadAtL1Block", hexutil.Uint64(100))
+	require.ErrorContains(t, err, safedb.ErrNotFound.Error())
+	safeDB.AssertExpectations(t)
+}
+
 type mockDriverClient struct {
 	mock.Mock
 }
@@ -224,3 +339,67 @@ func (c *mockDriverClient) StopSequencer(ctx context.Context) (common.Hash, erro
 func (c *mockDriverClient) SequencerActive(ctx context.Context) (bool, error) {
 	return c.Mock.MethodCalled("SequencerActive").Get(0).(bool), nil
 }
+
+func (c *mockDriverClient) RewindHead(ctx context.Context, num uint64, rewindSafe bool) error {
+	return *c.Mock.MethodCalled("RewindHead", num, rewindSafe).Get(0).(*error)
+}
+
+func (c *mockDriverClient) SetSequencerThrottle(ctx context.Context, interval uint64) error {
+	return *c.Mock.MethodCalled("SetSequencerThrottle", interval).Get(0).(*error)
+}
+
+func TestRewindHead(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	drClient := &mockDriverClient{}
+	rpcCfg := &RPCConfig{
+		ListenAddr:  "localhost",
+		ListenPort:  0,
+		EnableAdmin: true,
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, &rollup.Config{}, &testutils.MockL2Client{}, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	server.EnableAdminAPI(NewAdminAPI(drClient, metrics.NoopMetrics))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	rollupClient := sources.NewRollupClient(client)
+
+	var noErr error
+	drClient.Mock.On("RewindHead", uint64(1234), true).Return(&noErr)
+	require.NoError(t, rollupClient.RewindHead(context.Background(), 1234, true))
+
+	rewindErr := errors.New("cannot rewind to block 10, before finalized block")
+	drClient.Mock.On("RewindHead", uint64(10), false).Return(&rewindErr)
+	require.ErrorContains(t, rollupClient.RewindHead(context.Background(), 10, false), "before finalized block")
+	drClient.Mock.AssertExpectations(t)
+}
+
+func TestSetSequencerThrottle(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	drClient := &mockDriverClient{}
+	rpcCfg := &RPCConfig{
+		ListenAddr:  "localhost",
+		ListenPort:  0,
+		EnableAdmin: true,
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, &rollup.Config{}, &testutils.MockL2Client{}, drClient, safedb.Disabled, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	server.EnableAdminAPI(NewAdminAPI(drClient, metrics.NoopMetrics))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+	rollupClient := sources.NewRollupClient(client)
+
+	var noErr error
+	drClient.Mock.On("SetSequencerThrottle", uint64(4)).Return(&noErr)
+	require.NoError(t, rollupClient.SetSequencerThrottle(context.Background(), 4))
+
+	throttleErr := errors.New("sequencer is not enabled")
+	drClient.Mock.On("SetSequencerThrottle", uint64(0)).Return(&throttleErr)
+	require.ErrorContains(t, rollupClient.SetSequencerThrottle(context.Background(), 0), "not enabled")
+	drClient.Mock.AssertExpectations(t)
+}
@@ -0,0 +1,329 @@
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var SigningDomainAttestationsV1 = [32]byte{31: 1}
+
+const (
+	// payloadAttestationSize is the size of an encoded attestation:
+	// block hash, block number, L1 origin hash, L1 origin number, safe flag.
+	payloadAttestationSize = 32 + 8 + 32 + 8 + 1
+	// attestationHeights is the number of L2 block heights that attestations are kept for.
+	attestationHeights = 1000
+	// maxAttestationsPerHeight limits the attestations that are kept for a single L2 block height,
+	// to bound the memory used by attesters that spam conflicting attestations.
+	maxAttestationsPerHeight = 64
+	// attestationFutureHeights is the number of L2 block heights past the local unsafe head
+	// that attestations are accepted for, since attesters may process new blocks before we do.
+	attestationFutureHeights = 32
+)
+
+var ErrNoAttestationSigner = errors.New("no attestation signer")
+
+func attestationsTopicV1(cfg *rollup.Config) string {
+	return fmt.Sprintf("/optimism/%s/0/attestations", cfg.L2ChainID.String())
+}
+
+func AttestationSigningHash(cfg *rollup.Config, payloadBytes []byte) (common.Hash, error) {
+	return SigningHash(SigningDomainAttestationsV1, cfg.L2ChainID, payloadBytes)
+}
+
+// PayloadAttestation is the claim of an attester that it processed the L2 block,
+// and that the block is derived from the given L1 origin.
+// Safe attestations are made once the block is derived from L1 data,
+// unsafe attestations when the block is processed as the unsafe head.
+type PayloadAttestation struct {
+	BlockHash   common.Hash `json:"blockHash"`
+	BlockNumber uint64      `json:"blockNumber"`
+	L1Origin    eth.BlockID `json:"l1Origin"`
+	Safe        bool        `json:"safe"`
+}
+
+func NewPayloadAttestation(ref eth.L2BlockRef, safe bool) PayloadAttestation {
+	return PayloadAttestation{
+		BlockHash:   ref.Hash,
+		BlockNumber: ref.Number,
+		L1Origin:    ref.L1Origin,
+		Safe:        safe,
+	}
+}
+
+func (a *PayloadAttestation) MarshalBinary() ([]byte, error) {
+	out := make([]byte, payloadAttestationSize)
+	copy(out[0:32], a.BlockHash[:])
+	binary.BigEndian.PutUint64(out[32:40], a.BlockNumber)
+	copy(out[40:72], a.L1Origin.Hash[:])
+	binary.BigEndian.PutUint64(out[72:80], a.L1Origin.Number)
+	if a.Safe {
+		out[80] = 1
+	}
+	return out, nil
+}
+
+func (a *PayloadAttestation) UnmarshalBinary(data []byte) error {
+	if len(data) != payloadAttestationSize {
+		return fmt.Errorf("invalid attestation size %d, expected %d", len(data), payloadAttestationSize)
+	}
+	if data[80] > 1 {
+		return fmt.Errorf("invalid safe flag %d", data[80])
+	}
+	copy(a.BlockHash[:], data[0:32])
+	a.BlockNumber = binary.BigEndian.Uint64(data[32:40])
+	copy(a.L1Origin.Hash[:], data[40:72])
+	a.L1Origin.Number = binary.BigEndian.Uint64(data[72:80])
+	a.Safe = data[80] == 1
+	return nil
+}
+
+// SignedPayloadAttestation is a payload attestation, with the address that signed it.
+type SignedPayloadAttestation struct {
+	PayloadAttestation
+	Attester  common.Address `json:"attester"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// PayloadAttestations collects the attestations received from the attestations topic, by L2 block height.
+type PayloadAttestations struct {
+	log log.Logger
+
+	mu      sync.Mutex
+	heights *simplelru.LRU[uint64, []SignedPayloadAttestation]
+}
+
+func NewPayloadAttestations(log log.Logger) *PayloadAttestations {
+	heights, _ := simplelru.NewLRU[uint64, []SignedPayloadAttestation](attestationHeights, nil)
+	return &PayloadAttestations{log: log, heights: heights}
+}
+
+// Add adds the attestation, and returns false if it is already known, or if there are too many attestations at the height.
+// Attestations of different blocks at the same height are kept, and are logged as conflicting.
+func (p *PayloadAttestations) Add(att SignedPayloadAttestation) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	existing, _ := p.heights.Get(att.BlockNumber)
+	if len(existing) >= maxAttestationsPerHeight {
+		return false
+	}
+	for _, other := range existing {
+		if other.Attester == att.Attester && other.BlockHash == att.BlockHash && other.Safe == att.Safe {
+			return false
+		}
+	}
+	for _, other := range existing {
+		if other.BlockHash != att.BlockHash {
+			p.log.Warn("Conflicting payload attestations", "number", att.BlockNumber,
+				"block", att.BlockHash, "safe", att.Safe, "attester", att.Attester,
+				"other_block", other.BlockHash, "other_safe", other.Safe, "other_attester", other.Attester)
+			break
+		}
+	}
+	// copy, slices returned by AtHeight must not be modified
+	p.heights.Add(att.BlockNumber, append(existing[:len(existing):len(existing)], att))
+	return true
+}
+
+// AtHeight returns the known attestations of blocks at the given L2 block height.
+func (p *PayloadAttestations) AtHeight(num uint64) []SignedPayloadAttestation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	atts, _ := p.heights.Peek(num)
+	return atts
+}
+
+// BuildAttestationsValidator validates attestations of the given attesters, of L2 blocks within
+// the window of heights around the local unsafe head that attestations are kept for.
+// Attestations are ignored while the local unsafe head is not known yet.
+func BuildAttestationsValidator(log log.Logger, cfg *rollup.Config, attesters map[common.Address]struct{}, unsafeHead func() uint64) pubsub.ValidatorEx {
+	return func(ctx context.Context, id peer.ID, message *pubsub.Message) pubsub.ValidationResult {
+		// [REJECT] if the compression is not valid, or the message is not exactly a signed attestation
+		outLen, err := snappy.DecodedLen(message.Data)
+		if err != nil {
+			log.Warn("invalid snappy compression length data", "err", err, "peer", id)
+			return pubsub.ValidationReject
+		}
+		if outLen != 65+payloadAttestationSize {
+			log.Warn("invalid attestation size", "decoded_length", outLen, "peer", id)
+			return pubsub.ValidationReject
+		}
+		data, err := snappy.Decode(nil, message.Data)
+		if err != nil {
+			log.Warn("invalid snappy compression", "err", err, "peer", id)
+			return pubsub.ValidationReject
+		}
+		signatureBytes, payloadBytes := data[:65], data[65:]
+
+		// [REJECT] if the attestation encoding is not valid
+		var att SignedPayloadAttestation
+		if err := att.PayloadAttestation.UnmarshalBinary(payloadBytes); err != nil {
+			log.Warn("invalid attestation", "err", err, "peer", id)
+			return pubsub.ValidationReject
+		}
+
+		// [REJECT] if the signature is not valid
+		signingHash, err := AttestationSigningHash(cfg, payloadBytes)
+		if err != nil {
+			log.Warn("failed to compute attestation signing hash", "err", err, "peer", id)
+			return pubsub.ValidationReject
+		}
+		pub, err := crypto.SigToPub(signingHash[:], signatureBytes)
+		if err != nil {
+			log.Warn("invalid attestation signature", "err", err, "peer", id)
+			return pubsub.ValidationReject
+		}
+		att.Attester = crypto.PubkeyToAddress(*pub)
+		att.Signature = signatureBytes
+
+		// [REJECT] if the attester is not one of the configured attesters
+		if _, ok := attesters[att.Attester]; !ok {
+			log.Warn("attestation from unknown attester", "attester", att.Attester, "peer", id)
+			return pubsub.ValidationReject
+		}
+
+		// [IGNORE] if the block is too far from the local unsafe head: it cannot be checked, and must not evict the
+		// attestations of relevant heights. Attesters may be ahead or behind of us, so this is not penalized.
+		head := unsafeHead()
+		if head == 0 {
+			return pubsub.ValidationIgnore
+		}
+		if att.BlockNumber > head+attestationFutureHeights || att.BlockNumber+attestationHeights < head {
+			log.Debug("attestation height is too far from unsafe head", "number", att.BlockNumber, "unsafe_head", head, "peer", id)
+			return pubsub.ValidationIgnore
+		}
+
+		// remember the decoded attestation for later usage in topic subscriber.
+		message.ValidatorData = &att
+		return pubsub.ValidationAccept
+	}
+}
+
+func AttestationsHandler(onAttestation func(ctx context.Context, from peer.ID, msg *SignedPayloadAttestation) error) MessageHandler {
+	return func(ctx context.Context, from peer.ID, msg any) error {
+		att, ok := msg.(*SignedPayloadAttestation)
+		if !ok {
+			return fmt.Errorf("expected topic validator to parse and validate data into attestation, but got %T", msg)
+		}
+		return onAttestation(ctx, from, att)
+	}
+}
+
+// AttestationsTopic publishes our own attestations, if there is a signer, and collects those of the configured attesters.
+type AttestationsTopic struct {
+	log    log.Logger
+	cfg    *rollup.Config
+	topic  *pubsub.Topic
+	signer Signer // may be nil, if we do not attest
+	store  *PayloadAttestations
+	// unsafeHead is the number of the local unsafe L2 head, to validate the height of attestations against.
+	unsafeHead atomic.Uint64
+}
+
+func JoinAttestations(p2pCtx context.Context, self peer.ID, ps *pubsub.PubSub, log log.Logger, cfg *rollup.Config, signer Signer, attesters []common.Address) (*AttestationsTopic, error) {
+	out := &AttestationsTopic{
+		log:    log,
+		cfg:    cfg,
+		signer: signer,
+		store:  NewPayloadAttestations(log),
+	}
+	attesterSet := make(map[common.Address]struct{}, len(attesters))
+	for _, addr := range attesters {
+		attesterSet[addr] = struct{}{}
+	}
+	val := guardGossipValidator(log, logValidationResult(self, "validated attestation", log, BuildAttestationsValidator(log, cfg, attesterSet, out.unsafeHead.Load)))
+	topicName := attestationsTopicV1(cfg)
+	err := ps.RegisterTopicValidator(topicName,
+		val,
+		pubsub.WithValidatorTimeout(3*time.Second),
+		pubsub.WithValidatorConcurrency(4))
+	if err != nil {
+		return nil, fmt.Errorf("failed to register attestations gossip topic: %w", err)
+	}
+	topic, err := ps.Join(topicName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join attestations gossip topic: %w", err)
+	}
+	topicEvents, err := topic.EventHandler()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attestations gossip topic handler: %w", err)
+	}
+	go LogTopicEvents(p2pCtx, log.New("topic", "attestations"), topicEvents)
+
+	subscription, err := topic.Subscribe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to attestations gossip topic: %w", err)
+	}
+	out.topic = topic
+	subscriber := MakeSubscriber(log, AttestationsHandler(out.onAttestation))
+	go subscriber(p2pCtx, subscription)
+	return out, nil
+}
+
+func (a *AttestationsTopic) onAttestation(ctx context.Context, from peer.ID, att *SignedPayloadAttestation) error {
+	if a.store.Add(*att) {
+		a.log.Debug("Received payload attestation", "block", att.BlockHash, "number", att.BlockNumber,
+			"safe", att.Safe, "attester", att.Attester, "peer", from)
+	}
+	return nil
+}
+
+func (a *AttestationsTopic) PublishAttestation(ctx context.Context, att PayloadAttestation) error {
+	if a.signer == nil {
+		return ErrNoAttestationSigner
+	}
+	payloadData, err := att.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode attestation: %w", err)
+	}
+	sig, err := a.signer.Sign(ctx, SigningDomainAttestationsV1, a.cfg.L2ChainID, payloadData)
+	if err != nil {
+		return fmt.Errorf("failed to sign attestation: %w", err)
+	}
+	data := make([]byte, 0, 65+payloadAttestationSize)
+	data = append(data, sig[:]...)
+	data = append(data, payloadData...)
+	return a.topic.Publish(ctx, snappy.Encode(nil, data))
+}
+
+// OnUnsafeL2Head updates the local unsafe L2 head, that the height of received attestations is validated against.
+func (a *AttestationsTopic) OnUnsafeL2Head(ref eth.L2BlockRef) {
+	a.unsafeHead.Store(ref.Number)
+}
+
+// Attestations returns the collected attestations.
+func (a *AttestationsTopic) Attestations() *PayloadAttestations {
+	return a.store
+}
+
+func (a *AttestationsTopic) Close() error {
+	var result *multierror.Error
+	if a.signer != nil {
+		if err := a.signer.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close attestation signer: %w", err))
+		}
+	}
+	if err := a.topic.Close(); err != nil {
+		result = multierror.Append(result, fmt.Errorf("failed to close attestations topic: %w", err))
+	}
+	return result.ErrorOrNil()
+}
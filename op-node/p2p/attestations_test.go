@@ -0,0 +1,144 @@
+package p2p
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/golang/snappy"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func TestPayloadAttestationRoundTrip(t *testing.T) {
+	att := PayloadAttestation{
+		BlockHash:   common.Hash{1},
+		BlockNumber: 1234,
+		L1Origin:    eth.BlockID{Hash: common.Hash{2}, Number: 56},
+		Safe:        true,
+	}
+	data, err := att.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, payloadAttestationSize)
+	var out PayloadAttestation
+	require.NoError(t, out.UnmarshalBinary(data))
+	require.Equal(t, att, out)
+
+	data[80] = 2
+	require.ErrorContains(t, out.UnmarshalBinary(data), "invalid safe flag")
+	require.ErrorContains(t, out.UnmarshalBinary(data[:80]), "invalid attestation size")
+}
+
+func TestAttestationsValidator(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlCrit)
+	cfg := &rollup.Config{L2ChainID: big.NewInt(100)}
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	attesters := map[common.Address]struct{}{crypto.PubkeyToAddress(priv.PublicKey): {}}
+	unsafeHead := uint64(10)
+	val := BuildAttestationsValidator(logger, cfg, attesters, func() uint64 { return unsafeHead })
+	att := PayloadAttestation{BlockHash: common.Hash{1}, BlockNumber: 10, L1Origin: eth.BlockID{Hash: common.Hash{2}, Number: 5}}
+	payloadData, err := att.MarshalBinary()
+	require.NoError(t, err)
+
+	signedMessage := func(priv *ecdsa.PrivateKey, data []byte) *pubsub.Message {
+		sig, err := NewLocalSigner(priv).Sign(context.Background(), SigningDomainAttestationsV1, cfg.L2ChainID, data)
+		require.NoError(t, err)
+		return &pubsub.Message{Message: &pb.Message{Data: snappy.Encode(nil, append(sig[:], data...))}}
+	}
+	message := func(data []byte) *pubsub.Message {
+		return signedMessage(priv, data)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		msg := message(payloadData)
+		require.Equal(t, pubsub.ValidationAccept, val(context.Background(), "alice", msg))
+		signed, ok := msg.ValidatorData.(*SignedPayloadAttestation)
+		require.True(t, ok)
+		require.Equal(t, att, signed.PayloadAttestation)
+		require.Equal(t, crypto.PubkeyToAddress(priv.PublicKey), signed.Attester)
+	})
+
+	t.Run("UnknownAttester", func(t *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		require.Equal(t, pubsub.ValidationReject, val(context.Background(), "alice", signedMessage(other, payloadData)))
+	})
+
+	t.Run("HeightWindow", func(t *testing.T) {
+		at := func(num uint64) *pubsub.Message {
+			att := att
+			att.BlockNumber = num
+			data, err := att.MarshalBinary()
+			require.NoError(t, err)
+			return message(data)
+		}
+		defer func() { unsafeHead = 10 }()
+		unsafeHead = 0
+		require.Equal(t, pubsub.ValidationIgnore, val(context.Background(), "alice", at(10)), "unsafe head unknown")
+		unsafeHead = 2000
+		require.Equal(t, pubsub.ValidationAccept, val(context.Background(), "alice", at(2000+attestationFutureHeights)))
+		require.Equal(t, pubsub.ValidationIgnore, val(context.Background(), "alice", at(2000+attestationFutureHeights+1)), "too far ahead")
+		require.Equal(t, pubsub.ValidationAccept, val(context.Background(), "alice", at(2000-attestationHeights)))
+		require.Equal(t, pubsub.ValidationIgnore, val(context.Background(), "alice", at(2000-attestationHeights-1)), "too old")
+	})
+
+	t.Run("InvalidSize", func(t *testing.T) {
+		msg := message(payloadData[:80])
+		require.Equal(t, pubsub.ValidationReject, val(context.Background(), "alice", msg))
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		data := append(make([]byte, 65), payloadData...)
+		msg := &pubsub.Message{Message: &pb.Message{Data: snappy.Encode(nil, data)}}
+		require.Equal(t, pubsub.ValidationReject, val(context.Background(), "alice", msg))
+	})
+
+	t.Run("InvalidCompression", func(t *testing.T) {
+		msg := &pubsub.Message{Message: &pb.Message{Data: []byte{0xff, 0xff, 0xff}}}
+		require.Equal(t, pubsub.ValidationReject, val(context.Background(), "alice", msg))
+	})
+}
+
+func TestPayloadAttestations(t *testing.T) {
+	atts := NewPayloadAttestations(testlog.Logger(t, log.LvlCrit))
+	require.Empty(t, atts.AtHeight(10))
+
+	a := SignedPayloadAttestation{
+		PayloadAttestation: PayloadAttestation{BlockHash: common.Hash{1}, BlockNumber: 10},
+		Attester:           common.Address{0xaa},
+	}
+	require.True(t, atts.Add(a))
+	require.False(t, atts.Add(a), "duplicate attestation")
+
+	safe := a
+	safe.Safe = true
+	require.True(t, atts.Add(safe), "safe attestation of the same block")
+
+	conflict := a
+	conflict.BlockHash = common.Hash{2}
+	conflict.Attester = common.Address{0xbb}
+	require.True(t, atts.Add(conflict), "conflicting attestations are kept")
+
+	require.Equal(t, []SignedPayloadAttestation{a, safe, conflict}, atts.AtHeight(10))
+
+	for i := 3; i < maxAttestationsPerHeight; i++ {
+		spam := a
+		spam.Attester = common.Address{byte(i)}
+		require.True(t, atts.Add(spam))
+	}
+	spam := a
+	spam.Attester = common.Address{0xff}
+	require.False(t, atts.Add(spam), "attestations per height are limited")
+	require.Len(t, atts.AtHeight(10), maxAttestationsPerHeight)
+}
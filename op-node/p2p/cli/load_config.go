@@ -22,6 +22,8 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/ethereum/go-ethereum/common"
+	gcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
@@ -65,6 +67,10 @@ func NewConfig(ctx *cli.Context, rollupCfg *rollup.Config) (*p2p.Config, error)
 
 	conf.EnableReqRespSync = ctx.Bool(flags.SyncReqRespFlag.Name)
 
+	if err := loadAttestationOptions(conf, ctx); err != nil {
+		return nil, fmt.Errorf("failed to load attestation options: %w", err)
+	}
+
 	return conf, nil
 }
 
@@ -331,3 +337,21 @@ func loadGossipOptions(conf *p2p.Config, ctx *cli.Context) error {
 	conf.FloodPublish = ctx.Bool(flags.GossipFloodPublishFlag.Name)
 	return nil
 }
+
+func loadAttestationOptions(conf *p2p.Config, ctx *cli.Context) error {
+	conf.EnableAttestations = ctx.Bool(flags.AttestationsFlag.Name)
+	if key := ctx.String(flags.AttestationsKeyFlag.Name); key != "" {
+		priv, err := gcrypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+		if err != nil {
+			return fmt.Errorf("failed to read attestation key: %w", err)
+		}
+		conf.AttestationKey = priv
+	}
+	for _, addr := range ctx.StringSlice(flags.AttestationsAttestersFlag.Name) {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid attester address: %q", addr)
+		}
+		conf.AttestationAttesters = append(conf.AttestationAttesters, common.HexToAddress(addr))
+	}
+	return nil
+}
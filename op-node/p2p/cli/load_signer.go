@@ -8,14 +8,17 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
 )
 
-// TODO: implement remote signer setup (config to authenticated endpoint)
-// and remote signer itself (e.g. a open http client to make signing requests)
-
 // LoadSignerSetup loads a configuration for a Signer to be set up later
 func LoadSignerSetup(ctx *cli.Context) (p2p.SignerSetup, error) {
 	key := ctx.String(flags.SequencerP2PKeyFlag.Name)
+	endpoint := ctx.String(flags.SequencerP2PSignerEndpointFlag.Name)
+	if key != "" && endpoint != "" {
+		return nil, fmt.Errorf("only one of %s and %s can be set", flags.SequencerP2PKeyFlag.Name, flags.SequencerP2PSignerEndpointFlag.Name)
+	}
 	if key != "" {
 		// Mnemonics are bad because they leak *all* keys when they leak.
 		// Unencrypted keys from file are bad because they are easy to leak (and we are not checking file permissions).
@@ -27,7 +30,21 @@ func LoadSignerSetup(ctx *cli.Context) (p2p.SignerSetup, error) {
 		return &p2p.PreparedSigner{Signer: p2p.NewLocalSigner(priv)}, nil
 	}
 
-	// TODO: create remote signer
+	if endpoint != "" {
+		cfg := p2p.RemoteSignerConfig{
+			CLIConfig: opsigner.CLIConfig{
+				Endpoint:  endpoint,
+				Address:   ctx.String(flags.SequencerP2PSignerAddressFlag.Name),
+				TLSConfig: optls.ReadCLIConfigWithPrefix(ctx, flags.SequencerP2PSignerFlagPrefix),
+			},
+			Timeout:     ctx.Duration(flags.SequencerP2PSignerTimeoutFlag.Name),
+			MaxAttempts: ctx.Int(flags.SequencerP2PSignerMaxAttemptsFlag.Name),
+		}
+		if err := cfg.Check(); err != nil {
+			return nil, fmt.Errorf("invalid remote signer config: %w", err)
+		}
+		return &p2p.RemoteSignerSetup{Config: cfg}, nil
+	}
 
 	return nil, nil
 }
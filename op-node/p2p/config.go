@@ -1,6 +1,7 @@
 package p2p
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"net"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/p2p/gating"
 
+	"github.com/ethereum/go-ethereum/common"
+	gcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -49,6 +52,12 @@ type SetupP2P interface {
 	BanDuration() time.Duration
 	GossipSetupConfigurables
 	ReqRespSyncEnabled() bool
+	// AttestationsEnabled returns true if the payload attestations topic is joined
+	AttestationsEnabled() bool
+	// AttestationSigner returns the signer of our own payload attestations, nil if we do not attest
+	AttestationSigner() Signer
+	// Attesters returns the addresses of the attesters whose payload attestations are accepted
+	Attesters() []common.Address
 }
 
 // ScoringParams defines the various types of peer scoring parameters.
@@ -116,6 +125,14 @@ type Config struct {
 	Store ds.Batching
 
 	EnableReqRespSync bool
+
+	// Whether to join the payload attestations topic, to collect the attestations of other verifiers.
+	EnableAttestations bool
+	// Key to sign and publish our own payload attestations with, optional.
+	AttestationKey *ecdsa.PrivateKey
+	// Addresses of the attesters whose payload attestations are accepted. Required if attestations are enabled.
+	// The address of the AttestationKey is accepted as well.
+	AttestationAttesters []common.Address
 }
 
 func DefaultConnManager(conf *Config) (connmgr.ConnManager, error) {
@@ -158,6 +175,25 @@ func (conf *Config) ReqRespSyncEnabled() bool {
 	return conf.EnableReqRespSync
 }
 
+func (conf *Config) AttestationsEnabled() bool {
+	return conf.EnableAttestations
+}
+
+func (conf *Config) AttestationSigner() Signer {
+	if conf.AttestationKey == nil {
+		return nil
+	}
+	return NewLocalSigner(conf.AttestationKey)
+}
+
+func (conf *Config) Attesters() []common.Address {
+	attesters := append([]common.Address(nil), conf.AttestationAttesters...)
+	if conf.AttestationKey != nil {
+		attesters = append(attesters, gcrypto.PubkeyToAddress(conf.AttestationKey.PublicKey))
+	}
+	return attesters
+}
+
 const maxMeshParam = 1000
 
 func (conf *Config) Check() error {
@@ -187,5 +223,11 @@ func (conf *Config) Check() error {
 	if conf.MeshDLazy <= 0 || conf.MeshDLazy > maxMeshParam {
 		return fmt.Errorf("mesh Dlazy param must not be 0 or exceed %d, but got %d", maxMeshParam, conf.MeshDLazy)
 	}
+	if conf.AttestationKey != nil && !conf.EnableAttestations {
+		return errors.New("attestation key is set, but the attestations topic is not enabled")
+	}
+	if conf.EnableAttestations && len(conf.AttestationAttesters) == 0 {
+		return errors.New("attestations topic is enabled, but no attesters are configured")
+	}
 	return nil
 }
@@ -0,0 +1,37 @@
+package gating
+
+import (
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// TrustedPeers is the set of peers that is exempt from connection gating.
+type TrustedPeers interface {
+	IsTrustedPeer(id peer.ID) bool
+}
+
+// TrustedConnectionGater enhances a ConnectionGater by exempting trusted peers from gating:
+// connections with trusted peers are allowed, even if the peer is blocked, banned or has a low score.
+// Trusted peers are identified by peer ID only: inbound connections are accepted before the peer ID is known,
+// and are thus still subject to the address gating of InterceptAccept, like those of any other peer.
+type TrustedConnectionGater struct {
+	BlockingConnectionGater
+	trusted TrustedPeers
+}
+
+func AddTrusted(gater BlockingConnectionGater, trusted TrustedPeers) *TrustedConnectionGater {
+	return &TrustedConnectionGater{BlockingConnectionGater: gater, trusted: trusted}
+}
+
+func (g *TrustedConnectionGater) InterceptPeerDial(p peer.ID) (allow bool) {
+	return g.trusted.IsTrustedPeer(p) || g.BlockingConnectionGater.InterceptPeerDial(p)
+}
+
+func (g *TrustedConnectionGater) InterceptAddrDial(id peer.ID, ma multiaddr.Multiaddr) (allow bool) {
+	return g.trusted.IsTrustedPeer(id) || g.BlockingConnectionGater.InterceptAddrDial(id, ma)
+}
+
+func (g *TrustedConnectionGater) InterceptSecured(dir network.Direction, id peer.ID, mas network.ConnMultiaddrs) (allow bool) {
+	return g.trusted.IsTrustedPeer(id) || g.BlockingConnectionGater.InterceptSecured(dir, id, mas)
+}
@@ -0,0 +1,96 @@
+package gating
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-node/p2p/gating/mocks"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+type testTrustedPeers []peer.AddrInfo
+
+func (s testTrustedPeers) IsTrustedPeer(id peer.ID) bool {
+	for _, info := range s {
+		if info.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func trustedTestSetup(t *testing.T) (peer.ID, *mocks.BlockingConnectionGater, *TrustedConnectionGater) {
+	alice := peer.ID("alice")
+	addr, err := multiaddr.NewMultiaddr("/ip4/1.2.3.4/tcp/9000")
+	require.NoError(t, err)
+	mockGater := mocks.NewBlockingConnectionGater(t)
+	gater := AddTrusted(mockGater, testTrustedPeers{{ID: alice, Addrs: []multiaddr.Multiaddr{addr}}})
+	return alice, mockGater, gater
+}
+
+func TestTrustedConnectionGater_InterceptPeerDial(t *testing.T) {
+	t.Run("trusted peer", func(t *testing.T) {
+		alice, _, gater := trustedTestSetup(t)
+		require.True(t, gater.InterceptPeerDial(alice))
+	})
+	t.Run("untrusted peer", func(t *testing.T) {
+		_, mockGater, gater := trustedTestSetup(t)
+		mockGater.EXPECT().InterceptPeerDial(peer.ID("mallory")).Return(false)
+		require.False(t, gater.InterceptPeerDial("mallory"))
+	})
+}
+
+func TestTrustedConnectionGater_InterceptAccept(t *testing.T) {
+	t.Run("trusted IP is gated", func(t *testing.T) {
+		_, mockGater, gater := trustedTestSetup(t)
+		addr, err := multiaddr.NewMultiaddr("/ip4/1.2.3.4/tcp/1234")
+		require.NoError(t, err)
+		mas := localRemoteAddrs{remote: addr}
+		mockGater.EXPECT().InterceptAccept(mas).Return(false)
+		require.False(t, gater.InterceptAccept(mas), "the IP of a trusted peer does not bypass an IP ban")
+	})
+	t.Run("untrusted IP", func(t *testing.T) {
+		_, mockGater, gater := trustedTestSetup(t)
+		addr, err := multiaddr.NewMultiaddr("/ip4/5.6.7.8/tcp/9000")
+		require.NoError(t, err)
+		mas := localRemoteAddrs{remote: addr}
+		mockGater.EXPECT().InterceptAccept(mas).Return(false)
+		require.False(t, gater.InterceptAccept(mas))
+	})
+}
+
+// TestTrustedConnectionGater_UntrustedPeerFromTrustedIP ensures that an untrusted peer that connects
+// from the IP of a trusted peer does not bypass the IP ban of the expiry gater.
+func TestTrustedConnectionGater_UntrustedPeerFromTrustedIP(t *testing.T) {
+	cl, mockExpiryStore, mockGater, expiryGater := expiryTestSetup(t)
+	alice := peer.ID("alice")
+	trustedAddr, err := multiaddr.NewMultiaddr("/ip4/1.2.3.4/tcp/9000")
+	require.NoError(t, err)
+	gater := AddTrusted(expiryGater, testTrustedPeers{{ID: alice, Addrs: []multiaddr.Multiaddr{trustedAddr}}})
+
+	addr, err := multiaddr.NewMultiaddr("/ip4/1.2.3.4/tcp/1234")
+	require.NoError(t, err)
+	mas := localRemoteAddrs{remote: addr}
+	mockGater.EXPECT().InterceptAccept(mas).Return(true)
+	mockExpiryStore.EXPECT().GetIPBanExpiration(net.IPv4(1, 2, 3, 4).To4()).Return(cl.Now().Add(time.Second), nil)
+	require.False(t, gater.InterceptAccept(mas), "banned IP of a trusted peer is rejected")
+}
+
+func TestTrustedConnectionGater_InterceptSecured(t *testing.T) {
+	addr, err := multiaddr.NewMultiaddr("/ip4/5.6.7.8/tcp/9000")
+	require.NoError(t, err)
+	mas := localRemoteAddrs{remote: addr}
+	t.Run("trusted peer", func(t *testing.T) {
+		alice, _, gater := trustedTestSetup(t)
+		require.True(t, gater.InterceptSecured(network.DirInbound, alice, mas))
+	})
+	t.Run("untrusted peer", func(t *testing.T) {
+		_, mockGater, gater := trustedTestSetup(t)
+		mockGater.EXPECT().InterceptSecured(network.DirInbound, peer.ID("mallory"), mas).Return(false)
+		require.False(t, gater.InterceptSecured(network.DirInbound, "mallory", mas))
+	})
+}
@@ -34,6 +34,8 @@ import (
 
 const (
 	staticPeerTag = "static"
+	// trustedPeerTag protects trusted peers, separate from static peers, since trusted peers can be removed at runtime
+	trustedPeerTag = "trusted"
 )
 
 type ExtraHostFeatures interface {
@@ -49,6 +51,7 @@ type extraHost struct {
 	log     log.Logger
 
 	staticPeers []*peer.AddrInfo
+	trusted     store.TrustedPeersStore
 
 	quitC chan struct{}
 }
@@ -83,6 +86,22 @@ func (e *extraHost) initStaticPeers() {
 	}
 }
 
+// initTrustedPeers protects and dials the persisted trusted peers.
+func (e *extraHost) initTrustedPeers() {
+	for _, info := range e.trusted.TrustedPeers() {
+		info := info
+		e.Peerstore().AddAddrs(info.ID, info.Addrs, time.Hour*24*7)
+		e.connMgr.Protect(info.ID, trustedPeerTag)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+			defer cancel()
+			if err := e.dialStaticPeer(ctx, &info); err != nil {
+				e.log.Warn("error dialing trusted peer", "peer", info.ID, "err", err)
+			}
+		}()
+	}
+}
+
 func (e *extraHost) dialStaticPeer(ctx context.Context, addr *peer.AddrInfo) error {
 	e.log.Info("dialing static peer", "peer", addr.ID, "addrs", addr.Addrs)
 	if _, err := e.Network().DialPeer(ctx, addr.ID); err != nil {
@@ -101,8 +120,13 @@ func (e *extraHost) monitorStaticPeers() {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 			var wg sync.WaitGroup
 
-			e.log.Debug("polling static peers", "peers", len(e.staticPeers))
-			for _, addr := range e.staticPeers {
+			peers := e.staticPeers
+			for _, info := range e.trusted.TrustedPeers() {
+				info := info
+				peers = append(peers[:len(peers):len(peers)], &info)
+			}
+			e.log.Debug("polling static and trusted peers", "peers", len(peers))
+			for _, addr := range peers {
 				connectedness := e.Network().Connectedness(addr.ID)
 				e.log.Trace("static peer connectedness", "peer", addr.ID, "connectedness", connectedness)
 
@@ -172,6 +196,7 @@ func (conf *Config) Host(log log.Logger, reporter metrics.Reporter, metrics Host
 		return nil, fmt.Errorf("failed to open connection gater: %w", err)
 	}
 	connGtr = gating.AddBanExpiry(connGtr, ps, log, clock.SystemClock, metrics)
+	connGtr = gating.AddTrusted(connGtr, ps)
 	connGtr = gating.AddMetering(connGtr, metrics)
 
 	connMngr, err := DefaultConnManager(conf)
@@ -241,12 +266,13 @@ func (conf *Config) Host(log log.Logger, reporter metrics.Reporter, metrics Host
 		connMgr:     connMngr,
 		log:         log,
 		staticPeers: staticPeers,
+		trusted:     ps,
 		quitC:       make(chan struct{}),
 	}
 	out.initStaticPeers()
-	if len(conf.StaticPeers) > 0 {
-		go out.monitorStaticPeers()
-	}
+	out.initTrustedPeers()
+	// trusted peers may be added at runtime, the peers are always monitored
+	go out.monitorStaticPeers()
 
 	out.gater = connGtr
 	return out, nil
@@ -23,13 +23,13 @@ func (_m *PeerManager) EXPECT() *PeerManager_Expecter {
 	return &PeerManager_Expecter{mock: &_m.Mock}
 }
 
-// BanPeer provides a mock function with given fields: _a0, _a1
-func (_m *PeerManager) BanPeer(_a0 peer.ID, _a1 time.Time) error {
-	ret := _m.Called(_a0, _a1)
+// BanPeer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PeerManager) BanPeer(_a0 peer.ID, _a1 time.Time, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(peer.ID, time.Time) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(peer.ID, time.Time, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -45,13 +45,14 @@ type PeerManager_BanPeer_Call struct {
 // BanPeer is a helper method to define mock.On call
 //   - _a0 peer.ID
 //   - _a1 time.Time
-func (_e *PeerManager_Expecter) BanPeer(_a0 interface{}, _a1 interface{}) *PeerManager_BanPeer_Call {
-	return &PeerManager_BanPeer_Call{Call: _e.mock.On("BanPeer", _a0, _a1)}
+//   - _a2 string
+func (_e *PeerManager_Expecter) BanPeer(_a0 interface{}, _a1 interface{}, _a2 interface{}) *PeerManager_BanPeer_Call {
+	return &PeerManager_BanPeer_Call{Call: _e.mock.On("BanPeer", _a0, _a1, _a2)}
 }
 
-func (_c *PeerManager_BanPeer_Call) Run(run func(_a0 peer.ID, _a1 time.Time)) *PeerManager_BanPeer_Call {
+func (_c *PeerManager_BanPeer_Call) Run(run func(_a0 peer.ID, _a1 time.Time, _a2 string)) *PeerManager_BanPeer_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(peer.ID), args[1].(time.Time))
+		run(args[0].(peer.ID), args[1].(time.Time), args[2].(string))
 	})
 	return _c
 }
@@ -61,7 +62,7 @@ func (_c *PeerManager_BanPeer_Call) Return(_a0 error) *PeerManager_BanPeer_Call
 	return _c
 }
 
-func (_c *PeerManager_BanPeer_Call) RunAndReturn(run func(peer.ID, time.Time) error) *PeerManager_BanPeer_Call {
+func (_c *PeerManager_BanPeer_Call) RunAndReturn(run func(peer.ID, time.Time, string) error) *PeerManager_BanPeer_Call {
 	_c.Call.Return(run)
 	return _c
 }
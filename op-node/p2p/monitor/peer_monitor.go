@@ -22,7 +22,8 @@ type PeerManager interface {
 	GetPeerScore(id peer.ID) (float64, error)
 	IsStatic(peer.ID) bool
 	// BanPeer bans the peer until the specified time and disconnects any existing connections.
-	BanPeer(peer.ID, time.Time) error
+	// The reason is recorded in the history of the peer.
+	BanPeer(id peer.ID, expiry time.Time, reason string) error
 }
 
 // PeerMonitor runs a background process to periodically check for peers with scores below a minimum.
@@ -92,7 +93,7 @@ func (p *PeerMonitor) checkNextPeer() error {
 	if p.manager.IsStatic(id) {
 		return nil
 	}
-	if err := p.manager.BanPeer(id, p.clock.Now().Add(p.banDuration)); err != nil {
+	if err := p.manager.BanPeer(id, p.clock.Now().Add(p.banDuration), fmt.Sprintf("score %v below minimum %v", score, p.minScore)); err != nil {
 		return fmt.Errorf("banning peer %v: %w", id, err)
 	}
 
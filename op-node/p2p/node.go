@@ -42,12 +42,13 @@ type NodeP2P struct {
 	appScorer   ApplicationScorer
 	log         log.Logger
 	// the below components are all optional, and may be nil. They require the host to not be nil.
-	dv5Local *enode.LocalNode // p2p discovery identity
-	dv5Udp   *discover.UDPv5  // p2p discovery service
-	gs       *pubsub.PubSub   // p2p gossip router
-	gsOut    GossipOut        // p2p gossip application interface for publishing
-	syncCl   *SyncClient
-	syncSrv  *ReqRespServer
+	dv5Local     *enode.LocalNode   // p2p discovery identity
+	dv5Udp       *discover.UDPv5    // p2p discovery service
+	gs           *pubsub.PubSub     // p2p gossip router
+	gsOut        GossipOut          // p2p gossip application interface for publishing
+	attestations *AttestationsTopic // p2p payload attestations topic, for publishing and collecting attestations
+	syncCl       *SyncClient
+	syncSrv      *ReqRespServer
 }
 
 // NewNodeP2P creates a new p2p node, and returns a reference to it. If the p2p is disabled, it returns nil.
@@ -125,6 +126,8 @@ func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, l
 				// register the sync protocol with libp2p host
 				payloadByNumber := MakeStreamHandler(resourcesCtx, log.New("serve", "payloads_by_number"), n.syncSrv.HandleSyncRequest)
 				n.host.SetStreamHandler(PayloadByNumberProtocolID(rollupCfg.L2ChainID), payloadByNumber)
+				payloadsBatch := MakeStreamHandler(resourcesCtx, log.New("serve", "payloads_batch"), n.syncSrv.HandleBatchSyncRequest)
+				n.host.SetStreamHandler(PayloadsBatchProtocolID(rollupCfg.L2ChainID), payloadsBatch)
 			}
 		}
 		n.scorer = NewScorer(rollupCfg, eps, metrics, n.appScorer, log)
@@ -139,6 +142,12 @@ func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, l
 		if err != nil {
 			return fmt.Errorf("failed to join blocks gossip topic: %w", err)
 		}
+		if setup.AttestationsEnabled() {
+			n.attestations, err = JoinAttestations(resourcesCtx, n.host.ID(), n.gs, log, rollupCfg, setup.AttestationSigner(), setup.Attesters())
+			if err != nil {
+				return fmt.Errorf("failed to join attestations gossip topic: %w", err)
+			}
+		}
 		log.Info("started p2p host", "addrs", n.host.Addrs(), "peerID", n.host.ID().Pretty())
 
 		tcpPort, err := FindActiveTCPPort(n.host)
@@ -204,6 +213,43 @@ func (n *NodeP2P) ConnectionManager() connmgr.ConnManager {
 	return n.connMgr
 }
 
+func (n *NodeP2P) ExtendedPeerstore() store.ExtendedPeerstore {
+	return n.store
+}
+
+// PayloadAttestations returns the collected payload attestations, nil if the attestations topic is disabled.
+func (n *NodeP2P) PayloadAttestations() *PayloadAttestations {
+	if n.attestations == nil {
+		return nil
+	}
+	return n.attestations.Attestations()
+}
+
+// AttestationsEnabled returns true if the payload attestations topic is joined.
+func (n *NodeP2P) AttestationsEnabled() bool {
+	return n.attestations != nil
+}
+
+// OnUnsafeL2Head updates the local unsafe L2 head, that received payload attestations are validated against.
+func (n *NodeP2P) OnUnsafeL2Head(ref eth.L2BlockRef) {
+	if n.attestations != nil {
+		n.attestations.OnUnsafeL2Head(ref)
+	}
+}
+
+// CanAttest returns true if the node publishes its own payload attestations.
+func (n *NodeP2P) CanAttest() bool {
+	return n.attestations != nil && n.attestations.signer != nil
+}
+
+// PublishAttestation signs and publishes an attestation of the L2 block.
+func (n *NodeP2P) PublishAttestation(ctx context.Context, ref eth.L2BlockRef, safe bool) error {
+	if n.attestations == nil {
+		return errors.New("attestations topic is not enabled")
+	}
+	return n.attestations.PublishAttestation(ctx, NewPayloadAttestation(ref, safe))
+}
+
 func (n *NodeP2P) Peers() []peer.ID {
 	return n.host.Network().Peers()
 }
@@ -212,14 +258,21 @@ func (n *NodeP2P) GetPeerScore(id peer.ID) (float64, error) {
 	return n.store.GetPeerScore(id)
 }
 
+// IsStatic returns true for static and trusted peers, which are exempt from banning.
 func (n *NodeP2P) IsStatic(id peer.ID) bool {
+	if n.store != nil && n.store.IsTrustedPeer(id) {
+		return true
+	}
 	return n.connMgr != nil && n.connMgr.IsProtected(id, staticPeerTag)
 }
 
-func (n *NodeP2P) BanPeer(id peer.ID, expiration time.Time) error {
+func (n *NodeP2P) BanPeer(id peer.ID, expiration time.Time, reason string) error {
 	if err := n.store.SetPeerBanExpiration(id, expiration); err != nil {
 		return fmt.Errorf("failed to set peer ban expiry: %w", err)
 	}
+	if err := n.store.RecordPeerBan(id, expiration, reason); err != nil {
+		n.log.Warn("failed to record peer ban", "peer", id, "err", err)
+	}
 	if err := n.host.Network().ClosePeer(id); err != nil {
 		return fmt.Errorf("failed to close peer connection: %w", err)
 	}
@@ -259,6 +312,11 @@ func (n *NodeP2P) Close() error {
 			result = multierror.Append(result, fmt.Errorf("failed to close gossip cleanly: %w", err))
 		}
 	}
+	if n.attestations != nil {
+		if err := n.attestations.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close attestations gossip cleanly: %w", err))
+		}
+	}
 	if n.host != nil {
 		if err := n.host.Close(); err != nil {
 			result = multierror.Append(result, fmt.Errorf("failed to close p2p host cleanly: %w", err))
@@ -0,0 +1,130 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/retry"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+// remoteSignerRetryDelay is the delay between signing attempts, short enough to not miss the block time.
+const remoteSignerRetryDelay = 100 * time.Millisecond
+
+// BlockPayloadSigner is the signing service API used by the RemoteSigner, implemented by opsigner.SignerClient.
+type BlockPayloadSigner interface {
+	SignBlockPayload(ctx context.Context, args *opsigner.BlockPayloadArgs) ([65]byte, error)
+	Close()
+}
+
+type RemoteSignerConfig struct {
+	opsigner.CLIConfig
+
+	// Timeout of a single signing request
+	Timeout time.Duration
+	// MaxAttempts is the number of signing requests to make before giving up on signing a message
+	MaxAttempts int
+}
+
+func (c *RemoteSignerConfig) Check() error {
+	if err := c.CLIConfig.Check(); err != nil {
+		return err
+	}
+	if !common.IsHexAddress(c.Address) {
+		return fmt.Errorf("invalid remote signer address: %q", c.Address)
+	}
+	if c.Timeout <= 0 {
+		return errors.New("remote signer timeout must be positive")
+	}
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("remote signer needs at least 1 attempt, but has %d max attempts", c.MaxAttempts)
+	}
+	return nil
+}
+
+// RemoteSigner signs p2p messages with a remote signing service, so the sequencer key does not have to live
+// in the op-node process. Every signature is verified to be made by the configured address,
+// to not publish messages that the other nodes would reject.
+type RemoteSigner struct {
+	log     log.Logger
+	client  BlockPayloadSigner
+	address common.Address
+	cfg     RemoteSignerConfig
+	metrics SignerMetrics
+}
+
+func NewRemoteSigner(log log.Logger, client BlockPayloadSigner, cfg RemoteSignerConfig, metrics SignerMetrics) *RemoteSigner {
+	return &RemoteSigner{
+		log:     log,
+		client:  client,
+		address: common.HexToAddress(cfg.Address),
+		cfg:     cfg,
+		metrics: metrics,
+	}
+}
+
+func (s *RemoteSigner) Sign(ctx context.Context, domain [32]byte, chainID *big.Int, encodedMsg []byte) (sig *[65]byte, err error) {
+	signingHash, err := SigningHash(domain, chainID, encodedMsg)
+	if err != nil {
+		return nil, err
+	}
+	args := opsigner.NewBlockPayloadArgs(domain, chainID, encodedMsg, &s.address)
+	start := time.Now()
+	attempts := 0
+	signature, err := retry.Do(ctx, s.cfg.MaxAttempts, retry.Fixed(remoteSignerRetryDelay), func() ([65]byte, error) {
+		attempts += 1
+		reqCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+		signature, err := s.client.SignBlockPayload(reqCtx, args)
+		if err != nil {
+			s.log.Warn("Remote signer request failed", "attempt", attempts, "err", err)
+			return [65]byte{}, err
+		}
+		if err := s.verify(signingHash, signature); err != nil {
+			s.log.Warn("Remote signer returned invalid signature", "attempt", attempts, "err", err)
+			return [65]byte{}, err
+		}
+		return signature, nil
+	})
+	s.metrics.RecordRemoteSignerRequest(err == nil, attempts, time.Since(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with remote signer: %w", err)
+	}
+	return &signature, nil
+}
+
+func (s *RemoteSigner) verify(signingHash common.Hash, signature [65]byte) error {
+	pub, err := crypto.SigToPub(signingHash[:], signature[:])
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if addr := crypto.PubkeyToAddress(*pub); addr != s.address {
+		return fmt.Errorf("signature by %s, expected %s", addr, s.address)
+	}
+	return nil
+}
+
+func (s *RemoteSigner) Close() error {
+	s.client.Close()
+	return nil
+}
+
+// RemoteSignerSetup connects to the remote signing service when the signer is set up.
+type RemoteSignerSetup struct {
+	Config RemoteSignerConfig
+}
+
+func (r *RemoteSignerSetup) SetupSigner(ctx context.Context, log log.Logger, metrics SignerMetrics) (Signer, error) {
+	client, err := opsigner.NewSignerClientFromConfig(log, r.Config.CLIConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+	return NewRemoteSigner(log, client, r.Config, metrics), nil
+}
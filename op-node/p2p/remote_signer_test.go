@@ -0,0 +1,131 @@
+package p2p
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	opsigner "github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+type testHealthAPI struct{}
+
+func (testHealthAPI) Status() string {
+	return "test"
+}
+
+// testSignerAPI is a stand-in for the signing service, that fails the first requests
+type testSignerAPI struct {
+	priv     *ecdsa.PrivateKey
+	failures int32
+	requests int32
+}
+
+func (api *testSignerAPI) SignBlockPayload(args opsigner.BlockPayloadArgs) (hexutil.Bytes, error) {
+	if atomic.AddInt32(&api.requests, 1) <= atomic.LoadInt32(&api.failures) {
+		return nil, errors.New("temporarily unavailable")
+	}
+	msg, err := args.Message()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(msg[:], api.priv)
+}
+
+type testSignerMetrics struct {
+	success  bool
+	attempts int
+}
+
+func (m *testSignerMetrics) RecordRemoteSignerRequest(success bool, attempts int, duration time.Duration) {
+	m.success = success
+	m.attempts = attempts
+}
+
+func startTestSigner(t *testing.T, api *testSignerAPI) string {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("health", testHealthAPI{}))
+	require.NoError(t, server.RegisterName("opsigner", api))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+	return httpServer.URL
+}
+
+func TestRemoteSigner(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlCrit)
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	api := &testSignerAPI{priv: priv, failures: 2}
+	cfg := RemoteSignerConfig{
+		CLIConfig: opsigner.CLIConfig{
+			Endpoint:  startTestSigner(t, api),
+			Address:   crypto.PubkeyToAddress(priv.PublicKey).Hex(),
+			TLSConfig: optls.CLIConfig{},
+		},
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+	}
+	require.NoError(t, cfg.Check())
+
+	m := &testSignerMetrics{}
+	signer, err := (&RemoteSignerSetup{Config: cfg}).SetupSigner(context.Background(), logger, m)
+	require.NoError(t, err)
+	defer signer.Close()
+
+	chainID := big.NewInt(901)
+	msg := []byte("payload")
+	sig, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	require.Equal(t, &testSignerMetrics{success: true, attempts: 3}, m)
+
+	// the remote signature matches a local signature of the same message
+	expected, err := NewLocalSigner(priv).Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+	require.NoError(t, err)
+	require.Equal(t, expected, sig)
+
+	t.Run("give up after max attempts", func(t *testing.T) {
+		atomic.StoreInt32(&api.requests, 0)
+		atomic.StoreInt32(&api.failures, 3)
+		_, err := signer.Sign(context.Background(), SigningDomainBlocksV1, chainID, msg)
+		require.ErrorContains(t, err, "temporarily unavailable")
+		require.Equal(t, &testSignerMetrics{success: false, attempts: 3}, m)
+	})
+}
+
+func TestRemoteSignerWrongKey(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlCrit)
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cfg := RemoteSignerConfig{
+		CLIConfig: opsigner.CLIConfig{
+			Endpoint: startTestSigner(t, &testSignerAPI{priv: other}),
+			Address:  crypto.PubkeyToAddress(priv.PublicKey).Hex(),
+		},
+		Timeout:     time.Second,
+		MaxAttempts: 1,
+	}
+	m := &testSignerMetrics{}
+	signer, err := (&RemoteSignerSetup{Config: cfg}).SetupSigner(context.Background(), logger, m)
+	require.NoError(t, err)
+	defer signer.Close()
+
+	_, err = signer.Sign(context.Background(), SigningDomainBlocksV1, big.NewInt(901), []byte("payload"))
+	require.ErrorContains(t, err, "expected "+cfg.Address)
+	require.False(t, m.success)
+}
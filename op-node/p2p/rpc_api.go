@@ -59,4 +59,9 @@ type API interface {
 	UnprotectPeer(ctx context.Context, p peer.ID) error
 	ConnectPeer(ctx context.Context, addr string) error
 	DisconnectPeer(ctx context.Context, id peer.ID) error
+	PeerHistory(ctx context.Context, id peer.ID) (*store.PeerHistory, error)
+	AddTrustedPeer(ctx context.Context, addr string) error
+	RemoveTrustedPeer(ctx context.Context, id peer.ID) error
+	ListTrustedPeers(ctx context.Context) ([]peer.AddrInfo, error)
+	PayloadAttestations(ctx context.Context, num uint64) ([]SignedPayloadAttestation, error)
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/ethereum-optimism/optimism/op-node/p2p/store"
+
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -107,3 +109,29 @@ func (c *Client) ConnectPeer(ctx context.Context, addr string) error {
 func (c *Client) DisconnectPeer(ctx context.Context, id peer.ID) error {
 	return c.c.CallContext(ctx, nil, prefixRPC("disconnectPeer"), id)
 }
+
+func (c *Client) PeerHistory(ctx context.Context, id peer.ID) (*store.PeerHistory, error) {
+	var out *store.PeerHistory
+	err := c.c.CallContext(ctx, &out, prefixRPC("peerHistory"), id)
+	return out, err
+}
+
+func (c *Client) AddTrustedPeer(ctx context.Context, addr string) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("addTrustedPeer"), addr)
+}
+
+func (c *Client) RemoveTrustedPeer(ctx context.Context, id peer.ID) error {
+	return c.c.CallContext(ctx, nil, prefixRPC("removeTrustedPeer"), id)
+}
+
+func (c *Client) ListTrustedPeers(ctx context.Context) ([]peer.AddrInfo, error) {
+	var out []peer.AddrInfo
+	err := c.c.CallContext(ctx, &out, prefixRPC("listTrustedPeers"))
+	return out, err
+}
+
+func (c *Client) PayloadAttestations(ctx context.Context, num uint64) ([]SignedPayloadAttestation, error) {
+	var out []SignedPayloadAttestation
+	err := c.c.CallContext(ctx, &out, prefixRPC("payloadAttestations"), num)
+	return out, err
+}
@@ -36,6 +36,8 @@ var (
 	ErrDisabledDiscovery   = errors.New("discovery disabled")
 	ErrNoConnectionManager = errors.New("no connection manager")
 	ErrNoConnectionGater   = errors.New("no connection gater")
+	ErrNoPeerstore         = errors.New("no extended peerstore")
+	ErrNoAttestations      = errors.New("attestations topic disabled")
 )
 
 type Node interface {
@@ -53,6 +55,10 @@ type Node interface {
 	ConnectionGater() gating.BlockingConnectionGater
 	// ConnectionManager returns the connection manager, to protect peers with, may be nil
 	ConnectionManager() connmgr.ConnManager
+	// ExtendedPeerstore returns the peerstore with scores, bans, peer history and trusted peers, may be nil
+	ExtendedPeerstore() store.ExtendedPeerstore
+	// PayloadAttestations returns the collected payload attestations, nil if disabled
+	PayloadAttestations() *PayloadAttestations
 }
 
 type APIBackend struct {
@@ -251,11 +257,19 @@ func (s *APIBackend) DiscoveryTable(_ context.Context) ([]*enode.Node, error) {
 func (s *APIBackend) BlockPeer(_ context.Context, p peer.ID) error {
 	recordDur := s.m.RecordRPCServerRequest("opp2p_blockPeer")
 	defer recordDur()
-	if gater := s.node.ConnectionGater(); gater == nil {
+	gater := s.node.ConnectionGater()
+	if gater == nil {
 		return ErrNoConnectionGater
-	} else {
-		return gater.BlockPeer(p)
 	}
+	if err := gater.BlockPeer(p); err != nil {
+		return err
+	}
+	if ps := s.node.ExtendedPeerstore(); ps != nil {
+		if err := ps.RecordPeerBan(p, time.Time{}, "blocked via RPC"); err != nil {
+			s.log.Warn("failed to record peer block", "peer", p, "err", err)
+		}
+	}
+	return nil
 }
 
 func (s *APIBackend) UnblockPeer(_ context.Context, p peer.ID) error {
@@ -384,3 +398,84 @@ func (s *APIBackend) DisconnectPeer(_ context.Context, id peer.ID) error {
 	defer recordDur()
 	return s.node.Host().Network().ClosePeer(id)
 }
+
+// PeerHistory returns the sampled score components and the ban events of the peer.
+func (s *APIBackend) PeerHistory(_ context.Context, id peer.ID) (*store.PeerHistory, error) {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_peerHistory")
+	defer recordDur()
+	ps := s.node.ExtendedPeerstore()
+	if ps == nil {
+		return nil, ErrNoPeerstore
+	}
+	history, err := ps.GetPeerHistory(id)
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// AddTrustedPeer persists the peer as trusted, protects it, and connects to it.
+// Trusted peers are exempt from gating, and are reconnected to after restarts.
+func (s *APIBackend) AddTrustedPeer(ctx context.Context, addr string) error {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_addTrustedPeer")
+	defer recordDur()
+	ps := s.node.ExtendedPeerstore()
+	if ps == nil {
+		return ErrNoPeerstore
+	}
+	addrInfo, err := peer.AddrInfoFromString(addr)
+	if err != nil {
+		return fmt.Errorf("bad peer address: %w", err)
+	}
+	if err := ps.AddTrustedPeer(*addrInfo); err != nil {
+		return err
+	}
+	if manager := s.node.ConnectionManager(); manager != nil {
+		manager.Protect(addrInfo.ID, trustedPeerTag)
+	}
+	// Put a sanity limit on the connection time
+	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+	if err := s.node.Host().Connect(ctx, *addrInfo); err != nil {
+		s.log.Warn("failed to connect to trusted peer", "peer", addrInfo.ID, "err", err)
+	}
+	return nil
+}
+
+func (s *APIBackend) RemoveTrustedPeer(_ context.Context, id peer.ID) error {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_removeTrustedPeer")
+	defer recordDur()
+	ps := s.node.ExtendedPeerstore()
+	if ps == nil {
+		return ErrNoPeerstore
+	}
+	if err := ps.RemoveTrustedPeer(id); err != nil {
+		return err
+	}
+	if manager := s.node.ConnectionManager(); manager != nil {
+		manager.Unprotect(id, trustedPeerTag)
+	}
+	return nil
+}
+
+func (s *APIBackend) ListTrustedPeers(_ context.Context) ([]peer.AddrInfo, error) {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_listTrustedPeers")
+	defer recordDur()
+	ps := s.node.ExtendedPeerstore()
+	if ps == nil {
+		return nil, ErrNoPeerstore
+	}
+	return ps.TrustedPeers(), nil
+}
+
+// PayloadAttestations returns the collected attestations of L2 blocks at the given height.
+// Attestations of different blocks at the same height indicate a divergence between attesters.
+func (s *APIBackend) PayloadAttestations(_ context.Context, num uint64) ([]SignedPayloadAttestation, error) {
+	recordDur := s.m.RecordRPCServerRequest("opp2p_payloadAttestations")
+	defer recordDur()
+	atts := s.node.PayloadAttestations()
+	if atts == nil {
+		return nil, ErrNoAttestations
+	}
+	return atts.AtHeight(num), nil
+}
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"io"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 )
@@ -73,10 +75,14 @@ type PreparedSigner struct {
 	Signer
 }
 
-func (p *PreparedSigner) SetupSigner(ctx context.Context) (Signer, error) {
+func (p *PreparedSigner) SetupSigner(ctx context.Context, log log.Logger, metrics SignerMetrics) (Signer, error) {
 	return p.Signer, nil
 }
 
+type SignerMetrics interface {
+	RecordRemoteSignerRequest(success bool, attempts int, duration time.Duration)
+}
+
 type SignerSetup interface {
-	SetupSigner(ctx context.Context) (Signer, error)
+	SetupSigner(ctx context.Context, log log.Logger, metrics SignerMetrics) (Signer, error)
 }
@@ -9,15 +9,19 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum/go-ethereum/log"
 	ds "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 )
 
 type extendedStore struct {
+	log log.Logger
 	peerstore.Peerstore
 	peerstore.CertifiedAddrBook
 	*scoreBook
 	*peerBanBook
 	*ipBanBook
+	*peerHistoryBook
+	*trustedPeersBook
 }
 
 func NewExtendedPeerstore(ctx context.Context, logger log.Logger, clock clock.Clock, ps peerstore.Peerstore, store ds.Batching, scoreRetention time.Duration) (ExtendedPeerstore, error) {
@@ -40,17 +44,42 @@ func NewExtendedPeerstore(ctx context.Context, logger log.Logger, clock clock.Cl
 		return nil, fmt.Errorf("create IP ban book: %w", err)
 	}
 	ib.startGC()
+	hb, err := newPeerHistoryBook(ctx, logger, clock, store, sb)
+	if err != nil {
+		return nil, fmt.Errorf("create peer history book: %w", err)
+	}
+	hb.startGC()
+	tb, err := newTrustedPeersBook(ctx, logger, store)
+	if err != nil {
+		return nil, fmt.Errorf("create trusted peers book: %w", err)
+	}
 	return &extendedStore{
+		log:               logger,
 		Peerstore:         ps,
 		CertifiedAddrBook: cab,
 		scoreBook:         sb,
 		peerBanBook:       pb,
 		ipBanBook:         ib,
+		peerHistoryBook:   hb,
+		trustedPeersBook:  tb,
 	}, nil
 }
 
+// SetScore applies the score diff, and samples the updated scores into the peer history.
+func (s *extendedStore) SetScore(id peer.ID, diff ScoreDiff) (PeerScores, error) {
+	scores, err := s.scoreBook.SetScore(id, diff)
+	if err != nil {
+		return scores, err
+	}
+	if err := s.peerHistoryBook.recordScores(id, scores); err != nil {
+		s.log.Warn("Failed to record peer score history", "peer", id, "err", err)
+	}
+	return scores, nil
+}
+
 func (s *extendedStore) Close() error {
 	s.scoreBook.Close()
+	s.peerHistoryBook.Close()
 	return s.Peerstore.Close()
 }
 
@@ -120,6 +120,47 @@ type IPBanStore interface {
 	GetIPBanExpiration(ip net.IP) (time.Time, error)
 }
 
+// PeerScoreSample is the score of a peer at a point in time
+type PeerScoreSample struct {
+	Time   int64      `json:"time"` // unix timestamp in seconds
+	Scores PeerScores `json:"scores"`
+}
+
+// PeerBanEvent records why a peer was banned, and its scores at the time of the ban
+type PeerBanEvent struct {
+	Time   int64      `json:"time"`   // unix timestamp in seconds
+	Expiry int64      `json:"expiry"` // unix timestamp in seconds, 0 if the ban does not expire
+	Reason string     `json:"reason"`
+	Scores PeerScores `json:"scores"`
+}
+
+// PeerHistory is the recorded reputation of a peer, oldest entries first
+type PeerHistory struct {
+	Scores []PeerScoreSample `json:"scores"`
+	Bans   []PeerBanEvent    `json:"bans"`
+}
+
+type PeerHistoryStore interface {
+	// RecordPeerBan records that the peer was banned until the expiry time, for the given reason.
+	// A zero expiry time records a ban that does not expire.
+	RecordPeerBan(id peer.ID, expiry time.Time, reason string) error
+	// GetPeerHistory returns the recorded score samples and bans of the peer.
+	// A peer without any recorded history returns an empty history.
+	GetPeerHistory(id peer.ID) (PeerHistory, error)
+}
+
+// TrustedPeersStore persists the trusted peers, which are exempt from gating and kept connected.
+type TrustedPeersStore interface {
+	// AddTrustedPeer adds the peer to the trusted peers, or replaces its known addresses if it is already trusted.
+	AddTrustedPeer(info peer.AddrInfo) error
+	// RemoveTrustedPeer removes the peer from the trusted peers. It is a no-op if the peer is not trusted.
+	RemoveTrustedPeer(id peer.ID) error
+	// IsTrustedPeer returns true if the peer is trusted.
+	IsTrustedPeer(id peer.ID) bool
+	// TrustedPeers returns all trusted peers.
+	TrustedPeers() []peer.AddrInfo
+}
+
 // ExtendedPeerstore defines a type-safe API to work with additional peer metadata based on a libp2p peerstore.Peerstore
 type ExtendedPeerstore interface {
 	peerstore.Peerstore
@@ -127,4 +168,6 @@ type ExtendedPeerstore interface {
 	peerstore.CertifiedAddrBook
 	PeerBanStore
 	IPBanStore
+	PeerHistoryStore
+	TrustedPeersStore
 }
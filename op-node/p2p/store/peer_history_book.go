@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum/go-ethereum/log"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	peerHistoryCacheSize        = 100
+	peerHistoryRecordExpiration = time.Hour * 24 * 7
+	// peerHistorySampleInterval is the minimum time between recorded score samples of a peer,
+	// scores are updated much more frequently, but only need to be sampled to explain the reputation of a peer.
+	peerHistorySampleInterval = time.Minute * 5
+	maxPeerScoreSamples       = 50
+	maxPeerBanEvents          = 20
+)
+
+var peerHistoryBase = ds.NewKey("/peers/history")
+
+type peerHistoryRecord struct {
+	PeerHistory
+	LastUpdate int64 `json:"lastUpdate"` // unix timestamp in seconds
+}
+
+func (s *peerHistoryRecord) SetLastUpdated(t time.Time) {
+	s.LastUpdate = t.Unix()
+}
+
+func (s *peerHistoryRecord) LastUpdated() time.Time {
+	return time.Unix(s.LastUpdate, 0)
+}
+
+func (s *peerHistoryRecord) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(s)
+}
+
+func (s *peerHistoryRecord) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, s)
+}
+
+type peerScoreSampleUpdate PeerScoreSample
+
+func (u peerScoreSampleUpdate) Apply(rec *peerHistoryRecord) {
+	rec.Scores = append(rec.Scores, PeerScoreSample(u))
+	if len(rec.Scores) > maxPeerScoreSamples {
+		rec.Scores = rec.Scores[len(rec.Scores)-maxPeerScoreSamples:]
+	}
+}
+
+type peerBanEventUpdate PeerBanEvent
+
+func (u peerBanEventUpdate) Apply(rec *peerHistoryRecord) {
+	rec.Bans = append(rec.Bans, PeerBanEvent(u))
+	if len(rec.Bans) > maxPeerBanEvents {
+		rec.Bans = rec.Bans[len(rec.Bans)-maxPeerBanEvents:]
+	}
+}
+
+// peerHistoryBook records the score components and bans of peers over time.
+type peerHistoryBook struct {
+	book   *recordsBook[peer.ID, *peerHistoryRecord]
+	scores ScoreDatastore
+}
+
+func newPeerHistoryRecord() *peerHistoryRecord {
+	return new(peerHistoryRecord)
+}
+
+func newPeerHistoryBook(ctx context.Context, logger log.Logger, clock clock.Clock, store ds.Batching, scores ScoreDatastore) (*peerHistoryBook, error) {
+	book, err := newRecordsBook[peer.ID, *peerHistoryRecord](ctx, logger, clock, store, peerHistoryCacheSize, peerHistoryRecordExpiration, peerHistoryBase, newPeerHistoryRecord, peerIDKey)
+	if err != nil {
+		return nil, err
+	}
+	return &peerHistoryBook{book: book, scores: scores}, nil
+}
+
+func (d *peerHistoryBook) startGC() {
+	d.book.startGC()
+}
+
+// recordScores samples the updated scores of the peer, if the last sample is older than the sample interval.
+func (d *peerHistoryBook) recordScores(id peer.ID, scores PeerScores) error {
+	now := d.book.clock.Now()
+	d.book.RLock()
+	rec, err := d.book.getRecord(id)
+	var last time.Time
+	if err == nil && len(rec.Scores) > 0 {
+		last = time.Unix(rec.Scores[len(rec.Scores)-1].Time, 0)
+	}
+	d.book.RUnlock()
+	if err != nil && err != UnknownRecordErr {
+		return err
+	}
+	if now.Sub(last) < peerHistorySampleInterval {
+		return nil
+	}
+	_, err = d.book.SetRecord(id, peerScoreSampleUpdate{Time: now.Unix(), Scores: scores})
+	return err
+}
+
+func (d *peerHistoryBook) RecordPeerBan(id peer.ID, expiry time.Time, reason string) error {
+	scores, err := d.scores.GetPeerScores(id)
+	if err != nil {
+		return err
+	}
+	ev := peerBanEventUpdate{Time: d.book.clock.Now().Unix(), Reason: reason, Scores: scores}
+	if expiry != (time.Time{}) {
+		ev.Expiry = expiry.Unix()
+	}
+	_, err = d.book.SetRecord(id, ev)
+	return err
+}
+
+func (d *peerHistoryBook) GetPeerHistory(id peer.ID) (PeerHistory, error) {
+	d.book.RLock()
+	defer d.book.RUnlock()
+	rec, err := d.book.getRecord(id)
+	if err == UnknownRecordErr {
+		return PeerHistory{}, nil
+	}
+	if err != nil {
+		return PeerHistory{}, err
+	}
+	// copy, the record is modified by later updates
+	return PeerHistory{
+		Scores: append([]PeerScoreSample(nil), rec.Scores...),
+		Bans:   append([]PeerBanEvent(nil), rec.Bans...),
+	}, nil
+}
+
+func (d *peerHistoryBook) Close() {
+	d.book.Close()
+}
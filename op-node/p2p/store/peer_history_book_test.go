@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum/go-ethereum/log"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/host/peerstore/pstoreds"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUnknownPeerHistory(t *testing.T) {
+	_, eps := createPeerstoreWithClock(t)
+	history, err := eps.GetPeerHistory("a")
+	require.NoError(t, err)
+	require.Equal(t, PeerHistory{}, history)
+}
+
+func TestPeerHistorySamplesScores(t *testing.T) {
+	id := peer.ID("aaaa")
+	c, eps := createPeerstoreWithClock(t)
+	setScoreRequired(t, eps, id, &GossipScores{Total: 1})
+	// updates within the sample interval are not recorded
+	c.AdvanceTime(peerHistorySampleInterval / 2)
+	setScoreRequired(t, eps, id, &GossipScores{Total: 2})
+	c.AdvanceTime(peerHistorySampleInterval / 2)
+	setScoreRequired(t, eps, id, &GossipScores{Total: 3})
+
+	history, err := eps.GetPeerHistory(id)
+	require.NoError(t, err)
+	require.Len(t, history.Scores, 2)
+	require.Equal(t, PeerScores{Gossip: GossipScores{Total: 1}}, history.Scores[0].Scores)
+	require.Equal(t, PeerScores{Gossip: GossipScores{Total: 3}}, history.Scores[1].Scores)
+	require.Equal(t, c.Now().Unix(), history.Scores[1].Time)
+}
+
+func TestPeerHistoryLimitsSamples(t *testing.T) {
+	id := peer.ID("aaaa")
+	c, eps := createPeerstoreWithClock(t)
+	for i := 0; i < maxPeerScoreSamples+5; i++ {
+		setScoreRequired(t, eps, id, &GossipScores{Total: float64(i)})
+		c.AdvanceTime(peerHistorySampleInterval)
+	}
+	history, err := eps.GetPeerHistory(id)
+	require.NoError(t, err)
+	require.Len(t, history.Scores, maxPeerScoreSamples)
+	require.Equal(t, float64(5), history.Scores[0].Scores.Gossip.Total, "oldest samples are dropped")
+}
+
+func TestPeerHistoryRecordsBans(t *testing.T) {
+	id := peer.ID("aaaa")
+	c, eps := createPeerstoreWithClock(t)
+	setScoreRequired(t, eps, id, &GossipScores{Total: -200})
+	expiry := c.Now().Add(time.Hour)
+	require.NoError(t, eps.RecordPeerBan(id, expiry, "score too low"))
+	require.NoError(t, eps.RecordPeerBan(id, time.Time{}, "blocked"))
+
+	history, err := eps.GetPeerHistory(id)
+	require.NoError(t, err)
+	require.Equal(t, []PeerBanEvent{
+		{Time: c.Now().Unix(), Expiry: expiry.Unix(), Reason: "score too low", Scores: PeerScores{Gossip: GossipScores{Total: -200}}},
+		{Time: c.Now().Unix(), Reason: "blocked", Scores: PeerScores{Gossip: GossipScores{Total: -200}}},
+	}, history.Bans)
+}
+
+func TestPeerHistoryPersisted(t *testing.T) {
+	id := peer.ID("aaaa")
+	store := sync.MutexWrap(ds.NewMapDatastore())
+	eps := createPeerstoreWithBacking(t, store)
+	require.NoError(t, eps.RecordPeerBan(id, time.Time{}, "blocked"))
+	require.NoError(t, eps.Close())
+
+	eps = createPeerstoreWithBacking(t, store)
+	history, err := eps.GetPeerHistory(id)
+	require.NoError(t, err)
+	require.Len(t, history.Bans, 1)
+	require.Equal(t, "blocked", history.Bans[0].Reason)
+}
+
+func createPeerstoreWithClock(t *testing.T) (*clock.DeterministicClock, ExtendedPeerstore) {
+	store := sync.MutexWrap(ds.NewMapDatastore())
+	ps, err := pstoreds.NewPeerstore(context.Background(), store, pstoreds.DefaultOpts())
+	require.NoError(t, err, "Failed to create peerstore")
+	logger := testlog.Logger(t, log.LvlInfo)
+	c := clock.NewDeterministicClock(time.UnixMilli(100))
+	eps, err := NewExtendedPeerstore(context.Background(), logger, c, ps, store, 24*time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = eps.Close()
+	})
+	return c, eps
+}
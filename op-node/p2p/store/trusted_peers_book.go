@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+var trustedPeersBase = ds.NewKey("/peers/trusted")
+
+// trustedPeersBook persists the trusted peers. The set of trusted peers is small and does not expire,
+// it is kept in memory, and the datastore is only read once when the book is created.
+type trustedPeersBook struct {
+	ctx   context.Context
+	log   log.Logger
+	store ds.Batching
+
+	mu    sync.RWMutex
+	peers map[peer.ID]peer.AddrInfo
+}
+
+func newTrustedPeersBook(ctx context.Context, logger log.Logger, store ds.Batching) (*trustedPeersBook, error) {
+	results, err := store.Query(ctx, query.Query{Prefix: trustedPeersBase.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trusted peers: %w", err)
+	}
+	defer results.Close()
+	peers := make(map[peer.ID]peer.AddrInfo)
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to load trusted peer: %w", result.Error)
+		}
+		var info peer.AddrInfo
+		if err := json.Unmarshal(result.Value, &info); err != nil {
+			return nil, fmt.Errorf("invalid trusted peer entry %s: %w", result.Key, err)
+		}
+		peers[info.ID] = info
+	}
+	return &trustedPeersBook{
+		ctx:   ctx,
+		log:   logger,
+		store: store,
+		peers: peers,
+	}, nil
+}
+
+func (d *trustedPeersBook) AddTrustedPeer(info peer.AddrInfo) error {
+	data, err := json.Marshal(&info)
+	if err != nil {
+		return fmt.Errorf("failed to encode trusted peer %s: %w", info.ID, err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.store.Put(d.ctx, trustedPeersBase.Child(peerIDKey(info.ID)), data); err != nil {
+		return fmt.Errorf("failed to store trusted peer %s: %w", info.ID, err)
+	}
+	d.peers[info.ID] = info
+	d.log.Info("Added trusted peer", "peer", info.ID, "addrs", info.Addrs)
+	return nil
+}
+
+func (d *trustedPeersBook) RemoveTrustedPeer(id peer.ID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.store.Delete(d.ctx, trustedPeersBase.Child(peerIDKey(id))); err != nil {
+		return fmt.Errorf("failed to remove trusted peer %s: %w", id, err)
+	}
+	if _, ok := d.peers[id]; ok {
+		delete(d.peers, id)
+		d.log.Info("Removed trusted peer", "peer", id)
+	}
+	return nil
+}
+
+func (d *trustedPeersBook) IsTrustedPeer(id peer.ID) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.peers[id]
+	return ok
+}
+
+func (d *trustedPeersBook) TrustedPeers() []peer.AddrInfo {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]peer.AddrInfo, 0, len(d.peers))
+	for _, info := range d.peers {
+		out = append(out, info)
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedPeers(t *testing.T) {
+	eps := createMemoryStore(t)
+	require.Empty(t, eps.TrustedPeers())
+	require.False(t, eps.IsTrustedPeer("a"))
+
+	info := peer.AddrInfo{ID: "a"}
+	require.NoError(t, eps.AddTrustedPeer(info))
+	require.True(t, eps.IsTrustedPeer("a"))
+	require.Equal(t, []peer.AddrInfo{info}, eps.TrustedPeers())
+
+	require.NoError(t, eps.RemoveTrustedPeer("a"))
+	require.False(t, eps.IsTrustedPeer("a"))
+	require.Empty(t, eps.TrustedPeers())
+
+	require.NoError(t, eps.RemoveTrustedPeer("unknown"), "removing an unknown peer is a no-op")
+}
+
+func TestTrustedPeersPersisted(t *testing.T) {
+	id, err := peer.Decode("16Uiu2HAmEFqfMQwqSRkDV76ZcSG9yy8cAJhrq4yXRp9uUJ3wKtkq")
+	require.NoError(t, err)
+	addr, err := multiaddr.NewMultiaddr("/ip4/1.2.3.4/tcp/9000")
+	require.NoError(t, err)
+	info := peer.AddrInfo{ID: id, Addrs: []multiaddr.Multiaddr{addr}}
+
+	store := sync.MutexWrap(ds.NewMapDatastore())
+	eps := createPeerstoreWithBacking(t, store)
+	require.NoError(t, eps.AddTrustedPeer(info))
+	require.NoError(t, eps.Close())
+
+	eps = createPeerstoreWithBacking(t, store)
+	require.True(t, eps.IsTrustedPeer(id))
+	require.Equal(t, []peer.AddrInfo{info}, eps.TrustedPeers())
+}
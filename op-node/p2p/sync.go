@@ -59,12 +59,30 @@ const (
 	// and eventually kick the peer based on degraded scoring if it's really not serving us well.
 	// TODO(CLI-4009): Use a backoff rather than this mechanism.
 	clientErrRateCost = peerServerBlocksBurst
+	// Max number of payloads to request, or serve, in a single payloads-batch request.
+	// Bounded by the per-peer burst, since every payload takes a rate-limit token.
+	maxPayloadsPerRequest = peerServerBlocksBurst
+)
+
+// Result codes of a served payload
+const (
+	resultCodeSuccess        byte = 0
+	resultCodeNotFound       byte = 1
+	resultCodeInvalidRequest byte = 2
+	resultCodeServerError    byte = 3
 )
 
 func PayloadByNumberProtocolID(l2ChainID *big.Int) protocol.ID {
 	return protocol.ID(fmt.Sprintf("/opstack/req/payload_by_number/%d/0", l2ChainID))
 }
 
+// PayloadsBatchProtocolID identifies the successor of the payload_by_number protocol:
+// a range of block numbers, or a list of block hashes, is requested per stream,
+// and the payloads are streamed back with a result code per requested payload.
+func PayloadsBatchProtocolID(l2ChainID *big.Int) protocol.ID {
+	return protocol.ID(fmt.Sprintf("/opstack/req/payloads_batch/%d/0", l2ChainID))
+}
+
 type requestHandlerFn func(ctx context.Context, log log.Logger, stream network.Stream)
 
 func MakeStreamHandler(resourcesCtx context.Context, log log.Logger, fn requestHandlerFn) network.StreamHandler {
@@ -191,6 +209,7 @@ type SyncClient struct {
 
 	newStreamFn     newStreamFn
 	payloadByNumber protocol.ID
+	payloadsBatch   protocol.ID
 
 	peersLock sync.Mutex
 	// syncing worker per peer
@@ -244,6 +263,7 @@ func NewSyncClient(log log.Logger, cfg *rollup.Config, newStream newStreamFn, rc
 		appScorer:       appScorer,
 		newStreamFn:     newStream,
 		payloadByNumber: PayloadByNumberProtocolID(cfg.L2ChainID),
+		payloadsBatch:   PayloadsBatchProtocolID(cfg.L2ChainID),
 		peers:           make(map[peer.ID]context.CancelFunc),
 		quarantineByNum: make(map[uint64]common.Hash),
 		inFlight:        make(map[uint64]*atomic.Bool),
@@ -498,7 +518,15 @@ func (s *SyncClient) onResult(ctx context.Context, res syncResult) {
 
 // peerLoop for syncing from a single peer
 func (s *SyncClient) peerLoop(ctx context.Context, id peer.ID) {
+	// Requests taken from the queue that still have to be made to the peer
+	var queued []peerRequest
+
 	defer func() {
+		// Mark the requests that were never made to the peer as complete,
+		// so the main loop cleans up their in-flight state, and requests them again from other peers.
+		for _, pr := range queued {
+			pr.complete.Store(true)
+		}
 		s.peersLock.Lock()
 		delete(s.peers, id) // clean up
 		s.log.Debug("stopped syncing loop of peer", "id", id)
@@ -513,6 +541,9 @@ func (s *SyncClient) peerLoop(ctx context.Context, id peer.ID) {
 	// so we don't be too aggressive to the server.
 	rl := rate.NewLimiter(peerServerBlocksRateLimit, peerServerBlocksBurst)
 
+	// Peers that only support the payload_by_number protocol are sent a single request per stream.
+	legacy := false
+
 	for {
 		// wait for a global allocation to be available
 		if err := s.globalRL.Wait(ctx); err != nil {
@@ -524,43 +555,101 @@ func (s *SyncClient) peerLoop(ctx context.Context, id peer.ID) {
 		}
 
 		// once the peer is available, wait for a sync request.
-		select {
-		case pr := <-s.peerRequests:
-			// We already established the peer is available w.r.t. rate-limiting,
-			// and this is the only loop over this peer, so we can request now.
-			start := time.Now()
-			err := s.doRequest(ctx, id, pr.num)
-			if err != nil {
-				// mark as complete if there's an error: we are not sending any result and can complete immediately.
-				pr.complete.Store(true)
-				log.Warn("failed p2p sync request", "num", pr.num, "err", err)
-				s.appScorer.onResponseError(id)
-				// If we hit an error, then count it as many requests.
-				// We'd like to avoid making more requests for a while, to back off.
-				if err := rl.WaitN(ctx, clientErrRateCost); err != nil {
-					return
-				}
-			} else {
-				log.Debug("completed p2p sync request", "num", pr.num)
-				s.appScorer.onValidResponse(id)
+		var batch []peerRequest
+		if len(queued) > 0 {
+			batch, queued = []peerRequest{queued[0]}, queued[1:]
+		} else {
+			select {
+			case pr := <-s.peerRequests:
+				batch = []peerRequest{pr}
+			case <-ctx.Done():
+				return
+			}
+		}
+		if !legacy {
+			var next *peerRequest
+			batch, next = s.fillBatch(rl, batch)
+			if next != nil {
+				queued = append(queued, *next)
 			}
-			took := time.Since(start)
+		}
 
-			resultCode := byte(0)
+		// We already established the peer is available w.r.t. rate-limiting,
+		// and this is the only loop over this peer, so we can request now.
+		start := time.Now()
+		var errs []error
+		legacy, errs = s.doRequest(ctx, id, batch)
+		took := time.Since(start)
+
+		failed := false
+		var notRequested []peerRequest
+		for i, pr := range batch {
+			err := errs[i]
+			if err == errNotRequested {
+				// the peer only serves a single payload per stream, request it next
+				notRequested = append(notRequested, pr)
+				continue
+			}
+			resultCode := resultCodeSuccess
 			if err != nil {
+				// mark as complete if there's an error: we are not sending any result and can complete immediately.
+				pr.complete.Store(true)
 				if re, ok := err.(requestResultErr); ok {
 					resultCode = re.ResultCode()
 				} else {
 					resultCode = 1
 				}
+				if err == requestResultErr(resultCodeNotFound) && !legacy {
+					// The peer may simply not have synced the block yet: this is not a failure of the peer.
+					// The legacy protocol has no per-payload result, and is still scored and backed off from as before.
+					log.Debug("peer does not have requested payload", "num", pr.num)
+				} else {
+					log.Warn("failed p2p sync request", "num", pr.num, "err", err)
+					failed = true
+				}
+			} else {
+				log.Debug("completed p2p sync request", "num", pr.num)
 			}
 			s.metrics.ClientPayloadByNumberEvent(pr.num, resultCode, took)
-		case <-ctx.Done():
-			return
+		}
+		queued = append(notRequested, queued...)
+		if failed {
+			s.appScorer.onResponseError(id)
+			// If we hit an error, then count it as many requests.
+			// We'd like to avoid making more requests for a while, to back off.
+			if err := rl.WaitN(ctx, clientErrRateCost); err != nil {
+				return
+			}
+		} else {
+			s.appScorer.onValidResponse(id)
 		}
 	}
 }
 
+// fillBatch adds more scheduled requests to the batch, while the rate-limits allow for it.
+// The main loop schedules requests from high to low block numbers, and only consecutive numbers fit in the batch.
+// A request that does not fit is returned separately, to be processed next.
+func (s *SyncClient) fillBatch(rl *rate.Limiter, batch []peerRequest) ([]peerRequest, *peerRequest) {
+	for len(batch) < maxPayloadsPerRequest {
+		now := time.Now()
+		if rl.TokensAt(now) < 1 || s.globalRL.TokensAt(now) < 1 {
+			return batch, nil
+		}
+		select {
+		case pr := <-s.peerRequests:
+			if pr.num+1 != batch[len(batch)-1].num {
+				return batch, &pr
+			}
+			rl.AllowN(now, 1)
+			s.globalRL.AllowN(now, 1)
+			batch = append(batch, pr)
+		default:
+			return batch, nil
+		}
+	}
+	return batch, nil
+}
+
 type requestResultErr byte
 
 func (r requestResultErr) Error() string {
@@ -571,15 +660,62 @@ func (r requestResultErr) ResultCode() byte {
 	return byte(r)
 }
 
-func (s *SyncClient) doRequest(ctx context.Context, id peer.ID, n uint64) error {
-	// open stream to peer
+// errNotRequested marks requests that were not made to the peer, and have to be rescheduled.
+var errNotRequested = errors.New("not requested")
+
+// doRequest requests the batch of block numbers from the peer, and returns an error per request.
+// The batch is requested at once if the peer supports the payloads-batch protocol.
+// Otherwise only the first block is requested with the payload_by_number protocol, and the legacy flag is returned.
+func (s *SyncClient) doRequest(ctx context.Context, id peer.ID, batch []peerRequest) (legacy bool, errs []error) {
+	errs = make([]error, len(batch))
+	setErrs := func(err error) (bool, []error) {
+		for i := range errs {
+			errs[i] = err
+		}
+		return legacy, errs
+	}
+	// open stream to peer, the first supported protocol is negotiated
 	reqCtx, reqCancel := context.WithTimeout(ctx, streamTimeout)
-	str, err := s.newStreamFn(reqCtx, id, s.payloadByNumber)
+	str, err := s.newStreamFn(reqCtx, id, s.payloadsBatch, s.payloadByNumber)
 	reqCancel()
 	if err != nil {
-		return fmt.Errorf("failed to open stream: %w", err)
+		return setErrs(fmt.Errorf("failed to open stream: %w", err))
 	}
 	defer str.Close()
+	if str.Protocol() == s.payloadByNumber {
+		legacy = true
+		setErrs(errNotRequested)
+		errs[0] = s.doSingleRequest(ctx, str, id, batch[0].num)
+		return legacy, errs
+	}
+
+	// the batch consists of descending block numbers, and is requested as ascending range
+	req := payloadsRequest{start: batch[len(batch)-1].num, count: uint32(len(batch))}
+	payloads, err := s.doBatchRequest(ctx, str, req)
+	if err != nil {
+		return setErrs(err)
+	}
+	for i, res := range payloads {
+		pr := &batch[len(batch)-1-i]
+		if res.err != nil {
+			errs[len(batch)-1-i] = res.err
+			continue
+		}
+		if err := verifyBlock(res.payload, pr.num); err != nil {
+			errs[len(batch)-1-i] = fmt.Errorf("received execution payload is invalid: %w", err)
+			continue
+		}
+		select {
+		case s.results <- syncResult{payload: res.payload, peer: id}:
+		case <-ctx.Done():
+			errs[len(batch)-1-i] = fmt.Errorf("failed to process response, sync client is too busy: %w", ctx.Err())
+		}
+	}
+	return legacy, errs
+}
+
+// doSingleRequest requests a single payload by number, with the payload_by_number protocol
+func (s *SyncClient) doSingleRequest(ctx context.Context, str network.Stream, id peer.ID, n uint64) error {
 	// set write timeout (if available)
 	_ = str.SetWriteDeadline(time.Now().Add(clientWriteRequestTimeout))
 	if err := binary.Write(str, binary.LittleEndian, n); err != nil {
@@ -638,6 +774,174 @@ func (s *SyncClient) doRequest(ctx context.Context, id peer.ID, n uint64) error
 	return nil
 }
 
+// payloadsRequest is a request of the payloads-batch protocol:
+// either a range of count block numbers from start, or a list of block hashes.
+//
+// Encoding:
+//   - 1 byte request kind: 0 for a range, 1 for a list of hashes
+//   - range: 8 bytes start number, 4 bytes count, both little-endian
+//   - hashes: 4 bytes count, little-endian, followed by count block hashes of 32 bytes
+type payloadsRequest struct {
+	start  uint64
+	count  uint32
+	hashes []common.Hash
+}
+
+const (
+	payloadsRequestRange  byte = 0
+	payloadsRequestHashes byte = 1
+)
+
+func (req *payloadsRequest) Len() int {
+	if req.hashes != nil {
+		return len(req.hashes)
+	}
+	return int(req.count)
+}
+
+func (req *payloadsRequest) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if req.hashes != nil {
+		buf.WriteByte(payloadsRequestHashes)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(req.hashes)))
+		for _, h := range req.hashes {
+			buf.Write(h[:])
+		}
+	} else {
+		buf.WriteByte(payloadsRequestRange)
+		_ = binary.Write(&buf, binary.LittleEndian, req.start)
+		_ = binary.Write(&buf, binary.LittleEndian, req.count)
+	}
+	return buf.Bytes(), nil
+}
+
+// readPayloadsRequest decodes a request of at most maxPayloadsPerRequest payloads
+func readPayloadsRequest(r io.Reader) (*payloadsRequest, error) {
+	var kind [1]byte
+	if _, err := io.ReadFull(r, kind[:]); err != nil {
+		return nil, fmt.Errorf("failed to read request kind: %w", err)
+	}
+	var req payloadsRequest
+	switch kind[0] {
+	case payloadsRequestRange:
+		if err := binary.Read(r, binary.LittleEndian, &req.start); err != nil {
+			return nil, fmt.Errorf("failed to read range start: %w", err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &req.count); err != nil {
+			return nil, fmt.Errorf("failed to read range count: %w", err)
+		}
+		if req.count == 0 || req.count > maxPayloadsPerRequest {
+			return nil, fmt.Errorf("invalid range count %d: %w", req.count, invalidRequestErr)
+		}
+	case payloadsRequestHashes:
+		var count uint32
+		if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+			return nil, fmt.Errorf("failed to read hashes count: %w", err)
+		}
+		if count == 0 || count > maxPayloadsPerRequest {
+			return nil, fmt.Errorf("invalid hashes count %d: %w", count, invalidRequestErr)
+		}
+		req.hashes = make([]common.Hash, count)
+		for i := range req.hashes {
+			if _, err := io.ReadFull(r, req.hashes[i][:]); err != nil {
+				return nil, fmt.Errorf("failed to read hash %d: %w", i, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown request kind %d: %w", kind[0], invalidRequestErr)
+	}
+	return &req, nil
+}
+
+type payloadResult struct {
+	payload *eth.ExecutionPayload
+	err     error
+}
+
+// doBatchRequest requests a batch of payloads with the payloads-batch protocol.
+// The response has an item per requested payload, in order of the request:
+//   - 1 byte result code, the remaining item data is omitted if the code is not 0 (success)
+//   - 4 bytes version: 0, little-endian
+//   - 4 bytes length of the compressed payload, little-endian
+//   - the payload, SSZ encoded with Snappy block compression
+//
+// A per-payload error is returned with the payload result, the error return is for failure of the request as a whole.
+func (s *SyncClient) doBatchRequest(ctx context.Context, str network.Stream, req payloadsRequest) ([]payloadResult, error) {
+	data, err := req.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	// set write timeout (if available)
+	_ = str.SetWriteDeadline(time.Now().Add(clientWriteRequestTimeout))
+	if _, err := str.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+	if err := str.CloseWrite(); err != nil {
+		return nil, fmt.Errorf("failed to close writer side while making request: %w", err)
+	}
+
+	results := make([]payloadResult, req.Len())
+	for i := range results {
+		// the server may throttle every payload, so the read timeout applies per payload
+		_ = str.SetReadDeadline(time.Now().Add(clientReadResponsetimeout))
+		payload, err := readPayloadItem(str)
+		if err != nil {
+			if _, ok := err.(requestResultErr); !ok {
+				// the response cannot be read any further
+				for j := i; j < len(results); j++ {
+					results[j].err = err
+				}
+				break
+			}
+		}
+		results[i] = payloadResult{payload: payload, err: err}
+	}
+	if err := str.CloseRead(); err != nil {
+		return nil, fmt.Errorf("failed to close reading side")
+	}
+	return results, nil
+}
+
+func readPayloadItem(r io.Reader) (*eth.ExecutionPayload, error) {
+	var result [1]byte
+	if _, err := io.ReadFull(r, result[:]); err != nil {
+		return nil, fmt.Errorf("failed to read result part of response: %w", err)
+	}
+	if res := result[0]; res != resultCodeSuccess {
+		return nil, requestResultErr(res)
+	}
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("failed to read version and length part of response: %w", err)
+	}
+	if version := binary.LittleEndian.Uint32(header[:4]); version != 0 {
+		return nil, fmt.Errorf("unrecognized ExecutionPayload version: %d", version)
+	}
+	length := binary.LittleEndian.Uint32(header[4:])
+	if length > maxGossipSize {
+		return nil, fmt.Errorf("compressed payload of %d bytes is too large", length)
+	}
+	compressed := make([]byte, length)
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		return nil, fmt.Errorf("failed to read payload part of response: %w", err)
+	}
+	// check the decoded length before decoding, to not decode a zip-bomb
+	if n, err := snappy.DecodedLen(compressed); err != nil {
+		return nil, fmt.Errorf("invalid compressed payload: %w", err)
+	} else if n > maxGossipSize {
+		return nil, fmt.Errorf("payload of %d bytes is too large", n)
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+	var res eth.ExecutionPayload
+	if err := res.UnmarshalSSZ(uint32(len(data)), bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &res, nil
+}
+
 func verifyBlock(payload *eth.ExecutionPayload, expectedNum uint64) error {
 	// verify L2 block
 	if expectedNum != uint64(payload.BlockNumber) {
@@ -658,6 +962,7 @@ type peerStat struct {
 
 type L2Chain interface {
 	PayloadByNumber(ctx context.Context, number uint64) (*eth.ExecutionPayload, error)
+	PayloadByHash(ctx context.Context, hash common.Hash) (*eth.ExecutionPayload, error)
 }
 
 type ReqRespServerMetrics interface {
@@ -709,18 +1014,10 @@ func (srv *ReqRespServer) HandleSyncRequest(ctx context.Context, log log.Logger,
 	req, err := srv.handleSyncRequest(ctx, stream)
 	cancel()
 
-	resultCode := byte(0)
 	if err != nil {
 		log.Warn("failed to serve p2p sync request", "req", req, "err", err)
-		if errors.Is(err, ethereum.NotFound) {
-			resultCode = 1
-		} else if errors.Is(err, invalidRequestErr) {
-			resultCode = 2
-		} else {
-			resultCode = 3
-		}
 		// try to write error code, so the other peer can understand the reason for failure.
-		_, _ = stream.Write([]byte{resultCode})
+		_, _ = stream.Write([]byte{resultCodeOf(err)})
 	} else {
 		log.Debug("successfully served sync response", "req", req)
 	}
@@ -729,13 +1026,24 @@ func (srv *ReqRespServer) HandleSyncRequest(ctx context.Context, log log.Logger,
 
 var invalidRequestErr = errors.New("invalid request")
 
-func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.Stream) (uint64, error) {
-	peerId := stream.Conn().RemotePeer()
+func resultCodeOf(err error) byte {
+	if err == nil {
+		return resultCodeSuccess
+	} else if errors.Is(err, ethereum.NotFound) {
+		return resultCodeNotFound
+	} else if errors.Is(err, invalidRequestErr) {
+		return resultCodeInvalidRequest
+	} else {
+		return resultCodeServerError
+	}
+}
 
+// waitRateLimits takes a token from the global rate-limiter, and from the rate-limiter of the peer.
+func (srv *ReqRespServer) waitRateLimits(ctx context.Context, peerId peer.ID) error {
 	// take a token from the global rate-limiter,
 	// to make sure there's not too much concurrent server work between different peers.
 	if err := srv.globalRequestsRL.Wait(ctx); err != nil {
-		return 0, fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
+		return fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
 	}
 
 	// find rate limiting data of peer, or add otherwise
@@ -754,10 +1062,35 @@ func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.
 		// We'll disconnect ourselves only when failing to read/write,
 		// if the work is invalid (range validation), or when individual sub tasks timeout.
 		if err := ps.Requests.Wait(ctx); err != nil {
-			return 0, fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
+			srv.peerStatsLock.Unlock()
+			return fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
 		}
 	}
 	srv.peerStatsLock.Unlock()
+	return nil
+}
+
+// checkRequestedNumber checks the requested block number is within the expected range of blocks
+func (srv *ReqRespServer) checkRequestedNumber(req uint64) error {
+	if req < srv.cfg.Genesis.L2.Number {
+		return fmt.Errorf("cannot serve request for L2 block %d before genesis %d: %w", req, srv.cfg.Genesis.L2.Number, invalidRequestErr)
+	}
+	max, err := srv.cfg.TargetBlockNumber(uint64(time.Now().Unix()))
+	if err != nil {
+		return fmt.Errorf("cannot determine max target block number to verify request: %w", invalidRequestErr)
+	}
+	if req > max {
+		return fmt.Errorf("cannot serve request for L2 block %d after max expected block (%v): %w", req, max, invalidRequestErr)
+	}
+	return nil
+}
+
+func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.Stream) (uint64, error) {
+	peerId := stream.Conn().RemotePeer()
+
+	if err := srv.waitRateLimits(ctx, peerId); err != nil {
+		return 0, err
+	}
 
 	// Set read deadline, if available
 	_ = stream.SetReadDeadline(time.Now().Add(serverReadRequestTimeout))
@@ -772,15 +1105,8 @@ func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.
 	}
 
 	// Check the request is within the expected range of blocks
-	if req < srv.cfg.Genesis.L2.Number {
-		return req, fmt.Errorf("cannot serve request for L2 block %d before genesis %d: %w", req, srv.cfg.Genesis.L2.Number, invalidRequestErr)
-	}
-	max, err := srv.cfg.TargetBlockNumber(uint64(time.Now().Unix()))
-	if err != nil {
-		return req, fmt.Errorf("cannot determine max target block number to verify request: %w", invalidRequestErr)
-	}
-	if req > max {
-		return req, fmt.Errorf("cannot serve request for L2 block %d after max expected block (%v): %w", req, max, invalidRequestErr)
+	if err := srv.checkRequestedNumber(req); err != nil {
+		return req, err
 	}
 
 	payload, err := srv.l2.PayloadByNumber(ctx, req)
@@ -810,3 +1136,92 @@ func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.
 	}
 	return req, nil
 }
+
+// HandleBatchSyncRequest is a stream handler function to register the payloads-batch protocol,
+// the successor of the payload_by_number protocol. See doBatchRequest for the encoding of the response.
+// See MakeStreamHandler to transform this into a LibP2P handler function.
+//
+// Every served payload takes a rate-limit token, the response is throttled per payload.
+//
+// The caller must Close the stream.
+func (srv *ReqRespServer) HandleBatchSyncRequest(ctx context.Context, log log.Logger, stream network.Stream) {
+	start := time.Now()
+	peerId := stream.Conn().RemotePeer()
+
+	// Set read deadline, if available
+	_ = stream.SetReadDeadline(time.Now().Add(serverReadRequestTimeout))
+	req, err := readPayloadsRequest(io.LimitReader(stream, 1+4+maxPayloadsPerRequest*32))
+	if err == nil {
+		err = stream.CloseRead()
+	}
+	if err != nil {
+		log.Warn("failed to read p2p batch sync request", "err", err)
+		_, _ = stream.Write([]byte{resultCodeInvalidRequest})
+		srv.metrics.ServerPayloadByNumberEvent(0, resultCodeInvalidRequest, time.Since(start))
+		return
+	}
+
+	// We wait as long as necessary; we throttle the peer instead of disconnecting,
+	// unless the delay reaches a threshold that is unreasonable to wait for.
+	ctx, cancel := context.WithTimeout(ctx, maxThrottleDelay)
+	defer cancel()
+	for i := 0; i < req.Len(); i++ {
+		itemStart := time.Now()
+		var num uint64
+		payload, err := func() (*eth.ExecutionPayload, error) {
+			if err := srv.waitRateLimits(ctx, peerId); err != nil {
+				return nil, err
+			}
+			if req.hashes != nil {
+				return srv.l2.PayloadByHash(ctx, req.hashes[i])
+			}
+			num = req.start + uint64(i)
+			if err := srv.checkRequestedNumber(num); err != nil {
+				return nil, err
+			}
+			return srv.l2.PayloadByNumber(ctx, num)
+		}()
+		if payload != nil {
+			num = uint64(payload.BlockNumber)
+		} else if err == nil {
+			err = ethereum.NotFound
+		}
+		resultCode := resultCodeOf(err)
+		if err != nil {
+			log.Warn("failed to serve p2p batch sync request item", "index", i, "num", num, "err", err)
+		}
+		// We set write deadline, if available, to safely write without blocking on a throttling peer connection
+		_ = stream.SetWriteDeadline(time.Now().Add(serverWriteChunkTimeout))
+		if err := writePayloadItem(stream, resultCode, payload); err != nil {
+			log.Warn("failed to write p2p batch sync response", "index", i, "num", num, "err", err)
+			srv.metrics.ServerPayloadByNumberEvent(num, resultCodeServerError, time.Since(itemStart))
+			return
+		}
+		srv.metrics.ServerPayloadByNumberEvent(num, resultCode, time.Since(itemStart))
+	}
+	log.Debug("successfully served batch sync response", "count", req.Len())
+}
+
+func writePayloadItem(w io.Writer, resultCode byte, payload *eth.ExecutionPayload) error {
+	if resultCode != resultCodeSuccess {
+		_, err := w.Write([]byte{resultCode})
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err := payload.MarshalSSZ(&buf); err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	compressed := snappy.Encode(nil, buf.Bytes())
+	// 0 - resultCode: success = 0
+	// 1:5 - version: 0
+	// 5:9 - length of compressed payload
+	var header [9]byte
+	binary.LittleEndian.PutUint32(header[5:], uint32(len(compressed)))
+	if _, err := w.Write(header[:]); err != nil {
+		return fmt.Errorf("failed to write response header data: %w", err)
+	}
+	if _, err := w.Write(compressed); err != nil {
+		return fmt.Errorf("failed to write payload: %w", err)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package p2p
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,6 +31,10 @@ func (fn mockPayloadFn) PayloadByNumber(_ context.Context, number uint64) (*eth.
 	return fn(number)
 }
 
+func (fn mockPayloadFn) PayloadByHash(_ context.Context, hash common.Hash) (*eth.ExecutionPayload, error) {
+	return nil, ethereum.NotFound
+}
+
 var _ L2Chain = mockPayloadFn(nil)
 
 type syncTestData struct {
@@ -98,6 +104,15 @@ func setupSyncTestData(length uint64) (*rollup.Config, *syncTestData) {
 }
 
 func TestSinglePeerSync(t *testing.T) {
+	t.Run("payloads batch", func(t *testing.T) {
+		testSinglePeerSync(t, true)
+	})
+	t.Run("payload by number", func(t *testing.T) {
+		testSinglePeerSync(t, false)
+	})
+}
+
+func testSinglePeerSync(t *testing.T, batch bool) {
 	t.Parallel() // Takes a while, but can run in parallel
 
 	log := testlog.Logger(t, log.LvlError)
@@ -135,6 +150,10 @@ func TestSinglePeerSync(t *testing.T) {
 	srv := NewReqRespServer(cfg, servePayload, metrics.NoopMetrics)
 	payloadByNumber := MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleSyncRequest)
 	hostA.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), payloadByNumber)
+	if batch {
+		payloadsBatch := MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleBatchSyncRequest)
+		hostA.SetStreamHandler(PayloadsBatchProtocolID(cfg.L2ChainID), payloadsBatch)
+	}
 
 	// Setup host B as the client
 	cl := NewSyncClient(log.New("role", "client"), cfg, hostB.NewStream, receivePayload, metrics.NoopMetrics, &NoopApplicationScorer{})
@@ -189,6 +208,8 @@ func TestMultiPeerSync(t *testing.T) {
 		srv := NewReqRespServer(cfg, servePayload, metrics.NoopMetrics)
 		payloadByNumber := MakeStreamHandler(ctx, log.New("serve", "payloads_by_number"), srv.HandleSyncRequest)
 		h.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), payloadByNumber)
+		payloadsBatch := MakeStreamHandler(ctx, log.New("serve", "payloads_batch"), srv.HandleBatchSyncRequest)
+		h.SetStreamHandler(PayloadsBatchProtocolID(cfg.L2ChainID), payloadsBatch)
 
 		cl := NewSyncClient(log.New("role", "client"), cfg, h.NewStream, receivePayload, metrics.NoopMetrics, &NoopApplicationScorer{})
 		return cl, received
@@ -288,3 +309,204 @@ func TestMultiPeerSync(t *testing.T) {
 		require.Equal(t, exp.BlockHash, p.BlockHash, "expecting the correct payload")
 	}
 }
+
+type countingSyncPeerScorer struct {
+	valid    atomic.Int32
+	errors   atomic.Int32
+	rejected atomic.Int32
+}
+
+func (s *countingSyncPeerScorer) onValidResponse(id peer.ID)   { s.valid.Add(1) }
+func (s *countingSyncPeerScorer) onResponseError(id peer.ID)   { s.errors.Add(1) }
+func (s *countingSyncPeerScorer) onRejectedPayload(id peer.ID) { s.rejected.Add(1) }
+
+// TestSyncNotFoundNotPenalized ensures that a peer is not penalized for not having a block in a batch,
+// while the not-found responses of the legacy payload_by_number protocol are still penalized.
+func TestSyncNotFoundNotPenalized(t *testing.T) {
+	t.Run("batch", func(t *testing.T) {
+		testSyncNotFound(t, false)
+	})
+	t.Run("legacy", func(t *testing.T) {
+		testSyncNotFound(t, true)
+	})
+}
+
+func testSyncNotFound(t *testing.T, legacy bool) {
+	log := testlog.Logger(t, log.LvlError)
+	cfg, payloads := setupSyncTestData(25)
+	payloads.deletePayload(15)
+
+	received := make(chan *eth.ExecutionPayload, 100)
+	receivePayload := receivePayloadFn(func(ctx context.Context, from peer.ID, payload *eth.ExecutionPayload) error {
+		received <- payload
+		return nil
+	})
+
+	mnet, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err, "failed to setup mocknet")
+	defer mnet.Close()
+	hosts := mnet.Hosts()
+	hostA, hostB := hosts[0], hosts[1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := NewReqRespServer(cfg, mockPayloadFn(func(n uint64) (*eth.ExecutionPayload, error) {
+		p, ok := payloads.getPayload(n)
+		if !ok {
+			return nil, ethereum.NotFound
+		}
+		return p, nil
+	}), metrics.NoopMetrics)
+	if legacy {
+		hostA.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleSyncRequest))
+	} else {
+		hostA.SetStreamHandler(PayloadsBatchProtocolID(cfg.L2ChainID), MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleBatchSyncRequest))
+	}
+
+	scorer := &countingSyncPeerScorer{}
+	cl := NewSyncClient(log.New("role", "client"), cfg, hostB.NewStream, receivePayload, metrics.NoopMetrics, scorer)
+	cl.AddPeer(hostA.ID())
+	cl.Start()
+	defer cl.Close()
+
+	require.NoError(t, cl.RequestL2Range(ctx, payloads.getBlockRef(10), payloads.getBlockRef(20)))
+	for i := uint64(19); i > 15; i-- {
+		p := <-received
+		require.Equal(t, i, uint64(p.BlockNumber))
+	}
+	require.Eventually(t, func() bool {
+		inFlight, err := cl.isInFlight(ctx, 15)
+		require.NoError(t, err)
+		return !inFlight
+	}, 10*time.Second, 10*time.Millisecond, "not found request completes")
+	if legacy {
+		require.Eventually(t, func() bool {
+			return scorer.errors.Load() > 0
+		}, 10*time.Second, 10*time.Millisecond, "not found is a response error of the legacy protocol")
+	} else {
+		require.Zero(t, scorer.errors.Load(), "not found is not a response error")
+	}
+	require.NotZero(t, scorer.valid.Load())
+}
+
+// TestSyncRemovedPeerCompletesQueued ensures that requests that were queued for a peer, but never made to it,
+// are marked as complete when the peer is removed, so they can be requested again.
+func TestSyncRemovedPeerCompletesQueued(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	cfg, payloads := setupSyncTestData(25)
+
+	mnet, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err, "failed to setup mocknet")
+	defer mnet.Close()
+	hosts := mnet.Hosts()
+	hostA, hostB := hosts[0], hosts[1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The server only supports the payload_by_number protocol, so the client queues all but the first request,
+	// and the server holds on to the first request until the peer is removed by the client.
+	requested := make(chan uint64, 100)
+	unblock := make(chan struct{})
+	srv := NewReqRespServer(cfg, mockPayloadFn(func(n uint64) (*eth.ExecutionPayload, error) {
+		requested <- n
+		<-unblock
+		p, _ := payloads.getPayload(n)
+		return p, nil
+	}), metrics.NoopMetrics)
+	hostA.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleSyncRequest))
+
+	cl := NewSyncClient(log.New("role", "client"), cfg, hostB.NewStream, receivePayloadFn(func(ctx context.Context, from peer.ID, payload *eth.ExecutionPayload) error {
+		return nil
+	}), metrics.NoopMetrics, &NoopApplicationScorer{})
+	cl.AddPeer(hostA.ID())
+	cl.Start()
+	defer cl.Close()
+
+	require.NoError(t, cl.RequestL2Range(ctx, payloads.getBlockRef(10), payloads.getBlockRef(20)))
+	select {
+	case n := <-requested:
+		require.Equal(t, uint64(19), n)
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected request")
+	}
+	inFlight, err := cl.isInFlight(ctx, 18)
+	require.NoError(t, err)
+	require.True(t, inFlight, "queued for the peer")
+
+	cl.RemovePeer(hostA.ID())
+	close(unblock)
+	require.Eventually(t, func() bool {
+		inFlight, err := cl.isInFlight(ctx, 18)
+		require.NoError(t, err)
+		return !inFlight
+	}, 10*time.Second, 10*time.Millisecond, "queued request is completed when the peer is removed")
+}
+
+type mockPayloadsByHash map[common.Hash]*eth.ExecutionPayload
+
+func (m mockPayloadsByHash) PayloadByNumber(_ context.Context, number uint64) (*eth.ExecutionPayload, error) {
+	return nil, ethereum.NotFound
+}
+
+func (m mockPayloadsByHash) PayloadByHash(_ context.Context, hash common.Hash) (*eth.ExecutionPayload, error) {
+	if p, ok := m[hash]; ok {
+		return p, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func TestBatchSyncRequestByHash(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	cfg, payloads := setupSyncTestData(5)
+	byHash := make(mockPayloadsByHash)
+	for i := uint64(0); i <= 5; i++ {
+		p, _ := payloads.getPayload(i)
+		byHash[p.BlockHash] = p
+	}
+
+	mnet, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err, "failed to setup mocknet")
+	defer mnet.Close()
+	hosts := mnet.Hosts()
+	hostA, hostB := hosts[0], hosts[1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := NewReqRespServer(cfg, byHash, metrics.NoopMetrics)
+	hostA.SetStreamHandler(PayloadsBatchProtocolID(cfg.L2ChainID), MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleBatchSyncRequest))
+
+	p3, _ := payloads.getPayload(3)
+	p1, _ := payloads.getPayload(1)
+	req := payloadsRequest{hashes: []common.Hash{p3.BlockHash, {0x42}, p1.BlockHash}}
+	str, err := hostB.NewStream(ctx, hostA.ID(), PayloadsBatchProtocolID(cfg.L2ChainID))
+	require.NoError(t, err)
+	defer str.Close()
+
+	cl := NewSyncClient(log.New("role", "client"), cfg, hostB.NewStream, nil, metrics.NoopMetrics, &NoopApplicationScorer{})
+	results, err := cl.doBatchRequest(ctx, str, req)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].err)
+	require.Equal(t, p3.BlockHash, results[0].payload.BlockHash)
+	require.Equal(t, requestResultErr(resultCodeNotFound), results[1].err, "per-item result code")
+	require.NoError(t, results[2].err)
+	require.Equal(t, p1.BlockHash, results[2].payload.BlockHash)
+}
+
+func TestPayloadsRequestEncoding(t *testing.T) {
+	for _, req := range []payloadsRequest{
+		{start: 123, count: 10},
+		{hashes: []common.Hash{{0x01}, {0x02}}},
+	} {
+		data, err := req.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := readPayloadsRequest(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, req, *decoded)
+	}
+	_, err := readPayloadsRequest(bytes.NewReader([]byte{payloadsRequestRange, 0, 0, 0, 0, 0, 0, 0, 0, 100, 0, 0, 0}))
+	require.ErrorIs(t, err, invalidRequestErr, "too many payloads")
+}
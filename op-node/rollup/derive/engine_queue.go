@@ -63,6 +63,15 @@ type EngineControl interface {
 	BuildingPayload() (onto eth.L2BlockRef, id eth.PayloadID, safe bool)
 }
 
+// SafeHeadListener is notified of the L2 safe head, and the L1 block it was derived from.
+type SafeHeadListener interface {
+	// SafeHeadUpdated indicates the safe head was updated, after processing the batch data of the given L1 block.
+	SafeHeadUpdated(safeHead eth.L2BlockRef, l1Block eth.BlockID) error
+	// SafeHeadReset indicates the derivation pipeline was reset to the given safe head.
+	// Safe heads recorded after it are no longer valid, and are updated again as the pipeline re-derives them.
+	SafeHeadReset(resetSafeHead eth.L2BlockRef) error
+}
+
 // Max memory used for buffering unsafe payloads
 const maxUnsafePayloadsMemory = 500 * 1024 * 1024
 
@@ -139,12 +148,16 @@ type EngineQueue struct {
 	l1Fetcher L1Fetcher
 
 	syncCfg *sync.Config
+
+	safeHeadNotifs SafeHeadListener
+	// safeHeadNotified is the last safe head that safeHeadNotifs was notified of.
+	safeHeadNotified eth.L2BlockRef
 }
 
 var _ EngineControl = (*EngineQueue)(nil)
 
 // NewEngineQueue creates a new EngineQueue, which should be Reset(origin) before use.
-func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config) *EngineQueue {
+func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config, safeHeadNotifs SafeHeadListener) *EngineQueue {
 	return &EngineQueue{
 		log:            log,
 		cfg:            cfg,
@@ -155,6 +168,7 @@ func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics M
 		prev:           prev,
 		l1Fetcher:      l1Fetcher,
 		syncCfg:        syncCfg,
+		safeHeadNotifs: safeHeadNotifs,
 	}
 }
 
@@ -402,6 +416,14 @@ func (eq *EngineQueue) postProcessSafeL2() {
 			eq.log.Debug("updated finality-data", "last_l1", last.L1Block, "last_l2", last.L2Block)
 		}
 	}
+	// Record the L1 block the new safe head was derived from. This is retried on the next update if it fails.
+	if eq.safeHeadNotified != eq.safeHead {
+		if err := eq.safeHeadNotifs.SafeHeadUpdated(eq.safeHead, eq.origin.ID()); err != nil {
+			eq.log.Error("Failed to record safe head", "safe_head", eq.safeHead, "l1_origin", eq.origin, "err", err)
+			return
+		}
+		eq.safeHeadNotified = eq.safeHead
+	}
 }
 
 func (eq *EngineQueue) logSyncProgress(reason string) {
@@ -792,10 +814,20 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	if err != nil {
 		return NewTemporaryError(fmt.Errorf("failed to fetch L1 config of L2 block %s: %w", pipelineL2.ID(), err))
 	}
+	if err := eq.safeHeadNotifs.SafeHeadReset(safe); err != nil {
+		return NewTemporaryError(fmt.Errorf("failed to reset recorded safe heads to %s: %w", safe, err))
+	}
+	if safe.Hash == eq.cfg.Genesis.L2.Hash {
+		// The L2 genesis block is safe from the L1 block it starts from.
+		if err := eq.safeHeadNotifs.SafeHeadUpdated(safe, eq.cfg.Genesis.L1); err != nil {
+			return NewTemporaryError(fmt.Errorf("failed to record genesis safe head: %w", err))
+		}
+	}
 	eq.log.Debug("Reset engine queue", "safeHead", safe, "unsafe", unsafe, "safe_timestamp", safe.Time, "unsafe_timestamp", unsafe.Time, "l1Origin", l1Origin)
 	eq.unsafeHead = unsafe
 	eq.engineSyncTarget = unsafe
 	eq.safeHead = safe
+	eq.safeHeadNotified = safe
 	eq.safeAttributes = nil
 	eq.finalized = finalized
 	eq.resetBuildingState()
@@ -15,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
@@ -40,6 +41,26 @@ func (f *fakeAttributesQueue) NextAttributes(_ context.Context, _ eth.L2BlockRef
 
 var _ NextAttributesProvider = (*fakeAttributesQueue)(nil)
 
+type safeHeadUpdate struct {
+	safeHead eth.L2BlockRef
+	l1Block  eth.BlockID
+}
+
+type recordingSafeHeadListener struct {
+	updates []safeHeadUpdate
+	resets  []eth.L2BlockRef
+}
+
+func (r *recordingSafeHeadListener) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Block eth.BlockID) error {
+	r.updates = append(r.updates, safeHeadUpdate{safeHead: safeHead, l1Block: l1Block})
+	return nil
+}
+
+func (r *recordingSafeHeadListener) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	r.resets = append(r.resets, resetSafeHead)
+	return nil
+}
+
 func TestEngineQueue_Finalize(t *testing.T) {
 	logger := testlog.Logger(t, log.LvlInfo)
 
@@ -246,13 +267,20 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	}, nil)
 
 	prev := &fakeAttributesQueue{}
+	safeHeads := &recordingSafeHeadListener{}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, safeHeads)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, eq.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
 	require.Equal(t, refB, eq.Origin(), "Expecting to be set back derivation L1 progress to B")
 	require.Equal(t, refA1, eq.Finalized(), "A1 is recognized as finalized before we run any steps")
+	require.Equal(t, []eth.L2BlockRef{refB1}, safeHeads.resets, "recorded safe heads are reset")
+
+	// a new origin without a new safe head is not recorded
+	eq.origin = refC
+	eq.postProcessSafeL2()
+	require.Empty(t, safeHeads.updates)
 
 	// now say C1 was included in D and became the new safe head
 	eq.origin = refD
@@ -270,6 +298,10 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	eq.Finalize(refD)
 
 	require.Equal(t, refC1, eq.Finalized(), "C1 was included in finalized D, and should now be finalized")
+	require.Equal(t, []safeHeadUpdate{
+		{safeHead: refC1, l1Block: refD.ID()},
+		{safeHead: refD0, l1Block: refE.ID()},
+	}, safeHeads.updates, "safe heads are recorded with the L1 block they were derived from")
 
 	l1F.AssertExpectations(t)
 	eng.AssertExpectations(t)
@@ -481,7 +513,7 @@ func TestEngineQueue_ResetWhenUnsafeOriginNotCanonical(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refE}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, eq.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -812,7 +844,7 @@ func TestVerifyNewL1Origin(t *testing.T) {
 			}, nil)
 
 			prev := &fakeAttributesQueue{origin: refE}
-			eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+			eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 			require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 			require.Equal(t, refB1, eq.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -910,7 +942,7 @@ func TestBlockBuildingRace(t *testing.T) {
 	}
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs}
-	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	id := eth.PayloadID{0xff}
@@ -1080,7 +1112,7 @@ func TestResetLoop(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	eq.unsafeHead = refA2
 	eq.engineSyncTarget = refA2
 	eq.safeHead = refA1
@@ -1178,7 +1210,7 @@ func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA}
 
-	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, metrics.NoopMetrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	eq.unsafeHead = refA2
 	eq.safeHead = refA0
 	eq.finalized = refA0
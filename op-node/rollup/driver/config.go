@@ -20,4 +20,22 @@ type Config struct {
 	// SequencerMaxSafeLag is the maximum number of L2 blocks for restricting the distance between L2 safe and unsafe.
 	// Disabled if 0.
 	SequencerMaxSafeLag uint64 `json:"sequencer_max_safe_lag"`
+
+	// SequencerOriginSelection is the strategy to select the L1 origin of new L2 blocks with.
+	// Defaults to OriginSelectConfDepth if empty.
+	SequencerOriginSelection OriginSelectionKind `json:"sequencer_origin_selection"`
+
+	// SequencerTargetDrift is the time drift in seconds between a new L2 block and its L1 origin,
+	// that the adaptive-drift origin selection strategy holds on to the current L1 origin for,
+	// and at which the safe and finalized strategies fall back to the conf-depth strategy.
+	// Must be less than rollup.Config.MaxSequencerDrift. Defaults to half the max sequencer drift if 0.
+	SequencerTargetDrift uint64 `json:"sequencer_target_drift"`
+
+	// SequencerMaxOriginLag is the maximum number of seconds that a new L2 block timestamp may be ahead of its L1 origin,
+	// before the sequencer stops including transactions from the tx-pool. Disabled if 0.
+	SequencerMaxOriginLag uint64 `json:"sequencer_max_origin_lag"`
+
+	// SequencerGasTarget is the combined gas limit of the sequencer-signed transactions that policies add to a new
+	// L2 block, before the sequencer stops including transactions from the tx-pool. Deposits are not counted. Disabled if 0.
+	SequencerGasTarget uint64 `json:"sequencer_gas_target"`
 }
@@ -36,6 +36,7 @@ type Metrics interface {
 	EngineMetrics
 	L1FetcherMetrics
 	SequencerMetrics
+	OriginSelectorMetrics
 }
 
 type L1Chain interface {
@@ -112,18 +113,37 @@ type SequencerStateListener interface {
 	SequencerStopped() error
 }
 
+// SequencerLeadership coordinates which of several sequencer op-nodes, running in active/standby mode, may sequence.
+// The leader commits every payload it builds, before publishing it, so that a standby taking over leadership
+// can ensure it builds on top of the same unsafe head, and no reorg happens upon a leadership change.
+type SequencerLeadership interface {
+	// TryAcquire attempts to acquire, or hold on to, leadership, and returns true if leadership is held.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release gives up leadership, if held, so that a standby sequencer can take over.
+	Release(ctx context.Context) error
+	// CommitUnsafePayload records the latest payload built by the leader. This fails if leadership is not held.
+	CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error
+	// LatestUnsafePayload returns the latest payload committed by any leader, or nil if there is none.
+	LatestUnsafePayload(ctx context.Context) (*eth.ExecutionPayload, error)
+}
+
 // NewDriver composes an events handler that tracks L1 state, triggers L2 derivation, and optionally sequences new L2 blocks.
-func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, altSync AltSync, network Network, log log.Logger, snapshotLog log.Logger, metrics Metrics, sequencerStateListener SequencerStateListener, syncCfg *sync.Config) *Driver {
+// The sequencer leadership is optional, and may be nil if the sequencer does not run in active/standby mode.
+func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, altSync AltSync, network Network, log log.Logger, snapshotLog log.Logger, metrics Metrics, sequencerStateListener SequencerStateListener, sequencerLeadership SequencerLeadership, systemTxs SystemTxSource, syncCfg *sync.Config, safeHeadListener derive.SafeHeadListener) (*Driver, error) {
 	l1 = NewMeteredL1Fetcher(l1, metrics)
 	l1State := NewL1State(log, metrics)
-	sequencerConfDepth := NewConfDepth(driverCfg.SequencerConfDepth, l1State.L1Head, l1)
-	findL1Origin := NewL1OriginSelector(log, cfg, sequencerConfDepth)
+	findL1Origin, err := NewOriginSelector(log, cfg, driverCfg, l1State, l1, metrics)
+	if err != nil {
+		return nil, err
+	}
 	verifConfDepth := NewConfDepth(driverCfg.VerifierConfDepth, l1State.L1Head, l1)
-	derivationPipeline := derive.NewDerivationPipeline(log, cfg, verifConfDepth, l2, metrics, syncCfg)
+	derivationPipeline := derive.NewDerivationPipeline(log, cfg, verifConfDepth, l2, metrics, syncCfg, safeHeadListener)
 	attrBuilder := derive.NewFetchingAttributesBuilder(cfg, l1, l2)
 	engine := derivationPipeline
 	meteredEngine := NewMeteredEngine(cfg, engine, metrics, log)
-	sequencer := NewSequencer(log, cfg, meteredEngine, attrBuilder, findL1Origin, metrics)
+	throttle := new(ThrottlePolicy)
+	policy := SequencerPolicies{NewSequencerPolicy(driverCfg, systemTxs), throttle}
+	sequencer := NewSequencer(log, cfg, meteredEngine, attrBuilder, findL1Origin, policy, metrics)
 
 	return &Driver{
 		l1State:          l1State,
@@ -133,7 +153,9 @@ func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, al
 		startSequencer:   make(chan hashAndErrorChannel, 10),
 		stopSequencer:    make(chan chan hashAndError, 10),
 		sequencerActive:  make(chan chan bool, 10),
+		rewindHead:       make(chan rewindRequest, 10),
 		sequencerNotifs:  sequencerStateListener,
+		leadership:       sequencerLeadership,
 		config:           cfg,
 		driverConfig:     driverCfg,
 		done:             make(chan struct{}),
@@ -142,6 +164,7 @@ func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, al
 		l1:               l1,
 		l2:               l2,
 		sequencer:        sequencer,
+		throttle:         throttle,
 		network:          network,
 		metrics:          metrics,
 		l1HeadSig:        make(chan eth.L1BlockRef, 10),
@@ -149,5 +172,5 @@ func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, al
 		l1FinalizedSig:   make(chan eth.L1BlockRef, 10),
 		unsafeL2Payloads: make(chan *eth.ExecutionPayload, 10),
 		altSync:          altSync,
-	}
+	}, nil
 }
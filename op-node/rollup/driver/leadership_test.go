@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// testLeadershipLock is an in-memory leadership lock, shared by the testLeadership of each node.
+type testLeadershipLock struct {
+	holder *testLeadership
+	latest *eth.ExecutionPayload
+}
+
+type testLeadership struct {
+	lock      *testLeadershipLock
+	commitErr error
+}
+
+func (l *testLeadership) TryAcquire(ctx context.Context) (bool, error) {
+	if l.lock.holder == nil {
+		l.lock.holder = l
+	}
+	return l.lock.holder == l, nil
+}
+
+func (l *testLeadership) Release(ctx context.Context) error {
+	if l.lock.holder == l {
+		l.lock.holder = nil
+	}
+	return nil
+}
+
+func (l *testLeadership) CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error {
+	if l.commitErr != nil {
+		return l.commitErr
+	}
+	if l.lock.holder != l {
+		return errors.New("not holding sequencer leadership")
+	}
+	l.lock.latest = payload
+	return nil
+}
+
+func (l *testLeadership) LatestUnsafePayload(ctx context.Context) (*eth.ExecutionPayload, error) {
+	return l.lock.latest, nil
+}
+
+type testLeadershipDerivation struct {
+	DerivationPipeline
+	unsafe eth.L2BlockRef
+}
+
+func (d *testLeadershipDerivation) UnsafeL2Head() eth.L2BlockRef { return d.unsafe }
+
+func TestCommitAsLeaderFailure(t *testing.T) {
+	lock := &testLeadershipLock{}
+	mkDriver := func(unsafe eth.L2BlockRef) (*Driver, *testLeadership) {
+		leadership := &testLeadership{lock: lock}
+		return &Driver{
+			log:          testlog.Logger(t, log.LvlInfo),
+			driverConfig: &Config{SequencerEnabled: true},
+			derivation:   &testLeadershipDerivation{unsafe: unsafe},
+			leadership:   leadership,
+		}, leadership
+	}
+	head := eth.L2BlockRef{Hash: common.Hash{0x01}, Number: 1}
+	active, activeLeadership := mkDriver(head)
+	standby, _ := mkDriver(head)
+
+	acquired, err := active.checkLeadership(context.Background())
+	require.NoError(t, err)
+	require.True(t, acquired)
+	acquired, err = standby.checkLeadership(context.Background())
+	require.NoError(t, err)
+	require.False(t, acquired, "only one leader")
+
+	committed := &eth.ExecutionPayload{BlockHash: common.Hash{0x02}, BlockNumber: 2}
+	require.True(t, active.commitAsLeader(context.Background(), committed))
+	require.Equal(t, committed, lock.latest)
+
+	activeLeadership.commitErr = errors.New("mock commit err")
+	require.False(t, active.commitAsLeader(context.Background(), &eth.ExecutionPayload{BlockHash: common.Hash{0x03}, BlockNumber: 3}))
+	require.False(t, active.sequencerLeader())
+
+	standby.derivation.(*testLeadershipDerivation).unsafe = eth.L2BlockRef{Hash: committed.BlockHash, Number: 2}
+	acquired, err = standby.checkLeadership(context.Background())
+	require.NoError(t, err)
+	require.True(t, acquired, "standby takes over after failed commit")
+	require.Equal(t, committed, lock.latest, "uncommitted payload is not visible to the standby")
+}
+
+func TestCheckLeadershipConflict(t *testing.T) {
+	lock := &testLeadershipLock{latest: &eth.ExecutionPayload{BlockHash: common.Hash{0x02}, BlockNumber: 2}}
+	d := &Driver{
+		log:          testlog.Logger(t, log.LvlInfo),
+		driverConfig: &Config{SequencerEnabled: true},
+		derivation:   &testLeadershipDerivation{unsafe: eth.L2BlockRef{Hash: common.Hash{0x03}, Number: 2}},
+		leadership:   &testLeadership{lock: lock},
+	}
+	acquired, err := d.checkLeadership(context.Background())
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Nil(t, lock.holder, "conflicting node releases leadership for a standby to take over")
+}
@@ -0,0 +1,219 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// OriginSelectionKind identifies the strategy that the sequencer uses to select the L1 origin of new L2 blocks.
+type OriginSelectionKind string
+
+const (
+	// OriginSelectConfDepth adopts the next L1 origin as soon as it is SequencerConfDepth blocks deep.
+	OriginSelectConfDepth OriginSelectionKind = "conf-depth"
+	// OriginSelectSafe only adopts L1 origins up to the L1 safe block, unless the sequencer drift requires otherwise.
+	OriginSelectSafe OriginSelectionKind = "safe"
+	// OriginSelectFinalized only adopts L1 origins up to the L1 finalized block, unless the sequencer drift requires otherwise.
+	OriginSelectFinalized OriginSelectionKind = "finalized"
+	// OriginSelectAdaptiveDrift holds on to the current L1 origin until the sequencer drift reaches SequencerTargetDrift.
+	OriginSelectAdaptiveDrift OriginSelectionKind = "adaptive-drift"
+)
+
+var OriginSelectionKinds = []OriginSelectionKind{
+	OriginSelectConfDepth,
+	OriginSelectSafe,
+	OriginSelectFinalized,
+	OriginSelectAdaptiveDrift,
+}
+
+func (kind OriginSelectionKind) String() string {
+	return string(kind)
+}
+
+func (kind *OriginSelectionKind) Set(value string) error {
+	if !ValidOriginSelectionKind(OriginSelectionKind(value)) {
+		return fmt.Errorf("unknown origin selection kind: %q", value)
+	}
+	*kind = OriginSelectionKind(value)
+	return nil
+}
+
+func ValidOriginSelectionKind(value OriginSelectionKind) bool {
+	for _, k := range OriginSelectionKinds {
+		if k == value {
+			return true
+		}
+	}
+	return false
+}
+
+type OriginSelectorMetrics interface {
+	RecordSequencerOriginDrift(strategy string, timeDrift uint64, headDistance uint64)
+}
+
+// NewOriginSelector creates the L1 origin selection strategy of the sequencer, as configured in the driver config.
+// The conf-depth strategy is used if no strategy is configured.
+// All strategies keep a distance of SequencerConfDepth from the L1 head.
+func NewOriginSelector(log log.Logger, cfg *rollup.Config, driverCfg *Config, l1State L1StateIface, l1 derive.L1Fetcher, metrics OriginSelectorMetrics) (L1OriginSelectorIface, error) {
+	kind := driverCfg.SequencerOriginSelection
+	if kind == "" {
+		kind = OriginSelectConfDepth
+	}
+	confDepthL1 := NewConfDepth(driverCfg.SequencerConfDepth, l1State.L1Head, l1)
+	confDepthSelector := NewL1OriginSelector(log, cfg, confDepthL1)
+
+	targetDrift := driverCfg.SequencerTargetDrift
+	if targetDrift >= cfg.MaxSequencerDrift {
+		log.Warn("Sequencer target drift must be less than the max sequencer drift, using default",
+			"target_drift", targetDrift, "max_drift", cfg.MaxSequencerDrift)
+		targetDrift = 0
+	}
+	if targetDrift == 0 {
+		targetDrift = cfg.MaxSequencerDrift / 2
+	}
+
+	var selector L1OriginSelectorIface
+	switch kind {
+	case OriginSelectConfDepth:
+		selector = confDepthSelector
+	case OriginSelectSafe:
+		selector = NewBoundedOriginSelector(log, cfg, NewL1OriginSelector(log, cfg, NewL1Boundary(l1State.L1Safe, confDepthL1)), confDepthSelector, targetDrift)
+	case OriginSelectFinalized:
+		selector = NewBoundedOriginSelector(log, cfg, NewL1OriginSelector(log, cfg, NewL1Boundary(l1State.L1Finalized, confDepthL1)), confDepthSelector, targetDrift)
+	case OriginSelectAdaptiveDrift:
+		selector = NewAdaptiveDriftOriginSelector(cfg, confDepthL1, confDepthSelector, targetDrift)
+	default:
+		return nil, fmt.Errorf("unknown origin selection kind: %q", kind)
+	}
+	return NewMeteredOriginSelector(cfg, selector, string(kind), l1State.L1Head, metrics), nil
+}
+
+// l1Boundary is an util that wraps the L1 input fetcher used by the sequencer,
+// and hides the part of the L1 chain past the boundary, such as the L1 safe or finalized block.
+//
+// Unlike confDepth, all blocks by number are hidden if the boundary is not known yet.
+type l1Boundary struct {
+	// everything fetched by hash is trusted already, so we implement those by embedding the fetcher
+	derive.L1Fetcher
+	boundary func() eth.L1BlockRef
+}
+
+func NewL1Boundary(boundary func() eth.L1BlockRef, fetcher derive.L1Fetcher) *l1Boundary {
+	return &l1Boundary{L1Fetcher: fetcher, boundary: boundary}
+}
+
+// L1BlockRefByNumber mocks any block numbers past the boundary to be "not found".
+func (b *l1Boundary) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	boundary := b.boundary()
+	if boundary == (eth.L1BlockRef{}) || num > boundary.Number {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	return b.L1Fetcher.L1BlockRefByNumber(ctx, num)
+}
+
+var _ derive.L1Fetcher = (*l1Boundary)(nil)
+
+// BoundedOriginSelector selects the L1 origin with a preferred selector that is restricted to a part of the L1 chain,
+// and falls back to the less restricted selector if the preferred selector cannot find an origin,
+// or if the preferred origin is at or past the target drift from the next L2 block.
+// Falling back at the target drift, rather than at the max sequencer drift, keeps the sequencer clear of
+// the forced empty blocks past the max sequencer drift when the preferred part of the L1 chain lags behind.
+type BoundedOriginSelector struct {
+	log         log.Logger
+	cfg         *rollup.Config
+	preferred   L1OriginSelectorIface
+	fallback    L1OriginSelectorIface
+	targetDrift uint64
+}
+
+func NewBoundedOriginSelector(log log.Logger, cfg *rollup.Config, preferred L1OriginSelectorIface, fallback L1OriginSelectorIface, targetDrift uint64) *BoundedOriginSelector {
+	return &BoundedOriginSelector{log: log, cfg: cfg, preferred: preferred, fallback: fallback, targetDrift: targetDrift}
+}
+
+func (s *BoundedOriginSelector) FindL1Origin(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+	origin, err := s.preferred.FindL1Origin(ctx, l2Head)
+	if err != nil {
+		s.log.Warn("Failed to find preferred L1 origin, falling back", "l2_head", l2Head, "err", err)
+		return s.fallback.FindL1Origin(ctx, l2Head)
+	}
+	if l2Head.Time+s.cfg.BlockTime < origin.Time+s.targetDrift {
+		return origin, nil
+	}
+	fallbackOrigin, err := s.fallback.FindL1Origin(ctx, l2Head)
+	if err != nil {
+		// The preferred origin is still within the max sequencer drift, since the preferred selector accepted it.
+		s.log.Warn("Preferred L1 origin is past the target drift, but failed to find fallback L1 origin", "l2_head", l2Head, "origin", origin, "err", err)
+		return origin, nil
+	}
+	if fallbackOrigin.Hash != origin.Hash {
+		s.log.Info("Preferred L1 origin is past the target drift, falling back", "l2_head", l2Head, "origin", origin, "fallback", fallbackOrigin)
+	}
+	return fallbackOrigin, nil
+}
+
+// AdaptiveDriftOriginSelector holds on to the current L1 origin, until the time drift between
+// the next L2 block and the current L1 origin reaches the target drift.
+// Staying on older L1 origins reduces the exposure of the L2 chain to L1 reorgs,
+// while the target drift keeps the sequencer clear of the max sequencer drift.
+type AdaptiveDriftOriginSelector struct {
+	cfg         *rollup.Config
+	l1          L1Blocks
+	inner       L1OriginSelectorIface
+	targetDrift uint64
+}
+
+func NewAdaptiveDriftOriginSelector(cfg *rollup.Config, l1 L1Blocks, inner L1OriginSelectorIface, targetDrift uint64) *AdaptiveDriftOriginSelector {
+	return &AdaptiveDriftOriginSelector{cfg: cfg, l1: l1, inner: inner, targetDrift: targetDrift}
+}
+
+func (s *AdaptiveDriftOriginSelector) FindL1Origin(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+	origin, err := s.inner.FindL1Origin(ctx, l2Head)
+	if err != nil || origin.Hash == l2Head.L1Origin.Hash {
+		return origin, err
+	}
+	// The current origin is fetched by hash, and thus easily cached.
+	currentOrigin, err := s.l1.L1BlockRefByHash(ctx, l2Head.L1Origin.Hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	if l2Head.Time+s.cfg.BlockTime < currentOrigin.Time+s.targetDrift {
+		return currentOrigin, nil
+	}
+	return origin, nil
+}
+
+// MeteredOriginSelector records the drift of the sequencer from L1 for every selected L1 origin.
+type MeteredOriginSelector struct {
+	cfg      *rollup.Config
+	inner    L1OriginSelectorIface
+	strategy string
+	l1Head   func() eth.L1BlockRef
+	metrics  OriginSelectorMetrics
+}
+
+func NewMeteredOriginSelector(cfg *rollup.Config, inner L1OriginSelectorIface, strategy string, l1Head func() eth.L1BlockRef, metrics OriginSelectorMetrics) *MeteredOriginSelector {
+	return &MeteredOriginSelector{cfg: cfg, inner: inner, strategy: strategy, l1Head: l1Head, metrics: metrics}
+}
+
+func (s *MeteredOriginSelector) FindL1Origin(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+	origin, err := s.inner.FindL1Origin(ctx, l2Head)
+	if err != nil {
+		return origin, err
+	}
+	var timeDrift, headDistance uint64
+	if nextTime := l2Head.Time + s.cfg.BlockTime; nextTime > origin.Time {
+		timeDrift = nextTime - origin.Time
+	}
+	if l1Head := s.l1Head(); l1Head.Number > origin.Number {
+		headDistance = l1Head.Number - origin.Number
+	}
+	s.metrics.RecordSequencerOriginDrift(s.strategy, timeDrift, headDistance)
+	return origin, nil
+}
@@ -0,0 +1,212 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+type testOriginMetrics struct {
+	strategy     string
+	timeDrift    uint64
+	headDistance uint64
+}
+
+func (m *testOriginMetrics) RecordSequencerOriginDrift(strategy string, timeDrift uint64, headDistance uint64) {
+	m.strategy = strategy
+	m.timeDrift = timeDrift
+	m.headDistance = headDistance
+}
+
+func testOriginBlocks() (a, b eth.L1BlockRef) {
+	a = eth.L1BlockRef{
+		Hash:   common.Hash{'a'},
+		Number: 10,
+		Time:   20,
+	}
+	b = eth.L1BlockRef{
+		Hash:       common.Hash{'b'},
+		Number:     11,
+		Time:       25,
+		ParentHash: a.Hash,
+	}
+	return
+}
+
+func TestNewOriginSelector(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := &rollup.Config{MaxSequencerDrift: 500, BlockTime: 2}
+	l1State := NewL1State(log, metrics.NoopMetrics)
+	for _, kind := range append(OriginSelectionKinds, "") {
+		_, err := NewOriginSelector(log, cfg, &Config{SequencerOriginSelection: kind}, l1State, &testutils.MockL1Source{}, metrics.NoopMetrics)
+		require.NoError(t, err, "kind %q", kind)
+	}
+	_, err := NewOriginSelector(log, cfg, &Config{SequencerOriginSelection: "unknown"}, l1State, &testutils.MockL1Source{}, metrics.NoopMetrics)
+	require.ErrorContains(t, err, "unknown")
+}
+
+// TestBoundedOriginSelectorStaysBehindBoundary ensures that the origin selector does not
+// adopt the next L1 origin if it is past the boundary, while the sequencer drift allows it.
+//
+// There are 2 L1 blocks at time 20 & 25. The L2 Head is at time 26.
+// The next L1 block could be adopted, but block `a` is the boundary, e.g. the L1 safe block.
+func TestBoundedOriginSelectorStaysBehindBoundary(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := &rollup.Config{
+		MaxSequencerDrift: 500,
+		BlockTime:         2,
+	}
+	l1 := &testutils.MockL1Source{}
+	defer l1.AssertExpectations(t)
+	a, _ := testOriginBlocks()
+	l2Head := eth.L2BlockRef{
+		L1Origin: a.ID(),
+		Time:     26,
+	}
+
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+
+	boundary := func() eth.L1BlockRef { return a }
+	preferred := NewL1OriginSelector(log, cfg, NewL1Boundary(boundary, l1))
+	s := NewBoundedOriginSelector(log, cfg, preferred, NewL1OriginSelector(log, cfg, l1), cfg.MaxSequencerDrift/2)
+	next, err := s.FindL1Origin(context.Background(), l2Head)
+	require.NoError(t, err)
+	require.Equal(t, a, next)
+}
+
+// TestBoundedOriginSelectorFallback ensures that the origin selector
+// adopts the next L1 origin past the boundary if the sequencer drift requires it.
+//
+// There are 2 L1 blocks at time 20 & 25. The L2 Head is at time 28, the max sequencer drift is 8.
+// The next L2 block time 30 exceeds the drift, so the next L1 block has to be adopted.
+func TestBoundedOriginSelectorFallback(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := &rollup.Config{
+		MaxSequencerDrift: 8,
+		BlockTime:         2,
+	}
+	l1 := &testutils.MockL1Source{}
+	defer l1.AssertExpectations(t)
+	a, b := testOriginBlocks()
+	l2Head := eth.L2BlockRef{
+		L1Origin: a.ID(),
+		Time:     28,
+	}
+
+	// preferred and fallback selector each fetch the current origin
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, b, nil)
+
+	boundary := func() eth.L1BlockRef { return a }
+	preferred := NewL1OriginSelector(log, cfg, NewL1Boundary(boundary, l1))
+	s := NewBoundedOriginSelector(log, cfg, preferred, NewL1OriginSelector(log, cfg, l1), cfg.MaxSequencerDrift/2)
+	next, err := s.FindL1Origin(context.Background(), l2Head)
+	require.NoError(t, err)
+	require.Equal(t, b, next)
+}
+
+// TestBoundedOriginSelectorFallbackAtTargetDrift ensures that the origin selector adopts the next L1 origin
+// past the boundary once the target drift is reached, well before the max sequencer drift.
+//
+// There are 2 L1 blocks at time 20 & 25. The L2 Head is at time 28, the target drift is 10.
+// The next L2 block time 30 reaches the target drift, so the next L1 block is adopted.
+func TestBoundedOriginSelectorFallbackAtTargetDrift(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := &rollup.Config{
+		MaxSequencerDrift: 500,
+		BlockTime:         2,
+	}
+	l1 := &testutils.MockL1Source{}
+	defer l1.AssertExpectations(t)
+	a, b := testOriginBlocks()
+	l2Head := eth.L2BlockRef{
+		L1Origin: a.ID(),
+		Time:     28,
+	}
+
+	// preferred and fallback selector each fetch the current origin
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, b, nil)
+
+	boundary := func() eth.L1BlockRef { return a }
+	preferred := NewL1OriginSelector(log, cfg, NewL1Boundary(boundary, l1))
+	s := NewBoundedOriginSelector(log, cfg, preferred, NewL1OriginSelector(log, cfg, l1), 10)
+	next, err := s.FindL1Origin(context.Background(), l2Head)
+	require.NoError(t, err)
+	require.Equal(t, b, next)
+}
+
+func TestL1BoundaryUnknown(t *testing.T) {
+	l1 := &testutils.MockL1Source{}
+	defer l1.AssertExpectations(t)
+	b := NewL1Boundary(func() eth.L1BlockRef { return eth.L1BlockRef{} }, l1)
+	_, err := b.L1BlockRefByNumber(context.Background(), 1)
+	require.ErrorContains(t, err, "not found", "hide everything while the boundary is unknown")
+}
+
+// TestAdaptiveDriftOriginSelector ensures that the origin selector holds on to the current origin
+// until the target drift is reached.
+//
+// There are 2 L1 blocks at time 20 & 25, with a target drift of 10.
+func TestAdaptiveDriftOriginSelector(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := &rollup.Config{
+		MaxSequencerDrift: 500,
+		BlockTime:         2,
+	}
+	a, b := testOriginBlocks()
+
+	t.Run("below target drift", func(t *testing.T) {
+		l1 := &testutils.MockL1Source{}
+		defer l1.AssertExpectations(t)
+		// The next L2 block time is 28, a drift of 8 from the current origin
+		l2Head := eth.L2BlockRef{L1Origin: a.ID(), Time: 26}
+		l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+		l1.ExpectL1BlockRefByNumber(b.Number, b, nil)
+		l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+
+		s := NewAdaptiveDriftOriginSelector(cfg, l1, NewL1OriginSelector(log, cfg, l1), 10)
+		next, err := s.FindL1Origin(context.Background(), l2Head)
+		require.NoError(t, err)
+		require.Equal(t, a, next)
+	})
+	t.Run("at target drift", func(t *testing.T) {
+		l1 := &testutils.MockL1Source{}
+		defer l1.AssertExpectations(t)
+		// The next L2 block time is 30, a drift of 10 from the current origin
+		l2Head := eth.L2BlockRef{L1Origin: a.ID(), Time: 28}
+		l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+		l1.ExpectL1BlockRefByNumber(b.Number, b, nil)
+		l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+
+		s := NewAdaptiveDriftOriginSelector(cfg, l1, NewL1OriginSelector(log, cfg, l1), 10)
+		next, err := s.FindL1Origin(context.Background(), l2Head)
+		require.NoError(t, err)
+		require.Equal(t, b, next)
+	})
+}
+
+func TestMeteredOriginSelector(t *testing.T) {
+	cfg := &rollup.Config{BlockTime: 2}
+	a, _ := testOriginBlocks()
+	inner := testOriginSelectorFn(func(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+		return a, nil
+	})
+	l1Head := eth.L1BlockRef{Number: 14}
+	m := &testOriginMetrics{}
+	s := NewMeteredOriginSelector(cfg, inner, "test", func() eth.L1BlockRef { return l1Head }, m)
+	_, err := s.FindL1Origin(context.Background(), eth.L2BlockRef{L1Origin: a.ID(), Time: 30})
+	require.NoError(t, err)
+	require.Equal(t, &testOriginMetrics{strategy: "test", timeDrift: 12, headDistance: 4}, m)
+}
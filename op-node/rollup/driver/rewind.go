@@ -0,0 +1,61 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// RewindEngine rewinds the unsafe head of the execution engine to the L2 block of the given number,
+// with a forkchoice update. The safe head is rewound as well if rewindSafe is set,
+// otherwise the target may not be before the safe head. The finalized head is never rewound.
+//
+// The derivation pipeline must be reset afterwards, to continue from the rewound chain.
+// The execution engine must apply forkchoice updates to an older canonical block as a rewind of its head,
+// this is verified after the update.
+func RewindEngine(ctx context.Context, l2 L2Chain, state derive.EngineState, num uint64, rewindSafe bool) (target eth.L2BlockRef, safe eth.L2BlockRef, err error) {
+	unsafeHead, safeHead, finalized := state.UnsafeL2Head(), state.SafeL2Head(), state.Finalized()
+	if num < finalized.Number {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("cannot rewind to block %d, before finalized block %s", num, finalized)
+	}
+	if num > unsafeHead.Number {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("cannot rewind to block %d, after unsafe head %s", num, unsafeHead)
+	}
+	if num < safeHead.Number && !rewindSafe {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("cannot rewind to block %d, before safe head %s, without rewinding the safe head", num, safeHead)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+	target, err = l2.L2BlockRefByNumber(ctx, num)
+	if err != nil {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("failed to retrieve rewind target %d: %w", num, err)
+	}
+	safe = safeHead
+	if num < safeHead.Number {
+		safe = target
+	}
+	fc := eth.ForkchoiceState{
+		HeadBlockHash:      target.Hash,
+		SafeBlockHash:      safe.Hash,
+		FinalizedBlockHash: finalized.Hash,
+	}
+	fcRes, err := l2.ForkchoiceUpdate(ctx, &fc, nil)
+	if err != nil {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("failed to update forkchoice to rewind to %s: %w", target, err)
+	}
+	if fcRes.PayloadStatus.Status != eth.ExecutionValid {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("failed to rewind to %s: %w", target, eth.ForkchoiceUpdateErr(fcRes.PayloadStatus))
+	}
+	head, err := l2.L2BlockRefByLabel(ctx, eth.Unsafe)
+	if err != nil {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("failed to verify rewind to %s: %w", target, err)
+	}
+	if head.Hash != target.Hash {
+		return eth.L2BlockRef{}, eth.L2BlockRef{}, fmt.Errorf("execution engine did not rewind to %s, head is %s", target, head)
+	}
+	return target, safe, nil
+}
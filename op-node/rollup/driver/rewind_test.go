@@ -0,0 +1,84 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+type testEngineState struct {
+	finalized, safe, unsafe eth.L2BlockRef
+}
+
+func (s *testEngineState) Finalized() eth.L2BlockRef    { return s.finalized }
+func (s *testEngineState) SafeL2Head() eth.L2BlockRef   { return s.safe }
+func (s *testEngineState) UnsafeL2Head() eth.L2BlockRef { return s.unsafe }
+
+func TestRewindEngine(t *testing.T) {
+	ref := func(num uint64) eth.L2BlockRef {
+		return eth.L2BlockRef{Hash: common.Hash{byte(num)}, Number: num}
+	}
+	state := &testEngineState{finalized: ref(5), safe: ref(10), unsafe: ref(20)}
+	validFC := &eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}}
+
+	t.Run("before finalized", func(t *testing.T) {
+		_, _, err := RewindEngine(context.Background(), &testutils.MockEngine{}, state, 4, true)
+		require.ErrorContains(t, err, "before finalized")
+	})
+	t.Run("after unsafe head", func(t *testing.T) {
+		_, _, err := RewindEngine(context.Background(), &testutils.MockEngine{}, state, 21, true)
+		require.ErrorContains(t, err, "after unsafe head")
+	})
+	t.Run("before safe head", func(t *testing.T) {
+		_, _, err := RewindEngine(context.Background(), &testutils.MockEngine{}, state, 9, false)
+		require.ErrorContains(t, err, "before safe head")
+	})
+	t.Run("unsafe only", func(t *testing.T) {
+		eng := &testutils.MockEngine{}
+		defer eng.AssertExpectations(t)
+		eng.ExpectL2BlockRefByNumber(15, ref(15), nil)
+		eng.ExpectForkchoiceUpdate(&eth.ForkchoiceState{
+			HeadBlockHash:      ref(15).Hash,
+			SafeBlockHash:      ref(10).Hash,
+			FinalizedBlockHash: ref(5).Hash,
+		}, nil, validFC, nil)
+		eng.ExpectL2BlockRefByLabel(eth.Unsafe, ref(15), nil)
+		target, safe, err := RewindEngine(context.Background(), eng, state, 15, false)
+		require.NoError(t, err)
+		require.Equal(t, ref(15), target)
+		require.Equal(t, ref(10), safe)
+	})
+	t.Run("with safe head", func(t *testing.T) {
+		eng := &testutils.MockEngine{}
+		defer eng.AssertExpectations(t)
+		eng.ExpectL2BlockRefByNumber(7, ref(7), nil)
+		eng.ExpectForkchoiceUpdate(&eth.ForkchoiceState{
+			HeadBlockHash:      ref(7).Hash,
+			SafeBlockHash:      ref(7).Hash,
+			FinalizedBlockHash: ref(5).Hash,
+		}, nil, validFC, nil)
+		eng.ExpectL2BlockRefByLabel(eth.Unsafe, ref(7), nil)
+		target, safe, err := RewindEngine(context.Background(), eng, state, 7, true)
+		require.NoError(t, err)
+		require.Equal(t, ref(7), target)
+		require.Equal(t, ref(7), safe)
+	})
+	t.Run("engine did not rewind", func(t *testing.T) {
+		eng := &testutils.MockEngine{}
+		defer eng.AssertExpectations(t)
+		eng.ExpectL2BlockRefByNumber(15, ref(15), nil)
+		eng.ExpectForkchoiceUpdate(&eth.ForkchoiceState{
+			HeadBlockHash:      ref(15).Hash,
+			SafeBlockHash:      ref(10).Hash,
+			FinalizedBlockHash: ref(5).Hash,
+		}, nil, validFC, nil)
+		eng.ExpectL2BlockRefByLabel(eth.Unsafe, ref(20), nil)
+		_, _, err := RewindEngine(context.Background(), eng, state, 15, false)
+		require.ErrorContains(t, err, "did not rewind")
+	})
+}
@@ -0,0 +1,147 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// SequencerPolicy is consulted by the sequencer after the payload attributes of a new block are prepared,
+// and before the engine is asked to start building the block.
+//
+// A policy may append sequencer-signed (non-deposit) transactions and may force NoTxPool,
+// but must not modify or remove the deposit transactions that the attributes were prepared with:
+// these are reproduced by the derivation process and any change would invalidate the block.
+// The policy is not consulted when the block is past the sequencer time drift,
+// since such a block has to be empty by protocol rules.
+type SequencerPolicy interface {
+	ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error
+}
+
+// SequencerPolicies applies each of the contained policies in order, and stops at the first error.
+// An empty list of policies does not modify the payload attributes.
+type SequencerPolicies []SequencerPolicy
+
+func (ps SequencerPolicies) ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+	for _, p := range ps {
+		if err := p.ApplyPolicy(ctx, l2Head, l1Origin, attrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSequencerPolicy creates the block-building policy as selected by the driver config.
+// The systemTxs source is optional: if not nil, its transactions are injected into every new block,
+// before the gas target is checked.
+func NewSequencerPolicy(driverCfg *Config, systemTxs SystemTxSource) SequencerPolicy {
+	var policies SequencerPolicies
+	if driverCfg.SequencerMaxOriginLag > 0 {
+		policies = append(policies, &OriginLagPolicy{MaxOriginLag: driverCfg.SequencerMaxOriginLag})
+	}
+	if systemTxs != nil {
+		policies = append(policies, &SystemTxPolicy{Source: systemTxs})
+	}
+	if driverCfg.SequencerGasTarget > 0 {
+		policies = append(policies, &GasTargetPolicy{GasTarget: driverCfg.SequencerGasTarget})
+	}
+	return policies
+}
+
+// OriginLagPolicy forces NoTxPool when the timestamp of the new block is more than MaxOriginLag seconds
+// ahead of the L1 origin time. Configuring a lag smaller than rollup.Config.MaxSequencerDrift
+// makes the sequencer produce empty blocks before the protocol forces it to.
+type OriginLagPolicy struct {
+	MaxOriginLag uint64
+}
+
+func (p *OriginLagPolicy) ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+	if uint64(attrs.Timestamp) > l1Origin.Time+p.MaxOriginLag {
+		attrs.NoTxPool = true
+	}
+	return nil
+}
+
+// SystemTxSource provides the sequencer-signed transactions to include at the start of a new block,
+// after the deposits and before any transactions from the tx-pool.
+type SystemTxSource interface {
+	SystemTxs(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef) ([]eth.Data, error)
+}
+
+// SystemTxPolicy injects the transactions of a SystemTxSource into every new block.
+// The injected transactions are regular L2 transactions: they are batch-submitted like tx-pool transactions,
+// and thus must not be deposits.
+type SystemTxPolicy struct {
+	Source SystemTxSource
+}
+
+func (p *SystemTxPolicy) ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+	txs, err := p.Source.SystemTxs(ctx, l2Head, l1Origin)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve system txs: %w", err)
+	}
+	for i, tx := range txs {
+		if len(tx) == 0 {
+			return fmt.Errorf("system tx %d is empty", i)
+		}
+		if tx[0] == types.DepositTxType {
+			return fmt.Errorf("system tx %d is a deposit, sequencers may not inject deposits", i)
+		}
+	}
+	attrs.Transactions = append(attrs.Transactions, txs...)
+	return nil
+}
+
+// GasTargetPolicy forces NoTxPool when the sequencer-signed transactions that earlier policies added to the
+// payload attributes together reach the configured gas target. The gas limits of these transactions are used,
+// as an upper bound of the gas they use, since the block has not been built yet.
+// Deposits, including the L1 info transaction, are not counted: their gas limit is typically far above the gas
+// they use, and they are part of every block regardless.
+// The block gas limit itself is not changed, as it is part of the L2 system config, shared with verifiers.
+type GasTargetPolicy struct {
+	GasTarget uint64
+}
+
+func (p *GasTargetPolicy) ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+	var gas uint64
+	for i, otx := range attrs.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(otx); err != nil {
+			return fmt.Errorf("failed to decode tx %d of payload attributes: %w", i, err)
+		}
+		if tx.IsDepositTx() {
+			continue
+		}
+		gas += tx.Gas()
+	}
+	if gas >= p.GasTarget {
+		attrs.NoTxPool = true
+	}
+	return nil
+}
+
+// ThrottlePolicy forces NoTxPool on all blocks but every Interval-th block, to slow down the growth of
+// the unsafe chain data, e.g. when the batcher cannot keep up with submitting it to L1.
+// The interval can be changed at runtime, and an interval of 0 or 1 disables throttling.
+type ThrottlePolicy struct {
+	interval atomic.Uint64
+}
+
+func (p *ThrottlePolicy) SetInterval(interval uint64) {
+	p.interval.Store(interval)
+}
+
+func (p *ThrottlePolicy) Interval() uint64 {
+	return p.interval.Load()
+}
+
+func (p *ThrottlePolicy) ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+	if interval := p.interval.Load(); interval > 1 && (l2Head.Number+1)%interval != 0 {
+		attrs.NoTxPool = true
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+type testSystemTxSourceFn func(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef) ([]eth.Data, error)
+
+func (fn testSystemTxSourceFn) SystemTxs(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef) ([]eth.Data, error) {
+	return fn(ctx, l2Head, l1Origin)
+}
+
+func mockDepositBytes(t *testing.T, gas uint64) eth.Data {
+	data, err := types.NewTx(&types.DepositTx{Gas: gas}).MarshalBinary()
+	require.NoError(t, err)
+	return data
+}
+
+func mockTxBytes(t *testing.T, gas uint64) eth.Data {
+	data, err := types.NewTx(&types.DynamicFeeTx{Gas: gas}).MarshalBinary()
+	require.NoError(t, err)
+	return data
+}
+
+func TestNewSequencerPolicy(t *testing.T) {
+	require.Empty(t, NewSequencerPolicy(&Config{}, nil), "no policies by default")
+	p := NewSequencerPolicy(&Config{SequencerMaxOriginLag: 10, SequencerGasTarget: 1000}, nil)
+	require.Equal(t, SequencerPolicies{
+		&OriginLagPolicy{MaxOriginLag: 10},
+		&GasTargetPolicy{GasTarget: 1000},
+	}, p)
+
+	t.Run("system txs before gas target", func(t *testing.T) {
+		var txs []eth.Data
+		src := testSystemTxSourceFn(func(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef) ([]eth.Data, error) {
+			return txs, nil
+		})
+		p := NewSequencerPolicy(&Config{SequencerGasTarget: 1000}, src)
+		deposit := mockDepositBytes(t, 1_000_000)
+
+		txs = []eth.Data{mockTxBytes(t, 400)}
+		attrs := &eth.PayloadAttributes{Transactions: []eth.Data{deposit}}
+		require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs))
+		require.Equal(t, []eth.Data{deposit, txs[0]}, attrs.Transactions)
+		require.False(t, attrs.NoTxPool, "below gas target")
+
+		txs = []eth.Data{mockTxBytes(t, 400), mockTxBytes(t, 600)}
+		attrs = &eth.PayloadAttributes{Transactions: []eth.Data{deposit}}
+		require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs))
+		require.Equal(t, []eth.Data{deposit, txs[0], txs[1]}, attrs.Transactions)
+		require.True(t, attrs.NoTxPool, "injected system txs reach the gas target")
+	})
+}
+
+func TestSequencerPolicies(t *testing.T) {
+	var calls []int
+	mkPolicy := func(i int, err error) SequencerPolicy {
+		return testPolicyFn(func(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+			calls = append(calls, i)
+			return err
+		})
+	}
+	attrs := &eth.PayloadAttributes{}
+	require.NoError(t, SequencerPolicies{mkPolicy(0, nil), mkPolicy(1, nil)}.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs))
+	require.Equal(t, []int{0, 1}, calls)
+
+	calls = nil
+	mockErr := errors.New("mock policy err")
+	err := SequencerPolicies{mkPolicy(0, mockErr), mkPolicy(1, nil)}.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs)
+	require.ErrorIs(t, err, mockErr)
+	require.Equal(t, []int{0}, calls, "stop at first error")
+}
+
+func TestOriginLagPolicy(t *testing.T) {
+	p := &OriginLagPolicy{MaxOriginLag: 10}
+	origin := eth.L1BlockRef{Time: 100}
+
+	attrs := &eth.PayloadAttributes{Timestamp: 110}
+	require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, origin, attrs))
+	require.False(t, attrs.NoTxPool, "at max lag")
+
+	attrs = &eth.PayloadAttributes{Timestamp: 111}
+	require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, origin, attrs))
+	require.True(t, attrs.NoTxPool, "past max lag")
+}
+
+func TestThrottlePolicy(t *testing.T) {
+	p := new(ThrottlePolicy)
+	for _, interval := range []uint64{0, 1} {
+		p.SetInterval(interval)
+		attrs := &eth.PayloadAttributes{}
+		require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{Number: 10}, eth.L1BlockRef{}, attrs))
+		require.False(t, attrs.NoTxPool, "throttling disabled with interval %d", interval)
+	}
+
+	p.SetInterval(4)
+	for num := uint64(10); num < 20; num++ {
+		attrs := &eth.PayloadAttributes{}
+		require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{Number: num}, eth.L1BlockRef{}, attrs))
+		require.Equal(t, (num+1)%4 != 0, attrs.NoTxPool, "block %d", num+1)
+	}
+}
+
+func TestGasTargetPolicy(t *testing.T) {
+	p := &GasTargetPolicy{GasTarget: 1000}
+	deposit := mockDepositBytes(t, 1_000_000)
+
+	attrs := &eth.PayloadAttributes{Transactions: []eth.Data{deposit, mockTxBytes(t, 400), mockTxBytes(t, 500)}}
+	require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs))
+	require.False(t, attrs.NoTxPool, "below gas target, deposits are not counted")
+
+	attrs = &eth.PayloadAttributes{Transactions: []eth.Data{deposit, mockTxBytes(t, 400), mockTxBytes(t, 600)}}
+	require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs))
+	require.True(t, attrs.NoTxPool, "at gas target")
+
+	attrs = &eth.PayloadAttributes{Transactions: []eth.Data{{0x7e, 0xba, 0xd0}}}
+	require.ErrorContains(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs), "decode")
+}
+
+func TestSystemTxPolicy(t *testing.T) {
+	var txs []eth.Data
+	var srcErr error
+	p := &SystemTxPolicy{Source: testSystemTxSourceFn(func(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef) ([]eth.Data, error) {
+		return txs, srcErr
+	})}
+	deposit := mockDepositBytes(t, 100)
+
+	t.Run("append", func(t *testing.T) {
+		txs = []eth.Data{{0x02, 0x01}, {0x02, 0x02}}
+		attrs := &eth.PayloadAttributes{Transactions: []eth.Data{deposit}}
+		require.NoError(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs))
+		require.Equal(t, []eth.Data{deposit, {0x02, 0x01}, {0x02, 0x02}}, attrs.Transactions)
+	})
+	t.Run("reject deposit", func(t *testing.T) {
+		txs = []eth.Data{deposit}
+		attrs := &eth.PayloadAttributes{}
+		require.ErrorContains(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs), "deposit")
+		require.Empty(t, attrs.Transactions)
+	})
+	t.Run("reject empty", func(t *testing.T) {
+		txs = []eth.Data{{}}
+		attrs := &eth.PayloadAttributes{}
+		require.ErrorContains(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs), "empty")
+	})
+	t.Run("source error", func(t *testing.T) {
+		txs = nil
+		srcErr = errors.New("mock source err")
+		attrs := &eth.PayloadAttributes{}
+		require.ErrorIs(t, p.ApplyPolicy(context.Background(), eth.L2BlockRef{}, eth.L1BlockRef{}, attrs), srcErr)
+	})
+}
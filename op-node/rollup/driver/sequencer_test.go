@@ -147,6 +147,14 @@ func (fn testOriginSelectorFn) FindL1Origin(ctx context.Context, l2Head eth.L2Bl
 
 var _ L1OriginSelectorIface = (testOriginSelectorFn)(nil)
 
+type testPolicyFn func(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error
+
+func (fn testPolicyFn) ApplyPolicy(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+	return fn(ctx, l2Head, l1Origin, attrs)
+}
+
+var _ SequencerPolicy = (testPolicyFn)(nil)
+
 // TestSequencerChaosMonkey runs the sequencer in a mocked adversarial environment with
 // repeated random errors in dependencies and poor clock timing.
 // At the end the health of the chain is checked to show that the sequencer kept the chain in shape.
@@ -302,7 +310,21 @@ func TestSequencerChaosMonkey(t *testing.T) {
 		}
 	})
 
-	seq := NewSequencer(log, cfg, engControl, attrBuilder, originSelector, metrics.NoopMetrics)
+	// The policy injects a system tx into every block, and sometimes forces an empty block.
+	mockSystemTx := eth.Data("mock system tx")
+	var policyErr error
+	policy := testPolicyFn(func(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef, attrs *eth.PayloadAttributes) error {
+		if policyErr != nil {
+			return policyErr
+		}
+		attrs.Transactions = append(attrs.Transactions, mockSystemTx)
+		if rng.Intn(10) == 0 {
+			attrs.NoTxPool = true
+		}
+		return nil
+	})
+
+	seq := NewSequencer(log, cfg, engControl, attrBuilder, originSelector, policy, metrics.NoopMetrics)
 	seq.timeNow = clockFn
 
 	// try to build 1000 blocks, with 5x as many planning attempts, to handle errors and clock problems
@@ -326,13 +348,14 @@ func TestSequencerChaosMonkey(t *testing.T) {
 		// reset errors
 		originErr = nil
 		attrsErr = nil
+		policyErr = nil
 		if engControl.err != mockResetErr { // the mockResetErr requires the sequencer to Reset() to recover.
 			engControl.err = nil
 		}
 		engControl.errTyp = derive.BlockInsertOK
 
 		// maybe make something maybe fail, or try a new L1 origin
-		switch rng.Intn(20) { // 9/20 = 45% chance to fail sequencer action (!!!)
+		switch rng.Intn(20) { // 10/20 = 50% chance to fail sequencer action (!!!)
 		case 0, 1:
 			originErr = errors.New("mock origin error")
 		case 2, 3:
@@ -345,6 +368,8 @@ func TestSequencerChaosMonkey(t *testing.T) {
 			engControl.errTyp = derive.BlockInsertPrestateErr
 		case 8:
 			engControl.err = mockResetErr
+		case 9:
+			policyErr = errors.New("mock policy error")
 		default:
 			// no error
 		}
@@ -357,6 +382,11 @@ func TestSequencerChaosMonkey(t *testing.T) {
 			info, err := derive.L1InfoDepositTxData(tx.Data())
 			require.NoError(t, err)
 			require.GreaterOrEqual(t, uint64(payload.Timestamp), info.Time, "ensure L2 time >= L1 time")
+			if uint64(payload.Timestamp) > info.Time+cfg.MaxSequencerDrift {
+				require.Len(t, payload.Transactions, 1, "blocks past the sequencer drift must be empty, even with a policy")
+			} else {
+				require.Equal(t, mockSystemTx, payload.Transactions[1], "policy injected the system tx")
+			}
 		}
 	}
 
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
@@ -25,6 +26,9 @@ type SyncStatus = eth.SyncStatus
 // sealingDuration defines the expected time it takes to seal the block
 const sealingDuration = time.Millisecond * 50
 
+// leadershipCheckInterval defines how often the sequencer leadership is acquired or renewed in active/standby mode
+const leadershipCheckInterval = time.Second
+
 type Driver struct {
 	l1State L1StateIface
 
@@ -35,6 +39,11 @@ type Driver struct {
 	// Requests to block the event loop for synchronous execution to avoid reading an inconsistent state
 	stateReq chan chan struct{}
 
+	// syncStatusFeed publishes the sync status whenever any of the L1 or L2 heads changes.
+	syncStatusFeed syncStatusFeed
+	// lastSyncStatus is the last published sync status. Only accessed synchronously with the event loop.
+	lastSyncStatus *eth.SyncStatus
+
 	// Upon receiving a channel in this channel, the derivation pipeline is forced to be reset.
 	// It tells the caller that the reset occurred by closing the passed in channel.
 	forceReset chan chan struct{}
@@ -52,9 +61,21 @@ type Driver struct {
 	// true when the sequencer is active, false when it is not.
 	sequencerActive chan chan bool
 
+	// Upon receiving a request in this channel, the unsafe head (and optionally the safe head) is rewound.
+	// It tells the caller the result by sending an error, or nil, to the channel of the request.
+	rewindHead chan rewindRequest
+
 	// sequencerNotifs is notified when the sequencer is started or stopped
 	sequencerNotifs SequencerStateListener
 
+	// leadership coordinates active/standby sequencing with other op-nodes. Nil if disabled.
+	leadership SequencerLeadership
+	// isLeader is true when the leadership is held, and the unsafe head is in sync with the previous leader.
+	// Only accessed synchronously with the event loop.
+	isLeader bool
+	// leaderPayload is the hash of the latest payload of the previous leader that was queued to sync to.
+	leaderPayload common.Hash
+
 	// Rollup config: rollup chain configuration
 	config *rollup.Config
 
@@ -81,6 +102,7 @@ type Driver struct {
 	l1        L1Chain
 	l2        L2Chain
 	sequencer SequencerIface
+	throttle  *ThrottlePolicy
 	network   Network // may be nil, network for is optional
 
 	metrics     Metrics
@@ -229,11 +251,21 @@ func (s *Driver) eventLoop() {
 	defer altSyncTicker.Stop()
 	lastUnsafeL2 := s.derivation.UnsafeL2Head()
 
+	// In active/standby mode the sequencer leadership is checked periodically.
+	var leadershipCh <-chan time.Time
+	if s.driverConfig.SequencerEnabled && s.leadership != nil {
+		leadershipTicker := time.NewTicker(leadershipCheckInterval)
+		defer leadershipTicker.Stop()
+		leadershipCh = leadershipTicker.C
+	}
+
 	for {
+		s.publishSyncStatus()
+
 		// If we are sequencing, and the L1 state is ready, update the trigger for the next sequencer action.
 		// This may adjust at any time based on fork-choice changes or previous errors.
 		// And avoid sequencing if the derivation pipeline indicates the engine is not ready.
-		if s.driverConfig.SequencerEnabled && !s.driverConfig.SequencerStopped &&
+		if s.driverConfig.SequencerEnabled && !s.driverConfig.SequencerStopped && s.sequencerLeader() &&
 			s.l1State.L1Head() != (eth.L1BlockRef{}) && s.derivation.EngineReady() {
 			if s.driverConfig.SequencerMaxSafeLag > 0 && s.derivation.SafeL2Head().Number+s.driverConfig.SequencerMaxSafeLag <= s.derivation.UnsafeL2Head().Number {
 				// If the safe head has fallen behind by a significant number of blocks, delay creating new blocks
@@ -271,6 +303,9 @@ func (s *Driver) eventLoop() {
 				s.log.Error("Sequencer critical error", "err", err)
 				return
 			}
+			if payload != nil && !s.commitAsLeader(ctx, payload) {
+				continue
+			}
 			if s.network != nil && payload != nil {
 				// Publishing of unsafe data via p2p is optional.
 				// Errors are not severe enough to change/halt sequencing but should be logged and metered.
@@ -348,6 +383,16 @@ func (s *Driver) eventLoop() {
 				stepAttempts = 0
 				reqStep() // continue with the next step if we can
 			}
+		case <-leadershipCh:
+			prevLeaderPayload := s.leaderPayload
+			acquired, err := s.checkLeadership(ctx)
+			if err != nil {
+				s.log.Warn("Failed to check sequencer leadership", "err", err)
+			} else if acquired {
+				planSequencerAction() // start sequencing as new leader
+			} else if s.leaderPayload != prevLeaderPayload {
+				reqStep() // process the queued payload of the previous leader
+			}
 		case respCh := <-s.stateReq:
 			respCh <- struct{}{}
 		case respCh := <-s.forceReset:
@@ -381,16 +426,109 @@ func (s *Driver) eventLoop() {
 				}
 				s.log.Warn("Sequencer has been stopped")
 				s.driverConfig.SequencerStopped = true
+				s.releaseLeadership(ctx)
 				respCh <- hashAndError{hash: s.derivation.UnsafeL2Head().Hash}
 			}
 		case respCh := <-s.sequencerActive:
-			respCh <- !s.driverConfig.SequencerStopped
+			respCh <- !s.driverConfig.SequencerStopped && s.sequencerLeader()
+		case req := <-s.rewindHead:
+			req.err <- s.rewind(ctx, req.num, req.rewindSafe)
+			reqStep() // continue from the rewound chain
 		case <-s.done:
+			s.releaseLeadership(ctx)
 			return
 		}
 	}
 }
 
+// sequencerLeader returns true if the sequencer leadership is held, or if active/standby mode is disabled.
+func (s *Driver) sequencerLeader() bool {
+	return s.leadership == nil || s.isLeader
+}
+
+// checkLeadership acquires or renews the sequencer leadership, and returns true if this node just became the leader.
+// A standby that acquires the leadership only starts sequencing once its unsafe head matches
+// the latest payload committed by the previous leader, to avoid a reorg upon the leadership change.
+// This should only be called synchronously with the driver event loop.
+func (s *Driver) checkLeadership(ctx context.Context) (bool, error) {
+	if s.driverConfig.SequencerStopped {
+		return false, nil // a stopped sequencer does not compete for leadership
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second*2)
+	defer cancel()
+	held, err := s.leadership.TryAcquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leadership: %w", err)
+	}
+	if !held {
+		if s.isLeader {
+			s.log.Warn("Lost sequencer leadership")
+			s.isLeader = false
+		}
+		return false, nil
+	}
+	if s.isLeader {
+		return false, nil
+	}
+	latest, err := s.leadership.LatestUnsafePayload(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve latest payload of previous leader: %w", err)
+	}
+	head := s.derivation.UnsafeL2Head()
+	if latest != nil && latest.BlockHash != head.Hash {
+		if uint64(latest.BlockNumber) <= head.Number {
+			s.log.Error("Holding sequencer leadership, but unsafe head conflicts with latest block of previous leader, releasing it",
+				"unsafe_l2", head, "leader_l2", latest.ID())
+			s.releaseLeadership(ctx)
+			return false, nil
+		}
+		s.log.Info("Holding sequencer leadership, waiting to sync to latest block of previous leader",
+			"unsafe_l2", head, "leader_l2", latest.ID())
+		if s.leaderPayload != latest.BlockHash {
+			s.leaderPayload = latest.BlockHash
+			s.derivation.AddUnsafePayload(latest)
+		}
+		return false, nil
+	}
+	s.log.Info("Acquired sequencer leadership", "unsafe_l2", head)
+	s.isLeader = true
+	return true, nil
+}
+
+// commitAsLeader commits a newly built payload as sequencer leader, if active/standby mode is enabled,
+// and returns false if the payload must not be published.
+// The block must be committed before it is published, so that no standby sequencer takes over without it.
+// If the commit fails, the leadership is released: the unsafe head of this node now conflicts with
+// the latest committed payload, and a standby has to take over to continue sequencing.
+// This should only be called synchronously with the driver event loop.
+func (s *Driver) commitAsLeader(ctx context.Context, payload *eth.ExecutionPayload) bool {
+	if s.leadership == nil {
+		return true
+	}
+	commitCtx, cancel := context.WithTimeout(ctx, time.Second*2)
+	defer cancel()
+	if err := s.leadership.CommitUnsafePayload(commitCtx, payload); err != nil {
+		s.log.Error("Failed to commit new block as sequencer leader, not publishing it and releasing leadership", "id", payload.ID(), "err", err)
+		s.releaseLeadership(ctx)
+		return false
+	}
+	return true
+}
+
+// releaseLeadership gives up the sequencer leadership, if active/standby mode is enabled.
+// This should only be called synchronously with the driver event loop.
+func (s *Driver) releaseLeadership(ctx context.Context) {
+	if s.leadership == nil {
+		return
+	}
+	s.isLeader = false
+	ctx, cancel := context.WithTimeout(ctx, time.Second*2)
+	defer cancel()
+	if err := s.leadership.Release(ctx); err != nil {
+		s.log.Warn("Failed to release sequencer leadership", "err", err)
+	}
+}
+
 // ResetDerivationPipeline forces a reset of the derivation pipeline.
 // It waits for the reset to occur. It simply unblocks the caller rather
 // than fully cancelling the reset request upon a context cancellation.
@@ -466,6 +604,66 @@ func (s *Driver) SequencerActive(ctx context.Context) (bool, error) {
 	}
 }
 
+// RewindHead rewinds the unsafe head, and the safe head if rewindSafe is set, to the L2 block of the given number.
+// The target may not be before the finalized head. The sequencer must be stopped first.
+func (s *Driver) RewindHead(ctx context.Context, num uint64, rewindSafe bool) error {
+	req := rewindRequest{
+		num:        num,
+		rewindSafe: rewindSafe,
+		err:        make(chan error, 1),
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case s.rewindHead <- req:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-req.err:
+			return err
+		}
+	}
+}
+
+// SetSequencerThrottle makes the sequencer only include tx-pool transactions in every interval-th block.
+// An interval of 0 or 1 disables throttling.
+func (s *Driver) SetSequencerThrottle(ctx context.Context, interval uint64) error {
+	if !s.driverConfig.SequencerEnabled {
+		return errors.New("sequencer is not enabled")
+	}
+	if prev := s.throttle.Interval(); prev != interval {
+		s.log.Info("Changed sequencer throttle", "interval", interval, "prev", prev)
+		s.throttle.SetInterval(interval)
+	}
+	return nil
+}
+
+// rewind rewinds the execution engine and resets the derivation pipeline.
+// This should only be called synchronously with the driver event loop.
+func (s *Driver) rewind(ctx context.Context, num uint64, rewindSafe bool) error {
+	if s.driverConfig.SequencerEnabled && !s.driverConfig.SequencerStopped {
+		return errors.New("sequencer must be stopped to rewind")
+	}
+	prevUnsafe, prevSafe := s.derivation.UnsafeL2Head(), s.derivation.SafeL2Head()
+	target, safe, err := RewindEngine(ctx, s.l2, s.derivation, num, rewindSafe)
+	if err != nil {
+		// the engine may have applied part of the forkchoice update, reset to pick up its current state
+		s.derivation.Reset()
+		s.metrics.RecordPipelineReset()
+		return err
+	}
+	s.log.Warn("Rewound L2 chain", "target", target, "prev_unsafe", prevUnsafe, "prev_safe", prevSafe, "safe", safe)
+	s.snapshotLog.Info("Rollup Head Rewind",
+		"target", deferJSONString{target},
+		"l2Head", deferJSONString{prevUnsafe},
+		"l2Safe", deferJSONString{prevSafe},
+		"newL2Safe", deferJSONString{safe},
+		"l2FinalizedHead", deferJSONString{s.derivation.Finalized()})
+	s.derivation.Reset()
+	s.metrics.RecordPipelineReset()
+	return nil
+}
+
 // syncStatus returns the current sync status, and should only be called synchronously with
 // the driver event loop to avoid retrieval of an inconsistent status.
 func (s *Driver) syncStatus() *eth.SyncStatus {
@@ -483,6 +681,25 @@ func (s *Driver) syncStatus() *eth.SyncStatus {
 	}
 }
 
+// publishSyncStatus sends the sync status to the subscribers, if any of the L1 or L2 heads changed since it was last published.
+func (s *Driver) publishSyncStatus() {
+	status := s.syncStatus()
+	if last := s.lastSyncStatus; last != nil &&
+		last.HeadL1 == status.HeadL1 && last.SafeL1 == status.SafeL1 && last.FinalizedL1 == status.FinalizedL1 &&
+		last.UnsafeL2 == status.UnsafeL2 && last.SafeL2 == status.SafeL2 && last.FinalizedL2 == status.FinalizedL2 {
+		return
+	}
+	s.lastSyncStatus = status
+	s.syncStatusFeed.Send(status)
+}
+
+// SubscribeSyncStatus subscribes to sync status changes: a new status is sent whenever any of the L1 or L2 heads changes.
+// The driver event loop never blocks on subscribers: a subscriber that does not keep up skips intermediate statuses,
+// and receives the latest status once it reads from the channel again.
+func (s *Driver) SubscribeSyncStatus(ch chan<- *eth.SyncStatus) event.Subscription {
+	return s.syncStatusFeed.Subscribe(ch)
+}
+
 // SyncStatus blocks the driver event loop and captures the syncing status.
 // If the event loop is too busy and the context expires, a context error is returned.
 func (s *Driver) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
@@ -543,6 +760,12 @@ type hashAndErrorChannel struct {
 	err  chan error
 }
 
+type rewindRequest struct {
+	num        uint64
+	rewindSafe bool
+	err        chan error
+}
+
 // checkForGapInUnsafeQueue checks if there is a gap in the unsafe queue and attempts to retrieve the missing payloads from an alt-sync method.
 // WARNING: This is only an outgoing signal, the blocks are not guaranteed to be retrieved.
 // Results are received through OnUnsafeL2Payload.
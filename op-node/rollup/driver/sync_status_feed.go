@@ -0,0 +1,69 @@
+package driver
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// syncStatusFeed publishes sync status updates to subscribers without ever blocking the publisher.
+//
+// Unlike event.Feed, which waits for every subscriber to receive a value, every subscriber has its own
+// single-slot buffer that is overwritten with the latest status, and forwarded to the subscriber
+// by a dedicated goroutine. A slow subscriber thus skips intermediate statuses, and receives the latest status,
+// instead of stalling the driver event loop.
+type syncStatusFeed struct {
+	mu   sync.Mutex
+	subs map[chan *eth.SyncStatus]struct{}
+}
+
+// Send publishes the status to all subscribers, replacing any status they have not received yet.
+// Send must not be called concurrently, it is only called synchronously with the driver event loop.
+func (f *syncStatusFeed) Send(status *eth.SyncStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for latest := range f.subs {
+		// drop the previous status, if it was not forwarded yet
+		select {
+		case <-latest:
+		default:
+		}
+		// never blocks: Send is the only writer, and the slot was just emptied
+		select {
+		case latest <- status:
+		default:
+		}
+	}
+}
+
+// Subscribe forwards published statuses to ch, until the subscription is unsubscribed.
+func (f *syncStatusFeed) Subscribe(ch chan<- *eth.SyncStatus) event.Subscription {
+	latest := make(chan *eth.SyncStatus, 1)
+	f.mu.Lock()
+	if f.subs == nil {
+		f.subs = make(map[chan *eth.SyncStatus]struct{})
+	}
+	f.subs[latest] = struct{}{}
+	f.mu.Unlock()
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer func() {
+			f.mu.Lock()
+			delete(f.subs, latest)
+			f.mu.Unlock()
+		}()
+		for {
+			select {
+			case status := <-latest:
+				select {
+				case ch <- status:
+				case <-quit:
+					return nil
+				}
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
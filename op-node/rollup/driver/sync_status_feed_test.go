@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func TestSyncStatusFeedNonBlocking(t *testing.T) {
+	var feed syncStatusFeed
+	stuck := feed.Subscribe(make(chan *eth.SyncStatus)) // never read from
+	defer stuck.Unsubscribe()
+	ch := make(chan *eth.SyncStatus)
+	sub := feed.Subscribe(ch)
+	defer sub.Unsubscribe()
+
+	status := func(num uint64) *eth.SyncStatus {
+		return &eth.SyncStatus{UnsafeL2: eth.L2BlockRef{Number: num}}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := uint64(1); i <= 100; i++ {
+			feed.Send(status(i))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("publishing blocked on subscribers")
+	}
+
+	// the subscriber that was not reading receives the latest status,
+	// possibly after the one status that was already waiting to be forwarded.
+	var got *eth.SyncStatus
+	for got == nil || got.UnsafeL2.Number != 100 {
+		select {
+		case got = <-ch:
+		case <-time.After(10 * time.Second):
+			t.Fatal("expected latest status")
+		}
+	}
+
+	feed.Send(status(101))
+	select {
+	case got = <-ch:
+		require.Equal(t, uint64(101), got.UnsafeL2.Number)
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected status")
+	}
+
+	sub.Unsubscribe()
+	stuck.Unsubscribe()
+	require.Eventually(t, func() bool {
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		return len(feed.subs) == 0
+	}, 10*time.Second, 10*time.Millisecond, "unsubscribed subscribers are removed")
+}
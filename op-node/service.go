@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	"github.com/urfave/cli/v2"
@@ -16,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/node"
@@ -38,6 +40,13 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 	configPersistence := NewConfigPersistence(ctx)
 
+	sequencerLeadership := NewSequencerLeadership(ctx)
+
+	systemTxs, err := NewSystemTxSource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sequencer system tx source: %w", err)
+	}
+
 	driverConfig := NewDriverConfig(ctx)
 
 	p2pSignerSetup, err := p2pcli.LoadSignerSetup(ctx)
@@ -59,14 +68,20 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 	l2SyncEndpoint := NewL2SyncEndpointConfig(ctx)
 
+	l2ShadowEndpoint, err := NewL2ShadowEndpointConfig(ctx, l2Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load l2 shadow endpoint info: %w", err)
+	}
+
 	syncConfig := NewSyncConfig(ctx)
 
 	cfg := &node.Config{
-		L1:     l1Endpoint,
-		L2:     l2Endpoint,
-		L2Sync: l2SyncEndpoint,
-		Rollup: *rollupConfig,
-		Driver: *driverConfig,
+		L1:       l1Endpoint,
+		L2:       l2Endpoint,
+		L2Sync:   l2SyncEndpoint,
+		L2Shadow: l2ShadowEndpoint,
+		Rollup:   *rollupConfig,
+		Driver:   *driverConfig,
 		RPC: node.RPCConfig{
 			ListenAddr:  ctx.String(flags.RPCListenAddr.Name),
 			ListenPort:  ctx.Int(flags.RPCListenPort.Name),
@@ -85,13 +100,20 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		P2P:                 p2pConfig,
 		P2PSigner:           p2pSignerSetup,
 		L1EpochPollInterval: ctx.Duration(flags.L1EpochPollIntervalFlag.Name),
+		L1DiskCache: node.L1DiskCacheConfig{
+			Path:    ctx.String(flags.L1DiskCachePath.Name),
+			MaxSize: ctx.Uint64(flags.L1DiskCacheMaxSize.Name) * 1024 * 1024,
+		},
 		Heartbeat: node.HeartbeatConfig{
 			Enabled: ctx.Bool(flags.HeartbeatEnabledFlag.Name),
 			Moniker: ctx.String(flags.HeartbeatMonikerFlag.Name),
 			URL:     ctx.String(flags.HeartbeatURLFlag.Name),
 		},
-		ConfigPersistence: configPersistence,
-		Sync:              *syncConfig,
+		SafeDBPath:          ctx.String(flags.SafeDBPath.Name),
+		ConfigPersistence:   configPersistence,
+		SequencerLeadership: sequencerLeadership,
+		SequencerSystemTxs:  systemTxs,
+		Sync:                *syncConfig,
 	}
 
 	if err := cfg.LoadPersisted(log); err != nil {
@@ -106,12 +128,16 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 func NewL1EndpointConfig(ctx *cli.Context) *node.L1EndpointConfig {
 	return &node.L1EndpointConfig{
-		L1NodeAddr:       ctx.String(flags.L1NodeAddr.Name),
-		L1TrustRPC:       ctx.Bool(flags.L1TrustRPC.Name),
-		L1RPCKind:        sources.RPCProviderKind(strings.ToLower(ctx.String(flags.L1RPCProviderKind.Name))),
-		RateLimit:        ctx.Float64(flags.L1RPCRateLimit.Name),
-		BatchSize:        ctx.Int(flags.L1RPCMaxBatchSize.Name),
-		HttpPollInterval: ctx.Duration(flags.L1HTTPPollInterval.Name),
+		L1NodeAddr:            ctx.String(flags.L1NodeAddr.Name),
+		L1ExtraNodeAddrs:      ctx.StringSlice(flags.L1ExtraNodeAddrs.Name),
+		L1Quorum:              ctx.Int(flags.L1RPCQuorum.Name),
+		L1HealthCheckInterval: ctx.Duration(flags.L1RPCHealthCheckInterval.Name),
+		L1MaxHeadLag:          ctx.Uint64(flags.L1RPCMaxHeadLag.Name),
+		L1TrustRPC:            ctx.Bool(flags.L1TrustRPC.Name),
+		L1RPCKind:             sources.RPCProviderKind(strings.ToLower(ctx.String(flags.L1RPCProviderKind.Name))),
+		RateLimit:             ctx.Float64(flags.L1RPCRateLimit.Name),
+		BatchSize:             ctx.Int(flags.L1RPCMaxBatchSize.Name),
+		HttpPollInterval:      ctx.Duration(flags.L1HTTPPollInterval.Name),
 	}
 }
 
@@ -145,6 +171,31 @@ func NewL2EndpointConfig(ctx *cli.Context, log log.Logger) (*node.L2EndpointConf
 	}, nil
 }
 
+// NewL2ShadowEndpointConfig returns the config of the shadow L2 execution engine,
+// or nil if the shadow engine is not enabled.
+func NewL2ShadowEndpointConfig(ctx *cli.Context, l2Endpoint *node.L2EndpointConfig) (node.L2EndpointSetup, error) {
+	l2Addr := ctx.String(flags.L2ShadowEngineAddr.Name)
+	if l2Addr == "" {
+		return nil, nil
+	}
+	secret := l2Endpoint.L2EngineJWTSecret
+	if fileName := strings.TrimSpace(ctx.String(flags.L2ShadowEngineJWTSecret.Name)); fileName != "" {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read shadow engine jwt secret: %w", err)
+		}
+		jwtSecret := common.FromHex(strings.TrimSpace(string(data)))
+		if len(jwtSecret) != 32 {
+			return nil, fmt.Errorf("invalid jwt secret in path %s, not 32 hex-formatted bytes", fileName)
+		}
+		copy(secret[:], jwtSecret)
+	}
+	return &node.L2EndpointConfig{
+		L2EngineAddr:      l2Addr,
+		L2EngineJWTSecret: secret,
+	}, nil
+}
+
 // NewL2SyncEndpointConfig returns a pointer to a L2SyncEndpointConfig if the
 // flag is set, otherwise nil.
 func NewL2SyncEndpointConfig(ctx *cli.Context) *node.L2SyncEndpointConfig {
@@ -162,13 +213,39 @@ func NewConfigPersistence(ctx *cli.Context) node.ConfigPersistence {
 	return node.NewConfigPersistence(stateFile)
 }
 
+func NewSequencerLeadership(ctx *cli.Context) driver.SequencerLeadership {
+	lockFile := ctx.String(flags.SequencerLeadershipLockFlag.Name)
+	if lockFile == "" {
+		return nil
+	}
+	return node.NewFileLeadership(lockFile)
+}
+
+// NewSystemTxSource creates the source of the transactions that the sequencer injects into new blocks.
+// The RPC connection is not established until the first block is built.
+func NewSystemTxSource(ctx *cli.Context) (driver.SystemTxSource, error) {
+	addr := ctx.String(flags.SequencerSystemTxRPCFlag.Name)
+	if addr == "" {
+		return nil, nil
+	}
+	rpcClient, err := gethrpc.DialContext(ctx.Context, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial system tx RPC %q: %w", addr, err)
+	}
+	return sources.NewSystemTxClient(client.NewBaseRPCClient(rpcClient)), nil
+}
+
 func NewDriverConfig(ctx *cli.Context) *driver.Config {
 	return &driver.Config{
-		VerifierConfDepth:   ctx.Uint64(flags.VerifierL1Confs.Name),
-		SequencerConfDepth:  ctx.Uint64(flags.SequencerL1Confs.Name),
-		SequencerEnabled:    ctx.Bool(flags.SequencerEnabledFlag.Name),
-		SequencerStopped:    ctx.Bool(flags.SequencerStoppedFlag.Name),
-		SequencerMaxSafeLag: ctx.Uint64(flags.SequencerMaxSafeLagFlag.Name),
+		VerifierConfDepth:        ctx.Uint64(flags.VerifierL1Confs.Name),
+		SequencerConfDepth:       ctx.Uint64(flags.SequencerL1Confs.Name),
+		SequencerEnabled:         ctx.Bool(flags.SequencerEnabledFlag.Name),
+		SequencerStopped:         ctx.Bool(flags.SequencerStoppedFlag.Name),
+		SequencerMaxSafeLag:      ctx.Uint64(flags.SequencerMaxSafeLagFlag.Name),
+		SequencerMaxOriginLag:    ctx.Uint64(flags.SequencerMaxOriginLagFlag.Name),
+		SequencerGasTarget:       ctx.Uint64(flags.SequencerGasTargetFlag.Name),
+		SequencerOriginSelection: driver.OriginSelectionKind(strings.ToLower(ctx.String(flags.SequencerOriginSelectionFlag.Name))),
+		SequencerTargetDrift:     ctx.Uint64(flags.SequencerTargetDriftFlag.Name),
 	}
 }
 
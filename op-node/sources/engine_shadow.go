@@ -0,0 +1,218 @@
+package sources
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Results of mirrored engine API calls, as recorded in the shadow engine metrics.
+const (
+	ShadowMatch      = "match"
+	ShadowSyncing    = "syncing"
+	ShadowDivergence = "divergence"
+	ShadowError      = "error"
+	ShadowDropped    = "dropped"
+)
+
+// shadowQueueSize is the number of engine API calls that may be queued for the shadow engine,
+// before calls are dropped to not hold back the primary engine.
+const shadowQueueSize = 256
+
+type ShadowEngineMetrics interface {
+	RecordShadowEngineRequest(method string, result string)
+}
+
+// ShadowEngine is the subset of the EngineClient that is mirrored to the shadow execution engine.
+type ShadowEngine interface {
+	ForkchoiceUpdate(ctx context.Context, fc *eth.ForkchoiceState, attributes *eth.PayloadAttributes) (*eth.ForkchoiceUpdatedResult, error)
+	NewPayload(ctx context.Context, payload *eth.ExecutionPayload) (*eth.PayloadStatusV1, error)
+	GetPayload(ctx context.Context, payloadId eth.PayloadID) (*eth.ExecutionPayload, error)
+	Close()
+}
+
+// ShadowEngineClient is an EngineClient that mirrors the engine API calls to a second execution engine,
+// and reports any divergence of the shadow engine in payload status or block hash.
+// The primary engine stays authoritative: the shadow engine is called asynchronously,
+// and its results and failures never affect the results returned to the node.
+//
+// Blocks are only built on the shadow engine if the payload attributes exclude the tx-pool,
+// so the built block is deterministic and can be compared to that of the primary engine.
+type ShadowEngineClient struct {
+	*EngineClient
+
+	shadow  ShadowEngine
+	log     log.Logger
+	metrics ShadowEngineMetrics
+
+	queue chan func(ctx context.Context)
+
+	// payloadIDs maps the payload IDs of the primary engine to those of blocks built by the shadow engine.
+	// The node builds a single block at a time, so at most the latest block is tracked.
+	// Only accessed by the shadow worker.
+	payloadIDs map[eth.PayloadID]eth.PayloadID
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewShadowEngineClient(primary *EngineClient, shadow ShadowEngine, log log.Logger, metrics ShadowEngineMetrics) *ShadowEngineClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &ShadowEngineClient{
+		EngineClient: primary,
+		shadow:       shadow,
+		log:          log,
+		metrics:      metrics,
+		queue:        make(chan func(ctx context.Context), shadowQueueSize),
+		payloadIDs:   make(map[eth.PayloadID]eth.PayloadID),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	s.wg.Add(1)
+	go s.worker()
+	return s
+}
+
+func (s *ShadowEngineClient) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case fn := <-s.queue:
+			fn(s.ctx)
+		}
+	}
+}
+
+// mirror queues the call for the shadow engine, and drops it if the shadow engine is falling behind.
+func (s *ShadowEngineClient) mirror(method string, fn func(ctx context.Context)) {
+	select {
+	case s.queue <- fn:
+	default:
+		s.log.Warn("Shadow engine is falling behind, dropping call", "method", method)
+		s.metrics.RecordShadowEngineRequest(method, ShadowDropped)
+	}
+}
+
+func (s *ShadowEngineClient) ForkchoiceUpdate(ctx context.Context, fc *eth.ForkchoiceState, attributes *eth.PayloadAttributes) (*eth.ForkchoiceUpdatedResult, error) {
+	res, err := s.EngineClient.ForkchoiceUpdate(ctx, fc, attributes)
+	if err != nil {
+		return res, err
+	}
+	fcCopy := *fc
+	var shadowAttrs *eth.PayloadAttributes
+	if attributes != nil && attributes.NoTxPool {
+		shadowAttrs = attributes
+	}
+	primaryStatus := res.PayloadStatus
+	primaryID := res.PayloadID
+	s.mirror("engine_forkchoiceUpdated", func(ctx context.Context) {
+		if attributes != nil {
+			// A new block replaces any earlier block that was abandoned, or that failed to be retrieved.
+			s.forgetPayloads()
+		}
+		shadowRes, err := s.shadow.ForkchoiceUpdate(ctx, &fcCopy, shadowAttrs)
+		if err != nil {
+			s.log.Warn("Shadow engine failed forkchoice update", "state", &fcCopy, "err", err)
+			s.metrics.RecordShadowEngineRequest("engine_forkchoiceUpdated", ShadowError)
+			return
+		}
+		if shadowAttrs != nil && primaryID != nil && shadowRes.PayloadID != nil {
+			s.payloadIDs[*primaryID] = *shadowRes.PayloadID
+		}
+		s.compareStatus("engine_forkchoiceUpdated", fcCopy.HeadBlockHash.String(), primaryStatus, shadowRes.PayloadStatus)
+	})
+	return res, nil
+}
+
+func (s *ShadowEngineClient) NewPayload(ctx context.Context, payload *eth.ExecutionPayload) (*eth.PayloadStatusV1, error) {
+	res, err := s.EngineClient.NewPayload(ctx, payload)
+	if err != nil {
+		return res, err
+	}
+	primaryStatus := *res
+	s.mirror("engine_newPayload", func(ctx context.Context) {
+		shadowRes, err := s.shadow.NewPayload(ctx, payload)
+		if err != nil {
+			s.log.Warn("Shadow engine failed to process payload", "block", payload.ID(), "err", err)
+			s.metrics.RecordShadowEngineRequest("engine_newPayload", ShadowError)
+			return
+		}
+		s.compareStatus("engine_newPayload", payload.ID().String(), primaryStatus, *shadowRes)
+	})
+	return res, nil
+}
+
+func (s *ShadowEngineClient) GetPayload(ctx context.Context, payloadId eth.PayloadID) (*eth.ExecutionPayload, error) {
+	res, err := s.EngineClient.GetPayload(ctx, payloadId)
+	if err != nil {
+		s.mirror("engine_getPayload", func(ctx context.Context) {
+			delete(s.payloadIDs, payloadId)
+		})
+		return res, err
+	}
+	primaryBlock := res.ID()
+	s.mirror("engine_getPayload", func(ctx context.Context) {
+		shadowID, ok := s.payloadIDs[payloadId]
+		if !ok { // the shadow engine did not build this block
+			return
+		}
+		delete(s.payloadIDs, payloadId)
+		shadowRes, err := s.shadow.GetPayload(ctx, shadowID)
+		if err != nil {
+			s.log.Warn("Shadow engine failed to get payload", "payload_id", shadowID, "err", err)
+			s.metrics.RecordShadowEngineRequest("engine_getPayload", ShadowError)
+			return
+		}
+		if shadowRes.BlockHash != primaryBlock.Hash {
+			s.log.Error("Shadow engine built a different block", "primary", primaryBlock, "shadow", shadowRes.ID())
+			s.metrics.RecordShadowEngineRequest("engine_getPayload", ShadowDivergence)
+			return
+		}
+		s.metrics.RecordShadowEngineRequest("engine_getPayload", ShadowMatch)
+	})
+	return res, nil
+}
+
+// forgetPayloads removes the payload IDs of all blocks built by the shadow engine.
+// Only called by the shadow worker.
+func (s *ShadowEngineClient) forgetPayloads() {
+	for id := range s.payloadIDs {
+		delete(s.payloadIDs, id)
+	}
+}
+
+// compareStatus reports divergence of the payload status of the shadow engine.
+// A syncing shadow engine is not a divergence, it cannot validate the block yet.
+func (s *ShadowEngineClient) compareStatus(method string, block string, primary eth.PayloadStatusV1, shadow eth.PayloadStatusV1) {
+	if shadow.Status == eth.ExecutionSyncing || shadow.Status == eth.ExecutionAccepted {
+		s.log.Debug("Shadow engine is syncing", "method", method, "block", block, "status", shadow.Status)
+		s.metrics.RecordShadowEngineRequest(method, ShadowSyncing)
+		return
+	}
+	if primary.Status != shadow.Status {
+		s.log.Error("Shadow engine diverged in payload status", "method", method, "block", block,
+			"primary", primary.Status, "shadow", shadow.Status, "shadow_err", shadow.ValidationError)
+		s.metrics.RecordShadowEngineRequest(method, ShadowDivergence)
+		return
+	}
+	if primary.LatestValidHash != nil && shadow.LatestValidHash != nil && *primary.LatestValidHash != *shadow.LatestValidHash {
+		s.log.Error("Shadow engine diverged in latest valid hash", "method", method, "block", block,
+			"primary", *primary.LatestValidHash, "shadow", *shadow.LatestValidHash)
+		s.metrics.RecordShadowEngineRequest(method, ShadowDivergence)
+		return
+	}
+	s.metrics.RecordShadowEngineRequest(method, ShadowMatch)
+}
+
+// Close stops mirroring calls, and closes the shadow engine. The primary EngineClient is not closed.
+func (s *ShadowEngineClient) Close() {
+	s.cancel()
+	s.wg.Wait()
+	s.shadow.Close()
+}
@@ -0,0 +1,172 @@
+package sources
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// fakeEngineAPI serves the engine API calls of the EngineClient, with configurable results.
+type fakeEngineAPI struct {
+	status    eth.ExecutePayloadStatus
+	blockHash common.Hash
+	calls     chan string
+}
+
+func (api *fakeEngineAPI) ForkchoiceUpdatedV1(fc eth.ForkchoiceState, attrs *eth.PayloadAttributes) (*eth.ForkchoiceUpdatedResult, error) {
+	api.calls <- "engine_forkchoiceUpdatedV1"
+	res := &eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: api.status}}
+	if attrs != nil {
+		res.PayloadID = &eth.PayloadID{byte(attrs.Timestamp)}
+	}
+	return res, nil
+}
+
+func (api *fakeEngineAPI) NewPayloadV1(payload *eth.ExecutionPayload) (*eth.PayloadStatusV1, error) {
+	api.calls <- "engine_newPayloadV1"
+	return &eth.PayloadStatusV1{Status: api.status}, nil
+}
+
+func (api *fakeEngineAPI) GetPayloadV1(id eth.PayloadID) (*eth.ExecutionPayload, error) {
+	api.calls <- "engine_getPayloadV1"
+	return &eth.ExecutionPayload{BlockHash: api.blockHash}, nil
+}
+
+func newFakeEngineClient(t *testing.T, api *fakeEngineAPI) *EngineClient {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("engine", api))
+	t.Cleanup(srv.Stop)
+	cfg := EngineClientDefaultConfig(&rollup.Config{SeqWindowSize: 10, BlockTime: 2})
+	cl, err := NewEngineClient(client.NewBaseRPCClient(rpc.DialInProc(srv)), testlog.Logger(t, log.LvlCrit), nil, cfg)
+	require.NoError(t, err)
+	return cl
+}
+
+type shadowMetricResult struct {
+	method string
+	result string
+}
+
+type testShadowMetrics struct {
+	mu      sync.Mutex
+	results []shadowMetricResult
+}
+
+func (m *testShadowMetrics) RecordShadowEngineRequest(method string, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, shadowMetricResult{method, result})
+}
+
+func (m *testShadowMetrics) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = nil
+}
+
+func (m *testShadowMetrics) waitFor(t *testing.T, n int) []shadowMetricResult {
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.results) >= n
+	}, 5*time.Second, 10*time.Millisecond)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]shadowMetricResult(nil), m.results...)
+}
+
+func TestShadowEngineClient(t *testing.T) {
+	ctx := context.Background()
+	primaryAPI := &fakeEngineAPI{status: eth.ExecutionValid, blockHash: common.Hash{1}, calls: make(chan string, 100)}
+	shadowAPI := &fakeEngineAPI{status: eth.ExecutionValid, blockHash: common.Hash{1}, calls: make(chan string, 100)}
+	m := &testShadowMetrics{}
+	cl := NewShadowEngineClient(newFakeEngineClient(t, primaryAPI), newFakeEngineClient(t, shadowAPI), testlog.Logger(t, log.LvlCrit), m)
+	defer cl.Close()
+
+	fc := &eth.ForkchoiceState{HeadBlockHash: common.Hash{0xaa}}
+	res, err := cl.ForkchoiceUpdate(ctx, fc, &eth.PayloadAttributes{Timestamp: 1, NoTxPool: true})
+	require.NoError(t, err)
+	payload, err := cl.GetPayload(ctx, *res.PayloadID)
+	require.NoError(t, err)
+	_, err = cl.NewPayload(ctx, payload)
+	require.NoError(t, err)
+	require.Equal(t, []shadowMetricResult{
+		{"engine_forkchoiceUpdated", ShadowMatch},
+		{"engine_getPayload", ShadowMatch},
+		{"engine_newPayload", ShadowMatch},
+	}, m.waitFor(t, 3))
+
+	t.Run("divergence", func(t *testing.T) {
+		m.reset()
+		shadowAPI.status = eth.ExecutionInvalid
+		shadowAPI.blockHash = common.Hash{2}
+		res, err := cl.ForkchoiceUpdate(ctx, fc, &eth.PayloadAttributes{Timestamp: 2, NoTxPool: true})
+		require.NoError(t, err)
+		require.Equal(t, eth.ExecutionValid, res.PayloadStatus.Status, "primary engine is authoritative")
+		payload, err := cl.GetPayload(ctx, *res.PayloadID)
+		require.NoError(t, err)
+		require.Equal(t, common.Hash{1}, payload.BlockHash, "primary engine is authoritative")
+		status, err := cl.NewPayload(ctx, payload)
+		require.NoError(t, err)
+		require.Equal(t, eth.ExecutionValid, status.Status, "primary engine is authoritative")
+		require.Equal(t, []shadowMetricResult{
+			{"engine_forkchoiceUpdated", ShadowDivergence},
+			{"engine_getPayload", ShadowDivergence},
+			{"engine_newPayload", ShadowDivergence},
+		}, m.waitFor(t, 3))
+	})
+
+	t.Run("syncing", func(t *testing.T) {
+		m.reset()
+		shadowAPI.status = eth.ExecutionSyncing
+		_, err := cl.NewPayload(ctx, payload)
+		require.NoError(t, err)
+		require.Equal(t, []shadowMetricResult{{"engine_newPayload", ShadowSyncing}}, m.waitFor(t, 1))
+	})
+
+	t.Run("no block building with tx-pool", func(t *testing.T) {
+		m.reset()
+		shadowAPI.status = eth.ExecutionValid
+		for len(shadowAPI.calls) > 0 {
+			<-shadowAPI.calls
+		}
+		res, err := cl.ForkchoiceUpdate(ctx, fc, &eth.PayloadAttributes{Timestamp: 3})
+		require.NoError(t, err)
+		_, err = cl.GetPayload(ctx, *res.PayloadID)
+		require.NoError(t, err)
+		require.Equal(t, []shadowMetricResult{{"engine_forkchoiceUpdated", ShadowMatch}}, m.waitFor(t, 1))
+		require.Equal(t, "engine_forkchoiceUpdatedV1", <-shadowAPI.calls)
+		require.Empty(t, shadowAPI.calls, "shadow engine does not build the block")
+	})
+
+	t.Run("abandoned block", func(t *testing.T) {
+		m.reset()
+		_, err := cl.ForkchoiceUpdate(ctx, fc, &eth.PayloadAttributes{Timestamp: 4, NoTxPool: true})
+		require.NoError(t, err)
+		res, err := cl.ForkchoiceUpdate(ctx, fc, &eth.PayloadAttributes{Timestamp: 5, NoTxPool: true})
+		require.NoError(t, err)
+		m.waitFor(t, 2)
+		tracked := make(chan map[eth.PayloadID]eth.PayloadID)
+		cl.mirror("test", func(ctx context.Context) {
+			ids := make(map[eth.PayloadID]eth.PayloadID)
+			for k, v := range cl.payloadIDs {
+				ids[k] = v
+			}
+			tracked <- ids
+		})
+		ids := <-tracked
+		require.Len(t, ids, 1, "the abandoned block is no longer tracked")
+		require.Contains(t, ids, *res.PayloadID)
+	})
+}
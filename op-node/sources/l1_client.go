@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
@@ -20,6 +21,10 @@ type L1ClientConfig struct {
 	EthClientConfig
 
 	L1BlockRefsCacheSize int
+
+	// DiskCache persists L1 headers, transactions and receipts, fetched by block hash, across restarts.
+	// Optional, may be nil.
+	DiskCache *L1DiskCache
 }
 
 func L1ClientDefaultConfig(config *rollup.Config, trustRPC bool, kind RPCProviderKind) *L1ClientConfig {
@@ -57,6 +62,9 @@ type L1Client struct {
 	// cache L1BlockRef by hash
 	// common.Hash -> eth.L1BlockRef
 	l1BlockRefsCache *caching.LRUCache[common.Hash, eth.L1BlockRef]
+
+	// optional persistent cache of L1 data by block hash, consulted before the RPC
+	diskCache *L1DiskCache
 }
 
 // NewL1Client wraps a RPC with bindings to fetch L1 data, while logging errors, tracking metrics (optional), and caching.
@@ -69,6 +77,7 @@ func NewL1Client(client client.RPC, log log.Logger, metrics caching.Metrics, con
 	return &L1Client{
 		EthClient:        ethClient,
 		l1BlockRefsCache: caching.NewLRUCache[common.Hash, eth.L1BlockRef](metrics, "blockrefs", config.L1BlockRefsCacheSize),
+		diskCache:        config.DiskCache,
 	}, nil
 }
 
@@ -115,3 +124,77 @@ func (s *L1Client) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.
 	s.l1BlockRefsCache.Add(ref.Hash, ref)
 	return ref, nil
 }
+
+// InfoByHash returns the header of the given block, from the disk cache if available.
+func (s *L1Client) InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	if s.diskCache == nil {
+		return s.EthClient.InfoByHash(ctx, hash)
+	}
+	if info, ok := s.diskCache.Header(hash); ok {
+		return info, nil
+	}
+	info, err := s.EthClient.InfoByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	s.diskCache.AddHeader(info)
+	return info, nil
+}
+
+// InfoAndTxsByHash returns the header and transactions of the given block, from the disk cache if available.
+func (s *L1Client) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	if s.diskCache == nil {
+		return s.EthClient.InfoAndTxsByHash(ctx, hash)
+	}
+	if info, ok := s.diskCache.Header(hash); ok {
+		if txs, ok := s.diskCache.Transactions(hash); ok {
+			return info, txs, nil
+		}
+	}
+	info, txs, err := s.EthClient.InfoAndTxsByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.diskCache.AddHeader(info)
+	s.diskCache.AddTransactions(eth.ToBlockID(info), txs)
+	return info, txs, nil
+}
+
+// FetchReceipts returns the header and receipts of the given block, from the disk cache if available.
+func (s *L1Client) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	if s.diskCache == nil {
+		return s.EthClient.FetchReceipts(ctx, blockHash)
+	}
+	if info, ok := s.diskCache.Header(blockHash); ok {
+		if receipts, ok := s.diskCache.Receipts(blockHash); ok {
+			return info, receipts, nil
+		}
+	}
+	// The transactions are cached along with the receipts, to verify the cached receipts against.
+	if _, _, err := s.InfoAndTxsByHash(ctx, blockHash); err != nil {
+		return nil, nil, err
+	}
+	info, receipts, err := s.EthClient.FetchReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.diskCache.AddHeader(info)
+	s.diskCache.AddReceipts(eth.ToBlockID(info), receipts)
+	return info, receipts, nil
+}
+
+// InvalidateReorged removes the L1 blocks that are not part of the chain of the new L1 head from the disk cache, if any.
+func (s *L1Client) InvalidateReorged(head eth.L1BlockRef) {
+	if s.diskCache != nil {
+		s.diskCache.InvalidateReorged(head)
+	}
+}
+
+func (s *L1Client) Close() {
+	s.EthClient.Close()
+	if s.diskCache != nil {
+		if err := s.diskCache.Close(); err != nil {
+			s.log.Warn("Failed to close L1 disk cache", "err", err)
+		}
+	}
+}
@@ -0,0 +1,461 @@
+package sources
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+
+	"github.com/ethereum-optimism/optimism/op-node/sources/caching"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+const (
+	l1CacheHeaderPrefix   = "/l1cache/header/"
+	l1CacheTxsPrefix      = "/l1cache/txs/"
+	l1CacheReceiptsPrefix = "/l1cache/receipts/"
+	// The index maps block numbers to the hashes of the cached blocks at that height,
+	// with the total size of the cached data of the block as value.
+	// Numbers are zero-padded, so the index is ordered by number.
+	l1CacheIndexPrefix = "/l1cache/index/"
+)
+
+// L1DiskCache is a persistent cache of L1 headers, transactions and receipts, keyed by block hash.
+// The data of a block hash never changes, so the cache can be shared across restarts and pipeline resets.
+// Blocks that are reorged out of the canonical chain are invalidated with InvalidateReorged,
+// and the oldest blocks are pruned when the total size of the cached data exceeds the max size.
+//
+// The cache is best-effort: storage errors are logged, and treated as cache misses.
+type L1DiskCache struct {
+	log     log.Logger
+	store   ds.Batching
+	metrics caching.Metrics
+	maxSize uint64
+
+	mu     sync.Mutex
+	size   uint64
+	blocks int
+	// head is the L1 head of the last InvalidateReorged call
+	head eth.BlockID
+}
+
+// NewL1DiskCache creates a L1DiskCache on top of the given store, and loads the size of the existing cached data.
+// Metrics are optional: no metrics will be tracked if metrics == nil.
+func NewL1DiskCache(log log.Logger, store ds.Batching, maxSize uint64, metrics caching.Metrics) (*L1DiskCache, error) {
+	c := &L1DiskCache{
+		log:     log,
+		store:   store,
+		metrics: metrics,
+		maxSize: maxSize,
+	}
+	results, err := store.Query(context.Background(), query.Query{Prefix: l1CacheIndexPrefix})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query L1 cache index: %w", err)
+	}
+	defer results.Close()
+	for res := range results.Next() {
+		if res.Error != nil {
+			return nil, fmt.Errorf("failed to read L1 cache index: %w", res.Error)
+		}
+		c.size += decodeSize(res.Value)
+		c.blocks += 1
+	}
+	log.Info("Loaded L1 disk cache", "blocks", c.blocks, "size", c.size, "max_size", maxSize)
+	return c, nil
+}
+
+func l1CacheIndexKey(num uint64, hash common.Hash) ds.Key {
+	return ds.NewKey(fmt.Sprintf("%s%016x/%s", l1CacheIndexPrefix, num, hash))
+}
+
+func l1CacheIndexNumberPrefix(num uint64) string {
+	return fmt.Sprintf("%s%016x/", l1CacheIndexPrefix, num)
+}
+
+func l1CacheDataKey(prefix string, hash common.Hash) ds.Key {
+	return ds.NewKey(prefix + hash.String())
+}
+
+func decodeSize(v []byte) uint64 {
+	if len(v) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+func encodeSize(size uint64) []byte {
+	var out [8]byte
+	binary.BigEndian.PutUint64(out[:], size)
+	return out[:]
+}
+
+// Header returns the cached header of the given block, if any.
+// The header is verified against the block hash, to not serve corrupted data.
+func (c *L1DiskCache) Header(hash common.Hash) (eth.BlockInfo, bool) {
+	info, ok := c.header(hash)
+	if c.metrics != nil {
+		c.metrics.CacheGet("disk_headers", ok)
+	}
+	return info, ok
+}
+
+func (c *L1DiskCache) header(hash common.Hash) (*headerInfo, bool) {
+	data, ok := c.get(l1CacheDataKey(l1CacheHeaderPrefix, hash))
+	if !ok {
+		return nil, false
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(data, &header); err != nil {
+		c.log.Warn("Invalid header in L1 disk cache", "hash", hash, "err", err)
+		return nil, false
+	}
+	if computed := header.Hash(); computed != hash {
+		c.log.Warn("Header in L1 disk cache does not match block hash", "hash", hash, "computed", computed)
+		return nil, false
+	}
+	return &headerInfo{hash: hash, Header: &header}, true
+}
+
+// Transactions returns the cached transactions of the given block, if any.
+// The transactions are verified against the transactions root of the cached header, like the RPC path does,
+// and the block is evicted from the cache if they do not match, to not serve corrupted data.
+func (c *L1DiskCache) Transactions(hash common.Hash) (types.Transactions, bool) {
+	txs, ok := c.transactions(hash)
+	if c.metrics != nil {
+		c.metrics.CacheGet("disk_txs", ok)
+	}
+	return txs, ok
+}
+
+func (c *L1DiskCache) transactions(hash common.Hash) (types.Transactions, bool) {
+	data, ok := c.get(l1CacheDataKey(l1CacheTxsPrefix, hash))
+	if !ok {
+		return nil, false
+	}
+	header, ok := c.header(hash)
+	if !ok {
+		return nil, false
+	}
+	id := eth.ToBlockID(header)
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(data, &txs); err != nil {
+		c.log.Warn("Invalid transactions in L1 disk cache, evicting block", "block", id, "err", err)
+		c.evict(id)
+		return nil, false
+	}
+	if computed := types.DeriveSha(txs, trie.NewStackTrie(nil)); computed != header.Header.TxHash {
+		c.log.Warn("Transactions in L1 disk cache do not match transactions root, evicting block",
+			"block", id, "tx_root", header.Header.TxHash, "computed", computed)
+		c.evict(id)
+		return nil, false
+	}
+	return txs, true
+}
+
+// Receipts returns the cached receipts of the given block, if any.
+// The receipts are verified against the receipts root of the cached header and the cached transactions,
+// like the RPC path does, and the block is evicted from the cache if they do not match, to not serve corrupted data.
+// Receipts are a cache miss if the transactions of the block are not cached.
+func (c *L1DiskCache) Receipts(hash common.Hash) (types.Receipts, bool) {
+	receipts, ok := c.receipts(hash)
+	if c.metrics != nil {
+		c.metrics.CacheGet("disk_receipts", ok)
+	}
+	return receipts, ok
+}
+
+func (c *L1DiskCache) receipts(hash common.Hash) (types.Receipts, bool) {
+	data, ok := c.get(l1CacheDataKey(l1CacheReceiptsPrefix, hash))
+	if !ok {
+		return nil, false
+	}
+	header, ok := c.header(hash)
+	if !ok {
+		return nil, false
+	}
+	id := eth.ToBlockID(header)
+	var receipts types.Receipts
+	// Receipts are stored as JSON, unlike the consensus encoding this includes the derived fields.
+	if err := json.Unmarshal(data, &receipts); err != nil {
+		c.log.Warn("Invalid receipts in L1 disk cache, evicting block", "block", id, "err", err)
+		c.evict(id)
+		return nil, false
+	}
+	// The tx hashes are not part of the receipts root, and are verified against the cached transactions instead.
+	txs, ok := c.transactions(hash)
+	if !ok {
+		return nil, false
+	}
+	if err := validateReceipts(id, header.Header.ReceiptHash, eth.TransactionsToHashes(txs), receipts); err != nil {
+		c.log.Warn("Receipts in L1 disk cache do not match receipts root, evicting block", "block", id, "err", err)
+		c.evict(id)
+		return nil, false
+	}
+	return receipts, true
+}
+
+func (c *L1DiskCache) get(key ds.Key) ([]byte, bool) {
+	data, err := c.store.Get(context.Background(), key)
+	if err == ds.ErrNotFound {
+		return nil, false
+	} else if err != nil {
+		c.log.Warn("Failed to read from L1 disk cache", "key", key, "err", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// AddHeader stores the header of the given block.
+func (c *L1DiskCache) AddHeader(info eth.BlockInfo) {
+	data, err := info.HeaderRLP()
+	if err != nil {
+		c.log.Warn("Failed to encode header for L1 disk cache", "hash", info.Hash(), "err", err)
+		return
+	}
+	c.put(eth.ToBlockID(info), l1CacheHeaderPrefix, data)
+}
+
+// AddTransactions stores the transactions of the given block.
+func (c *L1DiskCache) AddTransactions(id eth.BlockID, txs types.Transactions) {
+	data, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		c.log.Warn("Failed to encode transactions for L1 disk cache", "block", id, "err", err)
+		return
+	}
+	c.put(id, l1CacheTxsPrefix, data)
+}
+
+// AddReceipts stores the receipts of the given block.
+func (c *L1DiskCache) AddReceipts(id eth.BlockID, receipts types.Receipts) {
+	data, err := json.Marshal(receipts)
+	if err != nil {
+		c.log.Warn("Failed to encode receipts for L1 disk cache", "block", id, "err", err)
+		return
+	}
+	c.put(id, l1CacheReceiptsPrefix, data)
+}
+
+func (c *L1DiskCache) put(id eth.BlockID, prefix string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ctx := context.Background()
+	dataKey := l1CacheDataKey(prefix, id.Hash)
+	if ok, err := c.store.Has(ctx, dataKey); err != nil {
+		c.log.Warn("Failed to read from L1 disk cache", "key", dataKey, "err", err)
+		return
+	} else if ok {
+		return // the data of a block hash never changes
+	}
+	indexKey := l1CacheIndexKey(id.Number, id.Hash)
+	indexValue, ok := c.get(indexKey)
+	blockSize := decodeSize(indexValue) + uint64(len(data))
+
+	b, err := c.store.Batch(ctx)
+	if err != nil {
+		c.log.Warn("Failed to write to L1 disk cache", "block", id, "err", err)
+		return
+	}
+	if err := b.Put(ctx, dataKey, data); err != nil {
+		c.log.Warn("Failed to write to L1 disk cache", "block", id, "err", err)
+		return
+	}
+	if err := b.Put(ctx, indexKey, encodeSize(blockSize)); err != nil {
+		c.log.Warn("Failed to write to L1 disk cache", "block", id, "err", err)
+		return
+	}
+	if err := b.Commit(ctx); err != nil {
+		c.log.Warn("Failed to write to L1 disk cache", "block", id, "err", err)
+		return
+	}
+	c.size += uint64(len(data))
+	if !ok {
+		c.blocks += 1
+	}
+	evicted := c.prune()
+	if c.metrics != nil {
+		c.metrics.CacheAdd("disk", c.blocks, evicted)
+	}
+}
+
+// prune removes the oldest blocks until the size of the cached data is within the max size.
+// The caller must hold the lock.
+func (c *L1DiskCache) prune() (evicted bool) {
+	if c.size <= c.maxSize {
+		return false
+	}
+	results, err := c.store.Query(context.Background(), query.Query{
+		Prefix: l1CacheIndexPrefix,
+		Orders: []query.Order{query.OrderByKey{}},
+	})
+	if err != nil {
+		c.log.Warn("Failed to query L1 disk cache index", "err", err)
+		return false
+	}
+	var oldest []eth.BlockID
+	var freed uint64
+	for res := range results.Next() {
+		if res.Error != nil {
+			c.log.Warn("Failed to read L1 disk cache index", "err", res.Error)
+			break
+		}
+		id, err := parseL1CacheIndexKey(res.Key)
+		if err != nil {
+			c.log.Warn("Invalid L1 disk cache index entry", "key", res.Key, "err", err)
+			continue
+		}
+		oldest = append(oldest, id)
+		freed += decodeSize(res.Value)
+		if c.size-freed <= c.maxSize {
+			break
+		}
+	}
+	results.Close()
+	for _, id := range oldest {
+		c.remove(id)
+	}
+	return len(oldest) > 0
+}
+
+func parseL1CacheIndexKey(key string) (eth.BlockID, error) {
+	parts := strings.Split(strings.TrimPrefix(key, l1CacheIndexPrefix), "/")
+	if len(parts) != 2 {
+		return eth.BlockID{}, fmt.Errorf("unexpected key format: %q", key)
+	}
+	var num uint64
+	if _, err := fmt.Sscanf(parts[0], "%016x", &num); err != nil {
+		return eth.BlockID{}, fmt.Errorf("invalid block number: %w", err)
+	}
+	var hash common.Hash
+	if err := hash.UnmarshalText([]byte(parts[1])); err != nil {
+		return eth.BlockID{}, fmt.Errorf("invalid block hash: %w", err)
+	}
+	return eth.BlockID{Hash: hash, Number: num}, nil
+}
+
+// blocksAt returns the hashes of the cached blocks at the given height.
+// The caller must hold the lock.
+func (c *L1DiskCache) blocksAt(num uint64) []common.Hash {
+	results, err := c.store.Query(context.Background(), query.Query{Prefix: l1CacheIndexNumberPrefix(num), KeysOnly: true})
+	if err != nil {
+		c.log.Warn("Failed to query L1 disk cache index", "number", num, "err", err)
+		return nil
+	}
+	defer results.Close()
+	var hashes []common.Hash
+	for res := range results.Next() {
+		if res.Error != nil {
+			c.log.Warn("Failed to read L1 disk cache index", "number", num, "err", res.Error)
+			return hashes
+		}
+		id, err := parseL1CacheIndexKey(res.Key)
+		if err != nil {
+			c.log.Warn("Invalid L1 disk cache index entry", "key", res.Key, "err", err)
+			continue
+		}
+		hashes = append(hashes, id.Hash)
+	}
+	return hashes
+}
+
+// evict removes all cached data of the given block, e.g. when part of it is found to be corrupted.
+func (c *L1DiskCache) evict(id eth.BlockID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(id)
+}
+
+// remove deletes all cached data of the given block.
+// The caller must hold the lock.
+func (c *L1DiskCache) remove(id eth.BlockID) {
+	ctx := context.Background()
+	indexKey := l1CacheIndexKey(id.Number, id.Hash)
+	indexValue, ok := c.get(indexKey)
+	if !ok {
+		return
+	}
+	b, err := c.store.Batch(ctx)
+	if err != nil {
+		c.log.Warn("Failed to remove block from L1 disk cache", "block", id, "err", err)
+		return
+	}
+	for _, key := range []ds.Key{
+		l1CacheDataKey(l1CacheHeaderPrefix, id.Hash),
+		l1CacheDataKey(l1CacheTxsPrefix, id.Hash),
+		l1CacheDataKey(l1CacheReceiptsPrefix, id.Hash),
+		indexKey,
+	} {
+		if err := b.Delete(ctx, key); err != nil {
+			c.log.Warn("Failed to remove block from L1 disk cache", "block", id, "err", err)
+			return
+		}
+	}
+	if err := b.Commit(ctx); err != nil {
+		c.log.Warn("Failed to remove block from L1 disk cache", "block", id, "err", err)
+		return
+	}
+	if size := decodeSize(indexValue); size < c.size {
+		c.size -= size
+	} else {
+		c.size = 0
+	}
+	c.blocks -= 1
+}
+
+// InvalidateReorged removes the cached blocks that are not part of the chain of the new L1 head:
+// all blocks past the head, and the blocks that conflict with the head or its cached ancestors.
+// The ancestors are traversed until the cached chain matches, or until an ancestor is not cached.
+// If the new head is the previous head, or extends it, nothing was reorged and the cache is not queried.
+func (c *L1DiskCache) InvalidateReorged(head eth.L1BlockRef) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev := c.head
+	c.head = head.ID()
+	if prev != (eth.BlockID{}) && (prev == head.ID() || (head.ParentHash == prev.Hash && head.Number == prev.Number+1)) {
+		return
+	}
+	for num := head.Number + 1; ; num++ {
+		hashes := c.blocksAt(num)
+		if len(hashes) == 0 {
+			break
+		}
+		for _, h := range hashes {
+			c.log.Debug("Removing reorged block from L1 disk cache", "number", num, "hash", h)
+			c.remove(eth.BlockID{Hash: h, Number: num})
+		}
+	}
+	num, hash, parent := head.Number, head.Hash, head.ParentHash
+	for {
+		matched, stale := false, 0
+		for _, h := range c.blocksAt(num) {
+			if h == hash {
+				matched = true
+				continue
+			}
+			c.log.Debug("Removing reorged block from L1 disk cache", "number", num, "hash", h)
+			c.remove(eth.BlockID{Hash: h, Number: num})
+			stale += 1
+		}
+		if (matched && stale == 0) || num == 0 || parent == (common.Hash{}) {
+			return
+		}
+		num, hash, parent = num-1, parent, common.Hash{}
+		if info, ok := c.header(hash); ok {
+			parent = info.ParentHash()
+		}
+	}
+}
+
+// Close closes the underlying store.
+func (c *L1DiskCache) Close() error {
+	return c.store.Close()
+}
@@ -0,0 +1,234 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func testCacheHeader(num uint64, parent common.Hash, extra byte) *headerInfo {
+	hdr := &types.Header{
+		ParentHash: parent,
+		Number:     new(big.Int).SetUint64(num),
+		Difficulty: big.NewInt(0),
+		BaseFee:    big.NewInt(7),
+		Extra:      []byte{extra},
+	}
+	return &headerInfo{hash: hdr.Hash(), Header: hdr}
+}
+
+// testCacheBlock creates a header with transactions and receipts that match its transactions and receipts roots.
+func testCacheBlock(num uint64) (*headerInfo, types.Transactions, types.Receipts) {
+	txs := types.Transactions{types.NewTransaction(1, common.Address{0xaa}, big.NewInt(3), 21000, big.NewInt(1), []byte{0x42})}
+	receipts := types.Receipts{{
+		Type:              types.LegacyTxType,
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		GasUsed:           21000,
+		Logs:              []*types.Log{{Address: common.Address{0xaa}, Topics: []common.Hash{}, Data: []byte{}, TxHash: txs[0].Hash(), BlockNumber: num}},
+		TxHash:            txs[0].Hash(),
+		BlockNumber:       new(big.Int).SetUint64(num),
+	}}
+	receipts[0].Bloom = types.CreateBloom(receipts)
+	hdr := &types.Header{
+		ParentHash:  common.Hash{0x01},
+		Number:      new(big.Int).SetUint64(num),
+		Difficulty:  big.NewInt(0),
+		BaseFee:     big.NewInt(7),
+		TxHash:      types.DeriveSha(txs, trie.NewStackTrie(nil)),
+		ReceiptHash: types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+	}
+	receipts[0].BlockHash = hdr.Hash()
+	receipts[0].Logs[0].BlockHash = hdr.Hash()
+	return &headerInfo{hash: hdr.Hash(), Header: hdr}, txs, receipts
+}
+
+func TestL1DiskCache(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlCrit)
+	store := sync.MutexWrap(ds.NewMapDatastore())
+	c, err := NewL1DiskCache(logger, store, 1<<20, nil)
+	require.NoError(t, err)
+
+	info, txs, receipts := testCacheBlock(10)
+	id := eth.ToBlockID(info)
+	_, ok := c.Header(info.Hash())
+	require.False(t, ok)
+
+	c.AddHeader(info)
+	c.AddTransactions(id, txs)
+	c.AddReceipts(id, receipts)
+
+	gotInfo, ok := c.Header(id.Hash)
+	require.True(t, ok)
+	require.Equal(t, id, eth.ToBlockID(gotInfo))
+	require.Equal(t, info.ParentHash(), gotInfo.ParentHash())
+	gotTxs, ok := c.Transactions(id.Hash)
+	require.True(t, ok)
+	require.Len(t, gotTxs, 1)
+	require.Equal(t, txs[0].Hash(), gotTxs[0].Hash())
+	gotReceipts, ok := c.Receipts(id.Hash)
+	require.True(t, ok)
+	require.Len(t, gotReceipts, 1)
+	require.Equal(t, receipts[0].TxHash, gotReceipts[0].TxHash)
+	require.Equal(t, receipts[0].BlockHash, gotReceipts[0].BlockHash)
+
+	// the cached data and its size persist across instances
+	reopened, err := NewL1DiskCache(logger, store, 1<<20, nil)
+	require.NoError(t, err)
+	require.Equal(t, c.size, reopened.size)
+	require.Equal(t, 1, reopened.blocks)
+	_, ok = reopened.Header(id.Hash)
+	require.True(t, ok)
+}
+
+func TestL1DiskCacheEvictCorrupted(t *testing.T) {
+	store := sync.MutexWrap(ds.NewMapDatastore())
+	c, err := NewL1DiskCache(testlog.Logger(t, log.LvlCrit), store, 1<<20, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("transactions", func(t *testing.T) {
+		info, txs, receipts := testCacheBlock(10)
+		c.AddHeader(info)
+		c.AddTransactions(eth.ToBlockID(info), txs)
+		c.AddReceipts(eth.ToBlockID(info), receipts)
+		other := types.Transactions{types.NewTransaction(2, common.Address{0xbb}, big.NewInt(3), 21000, big.NewInt(1), nil)}
+		data, err := rlp.EncodeToBytes(other)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, l1CacheDataKey(l1CacheTxsPrefix, info.Hash()), data))
+
+		_, ok := c.Transactions(info.Hash())
+		require.False(t, ok, "transactions do not match transactions root")
+		_, ok = c.Header(info.Hash())
+		require.False(t, ok, "block is evicted")
+		require.Zero(t, c.blocks)
+
+		c.AddHeader(info)
+		c.AddTransactions(eth.ToBlockID(info), txs)
+		gotTxs, ok := c.Transactions(info.Hash())
+		require.True(t, ok, "block can be cached again")
+		require.Equal(t, txs[0].Hash(), gotTxs[0].Hash())
+	})
+	t.Run("receipts", func(t *testing.T) {
+		info, txs, receipts := testCacheBlock(11)
+		c.AddHeader(info)
+		c.AddTransactions(eth.ToBlockID(info), txs)
+		c.AddReceipts(eth.ToBlockID(info), receipts)
+		receipts[0].Status = types.ReceiptStatusFailed
+		data, err := json.Marshal(receipts)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, l1CacheDataKey(l1CacheReceiptsPrefix, info.Hash()), data))
+
+		_, ok := c.Receipts(info.Hash())
+		require.False(t, ok, "receipts do not match receipts root")
+		_, ok = c.Header(info.Hash())
+		require.False(t, ok, "block is evicted")
+	})
+	t.Run("receipt tx hashes", func(t *testing.T) {
+		info, txs, receipts := testCacheBlock(12)
+		c.AddHeader(info)
+		c.AddTransactions(eth.ToBlockID(info), txs)
+		c.AddReceipts(eth.ToBlockID(info), receipts)
+		// the tx hashes are not part of the receipts root
+		receipts[0].TxHash = common.Hash{0xbb}
+		receipts[0].Logs[0].TxHash = common.Hash{0xbb}
+		data, err := json.Marshal(receipts)
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, l1CacheDataKey(l1CacheReceiptsPrefix, info.Hash()), data))
+
+		_, ok := c.Receipts(info.Hash())
+		require.False(t, ok, "receipts do not match the transactions of the block")
+		_, ok = c.Header(info.Hash())
+		require.False(t, ok, "block is evicted")
+	})
+	t.Run("receipts without transactions", func(t *testing.T) {
+		info, _, receipts := testCacheBlock(13)
+		c.AddHeader(info)
+		c.AddReceipts(eth.ToBlockID(info), receipts)
+		_, ok := c.Receipts(info.Hash())
+		require.False(t, ok, "receipts cannot be verified without the transactions")
+	})
+}
+
+func TestL1DiskCacheInvalidateReorged(t *testing.T) {
+	c, err := NewL1DiskCache(testlog.Logger(t, log.LvlCrit), sync.MutexWrap(ds.NewMapDatastore()), 1<<20, nil)
+	require.NoError(t, err)
+
+	// canonical chain a0 <- a1 <- a2 <- a3, and a fork b2 <- b3 <- b4 on top of a1
+	a0 := testCacheHeader(0, common.Hash{}, 'a')
+	a1 := testCacheHeader(1, a0.Hash(), 'a')
+	a2 := testCacheHeader(2, a1.Hash(), 'a')
+	a3 := testCacheHeader(3, a2.Hash(), 'a')
+	b2 := testCacheHeader(2, a1.Hash(), 'b')
+	b3 := testCacheHeader(3, b2.Hash(), 'b')
+	b4 := testCacheHeader(4, b3.Hash(), 'b')
+	for _, info := range []*headerInfo{a0, a1, a2, a3, b2, b3, b4} {
+		c.AddHeader(info)
+	}
+	require.Equal(t, 7, c.blocks)
+
+	c.InvalidateReorged(eth.InfoToL1BlockRef(a3))
+	for _, info := range []*headerInfo{a0, a1, a2, a3} {
+		_, ok := c.Header(info.Hash())
+		require.True(t, ok, "canonical block %d is kept", info.NumberU64())
+	}
+	for _, info := range []*headerInfo{b2, b3, b4} {
+		_, ok := c.Header(info.Hash())
+		require.False(t, ok, "reorged block %d is removed", info.NumberU64())
+	}
+	require.Equal(t, 4, c.blocks)
+
+	// a new head that extends the previous head does not query the cache
+	a4 := testCacheHeader(4, a3.Hash(), 'a')
+	c.AddHeader(a4)
+	c.AddHeader(b4)
+	c.InvalidateReorged(eth.InfoToL1BlockRef(a4))
+	_, ok := c.Header(b4.Hash())
+	require.True(t, ok, "stale block is kept while the chain is extended")
+
+	// a new head that does not extend the previous head invalidates the reorged blocks
+	c.InvalidateReorged(eth.InfoToL1BlockRef(b4))
+	for _, info := range []*headerInfo{a3, a4} {
+		_, ok = c.Header(info.Hash())
+		require.False(t, ok, "reorged block %d is removed", info.NumberU64())
+	}
+}
+
+func TestL1DiskCachePrune(t *testing.T) {
+	c, err := NewL1DiskCache(testlog.Logger(t, log.LvlCrit), sync.MutexWrap(ds.NewMapDatastore()), 0, nil)
+	require.NoError(t, err)
+	var infos []*headerInfo
+	parent := common.Hash{}
+	for i := uint64(0); i < 5; i++ {
+		info := testCacheHeader(i, parent, 0)
+		infos = append(infos, info)
+		parent = info.Hash()
+	}
+	data, err := infos[0].HeaderRLP()
+	require.NoError(t, err)
+	// all test headers encode to the same size, room for 3 headers
+	c.maxSize = uint64(len(data)) * 3
+
+	for _, info := range infos {
+		c.AddHeader(info)
+	}
+	require.Equal(t, 3, c.blocks)
+	require.LessOrEqual(t, c.size, c.maxSize)
+	for i, info := range infos {
+		_, ok := c.Header(info.Hash())
+		require.Equal(t, i >= 2, ok, "only the latest blocks are kept, block %d", i)
+	}
+}
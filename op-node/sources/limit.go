@@ -45,6 +45,11 @@ func (lc *limitClient) EthSubscribe(ctx context.Context, channel any, args ...an
 	return lc.c.EthSubscribe(ctx, channel, args...)
 }
 
+func (lc *limitClient) Subscribe(ctx context.Context, namespace string, channel any, args ...any) (ethereum.Subscription, error) {
+	// subscription doesn't count towards request limit
+	return lc.c.Subscribe(ctx, namespace, channel, args...)
+}
+
 func (lc *limitClient) Close() {
 	lc.wg.Wait()
 	close(lc.sema)
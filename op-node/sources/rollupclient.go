@@ -3,6 +3,7 @@ package sources
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
@@ -25,12 +26,24 @@ func (r *RollupClient) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth
 	return output, err
 }
 
+func (r *RollupClient) SafeHeadAtL1Block(ctx context.Context, blockNum uint64) (*eth.SafeHeadResponse, error) {
+	var output *eth.SafeHeadResponse
+	err := r.rpc.CallContext(ctx, &output, "optimism_safeHeadAtL1Block", hexutil.Uint64(blockNum))
+	return output, err
+}
+
 func (r *RollupClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	var output *eth.SyncStatus
 	err := r.rpc.CallContext(ctx, &output, "optimism_syncStatus")
 	return output, err
 }
 
+// SubscribeSyncStatus subscribes to the sync status, which is sent whenever any of the L1 or L2 heads changes.
+// The current sync status is sent first. Subscriptions require a websocket connection to the rollup node.
+func (r *RollupClient) SubscribeSyncStatus(ctx context.Context, ch chan<- *eth.SyncStatus) (ethereum.Subscription, error) {
+	return r.rpc.Subscribe(ctx, "optimism", ch, "syncStatus")
+}
+
 func (r *RollupClient) RollupConfig(ctx context.Context) (*rollup.Config, error) {
 	var output *rollup.Config
 	err := r.rpc.CallContext(ctx, &output, "optimism_rollupConfig")
@@ -58,3 +71,11 @@ func (r *RollupClient) SequencerActive(ctx context.Context) (bool, error) {
 	err := r.rpc.CallContext(ctx, &result, "admin_sequencerActive")
 	return result, err
 }
+
+func (r *RollupClient) RewindHead(ctx context.Context, num uint64, rewindSafe bool) error {
+	return r.rpc.CallContext(ctx, nil, "admin_rewindHead", hexutil.Uint64(num), rewindSafe)
+}
+
+func (r *RollupClient) SetSequencerThrottle(ctx context.Context, interval uint64) error {
+	return r.rpc.CallContext(ctx, nil, "admin_setSequencerThrottle", hexutil.Uint64(interval))
+}
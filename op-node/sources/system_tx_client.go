@@ -0,0 +1,24 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// SystemTxClient retrieves the sequencer-signed transactions to inject into new L2 blocks
+// from an external service, with the optimism_systemTxs RPC method.
+type SystemTxClient struct {
+	rpc client.RPC
+}
+
+func NewSystemTxClient(rpc client.RPC) *SystemTxClient {
+	return &SystemTxClient{rpc}
+}
+
+func (s *SystemTxClient) SystemTxs(ctx context.Context, l2Head eth.L2BlockRef, l1Origin eth.L1BlockRef) ([]eth.Data, error) {
+	var txs []eth.Data
+	err := s.rpc.CallContext(ctx, &txs, "optimism_systemTxs", l2Head, l1Origin)
+	return txs, err
+}
@@ -27,24 +27,46 @@ var (
 		Usage:   "HTTP provider URL for L1",
 		EnvVars: prefixEnvVars("L1_ETH_RPC"),
 	}
-	RollupRpcFlag = &cli.StringFlag{
+	RollupRpcFlag = &cli.StringSliceFlag{
 		Name:    "rollup-rpc",
-		Usage:   "HTTP provider URL for the rollup node",
+		Usage:   "HTTP provider URL for the rollup node. Multiple comma-separated URLs cross-check the proposed outputs against several rollup nodes.",
 		EnvVars: prefixEnvVars("ROLLUP_RPC"),
 	}
+
+	// Optional flags
 	L2OOAddressFlag = &cli.StringFlag{
 		Name:    "l2oo-address",
-		Usage:   "Address of the L2OutputOracle contract",
+		Usage:   "Address of the L2OutputOracle contract. Exactly one of l2oo-address and game-factory-address must be set.",
 		EnvVars: prefixEnvVars("L2OO_ADDRESS"),
 	}
-
-	// Optional flags
+	DisputeGameFactoryAddressFlag = &cli.StringFlag{
+		Name:    "game-factory-address",
+		Usage:   "Address of the DisputeGameFactory contract. If set, outputs are proposed by creating dispute games instead of proposing them to the L2OutputOracle.",
+		EnvVars: prefixEnvVars("GAME_FACTORY_ADDRESS"),
+	}
+	ProposalIntervalFlag = &cli.DurationFlag{
+		Name:    "proposal-interval",
+		Usage:   "Interval between output proposals by dispute game creation. Required if game-factory-address is set.",
+		EnvVars: prefixEnvVars("PROPOSAL_INTERVAL"),
+	}
+	GameTypeFlag = &cli.UintFlag{
+		Name:    "game-type",
+		Usage:   "Type of the dispute games to create, if game-factory-address is set.",
+		Value:   0,
+		EnvVars: prefixEnvVars("GAME_TYPE"),
+	}
 	PollIntervalFlag = &cli.DurationFlag{
 		Name:    "poll-interval",
 		Usage:   "How frequently to poll L2 for new blocks",
 		Value:   6 * time.Second,
 		EnvVars: prefixEnvVars("POLL_INTERVAL"),
 	}
+	RollupRpcQuorumFlag = &cli.UintFlag{
+		Name:    "rollup-rpc-quorum",
+		Usage:   "Number of rollup nodes that have to agree on an output before it is proposed. 0 requires all rollup nodes to agree.",
+		Value:   0,
+		EnvVars: prefixEnvVars("ROLLUP_RPC_QUORUM"),
+	}
 	AllowNonFinalizedFlag = &cli.BoolFlag{
 		Name:    "allow-non-finalized",
 		Usage:   "Allow the proposer to submit proposals for L2 blocks derived from non-finalized L1 blocks.",
@@ -57,11 +79,15 @@ var (
 var requiredFlags = []cli.Flag{
 	L1EthRpcFlag,
 	RollupRpcFlag,
-	L2OOAddressFlag,
 }
 
 var optionalFlags = []cli.Flag{
+	L2OOAddressFlag,
+	DisputeGameFactoryAddressFlag,
+	ProposalIntervalFlag,
+	GameTypeFlag,
 	PollIntervalFlag,
+	RollupRpcQuorumFlag,
 	AllowNonFinalizedFlag,
 	L2OutputHDPathFlag,
 }
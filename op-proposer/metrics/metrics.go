@@ -27,6 +27,11 @@ type Metricer interface {
 	txmetrics.TxMetricer
 
 	RecordL2BlocksProposed(l2ref eth.L2BlockRef)
+
+	RecordGamesInProgress(count int)
+	RecordGameResolved(status string)
+
+	RecordRollupDisagreement(kind string)
 }
 
 type Metrics struct {
@@ -39,6 +44,11 @@ type Metrics struct {
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
+
+	gamesInProgress prometheus.Gauge
+	gamesResolved   *prometheus.CounterVec
+
+	rollupDisagreements *prometheus.CounterVec
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -72,6 +82,25 @@ func NewMetrics(procName string) *Metrics {
 			Name:      "up",
 			Help:      "1 if the op-proposer has finished starting up",
 		}),
+		gamesInProgress: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "games_in_progress",
+			Help:      "Number of dispute games created by the op-proposer that are not resolved yet",
+		}),
+		gamesResolved: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "games_resolved_total",
+			Help:      "Number of resolved dispute games created by the op-proposer, by status",
+		}, []string{
+			"status",
+		}),
+		rollupDisagreements: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "rollup_disagreements_total",
+			Help:      "Number of times the rollup nodes disagreed on the sync status or an output, by kind",
+		}, []string{
+			"kind",
+		}),
 	}
 }
 
@@ -105,6 +134,21 @@ func (m *Metrics) RecordL2BlocksProposed(l2ref eth.L2BlockRef) {
 	m.RecordL2Ref(BlockProposed, l2ref)
 }
 
+// RecordGamesInProgress records the number of created dispute games that are not resolved yet.
+func (m *Metrics) RecordGamesInProgress(count int) {
+	m.gamesInProgress.Set(float64(count))
+}
+
+// RecordGameResolved records the resolution of a created dispute game with the given status.
+func (m *Metrics) RecordGameResolved(status string) {
+	m.gamesResolved.WithLabelValues(status).Inc()
+}
+
+// RecordRollupDisagreement records a disagreement of the rollup nodes of the given kind.
+func (m *Metrics) RecordRollupDisagreement(kind string) {
+	m.rollupDisagreements.WithLabelValues(kind).Inc()
+}
+
 func (m *Metrics) Document() []opmetrics.DocumentedMetric {
 	return m.factory.Document()
 }
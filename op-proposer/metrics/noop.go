@@ -17,3 +17,8 @@ func (*noopMetrics) RecordInfo(version string) {}
 func (*noopMetrics) RecordUp()                 {}
 
 func (*noopMetrics) RecordL2BlocksProposed(l2ref eth.L2BlockRef) {}
+
+func (*noopMetrics) RecordGamesInProgress(count int)  {}
+func (*noopMetrics) RecordGameResolved(status string) {}
+
+func (*noopMetrics) RecordRollupDisagreement(kind string) {}
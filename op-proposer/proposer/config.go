@@ -1,13 +1,15 @@
 package proposer
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 
-	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-proposer/flags"
 
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -25,8 +27,18 @@ type Config struct {
 	NetworkTimeout     time.Duration
 	TxManager          txmgr.TxManager
 	L1Client           *ethclient.Client
-	RollupClient       *sources.RollupClient
+	RollupClient       RollupClient
 	AllowNonFinalized  bool
+
+	// DisputeGameFactoryAddr enables the proposal of outputs by creating dispute
+	// games at the DisputeGameFactory, instead of proposing them to the L2OutputOracle.
+	// The games are created for the outputs of the L2OutputOracle of the game implementation,
+	// and the proposer checkpoints their L1 heads in the BlockOracle if needed.
+	DisputeGameFactoryAddr *common.Address
+	// ProposalInterval is the interval between dispute game creations.
+	ProposalInterval time.Duration
+	// GameType is the type of dispute games to create.
+	GameType uint8
 }
 
 // CLIConfig is a well typed config that is parsed from the CLI params.
@@ -38,12 +50,26 @@ type CLIConfig struct {
 	// L1EthRpc is the HTTP provider URL for L1.
 	L1EthRpc string
 
-	// RollupRpc is the HTTP provider URL for the rollup node.
-	RollupRpc string
+	// RollupRpcs are the HTTP provider URLs for the rollup nodes.
+	RollupRpcs []string
+
+	// RollupQuorum is the number of rollup nodes that have to agree on an
+	// output before it is proposed. If 0, all rollup nodes have to agree.
+	RollupQuorum uint
 
 	// L2OOAddress is the L2OutputOracle contract address.
 	L2OOAddress string
 
+	// DGFAddress is the DisputeGameFactory contract address. If set, outputs
+	// are proposed by creating dispute games, instead of proposing them to the L2OutputOracle.
+	DGFAddress string
+
+	// ProposalInterval is the interval between dispute game creations.
+	ProposalInterval time.Duration
+
+	// GameType is the type of dispute games to create.
+	GameType uint
+
 	// PollInterval is the delay between querying L2 for more transaction
 	// and creating a new batch.
 	PollInterval time.Duration
@@ -64,6 +90,21 @@ type CLIConfig struct {
 }
 
 func (c CLIConfig) Check() error {
+	if len(c.RollupRpcs) == 0 {
+		return errors.New("at least one rollup node RPC must be set")
+	}
+	if c.RollupQuorum > uint(len(c.RollupRpcs)) {
+		return fmt.Errorf("rollup quorum %d exceeds the number of rollup nodes %d", c.RollupQuorum, len(c.RollupRpcs))
+	}
+	if (c.L2OOAddress == "") == (c.DGFAddress == "") {
+		return errors.New("exactly one of the L2OutputOracle and DisputeGameFactory addresses must be set")
+	}
+	if c.DGFAddress != "" && c.ProposalInterval == 0 {
+		return errors.New("the proposal interval must be set to create dispute games")
+	}
+	if c.GameType > math.MaxUint8 {
+		return fmt.Errorf("invalid game type %d", c.GameType)
+	}
 	if err := c.RPCConfig.Check(); err != nil {
 		return err
 	}
@@ -87,11 +128,15 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 	return CLIConfig{
 		// Required Flags
 		L1EthRpc:     ctx.String(flags.L1EthRpcFlag.Name),
-		RollupRpc:    ctx.String(flags.RollupRpcFlag.Name),
-		L2OOAddress:  ctx.String(flags.L2OOAddressFlag.Name),
+		RollupRpcs:   ctx.StringSlice(flags.RollupRpcFlag.Name),
 		PollInterval: ctx.Duration(flags.PollIntervalFlag.Name),
 		TxMgrConfig:  txmgr.ReadCLIConfig(ctx),
 		// Optional Flags
+		L2OOAddress:       ctx.String(flags.L2OOAddressFlag.Name),
+		RollupQuorum:      ctx.Uint(flags.RollupRpcQuorumFlag.Name),
+		DGFAddress:        ctx.String(flags.DisputeGameFactoryAddressFlag.Name),
+		ProposalInterval:  ctx.Duration(flags.ProposalIntervalFlag.Name),
+		GameType:          ctx.Uint(flags.GameTypeFlag.Name),
 		AllowNonFinalized: ctx.Bool(flags.AllowNonFinalizedFlag.Name),
 		RPCConfig:         oprpc.ReadCLIConfig(ctx),
 		LogConfig:         oplog.ReadCLIConfig(ctx),
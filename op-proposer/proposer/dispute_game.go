@@ -0,0 +1,522 @@
+package proposer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+// GameStatus is the status of a dispute game, as defined by the GameStatus enum of the contracts.
+type GameStatus uint8
+
+const (
+	GameStatusInProgress GameStatus = iota
+	GameStatusChallengerWins
+	GameStatusDefenderWins
+)
+
+func (s GameStatus) String() string {
+	switch s {
+	case GameStatusInProgress:
+		return "in_progress"
+	case GameStatusChallengerWins:
+		return "challenger_wins"
+	case GameStatusDefenderWins:
+		return "defender_wins"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// gameFactoryCaller is the part of [bindings.DisputeGameFactoryCaller] that is used by the proposer.
+type gameFactoryCaller interface {
+	Games(opts *bind.CallOpts, _gameType uint8, _rootClaim [32]byte, _extraData []byte) (struct {
+		Proxy     common.Address
+		Timestamp uint64
+	}, error)
+}
+
+// gameStatusCaller is the part of [bindings.FaultDisputeGameCaller] that is used to watch proposed games.
+type gameStatusCaller interface {
+	Status(opts *bind.CallOpts) (uint8, error)
+}
+
+// gameOutputOracle looks up the L2OutputOracle outputs that the dispute games are created for.
+type gameOutputOracle interface {
+	// OutputAtOrBefore returns the index and the proposal of the latest output at or before the L2 block.
+	// It returns false if there is no such output.
+	OutputAtOrBefore(ctx context.Context, l2Block uint64) (uint64, bindings.TypesOutputProposal, bool, error)
+	// ProposedAt returns the number of the L1 block that included the output with the index.
+	// The output is looked up from the notBefore L1 block onwards.
+	ProposedAt(ctx context.Context, index uint64, notBefore uint64) (uint64, error)
+}
+
+// gameBlockOracle looks up the L1 blocks that are checkpointed in the BlockOracle.
+type gameBlockOracle interface {
+	// FirstCheckpoint returns the first checkpointed L1 block number that is at or after the given L1 block number.
+	// It returns false if no such block was checkpointed yet.
+	FirstCheckpoint(ctx context.Context, l1Block uint64) (uint64, bool, error)
+}
+
+// proposedGame is a dispute game that was created by this proposer and is watched until it resolves.
+type proposedGame struct {
+	caller    gameStatusCaller
+	l2Block   eth.L2BlockRef
+	rootClaim eth.Bytes32
+}
+
+// gameProposal is an output to create a dispute game for. The game disputes the L2OutputOracle output
+// of the same L2 block. Its L1 head is the first L1 block that was checkpointed in the BlockOracle after
+// that output was proposed, so that the extra data of the game does not depend on when it is created.
+type gameProposal struct {
+	output *eth.OutputResponse
+	// proposedAt is the number of the L1 block that included the L2OutputOracle output
+	proposedAt uint64
+	// l1Block is the L1 head of the game, only set if checkpointed is true
+	l1Block      uint64
+	checkpointed bool
+}
+
+// extraData returns the extra data of the dispute game, which is the ABI-encoded
+// L2 block number of the output and the L1 block number of the L1 head.
+func (p *gameProposal) extraData() []byte {
+	return gameExtraData(p.output.BlockRef.Number, p.l1Block)
+}
+
+// newDisputeGameSubmitter creates an L2 Output Submitter that proposes outputs by
+// creating dispute games at the DisputeGameFactory.
+func newDisputeGameSubmitter(cfg Config, l log.Logger, m metrics.Metricer) (*L2OutputSubmitter, error) {
+	factoryAddr := *cfg.DisputeGameFactoryAddr
+	factory, err := bindings.NewDisputeGameFactoryCaller(factoryAddr, cfg.L1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DisputeGameFactory at address %s: %w", factoryAddr, err)
+	}
+
+	cCtx, cCancel := context.WithTimeout(context.Background(), cfg.NetworkTimeout)
+	defer cCancel()
+	version, err := factory.Version(&bind.CallOpts{Context: cCtx})
+	if err != nil {
+		return nil, err
+	}
+	impl, err := factory.GameImpls(&bind.CallOpts{Context: cCtx}, cfg.GameType)
+	if err != nil {
+		return nil, err
+	}
+	if impl == (common.Address{}) {
+		return nil, fmt.Errorf("no implementation of game type %d at DisputeGameFactory %s", cfg.GameType, factoryAddr)
+	}
+	game, err := bindings.NewFaultDisputeGameCaller(impl, cfg.L1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind game implementation %s: %w", impl, err)
+	}
+	l2ooAddr, err := game.L2OUTPUTORACLE(&bind.CallOpts{Context: cCtx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2OutputOracle of game implementation %s: %w", impl, err)
+	}
+	blockOracleAddr, err := game.BLOCKORACLE(&bind.CallOpts{Context: cCtx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BlockOracle of game implementation %s: %w", impl, err)
+	}
+	l.Info("Connected to DisputeGameFactory", "address", factoryAddr, "version", version, "game_type", cfg.GameType,
+		"impl", impl, "l2oo", l2ooAddr, "block_oracle", blockOracleAddr)
+
+	l2oo, err := bindings.NewL2OutputOracle(l2ooAddr, cfg.L1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create L2OO at address %s: %w", l2ooAddr, err)
+	}
+	blockOracle, err := bindings.NewBlockOracleFilterer(blockOracleAddr, cfg.L1Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BlockOracle at address %s: %w", blockOracleAddr, err)
+	}
+	parsed, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	blockOracleABI, err := bindings.BlockOracleMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &L2OutputSubmitter{
+		txMgr:  cfg.TxManager,
+		done:   make(chan struct{}),
+		log:    l,
+		ctx:    ctx,
+		cancel: cancel,
+		metr:   m,
+
+		rollupClient: cfg.RollupClient,
+
+		dgfContract:      factory,
+		dgfContractAddr:  factoryAddr,
+		dgfABI:           parsed,
+		gameType:         cfg.GameType,
+		proposalInterval: cfg.ProposalInterval,
+		games:            make(map[common.Address]*proposedGame),
+		newGameCaller: func(addr common.Address) (gameStatusCaller, error) {
+			return bindings.NewFaultDisputeGameCaller(addr, cfg.L1Client)
+		},
+		gameOutputs:     &l2ooGameOutputs{l2oo: l2oo},
+		blockOracle:     &blockOracleCheckpoints{filterer: blockOracle},
+		blockOracleAddr: blockOracleAddr,
+		blockOracleABI:  blockOracleABI,
+
+		allowNonFinalized: cfg.AllowNonFinalized,
+		pollInterval:      cfg.PollInterval,
+		networkTimeout:    cfg.NetworkTimeout,
+	}, nil
+}
+
+// fetchNextGameProposal gets the output of the latest L2OutputOracle output at or before the
+// finalized, or if allowed safe, L2 block, to propose as the root claim of a new dispute game.
+// It returns: the proposal, if the proposal should be made, error
+func (l *L2OutputSubmitter) fetchNextGameProposal(ctx context.Context) (*gameProposal, bool, error) {
+	cCtx, cancel := context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	status, err := l.rollupClient.SyncStatus(cCtx)
+	if err != nil {
+		l.log.Error("proposer unable to get sync status", "err", err)
+		return nil, false, err
+	}
+
+	// Use either the finalized or safe head depending on the config. Finalized head is default & safer.
+	blockNumber := status.FinalizedL2.Number
+	if l.allowNonFinalized {
+		blockNumber = status.SafeL2.Number
+	}
+	if blockNumber == 0 || blockNumber <= l.lastGameBlock {
+		l.log.Debug("no new L2 block to propose", "block", blockNumber, "last_proposed", l.lastGameBlock)
+		return nil, false, nil
+	}
+
+	// The game disputes an L2OutputOracle output, and the output before it is the starting point of the game.
+	cCtx, cancel = context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	index, disputed, ok, err := l.gameOutputs.OutputAtOrBefore(cCtx, blockNumber)
+	if err != nil {
+		l.log.Error("proposer unable to look up L2OutputOracle output", "block", blockNumber, "err", err)
+		return nil, false, err
+	}
+	if !ok || index == 0 {
+		l.log.Debug("no L2OutputOracle output to dispute", "block", blockNumber)
+		return nil, false, nil
+	}
+	l2Block := disputed.L2BlockNumber.Uint64()
+	if l2Block <= l.lastGameBlock {
+		l.log.Debug("no new L2OutputOracle output to dispute", "block", l2Block, "last_proposed", l.lastGameBlock)
+		return nil, false, nil
+	}
+
+	output, shouldPropose, err := l.fetchOutput(ctx, new(big.Int).SetUint64(l2Block))
+	if err != nil || !shouldPropose {
+		return nil, false, err
+	}
+
+	cCtx, cancel = context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	// The output cannot have been proposed before the L1 origin of its L2 block.
+	proposedAt, err := l.gameOutputs.ProposedAt(cCtx, index, output.BlockRef.L1Origin.Number)
+	if err != nil {
+		l.log.Error("proposer unable to look up L1 block of L2OutputOracle output", "index", index, "err", err)
+		return nil, false, err
+	}
+	proposal := &gameProposal{output: output, proposedAt: proposedAt}
+	cCtx, cancel = context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	proposal.l1Block, proposal.checkpointed, err = l.blockOracle.FirstCheckpoint(cCtx, proposedAt)
+	if err != nil {
+		l.log.Error("proposer unable to look up BlockOracle checkpoint", "l1_block", proposedAt, "err", err)
+		return nil, false, err
+	}
+	if !proposal.checkpointed {
+		// a game needs a checkpoint, so no game exists for the output yet
+		return proposal, true, nil
+	}
+	exists, err := l.gameExists(ctx, proposal)
+	if err != nil || exists {
+		return nil, false, err
+	}
+	return proposal, true, nil
+}
+
+// gameExists checks whether a dispute game for the checkpointed proposal exists already.
+// If so, the L2 block of the proposal counts as proposed.
+func (l *L2OutputSubmitter) gameExists(ctx context.Context, proposal *gameProposal) (bool, error) {
+	cCtx, cancel := context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	output := proposal.output
+	game, err := l.dgfContract.Games(&bind.CallOpts{Context: cCtx}, l.gameType, output.OutputRoot, proposal.extraData())
+	if err != nil {
+		l.log.Error("proposer unable to look up existing dispute game", "err", err)
+		return false, err
+	}
+	if game.Proxy == (common.Address{}) {
+		return false, nil
+	}
+	l.log.Info("dispute game for output already exists, skipping proposal",
+		"game", game.Proxy, "l2_block", output.BlockRef, "root_claim", output.OutputRoot)
+	l.lastGameBlock = output.BlockRef.Number
+	return true, nil
+}
+
+// gameExtraData returns the extra data of a dispute game, which is the ABI-encoded
+// L2 block number of the output and the L1 block number of the L1 head. The L1 block
+// must be available in the game's BlockOracle.
+func gameExtraData(l2Block uint64, l1Block uint64) []byte {
+	data := make([]byte, 0, 64)
+	data = append(data, common.BigToHash(new(big.Int).SetUint64(l2Block)).Bytes()...)
+	data = append(data, common.BigToHash(new(big.Int).SetUint64(l1Block)).Bytes()...)
+	return data
+}
+
+// createGameTxData creates the transaction data for the DisputeGameFactory create function
+func createGameTxData(abi *abi.ABI, gameType uint8, proposal *gameProposal) ([]byte, error) {
+	return abi.Pack("create", gameType, proposal.output.OutputRoot, proposal.extraData())
+}
+
+// sendCheckpointTransaction checkpoints the parent of the L1 block that includes the
+// transaction in the BlockOracle, through the underlying transaction manager.
+// Once the transaction is included, the L1 block that included the proposed output
+// has a checkpoint at or after it.
+func (l *L2OutputSubmitter) sendCheckpointTransaction(ctx context.Context) error {
+	data, err := l.blockOracleABI.Pack("checkpoint")
+	if err != nil {
+		return err
+	}
+	receipt, err := l.txMgr.Send(ctx, txmgr.TxCandidate{
+		TxData:   data,
+		To:       &l.blockOracleAddr,
+		GasLimit: 0,
+	})
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return fmt.Errorf("checkpoint tx %s reverted", receipt.TxHash)
+	}
+	l.log.Info("L1 block successfully checkpointed", "tx_hash", receipt.TxHash, "l1_block", receipt.BlockNumber.Uint64()-1)
+	return nil
+}
+
+// sendCreateGameTransaction creates a dispute game for the proposal through the
+// underlying transaction manager, and starts watching the created game.
+// If no L1 block is checkpointed since the disputed output was proposed, it checkpoints one first.
+func (l *L2OutputSubmitter) sendCreateGameTransaction(ctx context.Context, proposal *gameProposal) error {
+	output := proposal.output
+	if !proposal.checkpointed {
+		if err := l.sendCheckpointTransaction(ctx); err != nil {
+			return fmt.Errorf("checkpointing L1 block: %w", err)
+		}
+		cCtx, cancel := context.WithTimeout(ctx, l.networkTimeout)
+		l1Block, ok, err := l.blockOracle.FirstCheckpoint(cCtx, proposal.proposedAt)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("looking up BlockOracle checkpoint: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("no BlockOracle checkpoint at or after L1 block %d", proposal.proposedAt)
+		}
+		proposal.l1Block, proposal.checkpointed = l1Block, true
+		// the game may have been created by another proposer with an earlier checkpoint
+		if exists, err := l.gameExists(ctx, proposal); err != nil || exists {
+			return err
+		}
+	}
+
+	err := l.waitForL1Head(ctx, output.Status.HeadL1.Number+1)
+	if err != nil {
+		return err
+	}
+	data, err := createGameTxData(l.dgfABI, l.gameType, proposal)
+	if err != nil {
+		return err
+	}
+	receipt, err := l.txMgr.Send(ctx, txmgr.TxCandidate{
+		TxData:   data,
+		To:       &l.dgfContractAddr,
+		GasLimit: 0,
+	})
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return fmt.Errorf("dispute game creation tx %s reverted", receipt.TxHash)
+	}
+	l.lastGameBlock = output.BlockRef.Number
+
+	cCtx, cancel := context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	game, err := l.dgfContract.Games(&bind.CallOpts{Context: cCtx}, l.gameType, output.OutputRoot, proposal.extraData())
+	if err != nil {
+		return fmt.Errorf("looking up created dispute game: %w", err)
+	}
+	caller, err := l.newGameCaller(game.Proxy)
+	if err != nil {
+		return fmt.Errorf("binding created dispute game %s: %w", game.Proxy, err)
+	}
+	l.games[game.Proxy] = &proposedGame{caller: caller, l2Block: output.BlockRef, rootClaim: output.OutputRoot}
+	l.metr.RecordGamesInProgress(len(l.games))
+	l.log.Info("dispute game successfully created",
+		"tx_hash", receipt.TxHash,
+		"game", game.Proxy,
+		"l2_block", output.BlockRef,
+		"root_claim", output.OutputRoot,
+		"l1blocknum", proposal.l1Block)
+	return nil
+}
+
+// updateGames checks the status of the dispute games created by this proposer,
+// and stops watching games once they are resolved.
+func (l *L2OutputSubmitter) updateGames(ctx context.Context) {
+	for addr, game := range l.games {
+		cCtx, cancel := context.WithTimeout(ctx, l.networkTimeout)
+		s, err := game.caller.Status(&bind.CallOpts{Context: cCtx})
+		cancel()
+		if err != nil {
+			l.log.Warn("unable to get dispute game status", "game", addr, "err", err)
+			continue
+		}
+		status := GameStatus(s)
+		switch status {
+		case GameStatusInProgress:
+			continue
+		case GameStatusDefenderWins:
+			l.log.Info("dispute game resolved, root claim stands", "game", addr, "l2_block", game.l2Block, "root_claim", game.rootClaim)
+		default:
+			l.log.Error("dispute game resolved against the proposer", "game", addr, "status", status, "l2_block", game.l2Block, "root_claim", game.rootClaim)
+		}
+		l.metr.RecordGameResolved(status.String())
+		delete(l.games, addr)
+	}
+	l.metr.RecordGamesInProgress(len(l.games))
+}
+
+// gameLoop is responsible for creating dispute games for new outputs and watching their status.
+func (l *L2OutputSubmitter) gameLoop() {
+	defer l.wg.Done()
+
+	ctx := l.ctx
+
+	proposalTicker := time.NewTicker(l.proposalInterval)
+	defer proposalTicker.Stop()
+	pollTicker := time.NewTicker(l.pollInterval)
+	defer pollTicker.Stop()
+	for {
+		select {
+		case <-proposalTicker.C:
+			proposal, shouldPropose, err := l.fetchNextGameProposal(ctx)
+			if err != nil || !shouldPropose {
+				break
+			}
+			cCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+			if err := l.sendCreateGameTransaction(cCtx, proposal); err != nil {
+				l.log.Error("Failed to send dispute game creation transaction",
+					"err", err,
+					"l2_block", proposal.output.BlockRef,
+					"l1_proposed_at", proposal.proposedAt,
+					"l1head", proposal.output.Status.HeadL1.Number)
+				cancel()
+				break
+			}
+			l.metr.RecordL2BlocksProposed(proposal.output.BlockRef)
+			cancel()
+
+		case <-pollTicker.C:
+			l.updateGames(ctx)
+
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// l2ooGameOutputs implements gameOutputOracle with the L2OutputOracle bindings.
+type l2ooGameOutputs struct {
+	l2oo *bindings.L2OutputOracle
+}
+
+func (o *l2ooGameOutputs) OutputAtOrBefore(ctx context.Context, l2Block uint64) (uint64, bindings.TypesOutputProposal, bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	next, err := o.l2oo.NextOutputIndex(opts)
+	if err != nil {
+		return 0, bindings.TypesOutputProposal{}, false, fmt.Errorf("getting next output index: %w", err)
+	}
+	if next.Sign() == 0 {
+		return 0, bindings.TypesOutputProposal{}, false, nil
+	}
+	index := new(big.Int).Sub(next, common.Big1)
+	latest, err := o.l2oo.GetL2Output(opts, index)
+	if err != nil {
+		return 0, bindings.TypesOutputProposal{}, false, fmt.Errorf("getting output %d: %w", index, err)
+	}
+	if latest.L2BlockNumber.Uint64() <= l2Block {
+		return index.Uint64(), latest, true, nil
+	}
+	// the first output at or after the L2 block, or the one before it
+	index, err = o.l2oo.GetL2OutputIndexAfter(opts, new(big.Int).SetUint64(l2Block))
+	if err != nil {
+		return 0, bindings.TypesOutputProposal{}, false, fmt.Errorf("getting output index after L2 block %d: %w", l2Block, err)
+	}
+	output, err := o.l2oo.GetL2Output(opts, index)
+	if err != nil {
+		return 0, bindings.TypesOutputProposal{}, false, fmt.Errorf("getting output %d: %w", index, err)
+	}
+	if output.L2BlockNumber.Uint64() == l2Block {
+		return index.Uint64(), output, true, nil
+	}
+	if index.Sign() == 0 {
+		return 0, bindings.TypesOutputProposal{}, false, nil
+	}
+	index.Sub(index, common.Big1)
+	output, err = o.l2oo.GetL2Output(opts, index)
+	if err != nil {
+		return 0, bindings.TypesOutputProposal{}, false, fmt.Errorf("getting output %d: %w", index, err)
+	}
+	return index.Uint64(), output, true, nil
+}
+
+func (o *l2ooGameOutputs) ProposedAt(ctx context.Context, index uint64, notBefore uint64) (uint64, error) {
+	it, err := o.l2oo.FilterOutputProposed(&bind.FilterOpts{Context: ctx, Start: notBefore}, nil, []*big.Int{new(big.Int).SetUint64(index)}, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	if it.Next() {
+		return it.Event.Raw.BlockNumber, nil
+	}
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no OutputProposed event of output %d", index)
+}
+
+// blockOracleCheckpoints implements gameBlockOracle with the BlockOracle bindings.
+type blockOracleCheckpoints struct {
+	filterer *bindings.BlockOracleFilterer
+}
+
+func (o *blockOracleCheckpoints) FirstCheckpoint(ctx context.Context, l1Block uint64) (uint64, bool, error) {
+	// a checkpoint of a block is made in the block after it
+	it, err := o.filterer.FilterCheckpoint(&bind.FilterOpts{Context: ctx, Start: l1Block + 1}, nil, nil, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer it.Close()
+	if it.Next() {
+		return it.Event.BlockNumber.Uint64(), true, nil
+	}
+	return 0, false, it.Error()
+}
@@ -0,0 +1,320 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+// TestManualCreateGameABIPacking ensures that the manual ABI packing of the dispute game
+// creation is the same as going through the bound contract.
+func TestManualCreateGameABIPacking(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+	opts, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1337))
+	require.NoError(t, err)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}}, 50_000_000)
+	_, _, contract, err := bindings.DeployDisputeGameFactory(opts, backend)
+	require.NoError(t, err)
+
+	abi, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+	require.NoError(t, err)
+	rng := rand.New(rand.NewSource(1234))
+	output := testutils.RandomOutputResponse(rng)
+
+	proposal := &gameProposal{output: output, l1Block: 1234, checkpointed: true}
+	txData, err := createGameTxData(abi, 2, proposal)
+	require.NoError(t, err)
+
+	extraData := proposal.extraData()
+	require.Len(t, extraData, 64)
+	require.Equal(t, output.BlockRef.Number, new(big.Int).SetBytes(extraData[:32]).Uint64())
+	require.Equal(t, uint64(1234), new(big.Int).SetBytes(extraData[32:]).Uint64())
+
+	// set a gas limit to disable gas estimation, there's no game implementation to create.
+	opts.GasLimit = 100_000
+	tx, err := contract.Create(opts, 2, output.OutputRoot, extraData)
+	require.NoError(t, err)
+	require.Equal(t, txData, tx.Data())
+}
+
+type testGameStatusCaller struct {
+	status uint8
+	err    error
+}
+
+func (c *testGameStatusCaller) Status(_ *bind.CallOpts) (uint8, error) {
+	return c.status, c.err
+}
+
+type testGameMetrics struct {
+	metrics.Metricer
+	inProgress int
+	resolved   map[string]int
+}
+
+func (m *testGameMetrics) RecordGamesInProgress(count int) {
+	m.inProgress = count
+}
+
+func (m *testGameMetrics) RecordGameResolved(status string) {
+	m.resolved[status]++
+}
+
+func TestUpdateGames(t *testing.T) {
+	m := &testGameMetrics{Metricer: metrics.NoopMetrics, resolved: make(map[string]int)}
+	inProgress := &testGameStatusCaller{status: uint8(GameStatusInProgress)}
+	failing := &testGameStatusCaller{err: errors.New("boom")}
+	l := &L2OutputSubmitter{
+		log:  testlog.Logger(t, log.LvlCrit),
+		metr: m,
+		games: map[common.Address]*proposedGame{
+			{1}: {caller: inProgress},
+			{2}: {caller: &testGameStatusCaller{status: uint8(GameStatusDefenderWins)}},
+			{3}: {caller: &testGameStatusCaller{status: uint8(GameStatusChallengerWins)}},
+			{4}: {caller: failing},
+		},
+	}
+
+	l.updateGames(context.Background())
+	require.Len(t, l.games, 2, "resolved games are not watched anymore")
+	require.Contains(t, l.games, common.Address{1})
+	require.Contains(t, l.games, common.Address{4}, "games are watched until the status is known")
+	require.Equal(t, 2, m.inProgress)
+	require.Equal(t, map[string]int{"defender_wins": 1, "challenger_wins": 1}, m.resolved)
+
+	inProgress.status = uint8(GameStatusDefenderWins)
+	failing.status, failing.err = uint8(GameStatusChallengerWins), nil
+	l.updateGames(context.Background())
+	require.Empty(t, l.games)
+	require.Zero(t, m.inProgress)
+	require.Equal(t, map[string]int{"defender_wins": 2, "challenger_wins": 2}, m.resolved)
+}
+
+func TestBlockOracleFirstCheckpoint(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+	opts, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1337))
+	require.NoError(t, err)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}}, 50_000_000)
+	_, _, contract, err := bindings.DeployBlockOracle(opts, backend)
+	require.NoError(t, err)
+	backend.Commit() // block 1
+	oracle := &blockOracleCheckpoints{filterer: &contract.BlockOracleFilterer}
+
+	_, ok, err := oracle.FirstCheckpoint(context.Background(), 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	checkpoint := func() {
+		_, err := contract.Checkpoint(opts)
+		require.NoError(t, err)
+		backend.Commit()
+	}
+	backend.Commit()
+	checkpoint() // block 3 checkpoints block 2
+	backend.Commit()
+	backend.Commit()
+	checkpoint() // block 6 checkpoints block 5
+
+	for l1Block, expected := range map[uint64]uint64{0: 2, 2: 2, 3: 5, 5: 5} {
+		num, ok, err := oracle.FirstCheckpoint(context.Background(), l1Block)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, expected, num, "first checkpoint at or after %d", l1Block)
+	}
+	_, ok, err = oracle.FirstCheckpoint(context.Background(), 6)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+type testGameOutputs struct {
+	index      uint64
+	output     bindings.TypesOutputProposal
+	proposedAt uint64
+	notBefore  uint64
+}
+
+func (o *testGameOutputs) OutputAtOrBefore(_ context.Context, l2Block uint64) (uint64, bindings.TypesOutputProposal, bool, error) {
+	if o.output.L2BlockNumber.Uint64() > l2Block {
+		return 0, bindings.TypesOutputProposal{}, false, nil
+	}
+	return o.index, o.output, true, nil
+}
+
+func (o *testGameOutputs) ProposedAt(_ context.Context, index uint64, notBefore uint64) (uint64, error) {
+	if index != o.index {
+		return 0, errors.New("unknown output")
+	}
+	o.notBefore = notBefore
+	return o.proposedAt, nil
+}
+
+type testBlockOracle struct {
+	checkpoints []uint64
+}
+
+func (o *testBlockOracle) FirstCheckpoint(_ context.Context, l1Block uint64) (uint64, bool, error) {
+	for _, num := range o.checkpoints {
+		if num >= l1Block {
+			return num, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+type testGameFactory struct {
+	games map[string]common.Address
+}
+
+func (f *testGameFactory) Games(_ *bind.CallOpts, gameType uint8, rootClaim [32]byte, extraData []byte) (struct {
+	Proxy     common.Address
+	Timestamp uint64
+}, error) {
+	var game struct {
+		Proxy     common.Address
+		Timestamp uint64
+	}
+	game.Proxy = f.games[string(append([]byte{gameType}, append(rootClaim[:], extraData...)...))]
+	return game, nil
+}
+
+// testGameTxManager includes the checkpoint and game creation txs that are sent to it.
+type testGameTxManager struct {
+	txmgr.TxManager
+	factory         *testGameFactory
+	factoryAddr     common.Address
+	oracle          *testBlockOracle
+	oracleAddr      common.Address
+	l1Head          uint64
+	revert          bool
+	checkpointsSent int
+}
+
+func (m *testGameTxManager) BlockNumber(_ context.Context) (uint64, error) {
+	return m.l1Head, nil
+}
+
+func (m *testGameTxManager) Send(_ context.Context, candidate txmgr.TxCandidate) (*types.Receipt, error) {
+	m.l1Head++
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: new(big.Int).SetUint64(m.l1Head)}
+	if m.revert {
+		receipt.Status = types.ReceiptStatusFailed
+		return receipt, nil
+	}
+	switch *candidate.To {
+	case m.oracleAddr:
+		m.checkpointsSent++
+		m.oracle.checkpoints = append(m.oracle.checkpoints, m.l1Head-1)
+	case m.factoryAddr:
+		// the create call data is the selector, the game type, the root claim, the offset and length of the extra data
+		args := candidate.TxData[4:]
+		key := append([]byte{args[31]}, args[32:64]...)
+		key = append(key, args[128:]...)
+		m.factory.games[string(key)] = common.Address{0x42}
+	}
+	return receipt, nil
+}
+
+func TestDisputeGameProposal(t *testing.T) {
+	require := require.New(t)
+	rng := rand.New(rand.NewSource(1234))
+	output := testutils.RandomOutputResponse(rng)
+	output.Version = supportedL2OutputVersion
+	output.BlockRef.Number = 100
+	output.Status.FinalizedL2.Number = 120
+	output.Status.HeadL1.Number = 70
+
+	factoryABI, err := bindings.DisputeGameFactoryMetaData.GetAbi()
+	require.NoError(err)
+	oracleABI, err := bindings.BlockOracleMetaData.GetAbi()
+	require.NoError(err)
+	factory := &testGameFactory{games: make(map[string]common.Address)}
+	oracle := &testBlockOracle{}
+	txMgr := &testGameTxManager{
+		factory:     factory,
+		factoryAddr: common.Address{0xdf},
+		oracle:      oracle,
+		oracleAddr:  common.Address{0xb0},
+		l1Head:      80,
+	}
+	gameOutputs := &testGameOutputs{index: 3, output: bindings.TypesOutputProposal{L2BlockNumber: big.NewInt(100)}, proposedAt: 60}
+	newSubmitter := func() *L2OutputSubmitter {
+		return &L2OutputSubmitter{
+			txMgr:           txMgr,
+			done:            make(chan struct{}),
+			log:             testlog.Logger(t, log.LvlCrit),
+			metr:            metrics.NoopMetrics,
+			rollupClient:    &testRollupClient{status: output.Status, output: output},
+			dgfContract:     factory,
+			dgfContractAddr: txMgr.factoryAddr,
+			dgfABI:          factoryABI,
+			gameType:        2,
+			games:           make(map[common.Address]*proposedGame),
+			newGameCaller: func(addr common.Address) (gameStatusCaller, error) {
+				return &testGameStatusCaller{}, nil
+			},
+			gameOutputs:     gameOutputs,
+			blockOracle:     oracle,
+			blockOracleAddr: txMgr.oracleAddr,
+			blockOracleABI:  oracleABI,
+			pollInterval:    time.Millisecond,
+			networkTimeout:  time.Second,
+		}
+	}
+	l := newSubmitter()
+
+	proposal, ok, err := l.fetchNextGameProposal(context.Background())
+	require.NoError(err)
+	require.True(ok)
+	require.False(proposal.checkpointed, "no checkpoint since the output was proposed")
+	require.Equal(output.BlockRef.L1Origin.Number, gameOutputs.notBefore, "output is looked up from the L1 origin of its L2 block")
+	require.NoError(l.sendCreateGameTransaction(context.Background(), proposal))
+	require.Equal(1, txMgr.checkpointsSent)
+	require.Equal(uint64(80), proposal.l1Block, "parent of the block that included the checkpoint")
+	require.Contains(l.games, common.Address{0x42})
+	require.Equal(uint64(100), l.lastGameBlock)
+
+	// after a restart, and with a different L1 head of the rollup node, the existing game is found
+	output.Status.CurrentL1.Number += 10
+	l = newSubmitter()
+	_, ok, err = l.fetchNextGameProposal(context.Background())
+	require.NoError(err)
+	require.False(ok, "game exists already")
+	require.Equal(uint64(100), l.lastGameBlock)
+	require.Equal(1, txMgr.checkpointsSent)
+
+	// a reverted game creation is an error
+	factory.games = make(map[string]common.Address)
+	l = newSubmitter()
+	proposal, ok, err = l.fetchNextGameProposal(context.Background())
+	require.NoError(err)
+	require.True(ok)
+	require.True(proposal.checkpointed, "existing checkpoint is used")
+	require.Equal(uint64(80), proposal.l1Block)
+	txMgr.revert = true
+	require.ErrorContains(l.sendCreateGameTransaction(context.Background(), proposal), "reverted")
+	require.Empty(l.games)
+	require.Zero(l.lastGameBlock)
+}
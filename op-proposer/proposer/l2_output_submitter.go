@@ -17,7 +17,6 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
-	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-proposer/flags"
 	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
@@ -67,6 +66,7 @@ func Main(version string, cliCtx *cli.Context) error {
 		l.Error("Unable to start L2 Output Submitter", "error", err)
 		return err
 	}
+	defer proposerConfig.TxManager.Close()
 	defer l2OutputSubmitter.Stop()
 
 	l.Info("L2 Output Submitter started")
@@ -119,12 +119,29 @@ type L2OutputSubmitter struct {
 	cancel context.CancelFunc
 
 	// RollupClient is used to retrieve output roots from
-	rollupClient *sources.RollupClient
+	rollupClient RollupClient
 
 	l2ooContract     *bindings.L2OutputOracleCaller
 	l2ooContractAddr common.Address
 	l2ooABI          *abi.ABI
 
+	// Dispute game mode, only set if outputs are proposed by creating dispute games.
+	dgfContract      gameFactoryCaller
+	dgfContractAddr  common.Address
+	dgfABI           *abi.ABI
+	gameType         uint8
+	proposalInterval time.Duration
+	// L2 block number of the last output that a dispute game exists for
+	lastGameBlock uint64
+	// games created by this proposer that are not resolved yet
+	games         map[common.Address]*proposedGame
+	newGameCaller func(addr common.Address) (gameStatusCaller, error)
+	// L2OutputOracle outputs that are disputed by the games, and BlockOracle that holds their L1 heads
+	gameOutputs     gameOutputOracle
+	blockOracle     gameBlockOracle
+	blockOracleAddr common.Address
+	blockOracleABI  *abi.ABI
+
 	// AllowNonFinalized enables the proposal of safe, but non-finalized L2 blocks.
 	// The L1 block-hash embedded in the proposal TX is checked and should ensure the proposal
 	// is never valid on an alternative L1 chain that would produce different L2 data.
@@ -146,9 +163,22 @@ func NewL2OutputSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Me
 
 // NewL2OutputSubmitterConfigFromCLIConfig creates the proposer config from the CLI config.
 func NewL2OutputSubmitterConfigFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metricer) (*Config, error) {
-	l2ooAddress, err := opservice.ParseAddress(cfg.L2OOAddress)
-	if err != nil {
-		return nil, err
+	var (
+		l2ooAddress common.Address
+		dgfAddress  *common.Address
+	)
+	if cfg.DGFAddress != "" {
+		addr, err := opservice.ParseAddress(cfg.DGFAddress)
+		if err != nil {
+			return nil, err
+		}
+		dgfAddress = &addr
+	} else {
+		addr, err := opservice.ParseAddress(cfg.L2OOAddress)
+		if err != nil {
+			return nil, err
+		}
+		l2ooAddress = addr
 	}
 
 	txManager, err := txmgr.NewSimpleTxManager("proposer", l, m, cfg.TxMgrConfig)
@@ -162,25 +192,44 @@ func NewL2OutputSubmitterConfigFromCLIConfig(cfg CLIConfig, l log.Logger, m metr
 		return nil, err
 	}
 
-	rollupClient, err := opclient.DialRollupClientWithTimeout(opclient.DefaultDialTimeout, l, cfg.RollupRpc)
-	if err != nil {
-		return nil, err
+	rollupClients := make([]RollupClient, 0, len(cfg.RollupRpcs))
+	for _, url := range cfg.RollupRpcs {
+		rollupClient, err := opclient.DialRollupClientWithTimeout(opclient.DefaultDialTimeout, l, url)
+		if err != nil {
+			return nil, err
+		}
+		rollupClients = append(rollupClients, rollupClient)
+	}
+	rollupClient := rollupClients[0]
+	if len(rollupClients) > 1 {
+		rollupClient, err = NewQuorumRollupClient(l, m, rollupClients, cfg.RollupRpcs, int(cfg.RollupQuorum))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &Config{
-		L2OutputOracleAddr: l2ooAddress,
-		PollInterval:       cfg.PollInterval,
-		NetworkTimeout:     cfg.TxMgrConfig.NetworkTimeout,
-		L1Client:           l1Client,
-		RollupClient:       rollupClient,
-		AllowNonFinalized:  cfg.AllowNonFinalized,
-		TxManager:          txManager,
+		L2OutputOracleAddr:     l2ooAddress,
+		DisputeGameFactoryAddr: dgfAddress,
+		ProposalInterval:       cfg.ProposalInterval,
+		GameType:               uint8(cfg.GameType),
+		PollInterval:           cfg.PollInterval,
+		NetworkTimeout:         cfg.TxMgrConfig.NetworkTimeout,
+		L1Client:               l1Client,
+		RollupClient:           rollupClient,
+		AllowNonFinalized:      cfg.AllowNonFinalized,
+		TxManager:              txManager,
 	}, nil
 
 }
 
-// NewL2OutputSubmitter creates a new L2 Output Submitter
+// NewL2OutputSubmitter creates a new L2 Output Submitter.
+// If the DisputeGameFactory address is configured, it proposes outputs by creating dispute games.
 func NewL2OutputSubmitter(cfg Config, l log.Logger, m metrics.Metricer) (*L2OutputSubmitter, error) {
+	if cfg.DisputeGameFactoryAddr != nil {
+		return newDisputeGameSubmitter(cfg, l, m)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	l2ooContract, err := bindings.NewL2OutputOracleCaller(cfg.L2OutputOracleAddr, cfg.L1Client)
@@ -226,7 +275,11 @@ func NewL2OutputSubmitter(cfg Config, l log.Logger, m metrics.Metricer) (*L2Outp
 
 func (l *L2OutputSubmitter) Start() error {
 	l.wg.Add(1)
-	go l.loop()
+	if l.dgfContract != nil {
+		go l.gameLoop()
+	} else {
+		go l.loop()
+	}
 	return nil
 }
 
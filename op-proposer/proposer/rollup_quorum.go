@@ -0,0 +1,220 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// RollupClient is the part of the rollup node RPC that is used by the proposer.
+type RollupClient interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+}
+
+var (
+	// ErrNoQuorum is returned if not enough rollup nodes responded to reach the quorum.
+	ErrNoQuorum = errors.New("rollup nodes did not reach quorum")
+	// ErrRollupDisagreement is returned if the rollup nodes responded with conflicting data.
+	ErrRollupDisagreement = errors.New("rollup nodes disagree")
+)
+
+const (
+	DisagreementSyncStatus = "sync_status"
+	DisagreementOutput     = "output"
+)
+
+// quorumRollupClient cross-checks the responses of several rollup nodes.
+// A response is only returned if at least quorum rollup nodes responded, and none
+// of the responding nodes disagree, so that a single faulty node can not make the
+// proposer post an invalid output.
+type quorumRollupClient struct {
+	log     log.Logger
+	metr    metrics.Metricer
+	clients []RollupClient
+	// names of the rollup nodes, for logging
+	names  []string
+	quorum int
+}
+
+// NewQuorumRollupClient creates a rollup client that requires quorum of the given
+// rollup clients to agree. A quorum of 0 requires all rollup clients to agree.
+func NewQuorumRollupClient(log log.Logger, metr metrics.Metricer, clients []RollupClient, names []string, quorum int) (RollupClient, error) {
+	if len(clients) == 0 || len(clients) != len(names) {
+		return nil, fmt.Errorf("invalid number of rollup clients %d, with %d names", len(clients), len(names))
+	}
+	if quorum == 0 {
+		quorum = len(clients)
+	}
+	if quorum > len(clients) {
+		return nil, fmt.Errorf("quorum %d exceeds the number of rollup clients %d", quorum, len(clients))
+	}
+	return &quorumRollupClient{
+		log:     log,
+		metr:    metr,
+		clients: clients,
+		names:   names,
+		quorum:  quorum,
+	}, nil
+}
+
+// SyncStatus returns the sync status that quorum of the rollup nodes reached: the
+// safe and finalized L2 heads are the highest heads that at least quorum nodes reached.
+func (q *quorumRollupClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	statuses := make([]*eth.SyncStatus, len(q.clients))
+	errs := make([]error, len(q.clients))
+	q.each(func(i int, cl RollupClient) {
+		statuses[i], errs[i] = cl.SyncStatus(ctx)
+	})
+
+	var responded []int
+	for i, err := range errs {
+		if err != nil {
+			q.log.Warn("Rollup node failed to return sync status", "node", q.names[i], "err", err)
+			continue
+		}
+		responded = append(responded, i)
+	}
+	if len(responded) < q.quorum {
+		return nil, fmt.Errorf("%w: %d of %d required rollup nodes returned the sync status", ErrNoQuorum, len(responded), q.quorum)
+	}
+	if err := q.checkSyncStatuses(statuses, responded); err != nil {
+		return nil, err
+	}
+	return q.quorumStatus(statuses, responded), nil
+}
+
+// OutputAtBlock returns the output at the given block, if quorum of the rollup nodes
+// returned the output, and all responding rollup nodes agree on it. The sync status of
+// the output is the status that quorum of the rollup nodes reached.
+func (q *quorumRollupClient) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	outputs := make([]*eth.OutputResponse, len(q.clients))
+	errs := make([]error, len(q.clients))
+	q.each(func(i int, cl RollupClient) {
+		outputs[i], errs[i] = cl.OutputAtBlock(ctx, blockNum)
+	})
+
+	type outputKey struct {
+		version    eth.Bytes32
+		outputRoot eth.Bytes32
+		blockHash  common.Hash
+	}
+	groups := make(map[outputKey][]int)
+	var responded []int
+	for i, err := range errs {
+		if err == nil && (outputs[i] == nil || outputs[i].Status == nil) {
+			err = errors.New("empty output response")
+		}
+		if err != nil {
+			q.log.Warn("Rollup node failed to return output", "node", q.names[i], "block", blockNum, "err", err)
+			continue
+		}
+		key := outputKey{outputs[i].Version, outputs[i].OutputRoot, outputs[i].BlockRef.Hash}
+		groups[key] = append(groups[key], i)
+		responded = append(responded, i)
+	}
+	if len(groups) > 1 {
+		q.metr.RecordRollupDisagreement(DisagreementOutput)
+		for _, i := range responded {
+			q.log.Error("Rollup nodes disagree on output", "node", q.names[i], "block", blockNum,
+				"output_root", outputs[i].OutputRoot, "block_hash", outputs[i].BlockRef.Hash, "version", outputs[i].Version)
+		}
+		return nil, fmt.Errorf("%w: %d different outputs at block %d", ErrRollupDisagreement, len(groups), blockNum)
+	}
+	if len(responded) < q.quorum {
+		return nil, fmt.Errorf("%w: %d of %d required rollup nodes returned the output at block %d", ErrNoQuorum, len(responded), q.quorum, blockNum)
+	}
+
+	statuses := make([]*eth.SyncStatus, len(outputs))
+	for _, i := range responded {
+		statuses[i] = outputs[i].Status
+	}
+	if err := q.checkSyncStatuses(statuses, responded); err != nil {
+		return nil, err
+	}
+	output := *outputs[responded[0]]
+	output.Status = q.quorumStatus(statuses, responded)
+	return &output, nil
+}
+
+// each calls fn for all rollup clients concurrently and waits for all calls to return.
+func (q *quorumRollupClient) each(fn func(i int, cl RollupClient)) {
+	var wg sync.WaitGroup
+	for i, cl := range q.clients {
+		wg.Add(1)
+		go func(i int, cl RollupClient) {
+			defer wg.Done()
+			fn(i, cl)
+		}(i, cl)
+	}
+	wg.Wait()
+}
+
+type headRef struct {
+	node int
+	hash common.Hash
+}
+
+// checkSyncStatuses checks that no two rollup nodes have different safe or finalized L2 blocks,
+// or different current or head L1 blocks, at the same height.
+func (q *quorumRollupClient) checkSyncStatuses(statuses []*eth.SyncStatus, responded []int) error {
+	l2Heads := make(map[uint64]headRef)
+	l1Heads := make(map[uint64]headRef)
+	for _, i := range responded {
+		for _, ref := range []eth.L2BlockRef{statuses[i].SafeL2, statuses[i].FinalizedL2} {
+			if err := q.checkHead(l2Heads, i, "L2", ref.Number, ref.Hash); err != nil {
+				return err
+			}
+		}
+		for _, ref := range []eth.L1BlockRef{statuses[i].CurrentL1, statuses[i].HeadL1} {
+			if err := q.checkHead(l1Heads, i, "L1", ref.Number, ref.Hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkHead records the block hash of node i at the given height, and returns an error if another node
+// recorded a different block hash at the same height.
+func (q *quorumRollupClient) checkHead(heads map[uint64]headRef, i int, layer string, number uint64, hash common.Hash) error {
+	prev, ok := heads[number]
+	if !ok {
+		heads[number] = headRef{node: i, hash: hash}
+		return nil
+	}
+	if prev.hash != hash {
+		q.metr.RecordRollupDisagreement(DisagreementSyncStatus)
+		q.log.Error("Rollup nodes disagree on "+layer+" block", "number", number,
+			"node", q.names[prev.node], "hash", prev.hash, "other_node", q.names[i], "other_hash", hash)
+		return fmt.Errorf("%w: different %s blocks at height %d", ErrRollupDisagreement, layer, number)
+	}
+	return nil
+}
+
+// quorumStatus returns the sync status of the responding rollup node with the
+// quorum-th highest finalized L2 head, with the safe L2 head, and the current and head
+// L1 blocks, set to the quorum-th highest of the responding rollup nodes. A single rollup
+// node can thus not make the proposer rely on an L1 block that the others have not seen.
+func (q *quorumRollupClient) quorumStatus(statuses []*eth.SyncStatus, responded []int) *eth.SyncStatus {
+	nth := func(number func(s *eth.SyncStatus) uint64) *eth.SyncStatus {
+		sorted := append([]int(nil), responded...)
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return number(statuses[sorted[a]]) > number(statuses[sorted[b]])
+		})
+		return statuses[sorted[q.quorum-1]]
+	}
+	status := *nth(func(s *eth.SyncStatus) uint64 { return s.FinalizedL2.Number })
+	status.SafeL2 = nth(func(s *eth.SyncStatus) uint64 { return s.SafeL2.Number }).SafeL2
+	status.CurrentL1 = nth(func(s *eth.SyncStatus) uint64 { return s.CurrentL1.Number }).CurrentL1
+	status.HeadL1 = nth(func(s *eth.SyncStatus) uint64 { return s.HeadL1.Number }).HeadL1
+	return &status
+}
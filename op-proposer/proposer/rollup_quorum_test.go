@@ -0,0 +1,136 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+type testRollupClient struct {
+	status *eth.SyncStatus
+	output *eth.OutputResponse
+	err    error
+}
+
+func (c *testRollupClient) SyncStatus(_ context.Context) (*eth.SyncStatus, error) {
+	return c.status, c.err
+}
+
+func (c *testRollupClient) OutputAtBlock(_ context.Context, _ uint64) (*eth.OutputResponse, error) {
+	return c.output, c.err
+}
+
+type testRollupMetrics struct {
+	metrics.Metricer
+	disagreements map[string]int
+}
+
+func (m *testRollupMetrics) RecordRollupDisagreement(kind string) {
+	m.disagreements[kind]++
+}
+
+func l2Ref(num uint64) eth.L2BlockRef {
+	return eth.L2BlockRef{Number: num, Hash: common.Hash{byte(num)}}
+}
+
+func syncStatus(safe, finalized uint64) *eth.SyncStatus {
+	return &eth.SyncStatus{SafeL2: l2Ref(safe), FinalizedL2: l2Ref(finalized)}
+}
+
+func newTestQuorumClient(t *testing.T, quorum int, clients ...*testRollupClient) (RollupClient, *testRollupMetrics) {
+	m := &testRollupMetrics{Metricer: metrics.NoopMetrics, disagreements: make(map[string]int)}
+	rollupClients := make([]RollupClient, 0, len(clients))
+	names := make([]string, 0, len(clients))
+	for i, cl := range clients {
+		rollupClients = append(rollupClients, cl)
+		names = append(names, string(rune('a'+i)))
+	}
+	q, err := NewQuorumRollupClient(testlog.Logger(t, log.LvlCrit), m, rollupClients, names, quorum)
+	require.NoError(t, err)
+	return q, m
+}
+
+func TestQuorumRollupClientSyncStatus(t *testing.T) {
+	a := &testRollupClient{status: syncStatus(10, 5)}
+	b := &testRollupClient{status: syncStatus(12, 4)}
+	c := &testRollupClient{status: syncStatus(8, 6)}
+	q, m := newTestQuorumClient(t, 2, a, b, c)
+
+	status, err := q.SyncStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, l2Ref(10), status.SafeL2, "highest safe head of two nodes")
+	require.Equal(t, l2Ref(5), status.FinalizedL2, "highest finalized head of two nodes")
+
+	b.err, c.err = errors.New("b down"), errors.New("c down")
+	_, err = q.SyncStatus(context.Background())
+	require.ErrorIs(t, err, ErrNoQuorum)
+
+	b.err, c.err = nil, nil
+	c.status.SafeL2.Hash = common.Hash{0xff}
+	c.status.SafeL2.Number = 12
+	_, err = q.SyncStatus(context.Background())
+	require.ErrorIs(t, err, ErrRollupDisagreement)
+	require.Equal(t, 1, m.disagreements[DisagreementSyncStatus])
+}
+
+func TestQuorumRollupClientSyncStatusL1(t *testing.T) {
+	withL1 := func(current, head uint64) *eth.SyncStatus {
+		status := syncStatus(10, 5)
+		status.CurrentL1 = eth.L1BlockRef{Number: current, Hash: common.Hash{byte(current)}}
+		status.HeadL1 = eth.L1BlockRef{Number: head, Hash: common.Hash{byte(head)}}
+		return status
+	}
+	a := &testRollupClient{status: withL1(20, 30)}
+	b := &testRollupClient{status: withL1(21, 31)}
+	c := &testRollupClient{status: withL1(99, 99)}
+	q, m := newTestQuorumClient(t, 2, a, b, c)
+
+	status, err := q.SyncStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(21), status.CurrentL1.Number, "highest current L1 block of two nodes")
+	require.Equal(t, uint64(31), status.HeadL1.Number, "highest L1 head of two nodes")
+
+	c.status = withL1(21, 31)
+	c.status.HeadL1.Hash = common.Hash{0xff}
+	_, err = q.SyncStatus(context.Background())
+	require.ErrorIs(t, err, ErrRollupDisagreement)
+	require.Equal(t, 1, m.disagreements[DisagreementSyncStatus])
+}
+
+func TestQuorumRollupClientOutputAtBlock(t *testing.T) {
+	output := func(root byte, status *eth.SyncStatus) *eth.OutputResponse {
+		return &eth.OutputResponse{OutputRoot: eth.Bytes32{root}, BlockRef: l2Ref(5), Status: status}
+	}
+	a := &testRollupClient{output: output(1, syncStatus(10, 5))}
+	b := &testRollupClient{output: output(1, syncStatus(12, 6))}
+	c := &testRollupClient{err: errors.New("c down")}
+	q, m := newTestQuorumClient(t, 2, a, b, c)
+
+	out, err := q.OutputAtBlock(context.Background(), 5)
+	require.NoError(t, err)
+	require.Equal(t, eth.Bytes32{1}, out.OutputRoot)
+	require.Equal(t, l2Ref(5), out.Status.FinalizedL2)
+	require.Equal(t, l2Ref(10), out.Status.SafeL2)
+
+	c.err = nil
+	c.output = output(2, syncStatus(10, 5))
+	_, err = q.OutputAtBlock(context.Background(), 5)
+	require.ErrorIs(t, err, ErrRollupDisagreement, "a single disagreeing node prevents the proposal")
+	require.Equal(t, 1, m.disagreements[DisagreementOutput])
+
+	b.err, c.err = errors.New("b down"), errors.New("c down")
+	_, err = q.OutputAtBlock(context.Background(), 5)
+	require.ErrorIs(t, err, ErrNoQuorum)
+
+	all, _ := newTestQuorumClient(t, 0, a, b)
+	_, err = all.OutputAtBlock(context.Background(), 5)
+	require.ErrorIs(t, err, ErrNoQuorum, "all nodes have to respond with quorum 0")
+}
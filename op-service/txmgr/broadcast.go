@@ -0,0 +1,117 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// primaryEndpoint is the name of the L1 RPC endpoint in broadcast metrics.
+const primaryEndpoint = "primary"
+
+// TxBroadcaster is an endpoint that signed transactions can be submitted to.
+type TxBroadcaster interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// BroadcastEndpoint is an endpoint that transactions are broadcast to, in addition to the L1 RPC.
+// Receipts, nonces and fees are only queried from the L1 RPC.
+type BroadcastEndpoint struct {
+	// Name identifies the endpoint in logs and metrics
+	Name   string
+	Client TxBroadcaster
+	// Private endpoints submit transactions without exposing them in the public mempool.
+	Private bool
+}
+
+// dialBroadcastEndpoints dials the given broadcast endpoint URLs. The endpoints are named by their host,
+// so that credentials in the URL path or query do not end up in logs and metrics.
+func dialBroadcastEndpoints(ctx context.Context, urls []string, private bool, names map[string]bool) ([]BroadcastEndpoint, error) {
+	endpoints := make([]BroadcastEndpoint, 0, len(urls))
+	for i, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid broadcast endpoint %d: %w", i, err)
+		}
+		name := u.Host
+		if names[name] {
+			name = fmt.Sprintf("%s#%d", name, i)
+		}
+		names[name] = true
+		client, err := ethclient.DialContext(ctx, rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not dial broadcast endpoint %s: %w", name, err)
+		}
+		endpoints = append(endpoints, BroadcastEndpoint{Name: name, Client: client, Private: private})
+	}
+	return endpoints, nil
+}
+
+// broadcast submits the transaction to the L1 RPC and all broadcast endpoints concurrently.
+// If private only submission is configured, it is only submitted to the private endpoints.
+// It succeeds if any endpoint accepted the transaction. Otherwise the error of the first
+// endpoint is returned, which is the L1 RPC unless private only submission is configured.
+func (m *SimpleTxManager) broadcast(ctx context.Context, tx *types.Transaction) error {
+	var endpoints []BroadcastEndpoint
+	if !m.cfg.PrivateOnly {
+		endpoints = append(endpoints, BroadcastEndpoint{Name: primaryEndpoint, Client: m.backend})
+	}
+	for _, e := range m.cfg.BroadcastEndpoints {
+		if e.Private || !m.cfg.PrivateOnly {
+			endpoints = append(endpoints, e)
+		}
+	}
+	if len(endpoints) == 0 {
+		return errors.New("no endpoints to broadcast to")
+	}
+
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e BroadcastEndpoint) {
+			defer wg.Done()
+			errs[i] = e.Client.SendTransaction(ctx, tx)
+			m.metr.RecordBroadcast(e.Name, broadcastResult(errs[i]))
+			if errs[i] != nil && len(endpoints) > 1 {
+				m.l.Debug("Endpoint did not accept transaction", "endpoint", e.Name, "hash", tx.Hash(), "err", errs[i])
+			}
+		}(i, e)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errs[0]
+}
+
+// broadcastResult returns the metrics label of the result of submitting a transaction to an endpoint.
+func broadcastResult(err error) string {
+	switch {
+	case err == nil:
+		return "accepted"
+	case errStringMatch(err, core.ErrNonceTooLow):
+		return "nonce_too_low"
+	case errStringMatch(err, context.Canceled):
+		return "context_cancelled"
+	case errStringMatch(err, context.DeadlineExceeded):
+		return "timeout"
+	case errStringMatch(err, txpool.ErrAlreadyKnown):
+		return "tx_already_known"
+	case errStringMatch(err, txpool.ErrReplaceUnderpriced):
+		return "tx_replacement_underpriced"
+	case errStringMatch(err, txpool.ErrUnderpriced):
+		return "tx_underpriced"
+	default:
+		return "unknown_error"
+	}
+}
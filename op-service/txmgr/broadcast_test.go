@@ -0,0 +1,115 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
+)
+
+type fakeBroadcaster struct {
+	send func(tx *types.Transaction) error
+
+	mu   sync.Mutex
+	sent int
+}
+
+func (b *fakeBroadcaster) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	b.sent++
+	b.mu.Unlock()
+	return b.send(tx)
+}
+
+func (b *fakeBroadcaster) Sent() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sent
+}
+
+type testBroadcastMetrics struct {
+	metrics.NoopTxMetrics
+
+	mu      sync.Mutex
+	results map[string][]string
+}
+
+func (m *testBroadcastMetrics) RecordBroadcast(endpoint string, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[endpoint] = append(m.results[endpoint], result)
+}
+
+func newBroadcastTestHarness(t *testing.T, privateOnly bool, endpoints ...BroadcastEndpoint) (*testHarness, *testBroadcastMetrics) {
+	cfg := configWithNumConfs(1)
+	cfg.BroadcastEndpoints = endpoints
+	cfg.PrivateOnly = privateOnly
+	h := newTestHarnessWithConfig(t, cfg)
+	m := &testBroadcastMetrics{results: make(map[string][]string)}
+	h.mgr.metr = m
+	return h, m
+}
+
+func TestBroadcastReceiptFromPrimary(t *testing.T) {
+	var h *testHarness
+	relay := &fakeBroadcaster{send: func(tx *types.Transaction) error {
+		// the relay includes the tx, and the receipt is found at the primary
+		txHash := tx.Hash()
+		h.backend.mine(&txHash, tx.GasFeeCap())
+		return nil
+	}}
+	failing := &fakeBroadcaster{send: func(tx *types.Transaction) error {
+		return errors.New("connection refused")
+	}}
+	h, m := newBroadcastTestHarness(t, false,
+		BroadcastEndpoint{Name: "relay", Client: relay, Private: true},
+		BroadcastEndpoint{Name: "public", Client: failing},
+	)
+	h.backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		return errors.New("dropped")
+	})
+
+	receipt, err := h.mgr.send(context.Background(), h.createTxCandidate())
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	require.Equal(t, 1, relay.Sent())
+	require.Equal(t, 1, failing.Sent())
+	require.Equal(t, []string{"unknown_error"}, m.results[primaryEndpoint])
+	require.Equal(t, []string{"accepted"}, m.results["relay"])
+	require.Equal(t, []string{"unknown_error"}, m.results["public"])
+}
+
+func TestBroadcastPrivateOnly(t *testing.T) {
+	private := &fakeBroadcaster{send: func(tx *types.Transaction) error { return nil }}
+	public := &fakeBroadcaster{send: func(tx *types.Transaction) error { return nil }}
+	h, m := newBroadcastTestHarness(t, true,
+		BroadcastEndpoint{Name: "private", Client: private, Private: true},
+		BroadcastEndpoint{Name: "public", Client: public},
+	)
+
+	require.NoError(t, h.mgr.broadcast(context.Background(), types.NewTx(&types.DynamicFeeTx{})))
+	require.Equal(t, 1, private.Sent())
+	require.Zero(t, public.Sent(), "public endpoints are not used for private only submission")
+	require.NotContains(t, m.results, primaryEndpoint, "primary is not used for private only submission")
+}
+
+func TestBroadcastAllFailing(t *testing.T) {
+	relay := &fakeBroadcaster{send: func(tx *types.Transaction) error {
+		return errors.New("relay unavailable")
+	}}
+	h, m := newBroadcastTestHarness(t, false, BroadcastEndpoint{Name: "relay", Client: relay, Private: true})
+	h.backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		return core.ErrNonceTooLow
+	})
+
+	err := h.mgr.broadcast(context.Background(), types.NewTx(&types.DynamicFeeTx{}))
+	require.ErrorIs(t, err, core.ErrNonceTooLow, "error of the primary is returned")
+	require.Equal(t, []string{"nonce_too_low"}, m.results[primaryEndpoint])
+	require.Equal(t, []string{"unknown_error"}, m.results["relay"])
+}
@@ -0,0 +1,122 @@
+package txmgr
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrFeeCapExceeded is returned if a transaction would need a fee cap above the configured maximum fee cap.
+	ErrFeeCapExceeded = errors.New("max fee cap exceeded")
+	// ErrTipCapExceeded is returned if a transaction would need a tip above the configured maximum tip cap.
+	ErrTipCapExceeded = errors.New("max tip cap exceeded")
+	// ErrBudgetExceeded is returned if the maximum cost of a transaction exceeds the remaining spend budget.
+	ErrBudgetExceeded = errors.New("spend budget exceeded")
+)
+
+const (
+	limitFeeCap = "fee_cap"
+	limitTipCap = "tip_cap"
+	limitBudget = "budget"
+)
+
+type spend struct {
+	at     time.Time
+	amount *big.Int
+}
+
+// spendBudget tracks the fees spent by confirmed transactions in a rolling time window.
+// The maximum cost of in-flight transactions is reserved until they are settled, so that
+// concurrent transactions can not overspend the budget together.
+type spendBudget struct {
+	limit  *big.Int
+	window time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	spends   []spend
+	reserved *big.Int
+}
+
+// newSpendBudget returns a budget of limit wei per window, or nil if the limit is not set.
+func newSpendBudget(limit *big.Int, window time.Duration) *spendBudget {
+	if limit == nil || limit.Sign() <= 0 {
+		return nil
+	}
+	return &spendBudget{
+		limit:    limit,
+		window:   window,
+		now:      time.Now,
+		reserved: new(big.Int),
+	}
+}
+
+// Remaining returns the budget that is left in the current window for new reservations.
+func (b *spendBudget) Remaining() *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining()
+}
+
+// TryReserve reserves the amount for an in-flight transaction, if it does not exceed the
+// remaining budget. It returns whether the amount was reserved, and the budget that was remaining.
+func (b *spendBudget) TryReserve(amount *big.Int) (*big.Int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	remaining := b.remaining()
+	if amount.Cmp(remaining) > 0 {
+		return remaining, false
+	}
+	b.reserved.Add(b.reserved, amount)
+	return remaining, true
+}
+
+// Reserve reserves the amount for an in-flight transaction, even if it exceeds the remaining budget.
+func (b *spendBudget) Reserve(amount *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reserved.Add(b.reserved, amount)
+}
+
+// Settle releases the reserved amount of a transaction and records the fees it spent now, if any.
+func (b *spendBudget) Settle(reserved, spent *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reserved.Sub(b.reserved, reserved)
+	if spent != nil {
+		b.spends = append(b.spends, spend{at: b.now(), amount: new(big.Int).Set(spent)})
+	}
+}
+
+// remaining returns the budget that is left after spends and reservations. The lock must be held.
+func (b *spendBudget) remaining() *big.Int {
+	b.prune()
+	remaining := new(big.Int).Sub(b.limit, b.reserved)
+	for _, s := range b.spends {
+		remaining.Sub(remaining, s.amount)
+	}
+	if remaining.Sign() < 0 {
+		remaining.SetUint64(0)
+	}
+	return remaining
+}
+
+// prune drops the spends that are outside of the window. The lock must be held.
+func (b *spendBudget) prune() {
+	cutoff := b.now().Add(-b.window)
+	i := 0
+	for i < len(b.spends) && !b.spends[i].at.After(cutoff) {
+		i++
+	}
+	b.spends = b.spends[i:]
+}
+
+// weiToGwei converts an amount in wei to a float in gwei, for metrics.
+func weiToGwei(wei *big.Int) float64 {
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei)).Float64()
+	return gwei
+}
@@ -0,0 +1,163 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
+)
+
+type testLimitMetrics struct {
+	metrics.NoopTxMetrics
+	hits map[string]int
+}
+
+func (m *testLimitMetrics) RecordFeeLimitHit(limit string) {
+	m.hits[limit]++
+}
+
+func newLimitTestHarness(t *testing.T, cfg Config) (*testHarness, *testLimitMetrics) {
+	h := newTestHarnessWithConfig(t, cfg)
+	m := &testLimitMetrics{hits: make(map[string]int)}
+	h.mgr.metr = m
+	h.mgr.budget = newSpendBudget(cfg.SpendBudget, cfg.SpendBudgetWindow)
+	return h, m
+}
+
+func TestSpendBudget(t *testing.T) {
+	require.Nil(t, newSpendBudget(nil, time.Hour))
+	require.Nil(t, newSpendBudget(big.NewInt(0), time.Hour))
+
+	now := time.Unix(1000, 0)
+	b := newSpendBudget(big.NewInt(100), time.Hour)
+	b.now = func() time.Time { return now }
+
+	b.Settle(new(big.Int), big.NewInt(30))
+	now = now.Add(30 * time.Minute)
+	b.Settle(new(big.Int), big.NewInt(50))
+	require.Equal(t, uint64(20), b.Remaining().Uint64())
+	b.Settle(new(big.Int), big.NewInt(50))
+	require.Equal(t, uint64(0), b.Remaining().Uint64(), "overspending leaves no budget")
+
+	now = now.Add(30*time.Minute + time.Second)
+	require.Equal(t, uint64(0), b.Remaining().Uint64(), "first spend left the window")
+	now = now.Add(30 * time.Minute)
+	require.Equal(t, uint64(100), b.Remaining().Uint64(), "all spends left the window")
+
+	remaining, ok := b.TryReserve(big.NewInt(60))
+	require.True(t, ok)
+	require.Equal(t, uint64(100), remaining.Uint64())
+	_, ok = b.TryReserve(big.NewInt(60))
+	require.False(t, ok, "reservations of in-flight txs are not available")
+	require.Equal(t, uint64(40), b.Remaining().Uint64())
+	b.Settle(big.NewInt(60), big.NewInt(25))
+	require.Equal(t, uint64(75), b.Remaining().Uint64(), "settled tx only spends its fees")
+	b.Reserve(big.NewInt(100))
+	require.Equal(t, uint64(0), b.Remaining().Uint64(), "sent txs are reserved above the budget")
+}
+
+func TestTxMgrCraftTxFeeCaps(t *testing.T) {
+	cfg := configWithNumConfs(1)
+	cfg.MaxGasTipCap = big.NewInt(3)
+	cfg.MaxGasFeeCap = big.NewInt(15)
+	h, m := newLimitTestHarness(t, cfg)
+
+	// epoch 1: tip 5, basefee 7, fee cap 19
+	tx, err := h.mgr.craftTx(context.Background(), h.createTxCandidate())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(3), tx.GasTipCap())
+	require.Equal(t, big.NewInt(15), tx.GasFeeCap())
+	require.Equal(t, 1, m.hits[limitTipCap])
+	require.Equal(t, 1, m.hits[limitFeeCap])
+
+	// epoch 2: basefee 14
+	h.mgr.cfg.MaxGasFeeCap = big.NewInt(13)
+	_, err = h.mgr.craftTx(context.Background(), h.createTxCandidate())
+	require.ErrorIs(t, err, ErrFeeCapExceeded)
+	require.Equal(t, 2, m.hits[limitFeeCap])
+
+	nonce, err := h.mgr.nextNonce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), nonce, "failed tx does not take a nonce")
+}
+
+func TestTxMgrIncreaseGasPriceFeeCaps(t *testing.T) {
+	cfg := configWithNumConfs(1)
+	cfg.MaxGasFeeCap = big.NewInt(100)
+	h, _ := newLimitTestHarness(t, cfg)
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: big.NewInt(10),
+		GasFeeCap: big.NewInt(100),
+	})
+	_, err := h.mgr.increaseGasPrice(context.Background(), tx)
+	require.ErrorIs(t, err, ErrFeeCapExceeded, "fee cap can not be bumped over the max fee cap")
+
+	h.mgr.cfg.MaxGasFeeCap = nil
+	h.mgr.cfg.MaxGasTipCap = big.NewInt(10)
+	_, err = h.mgr.increaseGasPrice(context.Background(), tx)
+	require.ErrorIs(t, err, ErrTipCapExceeded, "tip can not be bumped over the max tip cap")
+
+	h.mgr.cfg.MaxGasTipCap = big.NewInt(1000)
+	newTx, err := h.mgr.increaseGasPrice(context.Background(), tx)
+	require.NoError(t, err)
+	require.Equal(t, calcThresholdValue(tx.GasFeeCap()), newTx.GasFeeCap())
+}
+
+func TestTxMgrSpendBudget(t *testing.T) {
+	cfg := configWithNumConfs(1)
+	cfg.SpendBudget = big.NewInt(1000)
+	cfg.SpendBudgetWindow = time.Hour
+	h, m := newLimitTestHarness(t, cfg)
+	now := time.Unix(1000, 0)
+	h.mgr.budget.now = func() time.Time { return now }
+
+	candidate := h.createTxCandidate()
+	candidate.GasLimit = 10
+	// epoch 1: fee cap 19, max cost 190
+	tx, err := h.mgr.craftTx(context.Background(), candidate)
+	require.NoError(t, err)
+	require.Equal(t, uint64(810), h.mgr.budget.Remaining().Uint64(), "max cost is reserved")
+
+	h.mgr.settleBudget(txCost(tx), tx, &types.Receipt{GasUsed: 50, EffectiveGasPrice: big.NewInt(19)})
+	require.Equal(t, uint64(50), h.mgr.budget.Remaining().Uint64())
+
+	_, err = h.mgr.craftTx(context.Background(), candidate)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.Equal(t, 1, m.hits[limitBudget])
+
+	now = now.Add(time.Hour + time.Second)
+	_, err = h.mgr.craftTx(context.Background(), candidate)
+	require.NoError(t, err, "budget is available again in the next window")
+}
+
+func TestTxMgrSpendBudgetInFlight(t *testing.T) {
+	cfg := configWithNumConfs(1)
+	cfg.SpendBudget = big.NewInt(500)
+	cfg.SpendBudgetWindow = time.Hour
+	h, m := newLimitTestHarness(t, cfg)
+
+	candidate := h.createTxCandidate()
+	candidate.GasLimit = 10
+	// epoch 1: fee cap 19, max cost 190
+	tx0, err := h.mgr.craftTx(context.Background(), candidate)
+	require.NoError(t, err)
+	// epoch 2: fee cap 33, max cost 330, above the budget left by the in-flight tx
+	_, err = h.mgr.craftTx(context.Background(), candidate)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.Equal(t, 1, m.hits[limitBudget])
+
+	// the additional cost of a bump must fit into the remaining budget as well
+	_, err = h.mgr.increaseGasPrice(context.Background(), tx0)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.Equal(t, 2, m.hits[limitBudget])
+	require.Equal(t, uint64(310), h.mgr.budget.Remaining().Uint64())
+
+	h.mgr.settleBudget(txCost(tx0), nil, nil)
+	require.Equal(t, uint64(500), h.mgr.budget.Remaining().Uint64(), "aborted tx releases its reservation")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/urfave/cli/v2"
 )
 
@@ -31,6 +33,15 @@ const (
 	TxSendTimeoutFlagName             = "txmgr.send-timeout"
 	TxNotInMempoolTimeoutFlagName     = "txmgr.not-in-mempool-timeout"
 	ReceiptQueryIntervalFlagName      = "txmgr.receipt-query-interval"
+	JournalPathFlagName               = "txmgr.journal-path"
+	JournalRecoveryFlagName           = "txmgr.journal-recovery"
+	MaxFeeCapFlagName                 = "txmgr.max-fee-cap-gwei"
+	MaxTipCapFlagName                 = "txmgr.max-tip-cap-gwei"
+	SpendBudgetFlagName               = "txmgr.spend-budget-gwei"
+	SpendBudgetWindowFlagName         = "txmgr.spend-budget-window"
+	BroadcastRPCsFlagName             = "txmgr.broadcast-rpcs"
+	PrivateRPCsFlagName               = "txmgr.private-rpcs"
+	PrivateOnlyFlagName               = "txmgr.private-only"
 )
 
 var (
@@ -56,6 +67,8 @@ var (
 	defaultTxSendTimeout             = 0 * time.Second
 	defaultTxNotInMempoolTimeout     = 2 * time.Minute
 	defaultReceiptQueryInterval      = 12 * time.Second
+	defaultJournalRecovery           = JournalRecoveryResume
+	defaultSpendBudgetWindow         = 24 * time.Hour
 )
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -120,6 +133,54 @@ func CLIFlags(envPrefix string) []cli.Flag {
 			Value:   defaultReceiptQueryInterval,
 			EnvVars: prefixEnvVars("TXMGR_RECEIPT_QUERY_INTERVAL"),
 		},
+		&cli.StringFlag{
+			Name:    JournalPathFlagName,
+			Usage:   "Path of the file that in-flight transactions are journaled to, to recover them after a restart. If empty, the journal is disabled.",
+			EnvVars: prefixEnvVars("TXMGR_JOURNAL_PATH"),
+		},
+		&cli.StringFlag{
+			Name: JournalRecoveryFlagName,
+			Usage: "How journaled transactions are recovered on startup: " +
+				"'resume' rebroadcasts them and bumps their fees until they confirm, 'cancel' replaces them with self-transfers.",
+			Value:   defaultJournalRecovery,
+			EnvVars: prefixEnvVars("TXMGR_JOURNAL_RECOVERY"),
+		},
+		&cli.Float64Flag{
+			Name:    MaxFeeCapFlagName,
+			Usage:   "Maximum fee cap of transactions in gwei, may be fractional. Fees are capped at it, and sending fails with a max fee cap exceeded error while the L1 base fee is above it. 0 to disable.",
+			EnvVars: prefixEnvVars("TXMGR_MAX_FEE_CAP_GWEI"),
+		},
+		&cli.Float64Flag{
+			Name:    MaxTipCapFlagName,
+			Usage:   "Maximum tip cap of transactions in gwei, may be fractional. 0 to disable.",
+			EnvVars: prefixEnvVars("TXMGR_MAX_TIP_CAP_GWEI"),
+		},
+		&cli.Uint64Flag{
+			Name:    SpendBudgetFlagName,
+			Usage:   "Maximum fees in gwei that confirmed transactions may spend per spend budget window. 0 to disable.",
+			EnvVars: prefixEnvVars("TXMGR_SPEND_BUDGET_GWEI"),
+		},
+		&cli.DurationFlag{
+			Name:    SpendBudgetWindowFlagName,
+			Usage:   "Rolling time window of the spend budget",
+			Value:   defaultSpendBudgetWindow,
+			EnvVars: prefixEnvVars("TXMGR_SPEND_BUDGET_WINDOW"),
+		},
+		&cli.StringSliceFlag{
+			Name:    BroadcastRPCsFlagName,
+			Usage:   "Comma separated list of additional L1 RPC endpoints that transactions are broadcast to. Receipts are only queried from the L1 RPC.",
+			EnvVars: prefixEnvVars("TXMGR_BROADCAST_RPCS"),
+		},
+		&cli.StringSliceFlag{
+			Name:    PrivateRPCsFlagName,
+			Usage:   "Comma separated list of private transaction submission endpoints (eth_sendRawTransaction) that transactions are broadcast to.",
+			EnvVars: prefixEnvVars("TXMGR_PRIVATE_RPCS"),
+		},
+		&cli.BoolFlag{
+			Name:    PrivateOnlyFlagName,
+			Usage:   "Only submit transactions to the private endpoints, and not to the L1 RPC and the broadcast endpoints.",
+			EnvVars: prefixEnvVars("TXMGR_PRIVATE_ONLY"),
+		},
 	}, client.CLIFlags(envPrefix)...)
 }
 
@@ -138,6 +199,15 @@ type CLIConfig struct {
 	NetworkTimeout            time.Duration
 	TxSendTimeout             time.Duration
 	TxNotInMempoolTimeout     time.Duration
+	JournalPath               string
+	JournalRecovery           string
+	MaxFeeCapGwei             float64
+	MaxTipCapGwei             float64
+	SpendBudgetGwei           uint64
+	SpendBudgetWindow         time.Duration
+	BroadcastRPCs             []string
+	PrivateRPCs               []string
+	PrivateOnly               bool
 }
 
 func NewCLIConfig(l1RPCURL string) CLIConfig {
@@ -150,6 +220,8 @@ func NewCLIConfig(l1RPCURL string) CLIConfig {
 		TxSendTimeout:             defaultTxSendTimeout,
 		TxNotInMempoolTimeout:     defaultTxNotInMempoolTimeout,
 		ReceiptQueryInterval:      defaultReceiptQueryInterval,
+		JournalRecovery:           defaultJournalRecovery,
+		SpendBudgetWindow:         defaultSpendBudgetWindow,
 		SignerCLIConfig:           client.NewCLIConfig(),
 	}
 }
@@ -176,6 +248,24 @@ func (m CLIConfig) Check() error {
 	if m.SafeAbortNonceTooLowCount == 0 {
 		return errors.New("SafeAbortNonceTooLowCount must not be 0")
 	}
+	if m.JournalPath != "" && m.JournalRecovery != JournalRecoveryResume && m.JournalRecovery != JournalRecoveryCancel {
+		return fmt.Errorf("unknown journal recovery %q, must be %q or %q", m.JournalRecovery, JournalRecoveryResume, JournalRecoveryCancel)
+	}
+	if !validGwei(m.MaxFeeCapGwei) {
+		return fmt.Errorf("invalid max fee cap %v gwei", m.MaxFeeCapGwei)
+	}
+	if !validGwei(m.MaxTipCapGwei) {
+		return fmt.Errorf("invalid max tip cap %v gwei", m.MaxTipCapGwei)
+	}
+	if m.MaxFeeCapGwei != 0 && m.MaxTipCapGwei > m.MaxFeeCapGwei {
+		return fmt.Errorf("max tip cap %v gwei exceeds the max fee cap %v gwei", m.MaxTipCapGwei, m.MaxFeeCapGwei)
+	}
+	if m.SpendBudgetGwei != 0 && m.SpendBudgetWindow == 0 {
+		return errors.New("must provide SpendBudgetWindow with a spend budget")
+	}
+	if m.PrivateOnly && len(m.PrivateRPCs) == 0 {
+		return errors.New("must provide private RPCs for private only submission")
+	}
 	if err := m.SignerCLIConfig.Check(); err != nil {
 		return err
 	}
@@ -198,6 +288,15 @@ func ReadCLIConfig(ctx *cli.Context) CLIConfig {
 		NetworkTimeout:            ctx.Duration(NetworkTimeoutFlagName),
 		TxSendTimeout:             ctx.Duration(TxSendTimeoutFlagName),
 		TxNotInMempoolTimeout:     ctx.Duration(TxNotInMempoolTimeoutFlagName),
+		JournalPath:               ctx.String(JournalPathFlagName),
+		JournalRecovery:           ctx.String(JournalRecoveryFlagName),
+		MaxFeeCapGwei:             ctx.Float64(MaxFeeCapFlagName),
+		MaxTipCapGwei:             ctx.Float64(MaxTipCapFlagName),
+		SpendBudgetGwei:           ctx.Uint64(SpendBudgetFlagName),
+		SpendBudgetWindow:         ctx.Duration(SpendBudgetWindowFlagName),
+		BroadcastRPCs:             ctx.StringSlice(BroadcastRPCsFlagName),
+		PrivateRPCs:               ctx.StringSlice(PrivateRPCsFlagName),
+		PrivateOnly:               ctx.Bool(PrivateOnlyFlagName),
 	}
 }
 
@@ -228,6 +327,18 @@ func NewConfig(cfg CLIConfig, l log.Logger) (Config, error) {
 		hdPath = cfg.L2OutputHDPath
 	}
 
+	names := map[string]bool{primaryEndpoint: true}
+	ctx, cancel = context.WithTimeout(context.Background(), cfg.NetworkTimeout)
+	defer cancel()
+	broadcastEndpoints, err := dialBroadcastEndpoints(ctx, cfg.BroadcastRPCs, false, names)
+	if err != nil {
+		return Config{}, err
+	}
+	privateEndpoints, err := dialBroadcastEndpoints(ctx, cfg.PrivateRPCs, true, names)
+	if err != nil {
+		return Config{}, err
+	}
+
 	signerFactory, from, err := opcrypto.SignerFactoryFromConfig(l, cfg.PrivateKey, cfg.Mnemonic, hdPath, cfg.SignerCLIConfig)
 	if err != nil {
 		return Config{}, fmt.Errorf("could not init signer: %w", err)
@@ -245,9 +356,42 @@ func NewConfig(cfg CLIConfig, l log.Logger) (Config, error) {
 		SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
 		Signer:                    signerFactory(chainID),
 		From:                      from,
+		JournalPath:               cfg.JournalPath,
+		JournalRecovery:           cfg.JournalRecovery,
+		MaxGasFeeCap:              floatGweiToWei(cfg.MaxFeeCapGwei),
+		MaxGasTipCap:              floatGweiToWei(cfg.MaxTipCapGwei),
+		SpendBudget:               gweiToWei(cfg.SpendBudgetGwei),
+		SpendBudgetWindow:         cfg.SpendBudgetWindow,
+		BroadcastEndpoints:        append(broadcastEndpoints, privateEndpoints...),
+		PrivateOnly:               cfg.PrivateOnly,
 	}, nil
 }
 
+// gweiToWei converts an amount in gwei to wei. It returns nil for 0, which disables a limit.
+func gweiToWei(gwei uint64) *big.Int {
+	if gwei == 0 {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gwei), big.NewInt(params.GWei))
+}
+
+// validGwei checks that a fractional gwei amount is a finite, non-negative number.
+func validGwei(gwei float64) bool {
+	return gwei >= 0 && !math.IsInf(gwei, 1)
+}
+
+// floatGweiToWei converts a fractional amount in gwei to wei, rounded to the nearest wei.
+// It returns nil for 0, which disables a limit. The amount must be valid, see validGwei.
+func floatGweiToWei(gwei float64) *big.Int {
+	if gwei == 0 {
+		return nil
+	}
+	wei := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei))
+	wei.Add(wei, big.NewFloat(0.5))
+	out, _ := wei.Int(nil)
+	return out
+}
+
 // Config houses parameters for altering the behavior of a SimpleTxManager.
 type Config struct {
 	Backend ETHBackend
@@ -289,4 +433,31 @@ type Config struct {
 	// Signer is used to sign transactions when the gas price is increased.
 	Signer opcrypto.SignerFn
 	From   common.Address
+
+	// JournalPath is the path of the file that in-flight transactions are journaled to.
+	// If empty, in-flight transactions are not journaled.
+	JournalPath string
+
+	// JournalRecovery is how journaled transactions are recovered on startup,
+	// either JournalRecoveryResume or JournalRecoveryCancel.
+	JournalRecovery string
+
+	// MaxGasFeeCap is the maximum fee cap of transactions in wei. Nil means unlimited.
+	MaxGasFeeCap *big.Int
+
+	// MaxGasTipCap is the maximum tip cap of transactions in wei. Nil means unlimited.
+	MaxGasTipCap *big.Int
+
+	// SpendBudget is the maximum amount of fees in wei that confirmed transactions may
+	// spend per SpendBudgetWindow. Nil means unlimited.
+	SpendBudget *big.Int
+
+	// SpendBudgetWindow is the rolling time window of the SpendBudget.
+	SpendBudgetWindow time.Duration
+
+	// BroadcastEndpoints are the endpoints that transactions are broadcast to, in addition to the Backend.
+	BroadcastEndpoints []BroadcastEndpoint
+
+	// PrivateOnly restricts the submission of transactions to the private BroadcastEndpoints.
+	PrivateOnly bool
 }
@@ -1,6 +1,8 @@
 package txmgr
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,24 @@ func TestDefaultConfigIsValid(t *testing.T) {
 	require.NoError(t, cfg.Check())
 }
 
+func TestFeeCapFlags(t *testing.T) {
+	cfg := configForArgs("--txmgr.max-fee-cap-gwei=150.5", "--txmgr.max-tip-cap-gwei=0.3")
+	require.Equal(t, 150.5, cfg.MaxFeeCapGwei)
+	require.Equal(t, 0.3, cfg.MaxTipCapGwei)
+	require.NoError(t, cfg.Check())
+	require.Equal(t, big.NewInt(150_500_000_000), floatGweiToWei(cfg.MaxFeeCapGwei))
+	require.Equal(t, big.NewInt(300_000_000), floatGweiToWei(cfg.MaxTipCapGwei), "sub-gwei tip cap")
+	require.Nil(t, floatGweiToWei(0), "0 disables the cap")
+
+	cfg.MaxTipCapGwei = 151
+	require.ErrorContains(t, cfg.Check(), "exceeds the max fee cap")
+	cfg.MaxTipCapGwei = -1
+	require.ErrorContains(t, cfg.Check(), "invalid max tip cap")
+	cfg.MaxTipCapGwei = 0
+	cfg.MaxFeeCapGwei = math.NaN()
+	require.ErrorContains(t, cfg.Check(), "invalid max fee cap")
+}
+
 func configForArgs(args ...string) CLIConfig {
 	app := cli.NewApp()
 	// txmgr expects the --l1-eth-rpc option to be declared externally
@@ -36,6 +56,6 @@ func configForArgs(args ...string) CLIConfig {
 		config = ReadCLIConfig(ctx)
 		return nil
 	}
-	_ = app.Run(args)
+	_ = app.Run(append([]string{"test"}, args...))
 	return config
 }
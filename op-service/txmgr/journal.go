@@ -0,0 +1,325 @@
+package txmgr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// JournalRecoveryResume rebroadcasts journaled transactions on startup, and bumps their fees until they confirm.
+	JournalRecoveryResume = "resume"
+	// JournalRecoveryCancel replaces journaled transactions on startup with self-transfers.
+	JournalRecoveryCancel = "cancel"
+)
+
+// journalTx is the persisted state of an in-flight transaction.
+// Only the signed transaction is needed to resume it, the other fields are kept for inspection.
+type journalTx struct {
+	Nonce     uint64          `json:"nonce"`
+	Hash      common.Hash     `json:"hash"`
+	GasTipCap *hexutil.Big    `json:"gasTipCap"`
+	GasFeeCap *hexutil.Big    `json:"gasFeeCap"`
+	To        *common.Address `json:"to"`
+	Data      hexutil.Bytes   `json:"data"`
+	GasLimit  uint64          `json:"gasLimit"`
+	Tx        hexutil.Bytes   `json:"tx"`
+}
+
+func newJournalTx(tx *types.Transaction) (*journalTx, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encoding tx %s: %w", tx.Hash(), err)
+	}
+	return &journalTx{
+		Nonce:     tx.Nonce(),
+		Hash:      tx.Hash(),
+		GasTipCap: (*hexutil.Big)(tx.GasTipCap()),
+		GasFeeCap: (*hexutil.Big)(tx.GasFeeCap()),
+		To:        tx.To(),
+		Data:      tx.Data(),
+		GasLimit:  tx.Gas(),
+		Tx:        raw,
+	}, nil
+}
+
+// Transaction decodes the journaled signed transaction.
+func (j *journalTx) Transaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(j.Tx); err != nil {
+		return nil, fmt.Errorf("decoding journaled tx with nonce %d: %w", j.Nonce, err)
+	}
+	if tx.Hash() != j.Hash || tx.Nonce() != j.Nonce {
+		return nil, fmt.Errorf("journaled tx with nonce %d does not match hash %s", j.Nonce, j.Hash)
+	}
+	return tx, nil
+}
+
+// txJournal persists the latest signed transaction of every in-flight nonce to a JSON file,
+// so that in-flight transactions can be recovered after a restart.
+// A nil *txJournal is a disabled journal, all of its methods are no-ops.
+type txJournal struct {
+	path string
+
+	mu  sync.Mutex
+	txs map[uint64]*journalTx
+}
+
+// openTxJournal opens the journal at the given path. It is empty if the file does not exist yet.
+func openTxJournal(path string) (*txJournal, error) {
+	j := &txJournal{path: path, txs: make(map[uint64]*journalTx)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading tx journal %s: %w", path, err)
+	}
+	var txs []*journalTx
+	if err := json.Unmarshal(data, &txs); err != nil {
+		return nil, fmt.Errorf("decoding tx journal %s: %w", path, err)
+	}
+	for _, tx := range txs {
+		j.txs[tx.Nonce] = tx
+	}
+	return j, nil
+}
+
+// Add records the transaction, replacing any other transaction with the same nonce.
+func (j *txJournal) Add(tx *types.Transaction) error {
+	if j == nil {
+		return nil
+	}
+	jtx, err := newJournalTx(tx)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.txs[tx.Nonce()] = jtx
+	return j.save()
+}
+
+// Replace records the fee bumped transaction newTx, if oldTx is still the journaled transaction of its nonce.
+// If oldTx was replaced in the meantime, e.g. by a cancellation, the journal is left as is.
+func (j *txJournal) Replace(oldTx, newTx *types.Transaction) error {
+	if j == nil {
+		return nil
+	}
+	jtx, err := newJournalTx(newTx)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if prev, ok := j.txs[oldTx.Nonce()]; !ok || prev.Hash != oldTx.Hash() {
+		return nil
+	}
+	j.txs[newTx.Nonce()] = jtx
+	return j.save()
+}
+
+// Remove removes the transaction, if it is still the journaled transaction of its nonce.
+func (j *txJournal) Remove(tx *types.Transaction) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if prev, ok := j.txs[tx.Nonce()]; !ok || prev.Hash != tx.Hash() {
+		return nil
+	}
+	delete(j.txs, tx.Nonce())
+	return j.save()
+}
+
+// RemoveBelow removes all transactions with a nonce below the given nonce,
+// and returns the removed transactions.
+func (j *txJournal) RemoveBelow(nonce uint64) ([]*journalTx, error) {
+	if j == nil {
+		return nil, nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var removed []*journalTx
+	for n, tx := range j.txs {
+		if n < nonce {
+			removed = append(removed, tx)
+			delete(j.txs, n)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	sortJournalTxs(removed)
+	return removed, j.save()
+}
+
+// Get returns the journaled transaction with the given nonce, or nil if there is none.
+func (j *txJournal) Get(nonce uint64) *journalTx {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.txs[nonce]
+}
+
+// Txs returns all journaled transactions, ordered by nonce.
+func (j *txJournal) Txs() []*journalTx {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return sortJournalTxs(j.unsortedTxs())
+}
+
+// save atomically replaces the journal file. The lock must be held.
+func (j *txJournal) save() error {
+	data, err := json.Marshal(sortJournalTxs(j.unsortedTxs()))
+	if err != nil {
+		return fmt.Errorf("encoding tx journal: %w", err)
+	}
+	tmpFile := j.path + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening tx journal temp file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("writing tx journal temp file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("syncing tx journal temp file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing tx journal temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, j.path); err != nil {
+		return fmt.Errorf("replacing tx journal: %w", err)
+	}
+	return nil
+}
+
+func (j *txJournal) unsortedTxs() []*journalTx {
+	txs := make([]*journalTx, 0, len(j.txs))
+	for _, tx := range j.txs {
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func sortJournalTxs(txs []*journalTx) []*journalTx {
+	sort.Slice(txs, func(a, b int) bool { return txs[a].Nonce < txs[b].Nonce })
+	return txs
+}
+
+// recoverJournal reconciles the journaled transactions with the chain and recovers the
+// transactions that are still in-flight. Journaled transactions below the account nonce
+// were included or replaced and are dropped. The remaining nonces are recovered in the
+// background: journaled transactions are resumed or cancelled, depending on the
+// JournalRecovery config, and nonce gaps are filled with cancellations.
+// The internal nonce is set past the recovered nonces, so that new transactions do not
+// conflict with the recovered ones.
+func (m *SimpleTxManager) recoverJournal(ctx context.Context) error {
+	cCtx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
+	defer cancel()
+	nonce, err := m.backend.NonceAt(cCtx, m.cfg.From, nil)
+	if err != nil {
+		m.metr.RPCError()
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	included, err := m.journal.RemoveBelow(nonce)
+	if err != nil {
+		return err
+	}
+	for _, jtx := range included {
+		m.l.Info("Journaled transaction was included or replaced", "nonce", jtx.Nonce, "hash", jtx.Hash)
+	}
+
+	journaled := m.journal.Txs()
+	if len(journaled) == 0 {
+		return nil
+	}
+	last := journaled[len(journaled)-1].Nonce
+	txs := make([]*types.Transaction, 0, last-nonce+1)
+	for n := nonce; n <= last; n++ {
+		var tx *types.Transaction
+		if jtx := m.journal.Get(n); jtx == nil {
+			m.l.Warn("Cancelling nonce gap in journal", "nonce", n)
+		} else if tx, err = jtx.Transaction(); err != nil {
+			m.l.Warn("Cancelling undecodable journaled transaction", "nonce", n, "err", err)
+			tx = nil
+		} else if m.cfg.JournalRecovery == JournalRecoveryCancel && !m.isCancelTx(tx) {
+			m.l.Info("Cancelling journaled transaction", "nonce", n, "hash", tx.Hash())
+			tx = nil
+		}
+		if tx == nil {
+			if tx, err = m.craftCancelTx(ctx, n); err != nil {
+				return fmt.Errorf("failed to create the cancellation tx for nonce %d: %w", n, err)
+			}
+		}
+		if err := m.journal.Add(tx); err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+	}
+
+	m.nonceLock.Lock()
+	m.nonce = &last
+	m.nonceLock.Unlock()
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		var (
+			wg     sync.WaitGroup
+			failed atomic.Bool
+		)
+		for _, tx := range txs {
+			tx := tx
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := m.recoverTx(tx); err != nil {
+					m.l.Error("Failed to recover journaled transaction", "nonce", tx.Nonce(), "err", err)
+					failed.Store(true)
+				}
+			}()
+		}
+		wg.Wait()
+		// The nonce is only reset once no recovery is in flight anymore, so that the
+		// recovered nonces are not handed out to new transactions while still in use.
+		if failed.Load() {
+			m.resetNonce()
+		}
+	}()
+	return nil
+}
+
+// recoverTx sends the recovered transaction until it is confirmed, bumping its fees as necessary.
+// It is stopped by Close.
+func (m *SimpleTxManager) recoverTx(tx *types.Transaction) error {
+	ctx := m.ctx
+	if m.cfg.TxSendTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.TxSendTimeout)
+		defer cancel()
+	}
+	m.l.Info("Recovering journaled transaction", "nonce", tx.Nonce(), "hash", tx.Hash())
+	m.reserveSentBudget(tx)
+	receipt, err := m.sendTx(ctx, tx)
+	if err != nil {
+		return err
+	}
+	m.l.Info("Recovered journaled transaction", "nonce", tx.Nonce(), "hash", receipt.TxHash)
+	return nil
+}
@@ -0,0 +1,190 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func journalTestTx(nonce uint64, tip int64) *types.Transaction {
+	inbox := common.HexToAddress("0x42000000000000000000000000000000000000ff")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		To:        &inbox,
+		Gas:       1337,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(tip + 100),
+		Data:      []byte{0x00, 0x01, 0x02},
+	})
+}
+
+func TestTxJournalPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txs.json")
+	j, err := openTxJournal(path)
+	require.NoError(t, err)
+	require.Empty(t, j.Txs())
+
+	tx0, tx1 := journalTestTx(0, 1), journalTestTx(1, 1)
+	bumped := journalTestTx(1, 2)
+	require.NoError(t, j.Add(tx0))
+	require.NoError(t, j.Add(tx1))
+	require.NoError(t, j.Replace(tx1, bumped))
+	require.NoError(t, j.Replace(tx1, journalTestTx(1, 3)), "replacing a replaced tx is a no-op")
+	require.NoError(t, j.Remove(tx1), "removing a replaced tx is a no-op")
+
+	reopened, err := openTxJournal(path)
+	require.NoError(t, err)
+	txs := reopened.Txs()
+	require.Len(t, txs, 2)
+	for i, want := range []*types.Transaction{tx0, bumped} {
+		tx, err := txs[i].Transaction()
+		require.NoError(t, err)
+		require.Equal(t, want.Hash(), tx.Hash())
+	}
+
+	removed, err := reopened.RemoveBelow(1)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	require.Equal(t, tx0.Hash(), removed[0].Hash)
+	require.NoError(t, reopened.Remove(bumped))
+
+	reopened, err = openTxJournal(path)
+	require.NoError(t, err)
+	require.Empty(t, reopened.Txs())
+
+	var disabled *txJournal
+	require.NoError(t, disabled.Add(tx0))
+	require.Nil(t, disabled.Get(0))
+}
+
+func TestTxMgrRecoverJournal(t *testing.T) {
+	for _, recovery := range []string{JournalRecoveryResume, JournalRecoveryCancel} {
+		recovery := recovery
+		t.Run(recovery, func(t *testing.T) {
+			conf := configWithNumConfs(1)
+			conf.From = common.Address{0xaa}
+			conf.JournalRecovery = recovery
+			h := newTestHarnessWithConfig(t, conf)
+
+			var err error
+			h.mgr.journal, err = openTxJournal(filepath.Join(t.TempDir(), "txs.json"))
+			require.NoError(t, err)
+			// nonce 0 is included, nonce 1 is missing
+			journaled := []*types.Transaction{journalTestTx(0, 1), journalTestTx(2, 1), journalTestTx(3, 1)}
+			for _, tx := range journaled {
+				require.NoError(t, h.mgr.journal.Add(tx))
+			}
+			h.backend.nonce = 1
+
+			var mu sync.Mutex
+			sent := make(map[uint64]*types.Transaction)
+			h.backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if _, ok := sent[tx.Nonce()]; !ok {
+					sent[tx.Nonce()] = tx
+					txHash := tx.Hash()
+					h.backend.mine(&txHash, tx.GasFeeCap())
+				}
+				return nil
+			})
+
+			require.NoError(t, h.mgr.recoverJournal(context.Background()))
+			require.Eventually(t, func() bool {
+				return len(h.mgr.journal.Txs()) == 0
+			}, 5*time.Second, 10*time.Millisecond, "all recovered txs confirm")
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Len(t, sent, 3)
+			require.True(t, h.mgr.isCancelTx(sent[1]), "nonce gap is cancelled")
+			require.Equal(t, params.TxGas, sent[1].Gas())
+			for _, tx := range journaled[1:] {
+				if recovery == JournalRecoveryResume {
+					require.Equal(t, tx.Hash(), sent[tx.Nonce()].Hash(), "journaled tx is resumed")
+				} else {
+					require.True(t, h.mgr.isCancelTx(sent[tx.Nonce()]), "journaled tx is cancelled")
+				}
+			}
+
+			nonce, err := h.mgr.nextNonce(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, uint64(4), nonce, "new txs are sent after the recovered nonces")
+		})
+	}
+}
+
+// TestTxMgrRecoverJournalClose ensures that a failed recovery only resets the nonce once all
+// recoveries returned, and that Close stops the recoveries that are still in flight.
+func TestTxMgrRecoverJournalClose(t *testing.T) {
+	conf := configWithNumConfs(1)
+	conf.From = common.Address{0xaa}
+	conf.ResubmissionTimeout = 50 * time.Millisecond
+	conf.SafeAbortNonceTooLowCount = 1
+	h := newTestHarnessWithConfig(t, conf)
+
+	var err error
+	h.mgr.journal, err = openTxJournal(filepath.Join(t.TempDir(), "txs.json"))
+	require.NoError(t, err)
+	require.NoError(t, h.mgr.journal.Add(journalTestTx(1, 1)))
+	require.NoError(t, h.mgr.journal.Add(journalTestTx(2, 1)))
+	h.backend.nonce = 1
+	// nonce 1 fails, nonce 2 is never included
+	h.backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		if tx.Nonce() == 1 {
+			return core.ErrNonceTooLow
+		}
+		return nil
+	})
+	hasNonce := func() bool {
+		h.mgr.nonceLock.RLock()
+		defer h.mgr.nonceLock.RUnlock()
+		return h.mgr.nonce != nil
+	}
+
+	require.NoError(t, h.mgr.recoverJournal(context.Background()))
+	require.Eventually(t, func() bool {
+		return h.mgr.journal.Get(1) == nil
+	}, 5*time.Second, 10*time.Millisecond, "recovery of nonce 1 is aborted")
+	require.True(t, hasNonce(), "nonce is not reset while nonce 2 is recovered")
+
+	h.mgr.Close()
+	require.False(t, hasNonce(), "nonce is reset once all recoveries returned")
+	require.NotNil(t, h.mgr.journal.Get(2), "cancelled recovery is kept in the journal")
+}
+
+func TestTxMgrCancel(t *testing.T) {
+	conf := configWithNumConfs(1)
+	conf.From = common.Address{0xaa}
+	h := newTestHarnessWithConfig(t, conf)
+	var err error
+	h.mgr.journal, err = openTxJournal(filepath.Join(t.TempDir(), "txs.json"))
+	require.NoError(t, err)
+
+	pending := journalTestTx(5, 1000)
+	require.NoError(t, h.mgr.journal.Add(pending))
+
+	h.backend.setTxSender(func(ctx context.Context, tx *types.Transaction) error {
+		txHash := tx.Hash()
+		h.backend.mine(&txHash, tx.GasFeeCap())
+		return nil
+	})
+
+	receipt, err := h.mgr.Cancel(context.Background(), 5)
+	require.NoError(t, err)
+	require.NotEqual(t, pending.Hash(), receipt.TxHash)
+	require.Empty(t, h.mgr.journal.Txs())
+
+	// the replacement fees are bumped over the fees of the pending tx
+	require.GreaterOrEqual(t, receipt.GasUsed, calcThresholdValue(pending.GasFeeCap()).Uint64())
+}
@@ -4,10 +4,13 @@ import "github.com/ethereum/go-ethereum/core/types"
 
 type NoopTxMetrics struct{}
 
-func (*NoopTxMetrics) RecordNonce(uint64)                {}
-func (*NoopTxMetrics) RecordPendingTx(int64)             {}
-func (*NoopTxMetrics) RecordGasBumpCount(int)            {}
-func (*NoopTxMetrics) RecordTxConfirmationLatency(int64) {}
-func (*NoopTxMetrics) TxConfirmed(*types.Receipt)        {}
-func (*NoopTxMetrics) TxPublished(string)                {}
-func (*NoopTxMetrics) RPCError()                         {}
+func (*NoopTxMetrics) RecordNonce(uint64)                 {}
+func (*NoopTxMetrics) RecordPendingTx(int64)              {}
+func (*NoopTxMetrics) RecordGasBumpCount(int)             {}
+func (*NoopTxMetrics) RecordTxConfirmationLatency(int64)  {}
+func (*NoopTxMetrics) TxConfirmed(*types.Receipt)         {}
+func (*NoopTxMetrics) TxPublished(string)                 {}
+func (*NoopTxMetrics) RPCError()                          {}
+func (*NoopTxMetrics) RecordFeeLimitHit(string)           {}
+func (*NoopTxMetrics) RecordSpendBudgetRemaining(float64) {}
+func (*NoopTxMetrics) RecordBroadcast(string, string)     {}
@@ -16,6 +16,9 @@ type TxMetricer interface {
 	TxConfirmed(*types.Receipt)
 	TxPublished(string)
 	RPCError()
+	RecordFeeLimitHit(limit string)
+	RecordSpendBudgetRemaining(gwei float64)
+	RecordBroadcast(endpoint string, result string)
 }
 
 type TxMetrics struct {
@@ -30,6 +33,9 @@ type TxMetrics struct {
 	publishEvent       metrics.Event
 	confirmEvent       metrics.EventVec
 	rpcError           prometheus.Counter
+	feeLimitHits       *prometheus.CounterVec
+	budgetRemaining    prometheus.Gauge
+	broadcasts         *prometheus.CounterVec
 }
 
 func receiptStatusString(receipt *types.Receipt) string {
@@ -104,6 +110,24 @@ func MakeTxMetrics(ns string, factory metrics.Factory) TxMetrics {
 			Help:      "Temporary: Count of RPC errors (like timeouts) that have occurred",
 			Subsystem: "txmgr",
 		}),
+		feeLimitHits: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "fee_limit_hits_total",
+			Help:      "Count of transactions that hit a configured fee limit, by limit",
+			Subsystem: "txmgr",
+		}, []string{"limit"}),
+		budgetRemaining: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "spend_budget_remaining_gwei",
+			Help:      "Remaining spend budget of the current window in GWEI",
+			Subsystem: "txmgr",
+		}),
+		broadcasts: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "broadcast_total",
+			Help:      "Count of transaction submissions to each endpoint, by endpoint and result",
+			Subsystem: "txmgr",
+		}, []string{"endpoint", "result"}),
 	}
 }
 
@@ -144,3 +168,15 @@ func (t *TxMetrics) TxPublished(errString string) {
 func (t *TxMetrics) RPCError() {
 	t.rpcError.Inc()
 }
+
+func (t *TxMetrics) RecordFeeLimitHit(limit string) {
+	t.feeLimitHits.WithLabelValues(limit).Inc()
+}
+
+func (t *TxMetrics) RecordSpendBudgetRemaining(gwei float64) {
+	t.budgetRemaining.Set(gwei)
+}
+
+func (t *TxMetrics) RecordBroadcast(endpoint string, result string) {
+	t.broadcasts.WithLabelValues(endpoint, result).Inc()
+}
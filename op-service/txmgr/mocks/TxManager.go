@@ -72,6 +72,37 @@ func (_m *TxManager) Call(ctx context.Context, msg ethereum.CallMsg, blockNumber
 	return r0, r1
 }
 
+// Cancel provides a mock function with given fields: ctx, nonce
+func (_m *TxManager) Cancel(ctx context.Context, nonce uint64) (*types.Receipt, error) {
+	ret := _m.Called(ctx, nonce)
+
+	var r0 *types.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*types.Receipt, error)); ok {
+		return rf(ctx, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *types.Receipt); ok {
+		r0 = rf(ctx, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Close provides a mock function with given fields:
+func (_m *TxManager) Close() {
+	_m.Called()
+}
+
 // From provides a mock function with given fields:
 func (_m *TxManager) From() common.Address {
 	ret := _m.Called()
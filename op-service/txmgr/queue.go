@@ -2,7 +2,6 @@ package txmgr
 
 import (
 	"context"
-	"math"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/core/types"
@@ -19,12 +18,16 @@ type TxReceipt[T any] struct {
 }
 
 type Queue[T any] struct {
-	ctx        context.Context
-	txMgr      TxManager
-	maxPending uint64
-	groupLock  sync.Mutex
-	groupCtx   context.Context
-	group      *errgroup.Group
+	ctx   context.Context
+	txMgr TxManager
+	// pendingCond is signaled when a pending tx completes or the limit changes
+	pendingLock sync.Mutex
+	pendingCond *sync.Cond
+	maxPending  uint64
+	pending     uint64
+	groupLock   sync.Mutex
+	groupCtx    context.Context
+	group       *errgroup.Group
 }
 
 // NewQueue creates a new transaction sending Queue, with the following parameters:
@@ -32,15 +35,23 @@ type Queue[T any] struct {
 //   - pendingChanged: called whenever a tx send starts or finishes. The
 //     number of currently pending txs is passed as a parameter.
 func NewQueue[T any](ctx context.Context, txMgr TxManager, maxPending uint64) *Queue[T] {
-	if maxPending > math.MaxInt {
-		// ensure we don't overflow as errgroup only accepts int; in reality this will never be an issue
-		maxPending = math.MaxInt
-	}
-	return &Queue[T]{
+	q := &Queue[T]{
 		ctx:        ctx,
 		txMgr:      txMgr,
 		maxPending: maxPending,
 	}
+	q.pendingCond = sync.NewCond(&q.pendingLock)
+	return q
+}
+
+// SetMaxPending changes the max number of pending txs at once (0 == no limit).
+// The new limit applies right away. If it is lowered below the number of
+// currently pending txs, no new tx is sent until enough of them completed.
+func (q *Queue[T]) SetMaxPending(maxPending uint64) {
+	q.pendingLock.Lock()
+	defer q.pendingLock.Unlock()
+	q.maxPending = maxPending
+	q.pendingCond.Broadcast()
 }
 
 // Wait waits for all pending txs to complete (or fail).
@@ -58,10 +69,15 @@ func (q *Queue[T]) Wait() {
 // provided receipt channel. If the channel is unbuffered, the goroutine is
 // blocked from completing until the channel is read from.
 func (q *Queue[T]) Send(id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) {
+	// the group is taken before waiting, so that the tx fails if a tx that it waited for failed
 	group, ctx := q.groupContext()
-	group.Go(func() error {
-		return q.sendTx(ctx, id, candidate, receiptCh)
-	})
+	q.pendingLock.Lock()
+	for q.maxPending > 0 && q.pending >= q.maxPending {
+		q.pendingCond.Wait()
+	}
+	q.pending++
+	q.pendingLock.Unlock()
+	q.send(group, ctx, id, candidate, receiptCh)
 }
 
 // TrySend sends the next tx, but only if the number of pending txs is below the
@@ -75,11 +91,33 @@ func (q *Queue[T]) Send(id T, candidate TxCandidate, receiptCh chan TxReceipt[T]
 // blocked from completing until the channel is read from.
 func (q *Queue[T]) TrySend(id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) bool {
 	group, ctx := q.groupContext()
-	return group.TryGo(func() error {
+	q.pendingLock.Lock()
+	if q.maxPending > 0 && q.pending >= q.maxPending {
+		q.pendingLock.Unlock()
+		return false
+	}
+	q.pending++
+	q.pendingLock.Unlock()
+	q.send(group, ctx, id, candidate, receiptCh)
+	return true
+}
+
+// send sends the tx in the group, after a pending slot was taken for it.
+// The slot is released once the receipt was handed to the receipt channel.
+func (q *Queue[T]) send(group *errgroup.Group, ctx context.Context, id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) {
+	group.Go(func() error {
+		defer q.release()
 		return q.sendTx(ctx, id, candidate, receiptCh)
 	})
 }
 
+func (q *Queue[T]) release() {
+	q.pendingLock.Lock()
+	defer q.pendingLock.Unlock()
+	q.pending--
+	q.pendingCond.Signal()
+}
+
 func (q *Queue[T]) sendTx(ctx context.Context, id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) error {
 	receipt, err := q.txMgr.Send(ctx, candidate)
 	receiptCh <- TxReceipt[T]{
@@ -105,9 +143,6 @@ func (q *Queue[T]) groupContext() (*errgroup.Group, context.Context) {
 			_ = q.group.Wait()
 		}
 		q.group, q.groupCtx = errgroup.WithContext(q.ctx)
-		if q.maxPending > 0 {
-			q.group.SetLimit(int(q.maxPending))
-		}
 	}
 	return q.group, q.groupCtx
 }
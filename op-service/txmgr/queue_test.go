@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
 	"testing"
 	"time"
 
@@ -183,10 +184,15 @@ func TestSend(t *testing.T) {
 			}
 
 			// track the nonces, and return any expected errors from tx sending
-			var nonces []uint64
+			var (
+				noncesLock sync.Mutex
+				nonces     []uint64
+			)
 			sendTx := func(ctx context.Context, tx *types.Transaction) error {
 				index := int(tx.Data()[0])
+				noncesLock.Lock()
 				nonces = append(nonces, tx.Nonce())
+				noncesLock.Unlock()
 				var testTx *testTx
 				if index < len(test.txs) {
 					testTx = &test.txs[index]
@@ -232,8 +238,89 @@ func TestSend(t *testing.T) {
 			now := time.Now()
 			require.WithinDuration(t, now.Add(test.total), now.Add(duration), 500*time.Millisecond, "unexpected queue transaction timing")
 			// check that the nonces match
+			noncesLock.Lock()
+			defer noncesLock.Unlock()
 			slices.Sort(nonces)
 			require.Equal(t, test.nonces, nonces, "expected nonces do not match")
 		})
 	}
 }
+
+// blockingTxManager is a TxManager whose sends block until released.
+type blockingTxManager struct {
+	TxManager
+	release chan struct{}
+}
+
+func (m *blockingTxManager) Send(ctx context.Context, _ TxCandidate) (*types.Receipt, error) {
+	select {
+	case <-m.release:
+		return &types.Receipt{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestQueueSetMaxPending(t *testing.T) {
+	mgr := &blockingTxManager{release: make(chan struct{})}
+	queue := NewQueue[int](context.Background(), mgr, 1)
+	receiptCh := make(chan TxReceipt[int], 4)
+
+	require.True(t, queue.TrySend(0, TxCandidate{}, receiptCh))
+	require.False(t, queue.TrySend(1, TxCandidate{}, receiptCh))
+
+	queue.SetMaxPending(2)
+	require.True(t, queue.TrySend(1, TxCandidate{}, receiptCh), "new limit applies right away")
+	require.False(t, queue.TrySend(2, TxCandidate{}, receiptCh))
+
+	queue.SetMaxPending(1)
+	mgr.release <- struct{}{}
+	require.Eventually(t, func() bool { return len(receiptCh) == 1 }, time.Second, 10*time.Millisecond)
+	require.False(t, queue.TrySend(2, TxCandidate{}, receiptCh), "lowered limit is still reached")
+	mgr.release <- struct{}{}
+	require.Eventually(t, func() bool { return queue.TrySend(2, TxCandidate{}, receiptCh) }, time.Second, 10*time.Millisecond)
+
+	queue.SetMaxPending(0)
+	require.True(t, queue.TrySend(3, TxCandidate{}, receiptCh), "no limit")
+
+	close(mgr.release)
+	queue.Wait()
+	require.Len(t, receiptCh, 4)
+}
+
+// TestQueueSetMaxPendingUnbufferedReceipts ensures that changing the limit does not wait for
+// pending txs, whose receipts may not be read from an unbuffered receipt channel yet.
+func TestQueueSetMaxPendingUnbufferedReceipts(t *testing.T) {
+	mgr := &blockingTxManager{release: make(chan struct{})}
+	queue := NewQueue[int](context.Background(), mgr, 1)
+	receiptCh := make(chan TxReceipt[int])
+
+	require.True(t, queue.TrySend(0, TxCandidate{}, receiptCh))
+	mgr.release <- struct{}{} // tx 0 completed, but its receipt is not read yet
+
+	queue.SetMaxPending(2)
+	require.True(t, queue.TrySend(1, TxCandidate{}, receiptCh))
+	require.False(t, queue.TrySend(2, TxCandidate{}, receiptCh))
+
+	sent := make(chan struct{})
+	go func() {
+		queue.Send(2, TxCandidate{}, receiptCh)
+		close(sent)
+	}()
+	select {
+	case <-sent:
+		t.Fatal("send must wait for a pending tx to complete")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.Equal(t, 0, (<-receiptCh).ID)
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("send must continue once the receipt was read")
+	}
+
+	close(mgr.release)
+	ids := []int{(<-receiptCh).ID, (<-receiptCh).ID}
+	require.ElementsMatch(t, []int{1, 2}, ids)
+	queue.Wait()
+}
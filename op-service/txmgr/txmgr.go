@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
@@ -56,6 +57,14 @@ type TxManager interface {
 
 	// BlockNumber returns the most recent block number from the underlying network.
 	BlockNumber(ctx context.Context) (uint64, error)
+
+	// Cancel replaces the pending transaction with the given nonce by a zero-value
+	// self-transfer and waits for the cancellation to be included.
+	Cancel(ctx context.Context, nonce uint64) (*types.Receipt, error)
+
+	// Close stops the background work of the transaction manager, like the recovery of
+	// journaled transactions, and waits for it to return.
+	Close()
 }
 
 // ETHBackend is the set of methods that the transaction manager uses to resubmit gas & determine
@@ -104,6 +113,16 @@ type SimpleTxManager struct {
 	nonceLock sync.RWMutex
 
 	pending atomic.Int64
+
+	// journal of in-flight transactions, nil if disabled
+	journal *txJournal
+	// budget of fees spent by confirmed transactions, nil if unlimited
+	budget *spendBudget
+
+	// lifecycle of the background work, cancelled by Close
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // NewSimpleTxManager initializes a new SimpleTxManager with the passed Config.
@@ -113,14 +132,35 @@ func NewSimpleTxManager(name string, l log.Logger, m metrics.TxMetricer, cfg CLI
 		return nil, err
 	}
 
-	return &SimpleTxManager{
+	mgr := &SimpleTxManager{
 		chainID: conf.ChainID,
 		name:    name,
 		cfg:     conf,
 		backend: conf.Backend,
 		l:       l.New("service", name),
 		metr:    m,
-	}, nil
+		budget:  newSpendBudget(conf.SpendBudget, conf.SpendBudgetWindow),
+	}
+	mgr.ctx, mgr.cancel = context.WithCancel(context.Background())
+	if conf.JournalPath != "" {
+		mgr.journal, err = openTxJournal(conf.JournalPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := mgr.recoverJournal(mgr.ctx); err != nil {
+			mgr.cancel()
+			return nil, fmt.Errorf("failed to recover tx journal: %w", err)
+		}
+	}
+	return mgr, nil
+}
+
+// Close cancels the background recovery of journaled transactions and waits for it to return.
+func (m *SimpleTxManager) Close() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
 }
 
 func (m *SimpleTxManager) From() common.Address {
@@ -176,6 +216,7 @@ func (m *SimpleTxManager) send(ctx context.Context, candidate TxCandidate) (*typ
 		ctx, cancel = context.WithTimeout(ctx, m.cfg.TxSendTimeout)
 		defer cancel()
 	}
+	// craftTx reserves the maximum cost of the tx in the spend budget, which sendTx settles
 	tx, err := retry.Do(ctx, 30, retry.Fixed(2*time.Second), func() (*types.Transaction, error) {
 		tx, err := m.craftTx(ctx, candidate)
 		if err != nil {
@@ -201,15 +242,13 @@ func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*
 		return nil, fmt.Errorf("failed to get gas price info: %w", err)
 	}
 	gasFeeCap := calcGasFeeCap(basefee, gasTipCap)
-
-	nonce, err := m.nextNonce(ctx)
+	gasTipCap, gasFeeCap, err = m.capFees(gasTipCap, gasFeeCap, basefee)
 	if err != nil {
 		return nil, err
 	}
 
 	rawTx := &types.DynamicFeeTx{
 		ChainID:   m.chainID,
-		Nonce:     nonce,
 		To:        candidate.To,
 		GasTipCap: gasTipCap,
 		GasFeeCap: gasFeeCap,
@@ -235,12 +274,145 @@ func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*
 		}
 		rawTx.Gas = gas
 	}
+	cost := txCost(types.NewTx(rawTx))
+	if err := m.reserveBudget(cost); err != nil {
+		return nil, err
+	}
+
+	// The nonce is only taken once the tx is known to be sendable, to not leave a nonce gap
+	nonce, err := m.nextNonce(ctx)
+	if err != nil {
+		m.settleBudget(cost, nil, nil)
+		return nil, err
+	}
+	rawTx.Nonce = nonce
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
+	defer cancel()
+	tx, err := m.cfg.Signer(ctx, m.cfg.From, types.NewTx(rawTx))
+	if err != nil {
+		m.settleBudget(cost, nil, nil)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// capFees caps the tip and fee cap at the configured maximums. It returns ErrFeeCapExceeded
+// if the maximum fee cap is below the base fee, because the transaction could not be included.
+func (m *SimpleTxManager) capFees(tip, feeCap, basefee *big.Int) (*big.Int, *big.Int, error) {
+	if maxTip := m.cfg.MaxGasTipCap; maxTip != nil && tip.Cmp(maxTip) > 0 {
+		m.l.Warn("tip getting capped at the max tip cap", "tip", tip, "max", maxTip)
+		m.metr.RecordFeeLimitHit(limitTipCap)
+		tip = new(big.Int).Set(maxTip)
+	}
+	if maxFee := m.cfg.MaxGasFeeCap; maxFee != nil && feeCap.Cmp(maxFee) > 0 {
+		m.metr.RecordFeeLimitHit(limitFeeCap)
+		if basefee.Cmp(maxFee) > 0 {
+			return nil, nil, fmt.Errorf("%w: basefee %v is above the max fee cap %v", ErrFeeCapExceeded, basefee, maxFee)
+		}
+		m.l.Warn("fee cap getting capped at the max fee cap", "fee_cap", feeCap, "max", maxFee)
+		feeCap = new(big.Int).Set(maxFee)
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+	}
+	return tip, feeCap, nil
+}
+
+// reserveBudget reserves the maximum cost of an in-flight transaction in the spend budget.
+// It returns ErrBudgetExceeded if the cost exceeds the remaining budget, which already
+// excludes the reservations of the other in-flight transactions.
+func (m *SimpleTxManager) reserveBudget(cost *big.Int) error {
+	if m.budget == nil {
+		return nil
+	}
+	remaining, ok := m.budget.TryReserve(cost)
+	if !ok {
+		m.metr.RecordSpendBudgetRemaining(weiToGwei(remaining))
+		m.metr.RecordFeeLimitHit(limitBudget)
+		return fmt.Errorf("%w: max tx cost %v is above the remaining budget %v", ErrBudgetExceeded, cost, remaining)
+	}
+	m.metr.RecordSpendBudgetRemaining(weiToGwei(new(big.Int).Sub(remaining, cost)))
+	return nil
+}
+
+// reserveSentBudget reserves the maximum cost of a transaction that is resent, like a
+// cancellation or a recovered transaction. It is reserved even above the remaining budget,
+// because the nonce is already in use and may be included anyway.
+func (m *SimpleTxManager) reserveSentBudget(tx *types.Transaction) {
+	if m.budget != nil {
+		m.budget.Reserve(txCost(tx))
+	}
+}
+
+// settleBudget releases the reserved cost of a transaction, and records the fees spent
+// by the confirmed transaction if the receipt is not nil.
+func (m *SimpleTxManager) settleBudget(reserved *big.Int, tx *types.Transaction, receipt *types.Receipt) {
+	if m.budget == nil {
+		return
+	}
+	var spent *big.Int
+	if receipt != nil {
+		price := receipt.EffectiveGasPrice
+		if price == nil {
+			// upper bound, if the backend does not report the effective gas price
+			price = tx.GasFeeCap()
+		}
+		spent = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price)
+	}
+	m.budget.Settle(reserved, spent)
+	m.metr.RecordSpendBudgetRemaining(weiToGwei(m.budget.Remaining()))
+}
+
+// Cancel replaces the pending transaction with the given nonce by a self-transfer
+// without value, and waits for the self-transfer to be confirmed. It returns an
+// error if the original transaction is confirmed instead.
+func (m *SimpleTxManager) Cancel(ctx context.Context, nonce uint64) (*types.Receipt, error) {
+	tx, err := m.craftCancelTx(ctx, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cancellation tx: %w", err)
+	}
+	m.l.Info("Cancelling transaction", "nonce", nonce, "hash", tx.Hash())
+	m.reserveSentBudget(tx)
+	return m.sendTx(ctx, tx)
+}
 
+// craftCancelTx creates a signed self-transfer without value with the given nonce.
+// If a transaction with the nonce is journaled, the fees are bumped over its fees so
+// that the self-transfer replaces it in the transaction pool.
+func (m *SimpleTxManager) craftCancelTx(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+	gasTipCap, basefee, err := m.suggestGasPriceCaps(ctx)
+	if err != nil {
+		m.metr.RPCError()
+		return nil, fmt.Errorf("failed to get gas price info: %w", err)
+	}
+	gasFeeCap := calcGasFeeCap(basefee, gasTipCap)
+	if prev := m.journal.Get(nonce); prev != nil {
+		gasTipCap, gasFeeCap = updateFees(prev.GasTipCap.ToInt(), prev.GasFeeCap.ToInt(), gasTipCap, basefee, m.l)
+	}
+	gasTipCap, gasFeeCap, err = m.capFees(gasTipCap, gasFeeCap, basefee)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTx := &types.DynamicFeeTx{
+		ChainID:   m.chainID,
+		Nonce:     nonce,
+		To:        &m.cfg.From,
+		Gas:       params.TxGas,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+	}
 	ctx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 	defer cancel()
 	return m.cfg.Signer(ctx, m.cfg.From, types.NewTx(rawTx))
 }
 
+// isCancelTx returns whether the transaction is a self-transfer without value or data.
+func (m *SimpleTxManager) isCancelTx(tx *types.Transaction) bool {
+	return tx.To() != nil && *tx.To() == m.cfg.From && tx.Value().Sign() == 0 && len(tx.Data()) == 0
+}
+
 // nextNonce returns a nonce to use for the next transaction. It uses
 // eth_getTransactionCount with "latest" once, and then subsequent calls simply
 // increment this number. If the transaction manager is reset, it will query the
@@ -277,12 +449,21 @@ func (m *SimpleTxManager) resetNonce() {
 
 // send submits the same transaction several times with increasing gas prices as necessary.
 // It waits for the transaction to be confirmed on chain.
+// The maximum cost of the transaction must be reserved in the spend budget by the caller.
+// The additional cost of fee bumps is reserved as well, and the reservation is settled on return.
 func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	reserved := txCost(tx)
 	var wg sync.WaitGroup
 	defer wg.Wait()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// The journaled tx is only removed once it is confirmed or aborted. If sending is cancelled
+	// the tx may still be included, so it is kept to be reconciled on the next startup.
+	if err := m.journal.Add(tx); err != nil {
+		m.l.Error("Failed to journal transaction", "nonce", tx.Nonce(), "err", err)
+	}
+
 	sendState := NewSendState(m.cfg.SafeAbortNonceTooLowCount, m.cfg.TxNotInMempoolTimeout)
 	receiptChan := make(chan *types.Receipt, 1)
 	sendTxAsync := func(tx *types.Transaction) {
@@ -308,6 +489,8 @@ func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*t
 			// If we see lots of unrecoverable errors (and no pending transactions) abort sending the transaction.
 			if sendState.ShouldAbortImmediately() {
 				m.l.Warn("Aborting transaction submission")
+				m.removeJournalTx(tx)
+				m.settleBudget(reserved, nil, nil)
 				return nil, errors.New("aborted transaction sending")
 			}
 			// Increase the gas price & submit the new transaction
@@ -317,17 +500,28 @@ func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*t
 				// during the increaseGasPrice call. In some (but not all) cases increaseGasPrice
 				// will error out during gas estimation. In either case we should continue waiting
 				// rather than resubmit the tx.
+				if err == nil {
+					m.settleBudget(bumpCost(tx, newTx), nil, nil)
+				}
 				continue
 			}
+			// Any of the sent txs may be included, so the reservation covers the most expensive one
+			reserved.Add(reserved, bumpCost(tx, newTx))
+			if err := m.journal.Replace(tx, newTx); err != nil {
+				m.l.Error("Failed to journal transaction", "nonce", newTx.Nonce(), "err", err)
+			}
 			tx = newTx
 			wg.Add(1)
 			bumpCounter += 1
 			go sendTxAsync(tx)
 
 		case <-ctx.Done():
+			m.settleBudget(reserved, nil, nil)
 			return nil, ctx.Err()
 
 		case receipt := <-receiptChan:
+			m.removeJournalTx(tx)
+			m.settleBudget(reserved, tx, receipt)
 			m.metr.RecordGasBumpCount(bumpCounter)
 			m.metr.TxConfirmed(receipt)
 			return receipt, nil
@@ -335,6 +529,13 @@ func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*t
 	}
 }
 
+// removeJournalTx removes the transaction from the journal, if it is still the journaled tx of its nonce.
+func (m *SimpleTxManager) removeJournalTx(tx *types.Transaction) {
+	if err := m.journal.Remove(tx); err != nil {
+		m.l.Error("Failed to remove transaction from journal", "nonce", tx.Nonce(), "err", err)
+	}
+}
+
 // publishAndWaitForTx publishes the transaction to the transaction pool and then waits for it with [waitMined].
 // It should be called in a new go-routine. It will send the receipt to receiptChan in a non-blocking way if a receipt is found
 // for the transaction.
@@ -345,7 +546,7 @@ func (m *SimpleTxManager) publishAndWaitForTx(ctx context.Context, tx *types.Tra
 	cCtx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 	defer cancel()
 	t := time.Now()
-	err := m.backend.SendTransaction(cCtx, tx)
+	err := m.broadcast(cCtx, tx)
 	sendState.ProcessSendError(err)
 
 	// Properly log & exit if there is an error
@@ -483,6 +684,20 @@ func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transa
 		m.l.Warn("bumped fee getting capped at multiple of the implied suggested value", "bumped", bumpedFee, "suggestion", maxFee)
 		bumpedFee.Set(maxFee)
 	}
+	bumpedTip, bumpedFee, err = m.capFees(bumpedTip, bumpedFee, basefee)
+	if err != nil {
+		m.l.Warn("not bumping fees", "err", err)
+		return nil, err
+	}
+	// A replacement below the required fee bump would be rejected, so keep waiting for the current tx instead
+	if m.cfg.MaxGasFeeCap != nil && bumpedFee.Cmp(calcThresholdValue(tx.GasFeeCap())) < 0 {
+		m.l.Warn("not bumping fees, fee cap already at the max fee cap", "fee_cap", tx.GasFeeCap())
+		return nil, fmt.Errorf("%w: can not bump fee cap %v", ErrFeeCapExceeded, tx.GasFeeCap())
+	}
+	if m.cfg.MaxGasTipCap != nil && bumpedTip.Cmp(calcThresholdValue(tx.GasTipCap())) < 0 {
+		m.l.Warn("not bumping fees, tip already at the max tip cap", "tip", tx.GasTipCap())
+		return nil, fmt.Errorf("%w: can not bump tip %v", ErrTipCapExceeded, tx.GasTipCap())
+	}
 	rawTx := &types.DynamicFeeTx{
 		ChainID:    tx.ChainId(),
 		Nonce:      tx.Nonce(),
@@ -514,12 +729,19 @@ func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transa
 		m.l.Info("re-estimated gas differs", "oldgas", tx.Gas(), "newgas", gas)
 	}
 	rawTx.Gas = gas
+	// Only the additional cost over the sent tx needs to be reserved, sendTx reserved the rest
+	extra := bumpCost(tx, types.NewTx(rawTx))
+	if err := m.reserveBudget(extra); err != nil {
+		m.l.Warn("not bumping fees", "err", err)
+		return nil, err
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 	defer cancel()
 	newTx, err := m.cfg.Signer(ctx, m.cfg.From, types.NewTx(rawTx))
 	if err != nil {
 		m.l.Warn("failed to sign new transaction", "err", err)
+		m.settleBudget(extra, nil, nil)
 		return tx, nil
 	}
 	return newTx, nil
@@ -587,6 +809,20 @@ func updateFees(oldTip, oldFeeCap, newTip, newBaseFee *big.Int, lgr log.Logger)
 	}
 }
 
+// txCost returns the maximum fees that the transaction can spend.
+func txCost(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+}
+
+// bumpCost returns the maximum cost of the replacement above the maximum cost of the transaction.
+func bumpCost(tx, newTx *types.Transaction) *big.Int {
+	extra := new(big.Int).Sub(txCost(newTx), txCost(tx))
+	if extra.Sign() < 0 {
+		extra.SetUint64(0)
+	}
+	return extra
+}
+
 // calcGasFeeCap deterministically computes the recommended gas fee cap given
 // the base fee and gasTipCap. The resulting gasFeeCap is equal to:
 //
@@ -49,6 +49,8 @@ func newTestHarnessWithConfig(t *testing.T, cfg Config) *testHarness {
 		l:       testlog.Logger(t, log.LvlCrit),
 		metr:    &metrics.NoopTxMetrics{},
 	}
+	mgr.ctx, mgr.cancel = context.WithCancel(context.Background())
+	t.Cleanup(mgr.Close)
 
 	return &testHarness{
 		cfg:       cfg,
@@ -155,6 +157,9 @@ type mockBackend struct {
 
 	// minedTxs maps the hash of a mined transaction to its details.
 	minedTxs map[common.Hash]minedTxInfo
+
+	// nonce is the account nonce returned by NonceAt.
+	nonce uint64
 }
 
 // newMockBackend initializes a new mockBackend.
@@ -222,7 +227,9 @@ func (b *mockBackend) SendTransaction(ctx context.Context, tx *types.Transaction
 }
 
 func (b *mockBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
-	return 0, nil
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.nonce, nil
 }
 
 func (b *mockBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
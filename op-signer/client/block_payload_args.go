@@ -0,0 +1,51 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BlockPayloadArgs represents the arguments to sign a block payload, as gossiped by the sequencer over p2p.
+// Only the hash of the encoded payload is sent, the signer does not need the payload contents.
+type BlockPayloadArgs struct {
+	Domain        common.Hash     `json:"domain"`
+	ChainID       *hexutil.Big    `json:"chainId"`
+	PayloadHash   common.Hash     `json:"payloadHash"`
+	SenderAddress *common.Address `json:"senderAddress"`
+}
+
+// NewBlockPayloadArgs creates a BlockPayloadArgs struct from the encoded payload
+func NewBlockPayloadArgs(domain [32]byte, chainId *big.Int, encodedPayload []byte, senderAddress *common.Address) *BlockPayloadArgs {
+	return &BlockPayloadArgs{
+		Domain:        domain,
+		ChainID:       (*hexutil.Big)(chainId),
+		PayloadHash:   crypto.Keccak256Hash(encodedPayload),
+		SenderAddress: senderAddress,
+	}
+}
+
+func (args *BlockPayloadArgs) Check() error {
+	if args.ChainID == nil {
+		return errors.New("chainId not specified")
+	}
+	if args.ChainID.ToInt().BitLen() > 256 {
+		return errors.New("chainId is too large")
+	}
+	return nil
+}
+
+// Message returns the hash to sign: keccak256(domain ++ chain_id ++ payload_hash)
+func (args *BlockPayloadArgs) Message() (common.Hash, error) {
+	if err := args.Check(); err != nil {
+		return common.Hash{}, err
+	}
+	var msgInput [32 + 32 + 32]byte
+	copy(msgInput[:32], args.Domain[:])
+	args.ChainID.ToInt().FillBytes(msgInput[32:64])
+	copy(msgInput[64:], args.PayloadHash[:])
+	return crypto.Keccak256Hash(msgInput[:]), nil
+}
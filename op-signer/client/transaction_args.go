@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -51,6 +52,29 @@ func NewTransactionArgsFromTransaction(chainId *big.Int, from common.Address, tx
 	return args
 }
 
+// Check checks that the arguments specify all fields of an EIP-1559 transaction.
+func (args *TransactionArgs) Check() error {
+	if args.From == nil {
+		return errors.New("from not specified")
+	}
+	if args.ChainID == nil {
+		return errors.New("chainId not specified")
+	}
+	if args.Nonce == nil {
+		return errors.New("nonce not specified")
+	}
+	if args.Gas == nil {
+		return errors.New("gas not specified")
+	}
+	if args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil {
+		return errors.New("maxFeePerGas and maxPriorityFeePerGas must be specified")
+	}
+	if args.Data != nil && args.Input != nil && string(*args.Data) != string(*args.Input) {
+		return errors.New("both data and input specified, with different values")
+	}
+	return nil
+}
+
 // data retrieves the transaction calldata. Input field is preferred.
 func (args *TransactionArgs) data() []byte {
 	if args.Input != nil {
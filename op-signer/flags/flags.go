@@ -0,0 +1,98 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+)
+
+const EnvVarPrefix = "OP_SIGNER"
+
+const (
+	KeyBackendKeystore = "keystore"
+	KeyBackendHSM      = "hsm"
+)
+
+func prefixEnvVars(name string) []string {
+	return opservice.PrefixEnvVar(EnvVarPrefix, name)
+}
+
+var (
+	// Required Flags
+	ClientsConfigFlag = &cli.StringFlag{
+		Name:    "clients-config",
+		Usage:   "Path of the JSON file with the clients that may use the signer, and the to addresses and chain IDs each client may sign for.",
+		EnvVars: prefixEnvVars("CLIENTS_CONFIG"),
+	}
+
+	// Optional flags
+	KeyBackendFlag = &cli.StringFlag{
+		Name:    "key-backend",
+		Usage:   fmt.Sprintf("Backend that holds the signing keys: %q or %q", KeyBackendKeystore, KeyBackendHSM),
+		Value:   KeyBackendKeystore,
+		EnvVars: prefixEnvVars("KEY_BACKEND"),
+	}
+	KeystoreDirFlag = &cli.StringFlag{
+		Name:    "keystore.dir",
+		Usage:   "Directory of the encrypted keystore, for the keystore key backend",
+		EnvVars: prefixEnvVars("KEYSTORE_DIR"),
+	}
+	KeystorePasswordFileFlag = &cli.StringFlag{
+		Name:    "keystore.password-file",
+		Usage:   "Path of the file with the password that unlocks all keystore accounts",
+		EnvVars: prefixEnvVars("KEYSTORE_PASSWORD_FILE"),
+	}
+	HSMKeysDirFlag = &cli.StringFlag{
+		Name:    "hsm.soft-keys-dir",
+		Usage:   "Directory of <label>.key files with hex encoded private keys, for the software stand-in of the hsm key backend",
+		EnvVars: prefixEnvVars("HSM_SOFT_KEYS_DIR"),
+	}
+	HSMKeyLabelsFlag = &cli.StringSliceFlag{
+		Name:    "hsm.key-labels",
+		Usage:   "Comma separated labels of the hsm keys to sign with",
+		EnvVars: prefixEnvVars("HSM_KEY_LABELS"),
+	}
+	AuditLogFlag = &cli.StringFlag{
+		Name:    "audit-log",
+		Usage:   "Path of the JSON audit log of all signing requests. If empty, audit records are written to the regular log.",
+		EnvVars: prefixEnvVars("AUDIT_LOG"),
+	}
+)
+
+var requiredFlags = []cli.Flag{
+	ClientsConfigFlag,
+}
+
+var optionalFlags = []cli.Flag{
+	KeyBackendFlag,
+	KeystoreDirFlag,
+	KeystorePasswordFileFlag,
+	HSMKeysDirFlag,
+	HSMKeyLabelsFlag,
+	AuditLogFlag,
+}
+
+func init() {
+	optionalFlags = append(optionalFlags, oprpc.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, optls.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
+
+	Flags = append(requiredFlags, optionalFlags...)
+}
+
+// Flags contains the list of configuration options available to the binary.
+var Flags []cli.Flag
+
+func CheckRequired(ctx *cli.Context) error {
+	for _, f := range requiredFlags {
+		if !ctx.IsSet(f.Names()[0]) {
+			return fmt.Errorf("flag %s is required", f.Names()[0])
+		}
+	}
+	return nil
+}
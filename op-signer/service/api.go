@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+// Signer signs transactions and block payloads for the allowed clients with the keys of a key backend.
+type Signer struct {
+	log   log.Logger
+	audit log.Logger
+	keys  KeyBackend
+	auth  *authorizer
+}
+
+// NewSigner creates a signer. Every signing request is recorded in the audit log.
+func NewSigner(log log.Logger, audit log.Logger, keys KeyBackend, clients *ClientsConfig) (*Signer, error) {
+	auth, err := newAuthorizer(clients)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clients config: %w", err)
+	}
+	return &Signer{log: log, audit: audit, keys: keys, auth: auth}, nil
+}
+
+// APIs returns the eth_signTransaction and opsigner_signBlockPayload RPC APIs of the signer.
+func (s *Signer) APIs() []rpc.API {
+	return []rpc.API{
+		{Namespace: "eth", Service: &EthAPI{signer: s}},
+		{Namespace: "opsigner", Service: &OpsignerAPI{signer: s}},
+	}
+}
+
+// NewAuditLogger returns the logger of the audit log. Records are written to the JSON file
+// at the path, or to the regular log if the path is empty.
+func NewAuditLogger(path string, fallback log.Logger) (log.Logger, error) {
+	if path == "" {
+		return fallback.New("audit", true), nil
+	}
+	h, err := log.FileHandler(path, log.JSONFormat())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l := log.New()
+	l.SetHandler(h)
+	return l, nil
+}
+
+// record writes the audit record of a signing request.
+func (s *Signer) record(method string, client *clientPolicy, err error, ctx ...interface{}) {
+	name := ""
+	if client != nil {
+		name = client.name
+	}
+	ctx = append([]interface{}{"method", method, "client", name}, ctx...)
+	switch {
+	case err == nil:
+		s.audit.Info("Signed", ctx...)
+	case errors.Is(err, ErrNotAllowed):
+		s.audit.Warn("Denied signing request", append(ctx, "err", err)...)
+	default:
+		s.audit.Error("Failed signing request", append(ctx, "err", err)...)
+	}
+}
+
+// EthAPI is the eth namespace of the signer RPC.
+type EthAPI struct {
+	signer *Signer
+}
+
+// SignTransaction signs the transaction and returns it RLP encoded, if the client may sign for
+// the sender, chain ID and recipient of the transaction.
+func (api *EthAPI) SignTransaction(ctx context.Context, args client.TransactionArgs) (raw hexutil.Bytes, err error) {
+	s := api.signer
+	p, err := s.auth.Client(ctx)
+	defer func() {
+		var hash interface{}
+		if raw != nil {
+			hash = hashOf(raw)
+		}
+		s.record("eth_signTransaction", p, err,
+			"from", args.From, "to", args.To, "chain_id", args.ChainID, "nonce", args.Nonce, "tx_hash", hash)
+	}()
+	if err != nil {
+		return nil, err
+	}
+	if err := args.Check(); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+	if err := p.CheckFrom(*args.From); err != nil {
+		return nil, err
+	}
+	if err := p.CheckChainID(args.ChainID.ToInt()); err != nil {
+		return nil, err
+	}
+	if err := p.CheckTo(args.To); err != nil {
+		return nil, err
+	}
+
+	tx := args.ToTransaction()
+	txSigner := types.LatestSignerForChainID(args.ChainID.ToInt())
+	sig, err := s.keys.SignHash(*args.From, txSigner.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.WithSignature(txSigner, sig)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+func hashOf(raw hexutil.Bytes) interface{} {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil
+	}
+	return tx.Hash()
+}
+
+// OpsignerAPI is the opsigner namespace of the signer RPC.
+type OpsignerAPI struct {
+	signer *Signer
+}
+
+// SignBlockPayload signs the block payload message with the key of the sender address,
+// if the client may sign block payloads, for the sender and for the chain ID of the payload.
+func (api *OpsignerAPI) SignBlockPayload(ctx context.Context, args client.BlockPayloadArgs) (sig hexutil.Bytes, err error) {
+	s := api.signer
+	p, err := s.auth.Client(ctx)
+	defer func() {
+		s.record("opsigner_signBlockPayload", p, err,
+			"sender", args.SenderAddress, "chain_id", args.ChainID, "payload_hash", args.PayloadHash)
+	}()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckBlockPayloads(); err != nil {
+		return nil, err
+	}
+	if args.SenderAddress == nil {
+		return nil, errors.New("invalid block payload: senderAddress not specified")
+	}
+	msg, err := args.Message()
+	if err != nil {
+		return nil, fmt.Errorf("invalid block payload: %w", err)
+	}
+	if err := p.CheckFrom(*args.SenderAddress); err != nil {
+		return nil, err
+	}
+	if err := p.CheckChainID(args.ChainID.ToInt()); err != nil {
+		return nil, err
+	}
+	return s.keys.SignHash(*args.SenderAddress, msg)
+}
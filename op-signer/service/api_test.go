@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-signer/client"
+)
+
+var (
+	testInbox      = common.Address{0xff, 0x10}
+	testOracle     = common.Address{0xff, 0x20}
+	testUnknownKey = common.Address{0xaa}
+)
+
+// clientContext returns the request context of a client that authenticated with a certificate with the common name.
+func clientContext(t *testing.T, commonName string) context.Context {
+	var ctx context.Context
+	h := optls.NewPeerTLSMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	if commonName != "" {
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: commonName}}}}
+	}
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	require.NotNil(t, ctx)
+	return ctx
+}
+
+func newTestSigner(t *testing.T) (*Signer, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keys, err := NewHSMBackend(newSoftHSM(map[string]*ecdsa.PrivateKey{"key": key}), []string{"key"})
+	require.NoError(t, err)
+	l := testlog.Logger(t, log.LvlCrit)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	s, err := NewSigner(l, l, keys, &ClientsConfig{Clients: []ClientConfig{
+		{
+			Name:          "op-batcher",
+			FromAddresses: []common.Address{from, testUnknownKey},
+			ToAddresses:   []common.Address{testInbox},
+			ChainIDs:      []uint64{10},
+		},
+		{Name: "op-node", FromAddresses: []common.Address{from}, ChainIDs: []uint64{10}, SignBlockPayloads: true},
+		{Name: "op-proposer", ToAddresses: []common.Address{testOracle}, ChainIDs: []uint64{10}},
+	}})
+	require.NoError(t, err)
+	return s, key
+}
+
+func TestNewSignerInvalidClients(t *testing.T) {
+	l := testlog.Logger(t, log.LvlCrit)
+	_, err := NewSigner(l, l, nil, &ClientsConfig{Clients: []ClientConfig{{Name: "a"}, {Name: "a"}}})
+	require.Error(t, err)
+	_, err = NewSigner(l, l, nil, &ClientsConfig{Clients: []ClientConfig{{}}})
+	require.Error(t, err)
+}
+
+func TestSignTransaction(t *testing.T) {
+	s, key := newTestSigner(t)
+	api := &EthAPI{signer: s}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	txArgs := func(chainID int64, to common.Address) client.TransactionArgs {
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(chainID),
+			Nonce:     3,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(100),
+			Gas:       21_000,
+			To:        &to,
+			Data:      []byte{1, 2, 3},
+		})
+		return *client.NewTransactionArgsFromTransaction(big.NewInt(chainID), from, tx)
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		raw, err := api.SignTransaction(clientContext(t, "op-batcher"), txArgs(10, testInbox))
+		require.NoError(t, err)
+		tx := new(types.Transaction)
+		require.NoError(t, tx.UnmarshalBinary(raw))
+		sender, err := types.LatestSignerForChainID(big.NewInt(10)).Sender(tx)
+		require.NoError(t, err)
+		require.Equal(t, from, sender)
+		require.Equal(t, testInbox, *tx.To())
+		require.Equal(t, uint64(3), tx.Nonce())
+	})
+
+	t.Run("recipient not allowed", func(t *testing.T) {
+		_, err := api.SignTransaction(clientContext(t, "op-batcher"), txArgs(10, testOracle))
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("chain not allowed", func(t *testing.T) {
+		_, err := api.SignTransaction(clientContext(t, "op-batcher"), txArgs(1, testInbox))
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("contract creation", func(t *testing.T) {
+		args := txArgs(10, testInbox)
+		args.To = nil
+		_, err := api.SignTransaction(clientContext(t, "op-batcher"), args)
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("sender not allowed", func(t *testing.T) {
+		_, err := api.SignTransaction(clientContext(t, "op-proposer"), txArgs(10, testOracle))
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		_, err := api.SignTransaction(clientContext(t, "op-challenger"), txArgs(10, testInbox))
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		_, err := api.SignTransaction(clientContext(t, ""), txArgs(10, testInbox))
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		args := txArgs(10, testInbox)
+		args.From = &testUnknownKey
+		_, err := api.SignTransaction(clientContext(t, "op-batcher"), args)
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
+
+func TestSignBlockPayload(t *testing.T) {
+	s, key := newTestSigner(t)
+	api := &OpsignerAPI{signer: s}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	args := client.NewBlockPayloadArgs([32]byte{}, big.NewInt(10), []byte("payload"), &sender)
+	sig, err := api.SignBlockPayload(clientContext(t, "op-node"), *args)
+	require.NoError(t, err)
+	msg, err := args.Message()
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(msg[:], sig)
+	require.NoError(t, err)
+	require.Equal(t, sender, crypto.PubkeyToAddress(*pub))
+
+	other := client.NewBlockPayloadArgs([32]byte{}, big.NewInt(1), []byte("payload"), &sender)
+	_, err = api.SignBlockPayload(clientContext(t, "op-node"), *other)
+	require.ErrorIs(t, err, ErrNotAllowed)
+
+	_, err = api.SignBlockPayload(clientContext(t, "op-batcher"), *args)
+	require.ErrorIs(t, err, ErrNotAllowed, "client may not sign block payloads")
+
+	otherSender := client.NewBlockPayloadArgs([32]byte{}, big.NewInt(10), []byte("payload"), &testUnknownKey)
+	_, err = api.SignBlockPayload(clientContext(t, "op-node"), *otherSender)
+	require.ErrorIs(t, err, ErrNotAllowed, "sender not allowed")
+
+	_, err = api.SignBlockPayload(clientContext(t, "op-challenger"), *args)
+	require.ErrorIs(t, err, ErrNotAllowed)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+)
+
+// ErrNotAllowed is returned if a client is not allowed to make a signing request.
+var ErrNotAllowed = errors.New("not allowed")
+
+// ClientConfig is the allowlist of a client of the signer.
+type ClientConfig struct {
+	// Name is the common name or a DNS name of the TLS client certificate of the client.
+	Name string `json:"name"`
+	// FromAddresses are the keys the client may sign with, as transaction sender or block payload sender.
+	FromAddresses []common.Address `json:"fromAddresses"`
+	// ToAddresses are the transaction recipients the client may sign transactions for.
+	ToAddresses []common.Address `json:"toAddresses"`
+	// ChainIDs are the chains the client may sign transactions and block payloads for.
+	ChainIDs []uint64 `json:"chainIds"`
+	// SignBlockPayloads allows the client to sign block payloads, which is only needed by the sequencer.
+	SignBlockPayloads bool `json:"signBlockPayloads"`
+}
+
+// ClientsConfig is the allowlist of all clients of the signer.
+// Clients that are not listed can not sign anything.
+type ClientsConfig struct {
+	Clients []ClientConfig `json:"clients"`
+}
+
+// LoadClientsConfig reads the clients config from the JSON file.
+func LoadClientsConfig(path string) (*ClientsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clients config: %w", err)
+	}
+	var cfg ClientsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode clients config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+type clientPolicy struct {
+	name          string
+	from          map[common.Address]bool
+	to            map[common.Address]bool
+	chainIDs      map[uint64]bool
+	blockPayloads bool
+}
+
+// CheckFrom returns ErrNotAllowed if the client may not sign with the key of the address.
+func (p *clientPolicy) CheckFrom(from common.Address) error {
+	if !p.from[from] {
+		return fmt.Errorf("%w: client %q may not sign with %s", ErrNotAllowed, p.name, from)
+	}
+	return nil
+}
+
+// CheckBlockPayloads returns ErrNotAllowed if the client may not sign block payloads.
+func (p *clientPolicy) CheckBlockPayloads() error {
+	if !p.blockPayloads {
+		return fmt.Errorf("%w: client %q may not sign block payloads", ErrNotAllowed, p.name)
+	}
+	return nil
+}
+
+// CheckChainID returns ErrNotAllowed if the client may not sign for the chain.
+func (p *clientPolicy) CheckChainID(chainID *big.Int) error {
+	if chainID == nil || !chainID.IsUint64() || !p.chainIDs[chainID.Uint64()] {
+		return fmt.Errorf("%w: client %q may not sign for chain ID %v", ErrNotAllowed, p.name, chainID)
+	}
+	return nil
+}
+
+// CheckTo returns ErrNotAllowed if the client may not sign transactions to the recipient.
+// Contract creations are never allowed.
+func (p *clientPolicy) CheckTo(to *common.Address) error {
+	if to == nil {
+		return fmt.Errorf("%w: client %q may not sign contract creations", ErrNotAllowed, p.name)
+	}
+	if !p.to[*to] {
+		return fmt.Errorf("%w: client %q may not sign transactions to %s", ErrNotAllowed, p.name, to)
+	}
+	return nil
+}
+
+// authorizer identifies clients by their TLS client certificate, and looks up their allowlists.
+type authorizer struct {
+	clients map[string]*clientPolicy
+}
+
+func newAuthorizer(cfg *ClientsConfig) (*authorizer, error) {
+	a := &authorizer{clients: make(map[string]*clientPolicy)}
+	for _, c := range cfg.Clients {
+		if c.Name == "" {
+			return nil, errors.New("client without name")
+		}
+		if _, ok := a.clients[c.Name]; ok {
+			return nil, fmt.Errorf("duplicate client %q", c.Name)
+		}
+		p := &clientPolicy{
+			name:          c.Name,
+			from:          make(map[common.Address]bool),
+			to:            make(map[common.Address]bool),
+			chainIDs:      make(map[uint64]bool),
+			blockPayloads: c.SignBlockPayloads,
+		}
+		for _, from := range c.FromAddresses {
+			p.from[from] = true
+		}
+		for _, to := range c.ToAddresses {
+			p.to[to] = true
+		}
+		for _, id := range c.ChainIDs {
+			p.chainIDs[id] = true
+		}
+		a.clients[c.Name] = p
+	}
+	return a, nil
+}
+
+// Client returns the policy of the client of the request, based on the peer TLS certificate in the context.
+func (a *authorizer) Client(ctx context.Context) (*clientPolicy, error) {
+	cert := optls.PeerTLSInfoFromContext(ctx).LeafCertificate
+	if cert == nil {
+		return nil, fmt.Errorf("%w: no client certificate", ErrNotAllowed)
+	}
+	for _, name := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		if p, ok := a.clients[name]; ok {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown client %q", ErrNotAllowed, cert.Subject.CommonName)
+}
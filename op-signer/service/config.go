@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-signer/flags"
+)
+
+// CLIConfig is a well typed config that is parsed from the CLI params.
+type CLIConfig struct {
+	// ClientsConfig is the path of the JSON allowlist of the clients.
+	ClientsConfig string
+
+	// KeyBackend is the backend of the signing keys, flags.KeyBackendKeystore or flags.KeyBackendHSM.
+	KeyBackend string
+
+	KeystoreDir          string
+	KeystorePasswordFile string
+
+	// HSMSoftKeysDir is the key directory of the software stand-in HSM.
+	HSMSoftKeysDir string
+	HSMKeyLabels   []string
+
+	// AuditLog is the path of the audit log, empty to write audit records to the regular log.
+	AuditLog string
+
+	RPCConfig oprpc.CLIConfig
+	TLSConfig optls.CLIConfig
+	LogConfig oplog.CLIConfig
+}
+
+func (c CLIConfig) Check() error {
+	if err := c.RPCConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.LogConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.TLSConfig.Check(); err != nil {
+		return err
+	}
+	if !c.TLSConfig.TLSEnabled() {
+		return errors.New("tls must be enabled, clients are authenticated by their TLS client certificates")
+	}
+	if c.ClientsConfig == "" {
+		return errors.New("must provide a clients config")
+	}
+	switch c.KeyBackend {
+	case flags.KeyBackendKeystore:
+		if c.KeystoreDir == "" || c.KeystorePasswordFile == "" {
+			return errors.New("keystore key backend requires a keystore dir and password file")
+		}
+	case flags.KeyBackendHSM:
+		if c.HSMSoftKeysDir == "" {
+			return errors.New("hsm key backend requires a soft keys dir, no PKCS#11 module is built in")
+		}
+		if len(c.HSMKeyLabels) == 0 {
+			return errors.New("hsm key backend requires key labels")
+		}
+	default:
+		return fmt.Errorf("unknown key backend %q", c.KeyBackend)
+	}
+	return nil
+}
+
+// NewConfig parses the Config from the provided flags or environment variables.
+func NewConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		ClientsConfig:        ctx.String(flags.ClientsConfigFlag.Name),
+		KeyBackend:           ctx.String(flags.KeyBackendFlag.Name),
+		KeystoreDir:          ctx.String(flags.KeystoreDirFlag.Name),
+		KeystorePasswordFile: ctx.String(flags.KeystorePasswordFileFlag.Name),
+		HSMSoftKeysDir:       ctx.String(flags.HSMKeysDirFlag.Name),
+		HSMKeyLabels:         ctx.StringSlice(flags.HSMKeyLabelsFlag.Name),
+		AuditLog:             ctx.String(flags.AuditLogFlag.Name),
+		RPCConfig:            oprpc.ReadCLIConfig(ctx),
+		TLSConfig:            optls.ReadCLIConfig(ctx),
+		LogConfig:            oplog.ReadCLIConfig(ctx),
+	}
+}
+
+// NewKeyBackend opens the configured key backend.
+func (c CLIConfig) NewKeyBackend() (KeyBackend, error) {
+	switch c.KeyBackend {
+	case flags.KeyBackendKeystore:
+		password, err := readPassword(c.KeystorePasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeystoreBackend(c.KeystoreDir, password)
+	case flags.KeyBackendHSM:
+		hsm, err := NewSoftHSM(c.HSMSoftKeysDir)
+		if err != nil {
+			return nil, err
+		}
+		return NewHSMBackend(hsm, c.HSMKeyLabels)
+	default:
+		return nil, fmt.Errorf("unknown key backend %q", c.KeyBackend)
+	}
+}
+
+// readPassword reads the keystore password from the file, without trailing newlines.
+func readPassword(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrUnknownKey is returned if a key backend does not hold the key of the requested address.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeyBackend holds the signing keys of the signer.
+type KeyBackend interface {
+	// Addresses returns the addresses of all keys of the backend.
+	Addresses() []common.Address
+	// SignHash signs the hash with the key of the address. The signature is in the
+	// [R || S || V] format, with a recovery id V of 0 or 1.
+	SignHash(addr common.Address, hash common.Hash) ([]byte, error)
+}
+
+// keystoreBackend signs with the unlocked accounts of an encrypted keystore.
+type keystoreBackend struct {
+	ks *keystore.KeyStore
+}
+
+// NewKeystoreBackend opens the encrypted keystore in the directory and unlocks all of its accounts with the password.
+func NewKeystoreBackend(dir string, password string) (KeyBackend, error) {
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	return newKeystoreBackend(ks, password)
+}
+
+func newKeystoreBackend(ks *keystore.KeyStore, password string) (KeyBackend, error) {
+	if len(ks.Accounts()) == 0 {
+		return nil, errors.New("keystore has no accounts")
+	}
+	for _, account := range ks.Accounts() {
+		if err := ks.Unlock(account, password); err != nil {
+			return nil, fmt.Errorf("failed to unlock keystore account %s: %w", account.Address, err)
+		}
+	}
+	return &keystoreBackend{ks: ks}, nil
+}
+
+func (b *keystoreBackend) Addresses() []common.Address {
+	accs := b.ks.Accounts()
+	addrs := make([]common.Address, 0, len(accs))
+	for _, acc := range accs {
+		addrs = append(addrs, acc.Address)
+	}
+	return addrs
+}
+
+func (b *keystoreBackend) SignHash(addr common.Address, hash common.Hash) ([]byte, error) {
+	if !b.ks.HasAddress(addr) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, addr)
+	}
+	return b.ks.SignHash(accounts.Account{Address: addr}, hash[:])
+}
+
+// HSM is a PKCS#11-style hardware security module. Keys never leave the module,
+// they are looked up by label, and the module only signs digests.
+type HSM interface {
+	// FindKey returns the handle of the secp256k1 key pair with the label.
+	FindKey(label string) (KeyHandle, error)
+	// PublicKey returns the public key of the key pair.
+	PublicKey(key KeyHandle) (*ecdsa.PublicKey, error)
+	// Sign signs the digest with the private key, like the PKCS#11 CKM_ECDSA mechanism.
+	// The signature is the 64 byte [R || S], without recovery id, and S may be in the upper half of the curve order.
+	Sign(key KeyHandle, digest []byte) ([]byte, error)
+}
+
+// KeyHandle identifies a key pair in an HSM.
+type KeyHandle uint64
+
+type hsmKey struct {
+	handle KeyHandle
+	pub    *ecdsa.PublicKey
+}
+
+// hsmBackend signs with the keys of an HSM. It turns the raw ECDSA signatures of the
+// HSM into Ethereum signatures, by normalizing S and computing the recovery id.
+type hsmBackend struct {
+	hsm  HSM
+	keys map[common.Address]hsmKey
+}
+
+// NewHSMBackend creates a key backend that signs with the HSM keys with the given labels.
+func NewHSMBackend(hsm HSM, labels []string) (KeyBackend, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("no hsm key labels")
+	}
+	b := &hsmBackend{hsm: hsm, keys: make(map[common.Address]hsmKey)}
+	for _, label := range labels {
+		handle, err := hsm.FindKey(label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find hsm key %q: %w", label, err)
+		}
+		pub, err := hsm.PublicKey(handle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get public key of hsm key %q: %w", label, err)
+		}
+		b.keys[crypto.PubkeyToAddress(*pub)] = hsmKey{handle: handle, pub: pub}
+	}
+	return b, nil
+}
+
+func (b *hsmBackend) Addresses() []common.Address {
+	addrs := make([]common.Address, 0, len(b.keys))
+	for addr := range b.keys {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+func (b *hsmBackend) SignHash(addr common.Address, hash common.Hash) ([]byte, error) {
+	key, ok := b.keys[addr]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, addr)
+	}
+	rs, err := b.hsm.Sign(key.handle, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("hsm failed to sign: %w", err)
+	}
+	if len(rs) != 64 {
+		return nil, fmt.Errorf("invalid hsm signature length %d", len(rs))
+	}
+	// Ethereum only accepts signatures with S in the lower half of the curve order
+	s := new(big.Int).SetBytes(rs[32:])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+	sig := make([]byte, 65)
+	copy(sig[:32], rs[:32])
+	s.FillBytes(sig[32:64])
+	expected := crypto.FromECDSAPub(key.pub)
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.Ecrecover(hash[:], sig)
+		if err == nil && string(pub) == string(expected) {
+			return sig, nil
+		}
+	}
+	return nil, errors.New("hsm signature does not recover to the key")
+}
+
+// softHSM is a software stand-in for an HSM, that holds the keys in memory.
+// It is meant for testing and development, not for production keys.
+type softHSM struct {
+	labels map[string]KeyHandle
+	keys   []*ecdsa.PrivateKey
+}
+
+// NewSoftHSM creates a software HSM with the keys of the <label>.key files in the directory,
+// which contain hex encoded private keys.
+func NewSoftHSM(dir string) (HSM, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.key"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*ecdsa.PrivateKey)
+	for _, file := range files {
+		key, err := crypto.LoadECDSA(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load soft hsm key %s: %w", file, err)
+		}
+		keys[strings.TrimSuffix(filepath.Base(file), ".key")] = key
+	}
+	return newSoftHSM(keys), nil
+}
+
+func newSoftHSM(keys map[string]*ecdsa.PrivateKey) *softHSM {
+	h := &softHSM{labels: make(map[string]KeyHandle)}
+	for label, key := range keys {
+		h.labels[label] = KeyHandle(len(h.keys))
+		h.keys = append(h.keys, key)
+	}
+	return h
+}
+
+func (h *softHSM) FindKey(label string) (KeyHandle, error) {
+	handle, ok := h.labels[label]
+	if !ok {
+		return 0, os.ErrNotExist
+	}
+	return handle, nil
+}
+
+func (h *softHSM) key(handle KeyHandle) (*ecdsa.PrivateKey, error) {
+	if int(handle) >= len(h.keys) {
+		return nil, fmt.Errorf("invalid key handle %d", handle)
+	}
+	return h.keys[handle], nil
+}
+
+func (h *softHSM) PublicKey(handle KeyHandle) (*ecdsa.PublicKey, error) {
+	key, err := h.key(handle)
+	if err != nil {
+		return nil, err
+	}
+	return &key.PublicKey, nil
+}
+
+func (h *softHSM) Sign(handle KeyHandle, digest []byte) ([]byte, error) {
+	key, err := h.key(handle)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		return nil, err
+	}
+	// drop the recovery id, like a PKCS#11 module
+	return sig[:64], nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func requireRecovers(t *testing.T, addr common.Address, hash common.Hash, sig []byte) {
+	t.Helper()
+	require.Len(t, sig, 65)
+	require.LessOrEqual(t, sig[64], byte(1))
+	require.LessOrEqual(t, new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfN), 0, "S must be normalized")
+	pub, err := crypto.SigToPub(hash[:], sig)
+	require.NoError(t, err)
+	require.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+}
+
+func TestKeystoreBackend(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	_, err = ks.ImportECDSA(key, "password")
+	require.NoError(t, err)
+
+	_, err = newKeystoreBackend(ks, "wrong")
+	require.Error(t, err)
+
+	b, err := newKeystoreBackend(ks, "password")
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{addr}, b.Addresses())
+
+	hash := crypto.Keccak256Hash([]byte("message"))
+	sig, err := b.SignHash(addr, hash)
+	require.NoError(t, err)
+	requireRecovers(t, addr, hash, sig)
+
+	_, err = b.SignHash(common.Address{0xaa}, hash)
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+// highSHSM returns the signatures of the soft HSM with S in the upper half of the curve order,
+// like HSMs that do not normalize signatures.
+type highSHSM struct {
+	*softHSM
+}
+
+func (h highSHSM) Sign(handle KeyHandle, digest []byte) ([]byte, error) {
+	rs, err := h.softHSM.Sign(handle, digest)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(rs[32:]))
+	s.FillBytes(rs[32:])
+	return rs, nil
+}
+
+func TestHSMBackend(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	soft := newSoftHSM(map[string]*ecdsa.PrivateKey{"batcher": key})
+
+	_, err = NewHSMBackend(soft, []string{"proposer"})
+	require.Error(t, err)
+
+	for name, hsm := range map[string]HSM{"low-s": soft, "high-s": highSHSM{soft}} {
+		t.Run(name, func(t *testing.T) {
+			b, err := NewHSMBackend(hsm, []string{"batcher"})
+			require.NoError(t, err)
+			require.Equal(t, []common.Address{addr}, b.Addresses())
+
+			for i := 0; i < 8; i++ {
+				hash := crypto.Keccak256Hash([]byte{byte(i)})
+				sig, err := b.SignHash(addr, hash)
+				require.NoError(t, err)
+				requireRecovers(t, addr, hash, sig)
+			}
+
+			_, err = b.SignHash(common.Address{0xaa}, common.Hash{})
+			require.ErrorIs(t, err, ErrUnknownKey)
+		})
+	}
+}
+
+func TestSoftHSMKeysDir(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, crypto.SaveECDSA(filepath.Join(dir, "sequencer.key"), key))
+
+	hsm, err := NewSoftHSM(dir)
+	require.NoError(t, err)
+	b, err := NewHSMBackend(hsm, []string{"sequencer"})
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{crypto.PubkeyToAddress(key.PublicKey)}, b.Addresses())
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-service/opio"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-service/tls/certman"
+	"github.com/ethereum-optimism/optimism/op-signer/flags"
+)
+
+// Main is the entrypoint into the signer service. It serves the signing RPC
+// over mTLS until an interrupt is received.
+func Main(version string, cliCtx *cli.Context) error {
+	if err := flags.CheckRequired(cliCtx); err != nil {
+		return err
+	}
+	cfg := NewConfig(cliCtx)
+	if err := cfg.Check(); err != nil {
+		return fmt.Errorf("invalid CLI flags: %w", err)
+	}
+
+	l := oplog.NewLogger(cfg.LogConfig)
+	opservice.ValidateEnvVars(flags.EnvVarPrefix, flags.Flags, l)
+	l.Info("Initializing signer")
+
+	keys, err := cfg.NewKeyBackend()
+	if err != nil {
+		return fmt.Errorf("failed to open key backend: %w", err)
+	}
+	for _, addr := range keys.Addresses() {
+		l.Info("Loaded signing key", "address", addr, "backend", cfg.KeyBackend)
+	}
+	clients, err := LoadClientsConfig(cfg.ClientsConfig)
+	if err != nil {
+		return err
+	}
+	audit, err := NewAuditLogger(cfg.AuditLog, l)
+	if err != nil {
+		return err
+	}
+	signer, err := NewSigner(l, audit, keys, clients)
+	if err != nil {
+		return err
+	}
+
+	cm, err := certman.New(l, cfg.TLSConfig.TLSCert, cfg.TLSConfig.TLSKey)
+	if err != nil {
+		return fmt.Errorf("failed to read tls cert or key: %w", err)
+	}
+	if err := cm.Watch(); err != nil {
+		return fmt.Errorf("failed to start certman watcher: %w", err)
+	}
+	defer cm.Stop()
+	tlsConfig, err := newServerTLSConfig(cfg.TLSConfig, cm)
+	if err != nil {
+		return err
+	}
+
+	rpcCfg := cfg.RPCConfig
+	server := oprpc.NewServer(rpcCfg.ListenAddr, rpcCfg.ListenPort, version,
+		oprpc.WithLogger(l),
+		oprpc.WithAPIs(signer.APIs()),
+		oprpc.WithTLSConfig(&oprpc.ServerTLSConfig{Config: tlsConfig, CLIConfig: &cfg.TLSConfig}),
+	)
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("error starting RPC server: %w", err)
+	}
+	defer func() {
+		if err := server.Stop(); err != nil {
+			l.Error("failed to stop RPC server", "err", err)
+		}
+	}()
+	l.Info("Signer started", "endpoint", server.Endpoint())
+
+	opio.BlockOnInterrupts()
+	l.Info("Stopping signer")
+	return nil
+}
+
+// newServerTLSConfig creates the mTLS config of the server, that requires client
+// certificates signed by the CA. The server certificate is reloaded by the certman.
+func newServerTLSConfig(cfg optls.CLIConfig, cm *certman.CertMan) (*tls.Config, error) {
+	caCert, err := os.ReadFile(cfg.TLSCaCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls.ca: %w", err)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("no certificates in tls.ca")
+	}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS13,
+		GetCertificate: cm.GetCertificate,
+		ClientCAs:      caCertPool,
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+	}, nil
+}
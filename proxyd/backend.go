@@ -97,9 +97,16 @@ var (
 		Message:       "block is out of range",
 		HTTPErrorCode: 400,
 	}
+	ErrTooManySubscriptions = &RPCErr{
+		Code:          JSONRPCErrorInternal - 20,
+		Message:       "too many subscriptions",
+		HTTPErrorCode: 429,
+	}
 
 	ErrBackendUnexpectedJSONRPC = errors.New("backend returned an unexpected JSON-RPC response")
 
+	ErrWSClientTooSlow = errors.New("ws client does not keep up with subscription notifications")
+
 	ErrConsensusGetReceiptsCantBeBatched = errors.New("consensus_getReceipts cannot be batched")
 	ErrConsensusGetReceiptsInvalidTarget = errors.New("unsupported consensus_receipts_target")
 )
@@ -387,13 +394,21 @@ func (b *Backend) Forward(ctx context.Context, reqs []*RPCReq, isBatch bool) ([]
 }
 
 func (b *Backend) ProxyWS(clientConn *websocket.Conn, methodWhitelist *StringSet) (*WSProxier, error) {
+	backendConn, err := b.dialWS()
+	if err != nil {
+		return nil, err
+	}
+	return NewWSProxier(b, clientConn, backendConn, methodWhitelist), nil
+}
+
+func (b *Backend) dialWS() (*websocket.Conn, error) {
 	backendConn, _, err := b.dialer.Dial(b.wsURL, nil) // nolint:bodyclose
 	if err != nil {
 		return nil, wrapErr(err, "error dialing backend")
 	}
 
 	activeBackendWsConnsGauge.WithLabelValues(b.Name).Inc()
-	return NewWSProxier(b, clientConn, backendConn, methodWhitelist), nil
+	return backendConn, nil
 }
 
 // ForwardRPC makes a call directly to a backend and populate the response into `res`
@@ -663,6 +678,8 @@ type BackendGroup struct {
 	Name      string
 	Backends  []*Backend
 	Consensus *ConsensusPoller
+	// Subscriptions shares the eth_subscribe subscriptions of the WebSocket clients, if enabled
+	Subscriptions *SubscriptionMux
 }
 
 func (bg *BackendGroup) Forward(ctx context.Context, rpcReqs []*RPCReq, isBatch bool) ([]*RPCRes, error) {
@@ -781,8 +798,26 @@ func (bg *BackendGroup) Forward(ctx context.Context, rpcReqs []*RPCReq, isBatch
 }
 
 func (bg *BackendGroup) ProxyWS(ctx context.Context, clientConn *websocket.Conn, methodWhitelist *StringSet) (*WSProxier, error) {
+	if bg.Subscriptions != nil {
+		// the backend is dialed when the client sends a request that is not served by the shared subscriptions
+		proxier := NewWSProxier(nil, clientConn, nil, methodWhitelist)
+		proxier.subs = bg.Subscriptions
+		proxier.dialBackend = func() (*Backend, *websocket.Conn, error) {
+			return bg.dialWS(ctx)
+		}
+		return proxier, nil
+	}
+
+	back, backendConn, err := bg.dialWS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewWSProxier(back, clientConn, backendConn, methodWhitelist), nil
+}
+
+func (bg *BackendGroup) dialWS(ctx context.Context) (*Backend, *websocket.Conn, error) {
 	for _, back := range bg.Backends {
-		proxier, err := back.ProxyWS(clientConn, methodWhitelist)
+		backendConn, err := back.dialWS()
 		if errors.Is(err, ErrBackendOffline) {
 			log.Warn(
 				"skipping offline backend",
@@ -811,10 +846,10 @@ func (bg *BackendGroup) ProxyWS(ctx context.Context, clientConn *websocket.Conn,
 			)
 			continue
 		}
-		return proxier, nil
+		return back, backendConn, nil
 	}
 
-	return nil, ErrNoBackends
+	return nil, nil, ErrNoBackends
 }
 
 func (bg *BackendGroup) loadBalancedConsensusGroup() []*Backend {
@@ -855,6 +890,9 @@ func (bg *BackendGroup) Shutdown() {
 	if bg.Consensus != nil {
 		bg.Consensus.Shutdown()
 	}
+	if bg.Subscriptions != nil {
+		bg.Subscriptions.Close()
+	}
 }
 
 func calcBackoff(i int) time.Duration {
@@ -872,6 +910,24 @@ type WSProxier struct {
 	methodWhitelist *StringSet
 	readTimeout     time.Duration
 	writeTimeout    time.Duration
+
+	// subs serves the eth_subscribe requests that can be shared with other clients. If set,
+	// the backend connection is only dialed with dialBackend once a request is forwarded.
+	subs          *SubscriptionMux
+	dialBackend   func() (*Backend, *websocket.Conn, error)
+	subsMu        sync.Mutex
+	clientSubs    map[string]*clientSubscription
+	notifications chan []byte
+	closed        bool
+	done          chan struct{}
+}
+
+// clientSubscription is a shared subscription of the client. Notifications that arrive before
+// the eth_subscribe response was written to the client are queued until it is written.
+type clientSubscription struct {
+	key    string
+	acked  bool
+	queued [][]byte
 }
 
 func NewWSProxier(backend *Backend, clientConn, backendConn *websocket.Conn, methodWhitelist *StringSet) *WSProxier {
@@ -882,23 +938,62 @@ func NewWSProxier(backend *Backend, clientConn, backendConn *websocket.Conn, met
 		methodWhitelist: methodWhitelist,
 		readTimeout:     defaultWSReadTimeout,
 		writeTimeout:    defaultWSWriteTimeout,
+		clientSubs:      make(map[string]*clientSubscription),
+		notifications:   make(chan []byte, sharedSubscriptionClientQueueSize),
+		done:            make(chan struct{}),
 	}
 }
 
 func (w *WSProxier) Proxy(ctx context.Context) error {
-	errC := make(chan error, 2)
+	errC := make(chan error, 4)
 	go w.clientPump(ctx, errC)
-	go w.backendPump(ctx, errC)
+	if w.backendConn != nil {
+		go w.backendPump(ctx, errC)
+	}
+	if w.subs != nil {
+		go w.notificationPump(errC)
+	}
 	err := <-errC
 	w.close()
 	return err
 }
 
+func (w *WSProxier) backendName() string {
+	w.backendConnMu.Lock()
+	defer w.backendConnMu.Unlock()
+	if w.backend == nil {
+		return BackendProxyd
+	}
+	return w.backend.Name
+}
+
+// connectBackend dials the backend connection, if it is not connected yet.
+func (w *WSProxier) connectBackend(ctx context.Context, errC chan error) error {
+	w.backendConnMu.Lock()
+	defer w.backendConnMu.Unlock()
+	if w.backendConn != nil {
+		return nil
+	}
+	backend, backendConn, err := w.dialBackend()
+	if err != nil {
+		return err
+	}
+	w.backend = backend
+	w.backendConn = backendConn
+	go w.backendPump(ctx, errC)
+	return nil
+}
+
 func (w *WSProxier) clientPump(ctx context.Context, errC chan error) {
 	for {
 		// Block until we get a message.
 		msgType, msg, err := w.clientConn.ReadMessage()
 		if err != nil {
+			if w.backendName() == BackendProxyd {
+				// no backend connection to close
+				errC <- err
+				return
+			}
 			if err := w.writeBackendConn(websocket.CloseMessage, formatWSError(err)); err != nil {
 				log.Error("error writing backendConn message", "err", err)
 				errC <- err
@@ -906,7 +1001,7 @@ func (w *WSProxier) clientPump(ctx context.Context, errC chan error) {
 			}
 		}
 
-		RecordWSMessage(ctx, w.backend.Name, SourceClient)
+		RecordWSMessage(ctx, w.backendName(), SourceClient)
 
 		// Route control messages to the backend. These don't
 		// count towards the total RPC requests count.
@@ -961,7 +1056,30 @@ func (w *WSProxier) clientPump(ctx context.Context, errC chan error) {
 			continue
 		}
 
-		RecordRPCForward(ctx, w.backend.Name, req.Method, RPCRequestSourceWS)
+		if w.subs != nil {
+			handled, err := w.handleSharedSubscription(ctx, req, msgType)
+			if err != nil {
+				errC <- err
+				return
+			}
+			if handled {
+				continue
+			}
+			if err := w.connectBackend(ctx, errC); err != nil {
+				log.Error("error dialing ws backend", "auth", GetAuthCtx(ctx), "req_id", GetReqID(ctx), "err", err)
+				if errors.Is(err, ErrNoBackends) {
+					RecordUnserviceableRequest(ctx, RPCRequestSourceWS)
+				}
+				err = w.writeClientConn(msgType, mustMarshalJSON(NewRPCErrorRes(req.ID, err)))
+				if err != nil {
+					errC <- err
+					return
+				}
+				continue
+			}
+		}
+
+		RecordRPCForward(ctx, w.backendName(), req.Method, RPCRequestSourceWS)
 		log.Info(
 			"forwarded WS message to backend",
 			"method", req.Method,
@@ -1039,8 +1157,139 @@ func (w *WSProxier) backendPump(ctx context.Context, errC chan error) {
 
 func (w *WSProxier) close() {
 	w.clientConn.Close()
-	w.backendConn.Close()
-	activeBackendWsConnsGauge.WithLabelValues(w.backend.Name).Dec()
+	w.backendConnMu.Lock()
+	if w.backendConn != nil {
+		w.backendConn.Close()
+		activeBackendWsConnsGauge.WithLabelValues(w.backend.Name).Dec()
+	}
+	w.backendConnMu.Unlock()
+
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.done)
+	for id := range w.clientSubs {
+		w.subs.Unsubscribe(id)
+	}
+}
+
+// handleSharedSubscription serves eth_subscribe requests that can be shared from the shared
+// subscriptions, and eth_unsubscribe requests of shared subscriptions.
+// It returns false if the request must be forwarded to the backend.
+func (w *WSProxier) handleSharedSubscription(ctx context.Context, req *RPCReq, msgType int) (bool, error) {
+	switch req.Method {
+	case "eth_subscribe":
+		key, ok := subscriptionKey(req.Params)
+		if !ok {
+			return false, nil
+		}
+		id := newSubscriptionID()
+		w.subsMu.Lock()
+		if !w.canSubscribe(key) {
+			w.subsMu.Unlock()
+			return true, w.writeClientConn(msgType, mustMarshalJSON(NewRPCErrorRes(req.ID, ErrTooManySubscriptions)))
+		}
+		w.clientSubs[id] = &clientSubscription{key: key}
+		w.subsMu.Unlock()
+
+		if err := w.subs.Subscribe(id, req.Params, w.notify); err != nil {
+			log.Warn("error subscribing shared subscription", "auth", GetAuthCtx(ctx), "req_id", GetReqID(ctx), "err", err)
+			w.subsMu.Lock()
+			delete(w.clientSubs, id)
+			w.subsMu.Unlock()
+			return true, w.writeClientConn(msgType, mustMarshalJSON(NewRPCErrorRes(req.ID, err)))
+		}
+		RecordRPCForward(ctx, BackendProxyd, req.Method, RPCRequestSourceWS)
+		if err := w.writeClientConn(msgType, mustMarshalJSON(NewRPCRes(req.ID, id))); err != nil {
+			return true, err
+		}
+
+		w.subsMu.Lock()
+		defer w.subsMu.Unlock()
+		if w.closed {
+			w.subs.Unsubscribe(id)
+			return true, nil
+		}
+		sub := w.clientSubs[id]
+		sub.acked = true
+		for _, msg := range sub.queued {
+			if err := w.queueNotification(msg); err != nil {
+				return true, err
+			}
+		}
+		sub.queued = nil
+		return true, nil
+	case "eth_unsubscribe":
+		var p []string
+		if err := json.Unmarshal(req.Params, &p); err != nil || len(p) != 1 {
+			return false, nil
+		}
+		w.subsMu.Lock()
+		_, ok := w.clientSubs[p[0]]
+		delete(w.clientSubs, p[0])
+		w.subsMu.Unlock()
+		if !ok {
+			return false, nil
+		}
+		RecordRPCForward(ctx, BackendProxyd, req.Method, RPCRequestSourceWS)
+		return true, w.writeClientConn(msgType, mustMarshalJSON(NewRPCRes(req.ID, w.subs.Unsubscribe(p[0]))))
+	default:
+		return false, nil
+	}
+}
+
+// canSubscribe returns whether the client may subscribe the shared subscription of the key.
+// Each distinct shared subscription may need an upstream subscription, so their number is
+// limited per client. It must be called with subsMu held.
+func (w *WSProxier) canSubscribe(key string) bool {
+	keys := make(map[string]bool)
+	for _, sub := range w.clientSubs {
+		keys[sub.key] = true
+	}
+	return keys[key] || len(keys) < sharedSubscriptionMaxPerClient
+}
+
+// notify is the SubscriptionNotifier of the shared subscriptions of the client.
+func (w *WSProxier) notify(id string, msg []byte) error {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+	sub := w.clientSubs[id]
+	if sub == nil {
+		return nil
+	}
+	if !sub.acked {
+		sub.queued = append(sub.queued, msg)
+		return nil
+	}
+	return w.queueNotification(msg)
+}
+
+func (w *WSProxier) queueNotification(msg []byte) error {
+	select {
+	case w.notifications <- msg:
+		return nil
+	default:
+		// the client does not keep up with the notifications, disconnect it
+		w.clientConn.Close()
+		return ErrWSClientTooSlow
+	}
+}
+
+func (w *WSProxier) notificationPump(errC chan error) {
+	for {
+		select {
+		case msg := <-w.notifications:
+			if err := w.writeClientConn(websocket.TextMessage, msg); err != nil {
+				errC <- err
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
 }
 
 func (w *WSProxier) prepareClientMsg(msg []byte) (*RPCReq, error) {
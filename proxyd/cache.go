@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/go-redis/redis/v8"
@@ -120,12 +121,42 @@ type RPCCache interface {
 	PutRPC(ctx context.Context, req *RPCReq, res *RPCRes) error
 }
 
+// cacheFilter is implemented by method handlers that only cache some requests of a method.
+type cacheFilter interface {
+	Cacheable(req *RPCReq) bool
+}
+
+// FinalizedBlockFunc returns the highest block number that cached responses of the method may refer to.
+// It returns 0 if it is unknown, like when the backend group serving the method is not consensus aware.
+type FinalizedBlockFunc func(method string) hexutil.Uint64
+
+type RPCCacheOpt func(*rpcCache)
+
+// WithFinalizedBlocks enables caching of methods that refer to blocks by number,
+// like eth_getBlockByNumber, eth_getLogs and eth_call, up to the finalized block.
+func WithFinalizedBlocks(finalized FinalizedBlockFunc) RPCCacheOpt {
+	return func(c *rpcCache) {
+		handler := func(method string, reqBlock func(*RPCReq) (uint64, bool), resBlock func(*RPCRes) (uint64, bool)) RPCMethodHandler {
+			return &FinalizedMethodHandler{
+				cache:     c.cache,
+				finalized: func() hexutil.Uint64 { return finalized(method) },
+				reqBlock:  reqBlock,
+				resBlock:  resBlock,
+			}
+		}
+		c.handlers["eth_getBlockByNumber"] = handler("eth_getBlockByNumber", blockNumberParam(0), nil)
+		c.handlers["eth_getLogs"] = handler("eth_getLogs", logsFilterBlock, nil)
+		c.handlers["eth_getTransactionReceipt"] = handler("eth_getTransactionReceipt", nil, receiptBlock)
+		c.handlers["eth_call"] = handler("eth_call", blockNumberParam(1), nil)
+	}
+}
+
 type rpcCache struct {
 	cache    Cache
 	handlers map[string]RPCMethodHandler
 }
 
-func newRPCCache(cache Cache) RPCCache {
+func newRPCCache(cache Cache, opts ...RPCCacheOpt) RPCCache {
 	staticHandler := &StaticMethodHandler{cache: cache}
 	debugGetRawReceiptsHandler := &StaticMethodHandler{cache: cache,
 		filter: func(req *RPCReq) bool {
@@ -152,10 +183,14 @@ func newRPCCache(cache Cache) RPCCache {
 		"eth_getUncleByBlockHashAndIndex":       staticHandler,
 		"debug_getRawReceipts":                  debugGetRawReceiptsHandler,
 	}
-	return &rpcCache{
+	c := &rpcCache{
 		cache:    cache,
 		handlers: handlers,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *rpcCache) GetRPC(ctx context.Context, req *RPCReq) (*RPCRes, error) {
@@ -163,6 +198,10 @@ func (c *rpcCache) GetRPC(ctx context.Context, req *RPCReq) (*RPCRes, error) {
 	if handler == nil {
 		return nil, nil
 	}
+	// requests that can not be cached do not count towards the hit rate of the method
+	if f, ok := handler.(cacheFilter); ok && !f.Cacheable(req) {
+		return nil, nil
+	}
 	res, err := handler.GetRPCMethod(ctx, req)
 	if err != nil {
 		RecordCacheError(req.Method)
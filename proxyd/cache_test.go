@@ -2,9 +2,13 @@ package proxyd
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"testing"
 
+	"github.com/alicebob/miniredis"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/require"
 )
 
@@ -211,3 +215,81 @@ func TestRPCCacheUnsupportedMethod(t *testing.T) {
 	}
 
 }
+
+func TestRPCCacheFinalizedBlocks(t *testing.T) {
+	redisServer, err := miniredis.Run()
+	require.NoError(t, err)
+	defer redisServer.Close()
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf("127.0.0.1:%s", redisServer.Port()),
+	})
+
+	caches := []struct {
+		name  string
+		cache Cache
+	}{
+		{"memory", newMemoryCache()},
+		{"redis", newRedisCache(redisClient, "proxyd")},
+	}
+
+	ID := []byte(strconv.Itoa(1))
+	req := func(method string, params interface{}) *RPCReq {
+		return &RPCReq{JSONRPC: "2.0", Method: method, Params: mustMarshalJSON(params), ID: ID}
+	}
+	res := func(result interface{}) *RPCRes {
+		return &RPCRes{JSONRPC: "2.0", Result: result, ID: ID}
+	}
+	call := map[string]string{"to": "0x1234"}
+
+	rpcs := []struct {
+		name      string
+		req       *RPCReq
+		res       *RPCRes
+		cacheable bool
+	}{
+		{"eth_getBlockByNumber finalized", req("eth_getBlockByNumber", []interface{}{"0x64", false}), res("block"), true},
+		{"eth_getBlockByNumber unfinalized", req("eth_getBlockByNumber", []interface{}{"0x65", false}), res("block"), false},
+		{"eth_getBlockByNumber latest", req("eth_getBlockByNumber", []interface{}{"latest", false}), res("block"), false},
+		{"eth_getBlockByNumber finalized tag", req("eth_getBlockByNumber", []interface{}{"finalized", false}), res("block"), false},
+		{"eth_call finalized", req("eth_call", []interface{}{call, "0x10"}), res("0x01"), true},
+		{"eth_call unfinalized", req("eth_call", []interface{}{call, "0x100"}), res("0x01"), false},
+		{"eth_call no block", req("eth_call", []interface{}{call}), res("0x01"), false},
+		{"eth_call block hash", req("eth_call", []interface{}{call, map[string]string{"blockHash": "0xc6ef2fc5426d6ad6fd9e2a26abeab0aa2411b7ab17f30a99d3cb96aed1d1055b"}}), res("0x01"), false},
+		{"eth_getLogs finalized", req("eth_getLogs", []interface{}{map[string]string{"fromBlock": "0x1", "toBlock": "0x64"}}), res([]interface{}{}), true},
+		{"eth_getLogs unfinalized", req("eth_getLogs", []interface{}{map[string]string{"fromBlock": "0x1", "toBlock": "0x65"}}), res([]interface{}{}), false},
+		{"eth_getLogs latest", req("eth_getLogs", []interface{}{map[string]string{"fromBlock": "0x1"}}), res([]interface{}{}), false},
+		{"eth_getLogs block hash", req("eth_getLogs", []interface{}{map[string]string{"blockHash": "0xc6ef2fc5426d6ad6fd9e2a26abeab0aa2411b7ab17f30a99d3cb96aed1d1055b"}}), res([]interface{}{}), false},
+		{"eth_getTransactionReceipt finalized", req("eth_getTransactionReceipt", []string{"0x01"}), res(map[string]interface{}{"blockNumber": "0x64"}), true},
+		{"eth_getTransactionReceipt unfinalized", req("eth_getTransactionReceipt", []string{"0x02"}), res(map[string]interface{}{"blockNumber": "0x65"}), false},
+	}
+
+	for _, c := range caches {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := newRPCCache(c.cache, WithFinalizedBlocks(func(method string) hexutil.Uint64 { return 100 }))
+			for _, rpc := range rpcs {
+				t.Run(rpc.name, func(t *testing.T) {
+					require.NoError(t, cache.PutRPC(ctx, rpc.req, rpc.res))
+					cachedRes, err := cache.GetRPC(ctx, rpc.req)
+					require.NoError(t, err)
+					if rpc.cacheable {
+						require.Equal(t, rpc.res, cachedRes)
+					} else {
+						require.Nil(t, cachedRes)
+					}
+				})
+			}
+		})
+	}
+
+	t.Run("unknown finalized block", func(t *testing.T) {
+		ctx := context.Background()
+		cache := newRPCCache(newMemoryCache(), WithFinalizedBlocks(func(method string) hexutil.Uint64 { return 0 }))
+		r := req("eth_getBlockByNumber", []interface{}{"0x0", false})
+		require.NoError(t, cache.PutRPC(ctx, r, res("genesis")))
+		cachedRes, err := cache.GetRPC(ctx, r)
+		require.NoError(t, err)
+		require.Nil(t, cachedRes)
+	})
+}
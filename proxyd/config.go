@@ -144,6 +144,7 @@ type Config struct {
 	BackendGroups         BackendGroupsConfig   `toml:"backend_groups"`
 	RPCMethodMappings     map[string]string     `toml:"rpc_method_mappings"`
 	WSMethodWhitelist     []string              `toml:"ws_method_whitelist"`
+	WSSharedSubscriptions bool                  `toml:"ws_shared_subscriptions"`
 	WhitelistErrorMessage string                `toml:"whitelist_error_message"`
 	SenderRateLimit       SenderRateLimitConfig `toml:"sender_rate_limit"`
 }
@@ -0,0 +1,207 @@
+package integration_tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/proxyd"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type sharedSubsBackend struct {
+	mu         sync.Mutex
+	conns      map[*websocket.Conn]bool
+	subConn    *websocket.Conn
+	requests   map[string]int
+	unsubbedCh chan string
+}
+
+func (b *sharedSubsBackend) onMessage(conn *websocket.Conn, msgType int, data []byte) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params []interface{}   `json:"params"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conns[conn] = true
+	b.requests[req.Method]++
+	var result interface{}
+	switch req.Method {
+	case "eth_subscribe":
+		b.subConn = conn
+		result = fmt.Sprintf("0x%d", b.requests[req.Method])
+	case "eth_unsubscribe":
+		result = true
+		b.unsubbedCh <- req.Params[0].(string)
+	case "eth_chainId":
+		result = "0x420"
+	}
+	res, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	_ = conn.WriteMessage(websocket.TextMessage, res)
+}
+
+func (b *sharedSubsBackend) notify(t *testing.T, upstreamID string, result string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	msg := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"%s","result":%s}}`, upstreamID, result)
+	require.NoError(t, b.subConn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func (b *sharedSubsBackend) numRequests(method string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.requests[method]
+}
+
+func (b *sharedSubsBackend) numConns() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.conns)
+}
+
+type wsTestClient struct {
+	*ProxydWSClient
+	msgs chan map[string]interface{}
+}
+
+func newWSTestClient(t *testing.T) *wsTestClient {
+	c := &wsTestClient{msgs: make(chan map[string]interface{}, 16)}
+	client, err := NewProxydWSClient("ws://127.0.0.1:8546", func(msgType int, data []byte) {
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &msg))
+		c.msgs <- msg
+	}, nil)
+	require.NoError(t, err)
+	c.ProxydWSClient = client
+	return c
+}
+
+func (c *wsTestClient) call(t *testing.T, req string) interface{} {
+	require.NoError(t, c.WriteMessage(websocket.TextMessage, []byte(req)))
+	msg := c.next(t)
+	require.Nil(t, msg["error"])
+	return msg["result"]
+}
+
+func (c *wsTestClient) next(t *testing.T) map[string]interface{} {
+	select {
+	case msg := <-c.msgs:
+		return msg
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out")
+		return nil
+	}
+}
+
+func TestWSSharedSubscriptions(t *testing.T) {
+	backendHdlr := &sharedSubsBackend{
+		conns:      make(map[*websocket.Conn]bool),
+		requests:   make(map[string]int),
+		unsubbedCh: make(chan string, 4),
+	}
+	backend := NewMockWSBackend(nil, backendHdlr.onMessage, nil)
+	defer backend.Close()
+
+	require.NoError(t, os.Setenv("GOOD_BACKEND_RPC_URL", backend.URL()))
+
+	config := ReadConfig("ws_shared_subscriptions")
+	_, shutdown, err := proxyd.Start(config)
+	require.NoError(t, err)
+	defer shutdown()
+
+	clientA := newWSTestClient(t)
+	defer clientA.HardClose()
+	clientB := newWSTestClient(t)
+	defer clientB.HardClose()
+
+	subA := clientA.call(t, `{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["newHeads"]}`)
+	subB := clientB.call(t, `{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["newHeads"]}`)
+	require.NotEqual(t, subA, subB)
+	require.Equal(t, 1, backendHdlr.numRequests("eth_subscribe"), "identical subscriptions share one upstream subscription")
+
+	logsA := clientA.call(t, `{"jsonrpc":"2.0","id":2,"method":"eth_subscribe","params":["logs",{"address":"0x1234","topics":[]}]}`)
+	logsB := clientB.call(t, `{"jsonrpc":"2.0","id":2,"method":"eth_subscribe","params":["logs",{"topics":[],"address":"0x1234"}]}`)
+	require.NotEqual(t, logsA, logsB)
+	require.Equal(t, 2, backendHdlr.numRequests("eth_subscribe"), "identical logs filters share one upstream subscription")
+	require.Equal(t, 1, backendHdlr.numConns(), "clients do not dial the backend for shared subscriptions")
+
+	backendHdlr.notify(t, "0x1", `{"number":"0x10"}`)
+	for _, c := range []struct {
+		client *wsTestClient
+		sub    interface{}
+	}{{clientA, subA}, {clientB, subB}} {
+		msg := c.client.next(t)
+		require.Equal(t, "eth_subscription", msg["method"])
+		params := msg["params"].(map[string]interface{})
+		require.Equal(t, c.sub, params["subscription"])
+		require.Equal(t, map[string]interface{}{"number": "0x10"}, params["result"])
+	}
+
+	// requests that can not be shared are forwarded on a connection of the client
+	require.Equal(t, "0x420", clientA.call(t, `{"jsonrpc":"2.0","id":3,"method":"eth_chainId","params":[]}`))
+	require.Equal(t, 2, backendHdlr.numConns())
+
+	require.Equal(t, true, clientA.call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":4,"method":"eth_unsubscribe","params":["%s"]}`, subA)))
+	require.Zero(t, backendHdlr.numRequests("eth_unsubscribe"), "subscription is still used by another client")
+	require.Equal(t, true, clientB.call(t, fmt.Sprintf(`{"jsonrpc":"2.0","id":4,"method":"eth_unsubscribe","params":["%s"]}`, subB)))
+	select {
+	case id := <-backendHdlr.unsubbedCh:
+		require.Equal(t, "0x1", id)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out")
+	}
+
+	// the subscriptions of disconnected clients are unsubscribed
+	clientA.HardClose()
+	clientB.HardClose()
+	select {
+	case id := <-backendHdlr.unsubbedCh:
+		require.Equal(t, "0x2", id)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out")
+	}
+}
+
+func TestWSSharedSubscriptionsClientLimit(t *testing.T) {
+	backendHdlr := &sharedSubsBackend{
+		conns:      make(map[*websocket.Conn]bool),
+		requests:   make(map[string]int),
+		unsubbedCh: make(chan string, 32),
+	}
+	backend := NewMockWSBackend(nil, backendHdlr.onMessage, nil)
+	defer backend.Close()
+
+	require.NoError(t, os.Setenv("GOOD_BACKEND_RPC_URL", backend.URL()))
+
+	config := ReadConfig("ws_shared_subscriptions")
+	_, shutdown, err := proxyd.Start(config)
+	require.NoError(t, err)
+	defer shutdown()
+
+	client := newWSTestClient(t)
+	defer client.HardClose()
+
+	logs := func(i int) string {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"eth_subscribe","params":["logs",{"address":"0x%04x"}]}`, i, i)
+	}
+	// a client may have 16 distinct shared subscriptions
+	for i := 0; i < 16; i++ {
+		client.call(t, logs(i))
+	}
+	require.NoError(t, client.WriteMessage(websocket.TextMessage, []byte(logs(16))))
+	msg := client.next(t)
+	require.NotNil(t, msg["error"], "distinct subscriptions over the limit are rejected")
+	require.Equal(t, 16, backendHdlr.numRequests("eth_subscribe"))
+
+	client.call(t, logs(0))
+	require.Equal(t, 16, backendHdlr.numRequests("eth_subscribe"), "identical subscriptions are not limited")
+}
@@ -8,7 +8,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type RPCMethodHandler interface {
@@ -23,6 +26,10 @@ type StaticMethodHandler struct {
 }
 
 func (e *StaticMethodHandler) key(req *RPCReq) string {
+	return cacheKey(req)
+}
+
+func cacheKey(req *RPCReq) string {
 	// signature is the hashed json.RawMessage param contents
 	h := sha256.New()
 	h.Write(req.Params)
@@ -30,6 +37,10 @@ func (e *StaticMethodHandler) key(req *RPCReq) string {
 	return strings.Join([]string{"cache", req.Method, signature}, ":")
 }
 
+func (e *StaticMethodHandler) Cacheable(req *RPCReq) bool {
+	return e.filter == nil || e.filter(req)
+}
+
 func (e *StaticMethodHandler) GetRPCMethod(ctx context.Context, req *RPCReq) (*RPCRes, error) {
 	if e.cache == nil {
 		return nil, nil
@@ -84,3 +95,139 @@ func (e *StaticMethodHandler) PutRPCMethod(ctx context.Context, req *RPCReq, res
 	}
 	return nil
 }
+
+// FinalizedMethodHandler caches responses that only refer to blocks at or below the
+// finalized block of the backend group, which can not be reorged anymore.
+// Requests that refer to block tags like latest, or to blocks above the finalized block, are not cached.
+type FinalizedMethodHandler struct {
+	cache Cache
+	// finalized returns the highest block number that responses may refer to, 0 if unknown
+	finalized func() hexutil.Uint64
+	// reqBlock returns the highest block number the request refers to,
+	// false if the request does not refer to explicit block numbers
+	reqBlock func(*RPCReq) (uint64, bool)
+	// resBlock returns the block number the response refers to, for requests that
+	// do not refer to a block, like transaction receipts by hash
+	resBlock func(*RPCRes) (uint64, bool)
+}
+
+func (e *FinalizedMethodHandler) isFinalized(block uint64) bool {
+	finalized := e.finalized()
+	return finalized > 0 && block <= uint64(finalized)
+}
+
+func (e *FinalizedMethodHandler) Cacheable(req *RPCReq) bool {
+	if e.cache == nil {
+		return false
+	}
+	if e.reqBlock == nil {
+		return true
+	}
+	block, ok := e.reqBlock(req)
+	return ok && e.isFinalized(block)
+}
+
+func (e *FinalizedMethodHandler) GetRPCMethod(ctx context.Context, req *RPCReq) (*RPCRes, error) {
+	if !e.Cacheable(req) {
+		return nil, nil
+	}
+
+	key := cacheKey(req)
+	val, err := e.cache.Get(ctx, key)
+	if err != nil {
+		log.Error("error reading from cache", "key", key, "method", req.Method, "err", err)
+		return nil, err
+	}
+	if val == "" {
+		return nil, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		log.Error("error unmarshalling value from cache", "key", key, "method", req.Method, "err", err)
+		return nil, err
+	}
+	return &RPCRes{
+		JSONRPC: req.JSONRPC,
+		Result:  result,
+		ID:      req.ID,
+	}, nil
+}
+
+func (e *FinalizedMethodHandler) PutRPCMethod(ctx context.Context, req *RPCReq, res *RPCRes) error {
+	// the finalized block may have advanced since the request was checked, so check it again
+	if !e.Cacheable(req) {
+		return nil
+	}
+	if e.resBlock != nil {
+		block, ok := e.resBlock(res)
+		if !ok || !e.isFinalized(block) {
+			return nil
+		}
+	}
+
+	key := cacheKey(req)
+	value := mustMarshalJSON(res.Result)
+
+	err := e.cache.Put(ctx, key, string(value))
+	if err != nil {
+		log.Error("error putting into cache", "key", key, "method", req.Method, "err", err)
+		return err
+	}
+	return nil
+}
+
+// blockNumberParam returns the block number of the request parameter at the index,
+// false if the parameter is missing, a block tag or a block hash.
+func blockNumberParam(index int) func(*RPCReq) (uint64, bool) {
+	return func(req *RPCReq) (uint64, bool) {
+		var p []json.RawMessage
+		if err := json.Unmarshal(req.Params, &p); err != nil || len(p) <= index {
+			return 0, false
+		}
+		var bnh rpc.BlockNumberOrHash
+		if err := json.Unmarshal(p[index], &bnh); err != nil {
+			return 0, false
+		}
+		bn, ok := bnh.Number()
+		if !ok || bn < 0 {
+			return 0, false
+		}
+		return uint64(bn), true
+	}
+}
+
+// logsFilterBlock returns the toBlock of an eth_getLogs filter. Both fromBlock and toBlock
+// must be block numbers, filters by block hash or with block tags are not cached.
+func logsFilterBlock(req *RPCReq) (uint64, bool) {
+	var p []struct {
+		FromBlock *rpc.BlockNumber `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber `json:"toBlock"`
+		BlockHash *common.Hash     `json:"blockHash"`
+	}
+	if err := json.Unmarshal(req.Params, &p); err != nil || len(p) != 1 {
+		return 0, false
+	}
+	f := p[0]
+	if f.BlockHash != nil || f.FromBlock == nil || f.ToBlock == nil || *f.FromBlock < 0 || *f.ToBlock < 0 {
+		return 0, false
+	}
+	return uint64(*f.ToBlock), true
+}
+
+// receiptBlock returns the block number of a transaction receipt.
+func receiptBlock(res *RPCRes) (uint64, bool) {
+	receipt, ok := res.Result.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	s, ok := receipt["blockNumber"].(string)
+	if !ok {
+		return 0, false
+	}
+	block, err := hexutil.DecodeUint64(s)
+	if err != nil {
+		return 0, false
+	}
+	return block, true
+}
@@ -190,6 +190,31 @@ var (
 		"method",
 	})
 
+	sharedSubscriptionsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_shared_subscriptions",
+		Help:      "Number of upstream WS subscriptions shared by the clients of a backend group.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	sharedSubscriptionClientsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_shared_subscription_clients",
+		Help:      "Number of client WS subscriptions served by the shared subscriptions of a backend group.",
+	}, []string{
+		"backend_group_name",
+	})
+
+	sharedSubscriptionDropsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "group_shared_subscription_drops_total",
+		Help:      "Count of notifications and client subscriptions dropped by the shared subscriptions of a backend group.",
+	}, []string{
+		"backend_group_name",
+		"reason",
+	})
+
 	batchRPCShortCircuitsTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: MetricsNamespace,
 		Name:      "batch_rpc_short_circuits_total",
@@ -442,6 +467,15 @@ func RecordGroupConsensusLatestBlock(group *BackendGroup, blockNumber hexutil.Ui
 	consensusLatestBlock.WithLabelValues(group.Name).Set(float64(blockNumber))
 }
 
+func RecordGroupSharedSubscriptions(group *BackendGroup, subscriptions int, clients int) {
+	sharedSubscriptionsGauge.WithLabelValues(group.Name).Set(float64(subscriptions))
+	sharedSubscriptionClientsGauge.WithLabelValues(group.Name).Set(float64(clients))
+}
+
+func RecordSharedSubscriptionDrop(group *BackendGroup, reason string) {
+	sharedSubscriptionDropsTotal.WithLabelValues(group.Name, reason).Inc()
+}
+
 func RecordGroupConsensusSafeBlock(group *BackendGroup, blockNumber hexutil.Uint64) {
 	consensusSafeBlock.WithLabelValues(group.Name).Set(float64(blockNumber))
 }
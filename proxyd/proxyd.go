@@ -8,6 +8,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/go-redis/redis/v8"
@@ -192,6 +193,13 @@ func Start(config *Config) (*Server, func(), error) {
 		return nil, nil, fmt.Errorf("a ws port was defined, but no ws group was defined")
 	}
 
+	if config.WSSharedSubscriptions {
+		if wsBackendGroup == nil {
+			return nil, nil, fmt.Errorf("ws shared subscriptions are enabled, but no ws group was defined")
+		}
+		wsBackendGroup.Subscriptions = NewSubscriptionMux(wsBackendGroup)
+	}
+
 	for _, bg := range config.RPCMethodMappings {
 		if backendGroups[bg] == nil {
 			return nil, nil, fmt.Errorf("undefined backend group %s", bg)
@@ -222,7 +230,7 @@ func Start(config *Config) (*Server, func(), error) {
 		} else {
 			cache = newRedisCache(redisClient, config.Redis.Namespace)
 		}
-		rpcCache = newRPCCache(newCacheWithCompression(cache))
+		rpcCache = newRPCCache(newCacheWithCompression(cache), WithFinalizedBlocks(finalizedBlockFunc(config, backendGroups)))
 	}
 
 	srv, err := NewServer(
@@ -345,6 +353,19 @@ func validateReceiptsTarget(val string) (string, error) {
 	}
 }
 
+// finalizedBlockFunc returns the finalized block of the consensus of the backend group serving a method.
+// Only finalized blocks are cached, because block number responses are cached without expiry
+// and would be stale after a reorg of an unfinalized block.
+func finalizedBlockFunc(config *Config, backendGroups map[string]*BackendGroup) FinalizedBlockFunc {
+	return func(method string) hexutil.Uint64 {
+		bg := backendGroups[config.RPCMethodMappings[method]]
+		if bg == nil || bg.Consensus == nil {
+			return 0
+		}
+		return bg.Consensus.GetFinalizedBlockNumber()
+	}
+}
+
 func secondsToDuration(seconds int) time.Duration {
 	return time.Duration(seconds) * time.Second
 }
@@ -0,0 +1,646 @@
+package proxyd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	sharedSubscriptionCallTimeout     = 10 * time.Second
+	sharedSubscriptionCheckInterval   = time.Second
+	sharedSubscriptionFlushInterval   = 250 * time.Millisecond
+	sharedSubscriptionMaxPending      = 128
+	sharedSubscriptionClientQueueSize = 256
+	// sharedSubscriptionMaxPerClient is the maximum number of distinct shared subscriptions of a
+	// client, each of which may need an upstream subscription.
+	sharedSubscriptionMaxPerClient = 16
+)
+
+var errUpstreamClosed = errors.New("upstream subscription connection closed")
+
+type subscriptionResult struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+type subscriptionNotification struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  subscriptionResult `json:"params"`
+}
+
+// subscriptionKey returns the key of the shared subscription of the eth_subscribe params,
+// false if the subscription can not be shared. Only newHeads and logs subscriptions are shared.
+func subscriptionKey(params json.RawMessage) (string, bool) {
+	var p []interface{}
+	if err := json.Unmarshal(params, &p); err != nil || len(p) == 0 {
+		return "", false
+	}
+	switch p[0] {
+	case "newHeads":
+		if len(p) != 1 {
+			return "", false
+		}
+	case "logs":
+		if len(p) > 2 {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	// re-encoding sorts the keys of the logs filter, so that identical filters share a subscription
+	key, err := json.Marshal(p)
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// notificationBlock returns the block number of a newHeads or logs notification.
+func notificationBlock(result json.RawMessage) (hexutil.Uint64, bool) {
+	var r struct {
+		Number      *hexutil.Uint64 `json:"number"`
+		BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(result, &r); err != nil {
+		return 0, false
+	}
+	if r.Number != nil {
+		return *r.Number, true
+	}
+	if r.BlockNumber != nil {
+		return *r.BlockNumber, true
+	}
+	return 0, false
+}
+
+func newSubscriptionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hexutil.Encode(b[:])
+}
+
+// SubscriptionNotifier delivers a notification of the client subscription with the ID to a client.
+// It must not block, if an error is returned the client subscription is dropped.
+type SubscriptionNotifier func(id string, msg []byte) error
+
+type sharedSubscription struct {
+	key    string
+	params json.RawMessage
+	// upstream is the connection the subscription is subscribed on, with upstreamID.
+	// The subscription is resubscribed if it is not the current upstream connection.
+	upstream   *upstreamConn
+	upstreamID string
+	clients    map[string]SubscriptionNotifier
+	// pending holds the notifications ahead of the consensus head
+	pending []json.RawMessage
+}
+
+type clientNotification struct {
+	id     string
+	notify SubscriptionNotifier
+	msg    []byte
+}
+
+// SubscriptionMux multiplexes the identical eth_subscribe subscriptions of the WebSocket
+// clients of a backend group onto one upstream subscription, and fans out the notifications.
+// All upstream subscriptions share one connection to a backend of the group. If the backend
+// group is consensus aware, the connection is made to a backend of the consensus group, and
+// notifications are held back until the consensus head reaches their block.
+type SubscriptionMux struct {
+	bg *BackendGroup
+
+	// dialMu serializes dialing the upstream connection
+	dialMu sync.Mutex
+	// deliverMu serializes the delivery of notifications, so that clients receive them in order
+	deliverMu sync.Mutex
+
+	mu           sync.Mutex
+	upstream     *upstreamConn
+	subs         map[string]*sharedSubscription
+	upstreamSubs map[string]*sharedSubscription
+	clients      map[string]*sharedSubscription
+	// subscribing holds the keys that are being subscribed upstream. The channel is closed when done.
+	subscribing map[string]chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSubscriptionMux(bg *BackendGroup) *SubscriptionMux {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &SubscriptionMux{
+		bg:           bg,
+		subs:         make(map[string]*sharedSubscription),
+		upstreamSubs: make(map[string]*sharedSubscription),
+		clients:      make(map[string]*sharedSubscription),
+		subscribing:  make(map[string]chan struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	m.wg.Add(1)
+	go m.loop()
+	return m
+}
+
+// Subscribe subscribes the client subscription with the ID to the shared subscription of the
+// eth_subscribe params. The shared subscription is subscribed upstream if the client is the first
+// subscriber. Notifications may be delivered before Subscribe returns.
+func (m *SubscriptionMux) Subscribe(id string, params json.RawMessage, notify SubscriptionNotifier) error {
+	key, ok := subscriptionKey(params)
+	if !ok {
+		return ErrInvalidParams("subscription can not be shared")
+	}
+
+	for {
+		joined, wait := m.join(key, id, notify)
+		if joined {
+			return nil
+		}
+		if wait == nil {
+			break
+		}
+		// another client is subscribing the key upstream
+		select {
+		case <-wait:
+		case <-m.ctx.Done():
+			return ErrBackendOffline
+		}
+	}
+	defer m.release(key)
+
+	ctx, cancel := context.WithTimeout(m.ctx, sharedSubscriptionCallTimeout)
+	defer cancel()
+	u, err := m.connect(ctx)
+	if err != nil {
+		return err
+	}
+	upstreamID, err := u.subscribe(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub := m.subs[key]
+	if sub == nil {
+		sub = &sharedSubscription{key: key, params: params, clients: make(map[string]SubscriptionNotifier)}
+		m.subs[key] = sub
+	}
+	sub.upstream = u
+	sub.upstreamID = upstreamID
+	// if the connection was dropped in the meantime, the maintenance loop resubscribes
+	if m.upstream == u {
+		m.upstreamSubs[upstreamID] = sub
+	}
+	m.addClient(sub, id, notify)
+	log.Info("subscribed shared subscription upstream", "backend_group", m.bg.Name, "backend", u.backend.Name, "params", key)
+	return nil
+}
+
+// join adds the client to the shared subscription of the key, if it is subscribed upstream.
+// Otherwise, it claims the key for the caller to subscribe it upstream, or returns the channel
+// that is closed once the key is no longer claimed, if it is claimed already.
+func (m *SubscriptionMux) join(key string, id string, notify SubscriptionNotifier) (bool, chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub := m.subs[key]
+	if sub != nil && sub.upstream != nil && sub.upstream == m.upstream {
+		m.addClient(sub, id, notify)
+		return true, nil
+	}
+	if wait, ok := m.subscribing[key]; ok {
+		return false, wait
+	}
+	m.subscribing[key] = make(chan struct{})
+	return false, nil
+}
+
+// claim claims the key to resubscribe it upstream, it returns false if the key is claimed already.
+func (m *SubscriptionMux) claim(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subscribing[key]; ok {
+		return false
+	}
+	m.subscribing[key] = make(chan struct{})
+	return true
+}
+
+// release releases the claim of the key, and wakes up the clients waiting for it.
+func (m *SubscriptionMux) release(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	close(m.subscribing[key])
+	delete(m.subscribing, key)
+}
+
+func (m *SubscriptionMux) addClient(sub *sharedSubscription, id string, notify SubscriptionNotifier) {
+	sub.clients[id] = notify
+	m.clients[id] = sub
+	m.recordSubscriptions()
+}
+
+// Unsubscribe removes the client subscription. The shared subscription is unsubscribed
+// upstream when its last client unsubscribes. It returns false if the subscription does not exist.
+func (m *SubscriptionMux) Unsubscribe(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub := m.clients[id]
+	if sub == nil {
+		return false
+	}
+	delete(m.clients, id)
+	delete(sub.clients, id)
+	if len(sub.clients) == 0 {
+		delete(m.subs, sub.key)
+		if sub.upstream != nil && sub.upstream == m.upstream {
+			delete(m.upstreamSubs, sub.upstreamID)
+			go sub.upstream.unsubscribe(sub.upstreamID)
+		}
+	}
+	m.recordSubscriptions()
+	return true
+}
+
+// Close closes the upstream connection. Clients are not notified.
+func (m *SubscriptionMux) Close() {
+	m.cancel()
+	m.wg.Wait()
+	m.mu.Lock()
+	u := m.upstream
+	m.mu.Unlock()
+	if u != nil {
+		m.dropUpstream(u)
+	}
+}
+
+func (m *SubscriptionMux) recordSubscriptions() {
+	RecordGroupSharedSubscriptions(m.bg, len(m.subs), len(m.clients))
+}
+
+// connect returns the current upstream connection, or dials a new one.
+func (m *SubscriptionMux) connect(ctx context.Context) (*upstreamConn, error) {
+	m.dialMu.Lock()
+	defer m.dialMu.Unlock()
+	m.mu.Lock()
+	u := m.upstream
+	m.mu.Unlock()
+	if u != nil {
+		return u, nil
+	}
+
+	backends := m.bg.Backends
+	if m.bg.Consensus != nil {
+		backends = m.bg.loadBalancedConsensusGroup()
+	}
+	for _, back := range backends {
+		conn, _, err := back.dialer.DialContext(ctx, back.wsURL, nil) // nolint:bodyclose
+		if err != nil {
+			log.Warn("error dialing ws backend for shared subscriptions", "backend_group", m.bg.Name, "name", back.Name, "err", err)
+			continue
+		}
+		activeBackendWsConnsGauge.WithLabelValues(back.Name).Inc()
+		u = newUpstreamConn(back, conn)
+		m.mu.Lock()
+		m.upstream = u
+		m.mu.Unlock()
+		go func() {
+			err := u.readLoop(m.dispatch)
+			log.Warn("shared subscriptions connection closed", "backend_group", m.bg.Name, "backend", u.backend.Name, "err", err)
+			m.dropUpstream(u)
+		}()
+		log.Info("connected shared subscriptions", "backend_group", m.bg.Name, "backend", back.Name)
+		return u, nil
+	}
+	return nil, ErrNoBackends
+}
+
+// dropUpstream closes the upstream connection. The shared subscriptions are resubscribed
+// on a new connection by the maintenance loop.
+func (m *SubscriptionMux) dropUpstream(u *upstreamConn) {
+	m.mu.Lock()
+	if m.upstream == u {
+		m.upstream = nil
+		m.upstreamSubs = make(map[string]*sharedSubscription)
+	}
+	m.mu.Unlock()
+	u.close()
+}
+
+// dispatch fans out a notification of the upstream subscription to its clients.
+func (m *SubscriptionMux) dispatch(u *upstreamConn, upstreamID string, result json.RawMessage) {
+	m.deliverMu.Lock()
+	defer m.deliverMu.Unlock()
+	m.mu.Lock()
+	sub := m.upstreamSubs[upstreamID]
+	if sub == nil || sub.upstream != u || m.upstream != u {
+		m.mu.Unlock()
+		return
+	}
+	var out []clientNotification
+	if m.bg.Consensus != nil {
+		if len(sub.pending) >= sharedSubscriptionMaxPending {
+			sub.pending = sub.pending[1:]
+			RecordSharedSubscriptionDrop(m.bg, "pending_overflow")
+		}
+		sub.pending = append(sub.pending, result)
+		out = m.flushLocked(sub, m.bg.Consensus.GetLatestBlockNumber())
+	} else {
+		out = sub.notifications(result)
+	}
+	m.mu.Unlock()
+	m.deliver(out)
+}
+
+// flushPending releases the pending notifications at or below the consensus head.
+func (m *SubscriptionMux) flushPending() {
+	if m.bg.Consensus == nil {
+		return
+	}
+	m.deliverMu.Lock()
+	defer m.deliverMu.Unlock()
+	latest := m.bg.Consensus.GetLatestBlockNumber()
+	m.mu.Lock()
+	var out []clientNotification
+	for _, sub := range m.subs {
+		out = append(out, m.flushLocked(sub, latest)...)
+	}
+	m.mu.Unlock()
+	m.deliver(out)
+}
+
+func (m *SubscriptionMux) flushLocked(sub *sharedSubscription, latest hexutil.Uint64) []clientNotification {
+	var out []clientNotification
+	for len(sub.pending) > 0 {
+		if n, ok := notificationBlock(sub.pending[0]); ok && n > latest {
+			break
+		}
+		out = append(out, sub.notifications(sub.pending[0])...)
+		sub.pending = sub.pending[1:]
+	}
+	return out
+}
+
+func (sub *sharedSubscription) notifications(result json.RawMessage) []clientNotification {
+	out := make([]clientNotification, 0, len(sub.clients))
+	for id, notify := range sub.clients {
+		msg := mustMarshalJSON(subscriptionNotification{
+			JSONRPC: JSONRPCVersion,
+			Method:  "eth_subscription",
+			Params:  subscriptionResult{Subscription: id, Result: result},
+		})
+		out = append(out, clientNotification{id: id, notify: notify, msg: msg})
+	}
+	return out
+}
+
+// deliver delivers the notifications to the clients. It must be called with deliverMu held,
+// but without mu, because dropping a client unsubscribes it.
+func (m *SubscriptionMux) deliver(out []clientNotification) {
+	for _, n := range out {
+		if err := n.notify(n.id, n.msg); err != nil {
+			log.Warn("dropping shared subscription client", "backend_group", m.bg.Name, "err", err)
+			RecordSharedSubscriptionDrop(m.bg, "slow_client")
+			m.Unsubscribe(n.id)
+		}
+	}
+}
+
+func (m *SubscriptionMux) loop() {
+	defer m.wg.Done()
+	checkTicker := time.NewTicker(sharedSubscriptionCheckInterval)
+	defer checkTicker.Stop()
+	flushTicker := time.NewTicker(sharedSubscriptionFlushInterval)
+	defer flushTicker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-flushTicker.C:
+			m.flushPending()
+		case <-checkTicker.C:
+			m.maintain()
+		}
+	}
+}
+
+// maintain closes the upstream connection if it is unused or its backend left the consensus group,
+// and resubscribes the shared subscriptions that are not subscribed on the current connection.
+func (m *SubscriptionMux) maintain() {
+	m.mu.Lock()
+	u := m.upstream
+	var stale []*sharedSubscription
+	for _, sub := range m.subs {
+		if sub.upstream == nil || sub.upstream != u {
+			stale = append(stale, sub)
+		}
+	}
+	// keys that are being subscribed use the connection as well
+	numSubs := len(m.subs) + len(m.subscribing)
+	m.mu.Unlock()
+
+	if u != nil && numSubs == 0 {
+		log.Info("closing unused shared subscriptions connection", "backend_group", m.bg.Name, "backend", u.backend.Name)
+		m.dropUpstream(u)
+		return
+	}
+	if u != nil && m.bg.Consensus != nil && !m.inConsensus(u.backend) {
+		log.Warn("shared subscriptions backend left the consensus group, reconnecting", "backend_group", m.bg.Name, "backend", u.backend.Name)
+		m.dropUpstream(u)
+		m.mu.Lock()
+		stale = stale[:0]
+		for _, sub := range m.subs {
+			stale = append(stale, sub)
+		}
+		m.mu.Unlock()
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, sharedSubscriptionCallTimeout)
+	defer cancel()
+	u, err := m.connect(ctx)
+	if err != nil {
+		log.Error("error connecting shared subscriptions", "backend_group", m.bg.Name, "err", err)
+		return
+	}
+	for _, sub := range stale {
+		m.resubscribe(ctx, u, sub)
+	}
+}
+
+// resubscribe subscribes the stale shared subscription on the upstream connection,
+// unless a client is subscribing its key already.
+func (m *SubscriptionMux) resubscribe(ctx context.Context, u *upstreamConn, sub *sharedSubscription) {
+	if !m.claim(sub.key) {
+		return
+	}
+	defer m.release(sub.key)
+	upstreamID, err := u.subscribe(ctx, sub.params)
+	if err != nil {
+		log.Error("error resubscribing shared subscription", "backend_group", m.bg.Name, "params", sub.key, "err", err)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subs[sub.key] == sub {
+		sub.upstream = u
+		sub.upstreamID = upstreamID
+		m.upstreamSubs[upstreamID] = sub
+	} else {
+		// all clients unsubscribed in the meantime
+		go u.unsubscribe(upstreamID)
+	}
+}
+
+func (m *SubscriptionMux) inConsensus(be *Backend) bool {
+	for _, b := range m.bg.Consensus.GetConsensusGroup() {
+		if b == be && !m.bg.Consensus.IsBanned(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// upstreamConn is a WebSocket connection to a backend, that carries the shared subscriptions.
+type upstreamConn struct {
+	backend *Backend
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu     sync.Mutex
+	nextID uint64
+	calls  map[uint64]chan *RPCRes
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newUpstreamConn(backend *Backend, conn *websocket.Conn) *upstreamConn {
+	return &upstreamConn{
+		backend: backend,
+		conn:    conn,
+		calls:   make(map[uint64]chan *RPCRes),
+		done:    make(chan struct{}),
+	}
+}
+
+func (u *upstreamConn) close() {
+	u.closeOnce.Do(func() {
+		close(u.done)
+		u.conn.Close()
+		activeBackendWsConnsGauge.WithLabelValues(u.backend.Name).Dec()
+	})
+}
+
+// readLoop reads the responses and subscription notifications of the connection until it fails.
+func (u *upstreamConn) readLoop(onNotification func(u *upstreamConn, upstreamID string, result json.RawMessage)) error {
+	for {
+		_, msg, err := u.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var notification subscriptionNotification
+		if err := json.Unmarshal(msg, &notification); err == nil && notification.Method == "eth_subscription" {
+			onNotification(u, notification.Params.Subscription, notification.Params.Result)
+			continue
+		}
+		res, err := ParseRPCRes(bytes.NewReader(msg))
+		if err != nil {
+			log.Warn("error parsing shared subscriptions response", "backend", u.backend.Name, "err", err)
+			continue
+		}
+		id, err := strconv.ParseUint(string(res.ID), 10, 64)
+		if err != nil {
+			continue
+		}
+		u.mu.Lock()
+		ch := u.calls[id]
+		delete(u.calls, id)
+		u.mu.Unlock()
+		if ch != nil {
+			ch <- res
+		}
+	}
+}
+
+func (u *upstreamConn) call(ctx context.Context, method string, params json.RawMessage) (*RPCRes, error) {
+	ch := make(chan *RPCRes, 1)
+	u.mu.Lock()
+	u.nextID++
+	id := u.nextID
+	u.calls[id] = ch
+	u.mu.Unlock()
+	defer func() {
+		u.mu.Lock()
+		delete(u.calls, id)
+		u.mu.Unlock()
+	}()
+
+	req := &RPCReq{
+		JSONRPC: JSONRPCVersion,
+		Method:  method,
+		Params:  params,
+		ID:      json.RawMessage(strconv.FormatUint(id, 10)),
+	}
+	u.writeMu.Lock()
+	err := u.conn.SetWriteDeadline(time.Now().Add(defaultWSWriteTimeout))
+	if err == nil {
+		err = u.conn.WriteMessage(websocket.TextMessage, mustMarshalJSON(req))
+	}
+	u.writeMu.Unlock()
+	if err != nil {
+		return nil, wrapErr(err, "error writing to backend")
+	}
+
+	timer := time.NewTimer(sharedSubscriptionCallTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-ch:
+		if res.IsError() {
+			return nil, res.Error
+		}
+		return res, nil
+	case <-u.done:
+		return nil, errUpstreamClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, ErrGatewayTimeout
+	}
+}
+
+func (u *upstreamConn) subscribe(ctx context.Context, params json.RawMessage) (string, error) {
+	res, err := u.call(ctx, "eth_subscribe", params)
+	if err != nil {
+		return "", err
+	}
+	id, ok := res.Result.(string)
+	if !ok {
+		return "", ErrBackendBadResponse
+	}
+	return id, nil
+}
+
+func (u *upstreamConn) unsubscribe(upstreamID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedSubscriptionCallTimeout)
+	defer cancel()
+	if _, err := u.call(ctx, "eth_unsubscribe", mustMarshalJSON([]string{upstreamID})); err != nil && !errors.Is(err, errUpstreamClosed) {
+		log.Warn("error unsubscribing shared subscription", "backend", u.backend.Name, "err", err)
+	}
+}
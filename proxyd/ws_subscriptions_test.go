@@ -0,0 +1,158 @@
+package proxyd
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionKey(t *testing.T) {
+	key := func(params string) (string, bool) {
+		return subscriptionKey(json.RawMessage(params))
+	}
+
+	newHeads, ok := key(`["newHeads"]`)
+	require.True(t, ok)
+	require.Equal(t, `["newHeads"]`, newHeads)
+
+	logsA, ok := key(`["logs", {"address": "0x1234", "topics": []}]`)
+	require.True(t, ok)
+	logsB, ok := key(`["logs",{"topics":[],"address":"0x1234"}]`)
+	require.True(t, ok)
+	require.Equal(t, logsA, logsB, "identical filters share a subscription")
+
+	logsC, ok := key(`["logs",{"address":"0x5678"}]`)
+	require.True(t, ok)
+	require.NotEqual(t, logsA, logsC)
+
+	for _, params := range []string{
+		`["newPendingTransactions"]`,
+		`["syncing"]`,
+		`["newHeads", true]`,
+		`[]`,
+		`{}`,
+	} {
+		_, ok := key(params)
+		require.False(t, ok, params)
+	}
+}
+
+func TestNotificationBlock(t *testing.T) {
+	n, ok := notificationBlock(json.RawMessage(`{"number":"0x10","hash":"0x01"}`))
+	require.True(t, ok)
+	require.Equal(t, hexutil.Uint64(16), n)
+
+	n, ok = notificationBlock(json.RawMessage(`{"blockNumber":"0x20","removed":false}`))
+	require.True(t, ok)
+	require.Equal(t, hexutil.Uint64(32), n)
+
+	_, ok = notificationBlock(json.RawMessage(`"0x01"`))
+	require.False(t, ok)
+}
+
+func TestSubscriptionMuxFollowsConsensusHead(t *testing.T) {
+	bg := &BackendGroup{Name: "main"}
+	bg.Consensus = NewConsensusPoller(bg, WithAsyncHandler(NewNoopAsyncHandler()))
+	bg.Consensus.tracker.SetLatestBlockNumber(5)
+
+	u := &upstreamConn{}
+	var received []string
+	sub := &sharedSubscription{
+		key:        `["newHeads"]`,
+		upstream:   u,
+		upstreamID: "0x1",
+		clients: map[string]SubscriptionNotifier{
+			"0xa": func(id string, msg []byte) error {
+				var n subscriptionNotification
+				require.NoError(t, json.Unmarshal(msg, &n))
+				require.Equal(t, id, n.Params.Subscription)
+				received = append(received, string(n.Params.Result))
+				return nil
+			},
+		},
+	}
+	m := &SubscriptionMux{
+		bg:           bg,
+		upstream:     u,
+		subs:         map[string]*sharedSubscription{sub.key: sub},
+		upstreamSubs: map[string]*sharedSubscription{"0x1": sub},
+		clients:      map[string]*sharedSubscription{"0xa": sub},
+	}
+
+	m.dispatch(u, "0x1", json.RawMessage(`{"number":"0x5"}`))
+	m.dispatch(u, "0x1", json.RawMessage(`{"number":"0x6"}`))
+	m.dispatch(u, "0x1", json.RawMessage(`{"number":"0x7"}`))
+	require.Equal(t, []string{`{"number":"0x5"}`}, received, "notifications ahead of the consensus head are held back")
+
+	bg.Consensus.tracker.SetLatestBlockNumber(6)
+	m.flushPending()
+	require.Equal(t, []string{`{"number":"0x5"}`, `{"number":"0x6"}`}, received)
+
+	// notifications of previous connections are ignored
+	m.dispatch(&upstreamConn{}, "0x1", json.RawMessage(`{"number":"0x1"}`))
+	bg.Consensus.tracker.SetLatestBlockNumber(7)
+	m.flushPending()
+	require.Equal(t, []string{`{"number":"0x5"}`, `{"number":"0x6"}`, `{"number":"0x7"}`}, received)
+}
+
+func TestSubscriptionMuxDeliversInOrder(t *testing.T) {
+	bg := &BackendGroup{Name: "main"}
+	bg.Consensus = NewConsensusPoller(bg, WithAsyncHandler(NewNoopAsyncHandler()))
+	bg.Consensus.tracker.SetLatestBlockNumber(5)
+
+	u := &upstreamConn{}
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	delivering := make(chan struct{})
+	release := make(chan struct{})
+	sub := &sharedSubscription{
+		key:        `["newHeads"]`,
+		upstream:   u,
+		upstreamID: "0x1",
+		clients: map[string]SubscriptionNotifier{
+			"0xa": func(id string, msg []byte) error {
+				var n subscriptionNotification
+				require.NoError(t, json.Unmarshal(msg, &n))
+				if string(n.Params.Result) == `{"number":"0x5"}` {
+					// hold up the delivery of the first notification
+					close(delivering)
+					<-release
+				}
+				mu.Lock()
+				received = append(received, string(n.Params.Result))
+				mu.Unlock()
+				return nil
+			},
+		},
+	}
+	m := &SubscriptionMux{
+		bg:           bg,
+		upstream:     u,
+		subs:         map[string]*sharedSubscription{sub.key: sub},
+		upstreamSubs: map[string]*sharedSubscription{"0x1": sub},
+		clients:      map[string]*sharedSubscription{"0xa": sub},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.dispatch(u, "0x1", json.RawMessage(`{"number":"0x5"}`))
+	}()
+	<-delivering
+	go func() {
+		defer wg.Done()
+		m.dispatch(u, "0x1", json.RawMessage(`{"number":"0x4"}`))
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, []string{`{"number":"0x5"}`, `{"number":"0x4"}`}, received, "notifications are delivered in order")
+}